@@ -2,9 +2,11 @@ package main
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log"
 	"net"
+	"net/http"
 	"os"
 	"os/signal"
 	"strconv"
@@ -25,12 +27,15 @@ import (
 	"github.com/waduhek/flagger/proto/providerpb"
 
 	"github.com/waduhek/flagger/internal/auth"
+	"github.com/waduhek/flagger/internal/changefeed"
 	"github.com/waduhek/flagger/internal/environment"
 	"github.com/waduhek/flagger/internal/flag"
 	"github.com/waduhek/flagger/internal/flagsetting"
+	"github.com/waduhek/flagger/internal/flagview"
 	"github.com/waduhek/flagger/internal/logger"
 	"github.com/waduhek/flagger/internal/project"
 	"github.com/waduhek/flagger/internal/provider"
+	"github.com/waduhek/flagger/internal/rulesetcache"
 	"github.com/waduhek/flagger/internal/startup"
 	"github.com/waduhek/flagger/internal/user"
 )
@@ -53,7 +58,45 @@ func initAuthServer(db *mongo.Database) *auth.Server {
 	return authServer
 }
 
-func initProjectServer(db *mongo.Database) *project.Server {
+// initArchivedKeys loads the keys of the archived projects and refreshes them
+// in the background.
+func initArchivedKeys(db *mongo.Database) *project.ArchivedKeys {
+	const defaultRefreshInterval = 5 * time.Second
+
+	ctx := context.Background()
+	ctx, cancel := context.WithTimeout(ctx, 1*time.Second)
+	defer cancel()
+
+	projectRepo, err := project.NewProjectRepository(ctx, db, loggerImpl)
+	if err != nil {
+		log.Panicf("could not initialise project repository: %v", err)
+	}
+
+	refreshInterval, err := time.ParseDuration(os.Getenv("FLAGGER_ARCHIVED_KEYS_REFRESH_INTERVAL"))
+	if err != nil || refreshInterval <= 0 {
+		refreshInterval = defaultRefreshInterval
+	}
+
+	archivedKeys := project.NewArchivedKeys(projectRepo, loggerImpl)
+
+	if err = archivedKeys.Refresh(ctx); err != nil {
+		log.Panicf("could not load the keys of archived projects: %v", err)
+	}
+
+	archivedKeys.StartRefreshing(context.Background(), refreshInterval)
+
+	return archivedKeys
+}
+
+func initProjectServer(
+	client *mongo.Client,
+	db *mongo.Database,
+	changeFeedRepo changefeed.Repository,
+	cacheInvalidator rulesetcache.Invalidator,
+	archivedKeys *project.ArchivedKeys,
+) *project.Server {
+	const defaultRetention = 30 * 24 * time.Hour
+
 	ctx := context.Background()
 	ctx, cancel := context.WithTimeout(ctx, 1*time.Second)
 	defer cancel()
@@ -68,12 +111,62 @@ func initProjectServer(db *mongo.Database) *project.Server {
 		log.Panicf("could not initialise user repository: %v", err)
 	}
 
-	return project.NewProjectServer(projectRepo, userRepo, loggerImpl)
+	flagSettingRepo, err := flagsetting.NewFlagSettingRepository(
+		ctx,
+		db,
+		loggerImpl,
+	)
+	if err != nil {
+		log.Panicf("could not initialise flag setting repository: %v", err)
+	}
+
+	flagRepo, err := flag.NewFlagRepository(ctx, db, loggerImpl)
+	if err != nil {
+		log.Panicf("could not initialise flag repository: %v", err)
+	}
+
+	environmentRepo, err := environment.NewEnvironmentRepository(
+		ctx,
+		db,
+		loggerImpl,
+	)
+	if err != nil {
+		log.Panicf("could not initialise environment repository: %v", err)
+	}
+
+	flagViewRepo, err := flagview.NewFlagViewRepository(ctx, db, loggerImpl)
+	if err != nil {
+		log.Panicf("could not initialise flag view repository: %v", err)
+	}
+
+	retention, err := time.ParseDuration(os.Getenv("FLAGGER_PROJECT_RETENTION"))
+	if err != nil || retention < 0 {
+		retention = defaultRetention
+	}
+
+	return project.NewProjectServer(
+		client,
+		projectRepo,
+		userRepo,
+		flagSettingRepo,
+		flagRepo,
+		environmentRepo,
+		flagViewRepo,
+		changeFeedRepo,
+		cacheInvalidator,
+		archivedKeys,
+		retention,
+		loggerImpl,
+	)
 }
 
 func initEnvironmentServer(
 	client *mongo.Client,
 	db *mongo.Database,
+	changeFeedRepo changefeed.Repository,
+	cacheInvalidator rulesetcache.Invalidator,
+	cacheWarmer rulesetcache.Warmer,
+	usageRepo environment.UsageRepository,
 ) *environment.Server {
 	ctx := context.Background()
 	ctx, cancel := context.WithTimeout(ctx, 1*time.Second)
@@ -89,6 +182,11 @@ func initEnvironmentServer(
 		log.Panicf("could not initialise project repository: %v", err)
 	}
 
+	flagRepo, err := flag.NewFlagRepository(ctx, db, loggerImpl)
+	if err != nil {
+		log.Panicf("could not initialise flag repository: %v", err)
+	}
+
 	flagSettingRepo, err := flagsetting.NewFlagSettingRepository(
 		ctx,
 		db,
@@ -98,6 +196,11 @@ func initEnvironmentServer(
 		log.Panicf("could not initialise flag setting repository: %v", err)
 	}
 
+	flagViewRepo, err := flagview.NewFlagViewRepository(ctx, db, loggerImpl)
+	if err != nil {
+		log.Panicf("could not initialise flag view repository: %v", err)
+	}
+
 	environmentRepo, err := environment.NewEnvironmentRepository(
 		ctx,
 		db,
@@ -111,13 +214,26 @@ func initEnvironmentServer(
 		client,
 		userRepo,
 		projectRepo,
+		flagRepo,
 		flagSettingRepo,
+		flagViewRepo,
 		environmentRepo,
+		changeFeedRepo,
+		cacheInvalidator,
+		cacheWarmer,
+		usageRepo,
+		getRenameAliasPeriod(),
 		loggerImpl,
 	)
 }
 
-func initFlagServer(client *mongo.Client, db *mongo.Database) *flag.Server {
+func initFlagServer(
+	client *mongo.Client,
+	db *mongo.Database,
+	changeFeedRepo changefeed.Repository,
+	cacheInvalidator rulesetcache.Invalidator,
+	cacheWarmer rulesetcache.Warmer,
+) *flag.Server {
 	ctx := context.Background()
 	ctx, cancel := context.WithTimeout(ctx, 1*time.Second)
 	defer cancel()
@@ -146,6 +262,11 @@ func initFlagServer(client *mongo.Client, db *mongo.Database) *flag.Server {
 		log.Panicf("could not initialise flag setting repository: %v", err)
 	}
 
+	flagViewRepo, err := flagview.NewFlagViewRepository(ctx, db, loggerImpl)
+	if err != nil {
+		log.Panicf("could not initialise flag view repository: %v", err)
+	}
+
 	environmentRepo, err := environment.NewEnvironmentRepository(
 		ctx,
 		db,
@@ -162,18 +283,198 @@ func initFlagServer(client *mongo.Client, db *mongo.Database) *flag.Server {
 		environmentRepo,
 		flagRepo,
 		flagSettingRepo,
+		flagViewRepo,
+		changeFeedRepo,
+		cacheInvalidator,
+		cacheWarmer,
+		getRenameAliasPeriod(),
+		loggerImpl,
+	)
+}
+
+// getRenameAliasPeriod gets how long the previous names of the renamed flags
+// and environments still resolve for the SDKs.
+func getRenameAliasPeriod() time.Duration {
+	const defaultAliasPeriod = 7 * 24 * time.Hour
+
+	aliasPeriod, err := time.ParseDuration(os.Getenv("FLAGGER_RENAME_ALIAS_PERIOD"))
+	if err != nil || aliasPeriod < 0 {
+		aliasPeriod = defaultAliasPeriod
+	}
+
+	return aliasPeriod
+}
+
+func initHTTPServer(
+	flagProviderServer *provider.FlagProviderServer,
+	cacheRepo *provider.BreakerCacheRepository,
+	archivedKeys *project.ArchivedKeys,
+	port uint64,
+) *http.Server {
+	const readHeaderTimeout = 5 * time.Second
+
+	healthHandler := provider.NewHealthHandler(cacheRepo)
+
+	mux := http.NewServeMux()
+	mux.Handle("/ofrep/", provider.NewOFREPHandler(flagProviderServer, archivedKeys, loggerImpl))
+	mux.Handle("/healthz", healthHandler)
+	mux.Handle("/metrics", healthHandler)
+
+	return &http.Server{
+		Addr:              fmt.Sprintf(":%d", port),
+		Handler:           mux,
+		ReadHeaderTimeout: readHeaderTimeout,
+	}
+}
+
+func initBreakerCacheRepository(redisClient redis.UniversalClient) *provider.BreakerCacheRepository {
+	const defaultFailureThreshold = 5
+	const defaultCooldown = 30 * time.Second
+
+	failureThreshold, err := strconv.ParseUint(
+		os.Getenv("FLAGGER_CACHE_BREAKER_THRESHOLD"),
+		10,
+		32,
+	)
+	if err != nil {
+		failureThreshold = defaultFailureThreshold
+	}
+
+	cooldown, err := time.ParseDuration(os.Getenv("FLAGGER_CACHE_BREAKER_COOLDOWN"))
+	if err != nil {
+		cooldown = defaultCooldown
+	}
+
+	return provider.NewBreakerCacheRepository(
+		provider.NewProviderCacheRepository(redisClient),
+		uint(failureThreshold),
+		cooldown,
 		loggerImpl,
 	)
 }
 
+// initLocalCacheRepository adds a local cache in front of the cache unless the
+// size of the local cache is set to 0.
+func initLocalCacheRepository(
+	cacheRepo provider.CacheRepository,
+	redisClient redis.UniversalClient,
+) provider.CacheRepository {
+	const defaultSize = 10000
+	const defaultMaxStaleness = 1 * time.Second
+
+	size, err := strconv.Atoi(os.Getenv("FLAGGER_LOCAL_CACHE_SIZE"))
+	if err != nil {
+		size = defaultSize
+	}
+
+	if size <= 0 {
+		return cacheRepo
+	}
+
+	maxStaleness, err := time.ParseDuration(os.Getenv("FLAGGER_LOCAL_CACHE_MAX_STALENESS"))
+	if err != nil {
+		maxStaleness = defaultMaxStaleness
+	}
+
+	localCacheRepo := provider.NewLocalCacheRepository(
+		cacheRepo,
+		redisClient,
+		size,
+		maxStaleness,
+		loggerImpl,
+	)
+
+	go localCacheRepo.ListenForInvalidations(context.Background())
+
+	return localCacheRepo
+}
+
+func initMemoryCacheRepository() *provider.MemoryCacheRepository {
+	const defaultSize = 10000
+
+	size, err := strconv.Atoi(os.Getenv("FLAGGER_MEMORY_CACHE_SIZE"))
+	if err != nil {
+		size = defaultSize
+	}
+
+	return provider.NewMemoryCacheRepository(size)
+}
+
 func initFlagProviderServer(
 	db *mongo.Database,
-	redisClient *redis.Client,
+	cacheRepo provider.CacheRepository,
+	changeFeedRepo changefeed.Repository,
+	usageRepo provider.UsageRepository,
 ) *provider.FlagProviderServer {
 	providerRepo := provider.NewProviderRepository(db)
-	cacheRepo := provider.NewProviderCacheRepository(redisClient)
 
-	return provider.NewFlagProviderServer(providerRepo, cacheRepo, loggerImpl)
+	return provider.NewFlagProviderServer(
+		providerRepo,
+		cacheRepo,
+		changeFeedRepo,
+		usageRepo,
+		loggerImpl,
+	)
+}
+
+// startCacheWarming warms the cache with the flags of the recently used
+// environments in the background, unless the number of warmed environments is
+// set to 0.
+func startCacheWarming(flagProviderServer *provider.FlagProviderServer) {
+	const defaultWorkers = 4
+	const defaultRecentWindow = 24 * time.Hour
+	const defaultMaxEnvironments = 100
+
+	workers, err := strconv.Atoi(os.Getenv("FLAGGER_CACHE_WARM_CONCURRENCY"))
+	if err != nil {
+		workers = defaultWorkers
+	}
+
+	recentWindow, err := time.ParseDuration(os.Getenv("FLAGGER_CACHE_WARM_WINDOW"))
+	if err != nil {
+		recentWindow = defaultRecentWindow
+	}
+
+	maxEnvironments, err := strconv.Atoi(os.Getenv("FLAGGER_CACHE_WARM_ENVIRONMENTS"))
+	if err != nil {
+		maxEnvironments = defaultMaxEnvironments
+	}
+
+	if maxEnvironments <= 0 {
+		return
+	}
+
+	flagProviderServer.StartCacheWarming(context.Background(), provider.CacheWarmingOptions{
+		Workers:         workers,
+		RecentWindow:    recentWindow,
+		MaxEnvironments: maxEnvironments,
+	})
+}
+
+// startStaleRefreshing refetches the flags that are served stale while the
+// database fails in the background.
+func startStaleRefreshing(flagProviderServer *provider.FlagProviderServer) {
+	const defaultWorkers = 4
+
+	workers, err := strconv.Atoi(os.Getenv("FLAGGER_STALE_REFRESH_CONCURRENCY"))
+	if err != nil {
+		workers = defaultWorkers
+	}
+
+	flagProviderServer.StartStaleRefreshing(context.Background(), workers)
+}
+
+// startProjectPurging purges the deleted projects whose retention window has
+// passed in the background.
+func startProjectPurging(projectServer *project.Server) {
+	const defaultInterval = 1 * time.Hour
+
+	interval, err := time.ParseDuration(os.Getenv("FLAGGER_PROJECT_PURGE_INTERVAL"))
+	if err != nil || interval <= 0 {
+		interval = defaultInterval
+	}
+
+	projectServer.StartPurging(context.Background(), interval)
 }
 
 func gracefulShutdown(cleanup func()) {
@@ -190,9 +491,34 @@ func gracefulShutdown(cleanup func()) {
 	}()
 }
 
+// The ports that the servers listen on unless they are set.
+const (
+	defaultServerPort = 50051
+	defaultHTTPPort   = 8080
+)
+
+// parsePort parses the port in the environment variable, which defaults to the
+// default port when it is not set. Panics if the port is invalid, so that the
+// server does not listen on a random port.
+func parsePort(name string, defaultPort uint64) uint64 {
+	value := os.Getenv(name)
+	if value == "" {
+		return defaultPort
+	}
+
+	port, err := strconv.ParseUint(value, 10, 16)
+	if err != nil || port == 0 {
+		log.Panicf("invalid port %q in %s", value, name)
+	}
+
+	return port
+}
+
 func main() {
 	flaggerDB := os.Getenv("FLAGGER_DB")
-	serverPort, _ := strconv.ParseUint(os.Getenv("FLAGGER_PORT"), 10, 16)
+	serverPort := parsePort("FLAGGER_PORT", defaultServerPort)
+	httpPort := parsePort("FLAGGER_HTTP_PORT", defaultHTTPPort)
+	cacheBackend := os.Getenv("FLAGGER_CACHE_BACKEND")
 
 	lis, lisErr := net.Listen("tcp", fmt.Sprintf(":%d", serverPort))
 	if lisErr != nil {
@@ -205,17 +531,51 @@ func main() {
 	}
 	mongoDB := mongoClient.Database(flaggerDB)
 
-	redisClient, redisClientErr := startup.ConnectRedis(loggerImpl)
-	if redisClientErr != nil {
-		panic(redisClientErr)
+	// Redis is only required by the Redis cache backend. The other backends
+	// keep the changes to the flags in memory, so they can only be used by a
+	// single replica.
+	var cacheRepo provider.CacheRepository
+	var breakerCacheRepo *provider.BreakerCacheRepository
+	var changeFeedRepo changefeed.Repository
+	var usageRepo provider.UsageRepository
+
+	switch cacheBackend {
+	case "", "redis":
+		redisClient, redisClientErr := startup.ConnectRedis(loggerImpl)
+		if redisClientErr != nil {
+			panic(redisClientErr)
+		}
+
+		breakerCacheRepo = initBreakerCacheRepository(redisClient)
+		cacheRepo = initLocalCacheRepository(breakerCacheRepo, redisClient)
+		changeFeedRepo = changefeed.NewChangeFeedRepository(redisClient)
+		usageRepo = provider.NewRedisUsageRepository(redisClient)
+	case "memory":
+		cacheRepo = initMemoryCacheRepository()
+		changeFeedRepo = changefeed.NewMemoryChangeFeedRepository()
+		usageRepo = provider.NewMemoryUsageRepository()
+	case "none":
+		cacheRepo = provider.NewNoCacheRepository()
+		changeFeedRepo = changefeed.NewMemoryChangeFeedRepository()
+	default:
+		log.Panicf("unknown cache backend %q", cacheBackend)
 	}
 
 	// Initialising all the servers
 	authServer := initAuthServer(mongoDB)
-	projectServer := initProjectServer(mongoDB)
-	environmentServer := initEnvironmentServer(mongoClient, mongoDB)
-	flagServer := initFlagServer(mongoClient, mongoDB)
-	flagProviderServer := initFlagProviderServer(mongoDB, redisClient)
+	archivedKeys := initArchivedKeys(mongoDB)
+	projectServer := initProjectServer(mongoClient, mongoDB, changeFeedRepo, cacheRepo, archivedKeys)
+	flagProviderServer := initFlagProviderServer(mongoDB, cacheRepo, changeFeedRepo, usageRepo)
+	environmentServer := initEnvironmentServer(
+		mongoClient,
+		mongoDB,
+		changeFeedRepo,
+		cacheRepo,
+		flagProviderServer,
+		usageRepo,
+	)
+	flagServer := initFlagServer(mongoClient, mongoDB, changeFeedRepo, cacheRepo, flagProviderServer)
+	httpServer := initHTTPServer(flagProviderServer, breakerCacheRepo, archivedKeys, httpPort)
 
 	grpcServer := grpc.NewServer(
 		grpc.ChainUnaryInterceptor(
@@ -226,7 +586,10 @@ func main() {
 				"/environmentpb.Environment/",
 			),
 			auth.AuthoriseRequestInterceptor(loggerImpl, "/flagpb.Flag/"),
-			project.KeyUnaryInterceptor(loggerImpl, "/providerpb.FlagProvider/"),
+			project.KeyUnaryInterceptor(loggerImpl, "/providerpb.FlagProvider/", archivedKeys),
+		),
+		grpc.ChainStreamInterceptor(
+			project.KeyStreamInterceptor(loggerImpl, "/providerpb.FlagProvider/", archivedKeys),
 		),
 	)
 	// Registering servers
@@ -246,9 +609,31 @@ func main() {
 			log.Panicf("could not disconnect from mongodb: %v", disconnectErr)
 		}
 
+		if shutdownErr := httpServer.Shutdown(ctx); shutdownErr != nil {
+			log.Printf("could not shut down the http server: %v", shutdownErr)
+		}
+
 		grpcServer.GracefulStop()
 	})
 
+	// The cache is warmed in the background so that it does not delay
+	// serving.
+	if usageRepo != nil {
+		startCacheWarming(flagProviderServer)
+	}
+
+	startStaleRefreshing(flagProviderServer)
+	startProjectPurging(projectServer)
+
+	go func() {
+		log.Printf("http server listening at %q", httpServer.Addr)
+
+		serveErr := httpServer.ListenAndServe()
+		if serveErr != nil && !errors.Is(serveErr, http.ErrServerClosed) {
+			log.Fatalf("could not serve http: %v", serveErr)
+		}
+	}()
+
 	log.Printf("flagger server listening at %q", lis.Addr().String())
 	if serveErr := grpcServer.Serve(lis); serveErr != nil {
 		log.Fatalf("could not serve: %v", serveErr)
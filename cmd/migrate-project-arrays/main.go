@@ -0,0 +1,59 @@
+// Command migrate-project-arrays migrates the projects that keep the IDs of
+// their environments, flags and flag settings in arrays. The project ID of the
+// documents in the arrays is backfilled before the arrays are dropped and the
+// views of the flags are then rebuilt, so that the flags of the backfilled
+// documents are served. It can be run again if it is interrupted.
+package main
+
+import (
+	"context"
+	"log"
+	"os"
+	"time"
+
+	"github.com/waduhek/flagger/internal/flagview"
+	"github.com/waduhek/flagger/internal/logger"
+	"github.com/waduhek/flagger/internal/project"
+	"github.com/waduhek/flagger/internal/startup"
+)
+
+func main() {
+	const migrationTimeout = 30 * time.Minute
+
+	loggerImpl := logger.CreateLogger()
+
+	mongoClient, mongoClientErr := startup.ConnectMongo(loggerImpl)
+	if mongoClientErr != nil {
+		log.Panicf("could not connect to mongodb: %v", mongoClientErr)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), migrationTimeout)
+	defer cancel()
+
+	defer func() {
+		if disconnectErr := mongoClient.Disconnect(context.Background()); disconnectErr != nil {
+			log.Printf("could not disconnect from mongodb: %v", disconnectErr)
+		}
+	}()
+
+	db := mongoClient.Database(os.Getenv("FLAGGER_DB"))
+
+	migratedCount, err := project.MigrateProjectArrays(ctx, db, loggerImpl)
+	if err != nil {
+		log.Panicf("could not migrate projects after %d projects: %v", migratedCount, err)
+	}
+
+	log.Printf("migrated %d projects", migratedCount)
+
+	flagViewRepo, err := flagview.NewFlagViewRepository(ctx, db, loggerImpl)
+	if err != nil {
+		log.Panicf("could not initialise flag view repository: %v", err)
+	}
+
+	rebuiltCount, err := flagViewRepo.Rebuild(ctx)
+	if err != nil {
+		log.Panicf("could not rebuild flag views after %d views: %v", rebuiltCount, err)
+	}
+
+	log.Printf("rebuilt %d flag views", rebuiltCount)
+}
@@ -0,0 +1,52 @@
+// Command rebuild-flag-views recomputes the views of all the flags that the
+// provider evaluates the flags from. It backfills the views of the flags that
+// were created before the views were introduced and repairs views that have
+// drifted from their sources. It is safe to run while the server is serving.
+package main
+
+import (
+	"context"
+	"log"
+	"os"
+	"time"
+
+	"github.com/waduhek/flagger/internal/flagview"
+	"github.com/waduhek/flagger/internal/logger"
+	"github.com/waduhek/flagger/internal/startup"
+)
+
+func main() {
+	const rebuildTimeout = 30 * time.Minute
+
+	loggerImpl := logger.CreateLogger()
+
+	mongoClient, mongoClientErr := startup.ConnectMongo(loggerImpl)
+	if mongoClientErr != nil {
+		log.Panicf("could not connect to mongodb: %v", mongoClientErr)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), rebuildTimeout)
+	defer cancel()
+
+	defer func() {
+		if disconnectErr := mongoClient.Disconnect(context.Background()); disconnectErr != nil {
+			log.Printf("could not disconnect from mongodb: %v", disconnectErr)
+		}
+	}()
+
+	flagViewRepo, err := flagview.NewFlagViewRepository(
+		ctx,
+		mongoClient.Database(os.Getenv("FLAGGER_DB")),
+		loggerImpl,
+	)
+	if err != nil {
+		log.Panicf("could not initialise flag view repository: %v", err)
+	}
+
+	rebuiltCount, err := flagViewRepo.Rebuild(ctx)
+	if err != nil {
+		log.Panicf("could not rebuild flag views after %d views: %v", rebuiltCount, err)
+	}
+
+	log.Printf("rebuilt %d flag views", rebuiltCount)
+}
@@ -0,0 +1,98 @@
+// Package alias keeps the previous names of renamed documents, so that the
+// documents can still be found by their previous names until the aliases
+// expire. The aliases of a document are stored in its "aliases" array next to
+// its "name".
+package alias
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+// Alias is a previous name of a document that it can be found by until the
+// alias expires.
+type Alias struct {
+	Name      string    `bson:"name"`
+	ExpiresAt time.Time `bson:"expires_at"`
+}
+
+// RenameUpdate creates the update pipeline that renames a document to the name
+// and changes its display name. The name and the display name are not changed
+// if they are empty. Unless the time of expiry is zero, the current name of the
+// document is kept as an alias until then if the name changes, and the expired
+// aliases and the alias of the new name are removed.
+func RenameUpdate(name string, displayName string, expiresAt time.Time) bson.A {
+	update := bson.A{}
+
+	// The names are literals so that they are not read as field paths.
+	literalName := bson.D{{Key: "$literal", Value: name}}
+
+	if name != "" && !expiresAt.IsZero() {
+		aliases := bson.D{{
+			Key: "$concatArrays",
+			Value: bson.A{
+				bson.D{{
+					Key: "$filter",
+					Value: bson.D{
+						{Key: "input", Value: bson.D{{Key: "$ifNull", Value: bson.A{"$aliases", bson.A{}}}}},
+						{Key: "as", Value: "alias"},
+						{Key: "cond", Value: bson.D{{Key: "$and", Value: bson.A{
+							bson.D{{Key: "$gt", Value: bson.A{"$$alias.expires_at", "$$NOW"}}},
+							bson.D{{Key: "$ne", Value: bson.A{"$$alias.name", literalName}}},
+						}}}},
+					},
+				}},
+				bson.D{{
+					Key: "$cond",
+					Value: bson.A{
+						bson.D{{Key: "$ne", Value: bson.A{"$name", literalName}}},
+						bson.A{bson.D{{Key: "name", Value: "$name"}, {Key: "expires_at", Value: expiresAt}}},
+						bson.A{},
+					},
+				}},
+			},
+		}}
+
+		update = append(update, bson.D{{Key: "$set", Value: bson.D{{Key: "aliases", Value: aliases}}}})
+	}
+
+	fields := bson.D{}
+
+	if name != "" {
+		fields = append(fields, bson.E{Key: "name", Value: literalName})
+	}
+
+	if displayName != "" {
+		fields = append(fields, bson.E{Key: "display_name", Value: bson.D{{Key: "$literal", Value: displayName}}})
+	}
+
+	if len(fields) > 0 {
+		update = append(update, bson.D{{Key: "$set", Value: fields}})
+	}
+
+	return update
+}
+
+// NameFilter creates the filter of the documents whose name is the name or
+// that have an alias of the name that has not expired at the time. The prefix
+// is the path of the embedded document holding the name and the aliases, such
+// as "flag.", and is empty for the top level document.
+func NameFilter(prefix string, name string, now time.Time) bson.D {
+	return bson.D{{
+		Key: "$or",
+		Value: bson.A{
+			bson.D{{Key: prefix + "name", Value: name}},
+			bson.D{{
+				Key: prefix + "aliases",
+				Value: bson.D{{
+					Key: "$elemMatch",
+					Value: bson.D{
+						{Key: "name", Value: name},
+						{Key: "expires_at", Value: bson.D{{Key: "$gt", Value: now}}},
+					},
+				}},
+			}},
+		},
+	}}
+}
@@ -0,0 +1,33 @@
+package changefeed
+
+import "context"
+
+// Change is a change to the flags of a project. A change without an environment
+// name applies to all the environments of the project and a change without a
+// flag name applies to all the flags of the environment.
+type Change struct {
+	Version         string
+	EnvironmentName string
+	FlagName        string
+}
+
+// Repository provides the interface for publishing and reading the ordered
+// feed of changes to the flags of a project. Every change is assigned a
+// version that increases with every change published to the feed.
+type Repository interface {
+	// Publish appends the change to the feed of the project and returns the
+	// version of the change.
+	Publish(ctx context.Context, projectKey string, change *Change) (string, error)
+
+	// LatestVersion gets the version of the latest change of the project.
+	// Returns an empty version if no changes have been published.
+	LatestVersion(ctx context.Context, projectKey string) (string, error)
+
+	// IsRetained checks whether all the changes of the project after the
+	// provided version are still available in the feed.
+	IsRetained(ctx context.Context, projectKey string, version string) (bool, error)
+
+	// ReadAfter reads the changes of the project published after the provided
+	// version without waiting for new changes.
+	ReadAfter(ctx context.Context, projectKey string, version string) ([]Change, error)
+}
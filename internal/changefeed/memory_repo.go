@@ -0,0 +1,111 @@
+package changefeed
+
+import (
+	"context"
+	"fmt"
+	"sync"
+)
+
+// MemoryRepository keeps the feeds of changes in memory. The changes are only
+// visible to the process that published them, so it can only be used by a
+// single replica.
+type MemoryRepository struct {
+	mu       sync.Mutex
+	sequence uint64
+	feeds    map[string][]Change
+}
+
+func (r *MemoryRepository) Publish(
+	_ context.Context,
+	projectKey string,
+	change *Change,
+) (string, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.sequence++
+
+	published := *change
+	published.Version = fmt.Sprintf("0-%d", r.sequence)
+
+	r.feeds[projectKey] = append(r.feeds[projectKey], published)
+
+	if feed := r.feeds[projectKey]; int64(len(feed)) > maxFeedLength {
+		r.feeds[projectKey] = feed[int64(len(feed))-maxFeedLength:]
+	}
+
+	return published.Version, nil
+}
+
+func (r *MemoryRepository) LatestVersion(
+	_ context.Context,
+	projectKey string,
+) (string, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	feed := r.feeds[projectKey]
+	if len(feed) == 0 {
+		return "", nil
+	}
+
+	return feed[len(feed)-1].Version, nil
+}
+
+func (r *MemoryRepository) IsRetained(
+	_ context.Context,
+	projectKey string,
+	version string,
+) (bool, error) {
+	requested, ok := parseVersion(version)
+	if !ok {
+		return false, nil
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	feed := r.feeds[projectKey]
+	if len(feed) == 0 {
+		return false, nil
+	}
+
+	oldestVersion, _ := parseVersion(feed[0].Version)
+	latestVersion, _ := parseVersion(feed[len(feed)-1].Version)
+
+	return requested.compare(oldestVersion) >= 0 &&
+		requested.compare(latestVersion) <= 0, nil
+}
+
+func (r *MemoryRepository) ReadAfter(
+	_ context.Context,
+	projectKey string,
+	version string,
+) ([]Change, error) {
+	after, ok := parseVersion(version)
+	if !ok {
+		after = streamID{}
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	changes := []Change{}
+	for _, change := range r.feeds[projectKey] {
+		changeVersion, _ := parseVersion(change.Version)
+		if changeVersion.compare(after) <= 0 {
+			continue
+		}
+
+		changes = append(changes, change)
+		if int64(len(changes)) == maxReadCount {
+			break
+		}
+	}
+
+	return changes, nil
+}
+
+func NewMemoryChangeFeedRepository() *MemoryRepository {
+	return &MemoryRepository{feeds: make(map[string][]Change)}
+}
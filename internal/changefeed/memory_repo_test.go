@@ -0,0 +1,54 @@
+package changefeed_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/waduhek/flagger/internal/changefeed"
+)
+
+func TestMemoryRepository_PublishAndReadAfter(t *testing.T) {
+	testPublishAndReadAfter(t, changefeed.NewMemoryChangeFeedRepository(), "changefeed-test-read")
+}
+
+func TestMemoryRepository_IsRetained(t *testing.T) {
+	testIsRetained(t, changefeed.NewMemoryChangeFeedRepository(), "changefeed-test-retained")
+}
+
+func TestMemoryRepository_TrimsFeed(t *testing.T) {
+	const projectKey = "changefeed-test-trim"
+
+	ctx := context.Background()
+	repo := changefeed.NewMemoryChangeFeedRepository()
+
+	firstVersion, err := repo.Publish(ctx, projectKey, &changefeed.Change{FlagName: "first"})
+	if err != nil {
+		t.Fatalf("error while publishing change: %v", err)
+	}
+
+	for i := range 1000 {
+		_, err = repo.Publish(ctx, projectKey, &changefeed.Change{FlagName: fmt.Sprintf("flag-%d", i)})
+		if err != nil {
+			t.Fatalf("error while publishing change: %v", err)
+		}
+	}
+
+	isRetained, err := repo.IsRetained(ctx, projectKey, firstVersion)
+	if err != nil {
+		t.Fatalf("error while checking retention: %v", err)
+	}
+
+	if isRetained {
+		t.Fatal("expected the first version to be trimmed from the feed")
+	}
+
+	changes, err := repo.ReadAfter(ctx, projectKey, "")
+	if err != nil {
+		t.Fatalf("error while reading changes: %v", err)
+	}
+
+	if len(changes) != 100 || changes[0].FlagName != "flag-0" {
+		t.Fatalf("expected the first 100 retained changes but got %d starting with %+v", len(changes), changes[0])
+	}
+}
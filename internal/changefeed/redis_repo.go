@@ -0,0 +1,188 @@
+package changefeed
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// maxFeedLength is the approximate number of changes that are retained in the
+// feed of a project. Clients that fall further behind need a new snapshot.
+const maxFeedLength int64 = 1000
+
+// maxReadCount is the maximum number of changes read from the feed at a time.
+const maxReadCount int64 = 100
+
+// Field names of the entries of the Redis stream.
+const (
+	environmentField = "environment"
+	flagField        = "flag"
+)
+
+type RedisRepository struct {
+	rdb redis.UniversalClient
+}
+
+func (r *RedisRepository) Publish(
+	ctx context.Context,
+	projectKey string,
+	change *Change,
+) (string, error) {
+	return r.rdb.XAdd(ctx, &redis.XAddArgs{
+		Stream: genFeedKey(projectKey),
+		MaxLen: maxFeedLength,
+		Approx: true,
+		Values: []string{
+			environmentField, change.EnvironmentName,
+			flagField, change.FlagName,
+		},
+	}).Result()
+}
+
+func (r *RedisRepository) LatestVersion(
+	ctx context.Context,
+	projectKey string,
+) (string, error) {
+	messages, err := r.rdb.XRevRangeN(ctx, genFeedKey(projectKey), "+", "-", 1).Result()
+	if err != nil {
+		return "", err
+	}
+
+	if len(messages) == 0 {
+		return "", nil
+	}
+
+	return messages[0].ID, nil
+}
+
+func (r *RedisRepository) IsRetained(
+	ctx context.Context,
+	projectKey string,
+	version string,
+) (bool, error) {
+	requested, ok := parseVersion(version)
+	if !ok {
+		return false, nil
+	}
+
+	feedKey := genFeedKey(projectKey)
+
+	oldest, err := r.rdb.XRangeN(ctx, feedKey, "-", "+", 1).Result()
+	if err != nil {
+		return false, err
+	}
+
+	latest, err := r.rdb.XRevRangeN(ctx, feedKey, "+", "-", 1).Result()
+	if err != nil {
+		return false, err
+	}
+
+	if len(oldest) == 0 || len(latest) == 0 {
+		return false, nil
+	}
+
+	oldestVersion, _ := parseVersion(oldest[0].ID)
+	latestVersion, _ := parseVersion(latest[0].ID)
+
+	// The requested version must have been seen in the feed for none of the
+	// changes after it to have been trimmed.
+	return requested.compare(oldestVersion) >= 0 &&
+		requested.compare(latestVersion) <= 0, nil
+}
+
+func (r *RedisRepository) ReadAfter(
+	ctx context.Context,
+	projectKey string,
+	version string,
+) ([]Change, error) {
+	if version == "" {
+		version = "0"
+	}
+
+	streams, err := r.rdb.XRead(ctx, &redis.XReadArgs{
+		Streams: []string{genFeedKey(projectKey), version},
+		Count:   maxReadCount,
+		Block:   -1,
+	}).Result()
+	if err != nil {
+		if errors.Is(err, redis.Nil) {
+			return []Change{}, nil
+		}
+
+		return nil, err
+	}
+
+	changes := []Change{}
+	for _, stream := range streams {
+		for _, message := range stream.Messages {
+			environmentName, _ := message.Values[environmentField].(string)
+			flagName, _ := message.Values[flagField].(string)
+
+			changes = append(changes, Change{
+				Version:         message.ID,
+				EnvironmentName: environmentName,
+				FlagName:        flagName,
+			})
+		}
+	}
+
+	return changes, nil
+}
+
+// streamID is a parsed ID of an entry of a Redis stream.
+type streamID struct {
+	Milliseconds uint64
+	Sequence     uint64
+}
+
+// parseVersion parses a version of the feed which is an ID of an entry of the
+// Redis stream.
+func parseVersion(version string) (streamID, bool) {
+	milliseconds, sequence, ok := strings.Cut(version, "-")
+	if !ok {
+		return streamID{}, false
+	}
+
+	parsedMilliseconds, err := strconv.ParseUint(milliseconds, 10, 64)
+	if err != nil {
+		return streamID{}, false
+	}
+
+	parsedSequence, err := strconv.ParseUint(sequence, 10, 64)
+	if err != nil {
+		return streamID{}, false
+	}
+
+	return streamID{Milliseconds: parsedMilliseconds, Sequence: parsedSequence}, true
+}
+
+// compare returns -1, 0 or +1 depending on whether the ID is lower, equal to or
+// higher than the other ID.
+func (id streamID) compare(other streamID) int {
+	switch {
+	case id.Milliseconds < other.Milliseconds:
+		return -1
+	case id.Milliseconds > other.Milliseconds:
+		return 1
+	case id.Sequence < other.Sequence:
+		return -1
+	case id.Sequence > other.Sequence:
+		return 1
+	default:
+		return 0
+	}
+}
+
+// genFeedKey generates the key of the Redis stream that stores the changes of
+// the project.
+func genFeedKey(projectKey string) string {
+	return fmt.Sprintf("changes:%v", projectKey)
+}
+
+func NewChangeFeedRepository(rdb redis.UniversalClient) *RedisRepository {
+	return &RedisRepository{rdb: rdb}
+}
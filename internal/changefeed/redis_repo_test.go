@@ -0,0 +1,135 @@
+package changefeed_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+
+	"github.com/waduhek/flagger/internal/changefeed"
+)
+
+func newTestRepository(t *testing.T, projectKey string) *changefeed.RedisRepository {
+	t.Helper()
+
+	rdb := redis.NewClient(&redis.Options{Addr: "localhost:6379"})
+
+	t.Cleanup(func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 1*time.Second)
+		defer cancel()
+
+		rdb.Del(ctx, "changes:"+projectKey)
+		rdb.Close()
+	})
+
+	return changefeed.NewChangeFeedRepository(rdb)
+}
+
+func TestPublishAndReadAfter(t *testing.T) {
+	const projectKey = "changefeed-test-read"
+
+	testPublishAndReadAfter(t, newTestRepository(t, projectKey), projectKey)
+}
+
+func TestIsRetained(t *testing.T) {
+	const projectKey = "changefeed-test-retained"
+
+	testIsRetained(t, newTestRepository(t, projectKey), projectKey)
+}
+
+// testPublishAndReadAfter tests that the changes published to the feed of the
+// project are read after the versions that preceded them.
+func testPublishAndReadAfter(t *testing.T, repo changefeed.Repository, projectKey string) {
+	t.Helper()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 1*time.Second)
+	defer cancel()
+
+	latestVersion, err := repo.LatestVersion(ctx, projectKey)
+	if err != nil {
+		t.Fatalf("error while getting latest version: %v", err)
+	}
+
+	if latestVersion != "" {
+		t.Fatalf("expected no version for an empty feed but got %q", latestVersion)
+	}
+
+	firstVersion, err := repo.Publish(
+		ctx,
+		projectKey,
+		&changefeed.Change{EnvironmentName: "production", FlagName: "first"},
+	)
+	if err != nil {
+		t.Fatalf("error while publishing change: %v", err)
+	}
+
+	secondVersion, err := repo.Publish(
+		ctx,
+		projectKey,
+		&changefeed.Change{EnvironmentName: "production", FlagName: "second"},
+	)
+	if err != nil {
+		t.Fatalf("error while publishing change: %v", err)
+	}
+
+	changes, err := repo.ReadAfter(ctx, projectKey, firstVersion)
+	if err != nil {
+		t.Fatalf("error while reading changes: %v", err)
+	}
+
+	if len(changes) != 1 {
+		t.Fatalf("expected 1 change but got %d", len(changes))
+	}
+
+	if changes[0].FlagName != "second" || changes[0].Version != secondVersion {
+		t.Fatalf("expected the second change but got %+v", changes[0])
+	}
+
+	changes, err = repo.ReadAfter(ctx, projectKey, secondVersion)
+	if err != nil {
+		t.Fatalf("error while reading changes: %v", err)
+	}
+
+	if len(changes) != 0 {
+		t.Fatalf("expected no changes but got %d", len(changes))
+	}
+}
+
+// testIsRetained tests that only the published versions of the feed of the
+// project are retained.
+func testIsRetained(t *testing.T, repo changefeed.Repository, projectKey string) {
+	t.Helper()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 1*time.Second)
+	defer cancel()
+
+	version, err := repo.Publish(
+		ctx,
+		projectKey,
+		&changefeed.Change{EnvironmentName: "production"},
+	)
+	if err != nil {
+		t.Fatalf("error while publishing change: %v", err)
+	}
+
+	isRetained, err := repo.IsRetained(ctx, projectKey, version)
+	if err != nil {
+		t.Fatalf("error while checking retention: %v", err)
+	}
+
+	if !isRetained {
+		t.Fatal("expected the published version to be retained")
+	}
+
+	for _, staleVersion := range []string{"", "0-0", "not-a-version"} {
+		isRetained, err = repo.IsRetained(ctx, projectKey, staleVersion)
+		if err != nil {
+			t.Fatalf("error while checking retention: %v", err)
+		}
+
+		if isRetained {
+			t.Fatalf("expected version %q to not be retained", staleVersion)
+		}
+	}
+}
@@ -3,14 +3,23 @@ package environment
 import (
 	"context"
 	"time"
+
+	"github.com/waduhek/flagger/internal/alias"
+	"github.com/waduhek/flagger/internal/pagination"
 )
 
+// Environment is an environment of a project that its flags are served in.
+// The clients request the flags of the environment by its name, while the
+// display name is only shown to the users. The flags of a renamed environment
+// are still served by its aliases until they expire.
 type Environment struct {
-	ID        string
-	Name      string
-	ProjectID string
-	CreatedBy string
-	CreatedAt time.Time
+	ID          string
+	Name        string
+	DisplayName string
+	Aliases     []alias.Alias
+	ProjectID   string
+	CreatedBy   string
+	CreatedAt   time.Time
 }
 
 type DataRepository interface {
@@ -29,4 +38,64 @@ type DataRepository interface {
 		environmentName string,
 		projectID string,
 	) (*Environment, error)
+
+	// GetIDsByProjectID gets the IDs of all the environments of the project.
+	GetIDsByProjectID(ctx context.Context, projectID string) ([]string, error)
+
+	// GetAllByProjectID gets all the environments of the project sorted by
+	// their name.
+	GetAllByProjectID(ctx context.Context, projectID string) ([]Environment, error)
+
+	// ListByProjectID gets a page of the environments of the project along
+	// with the token of the next page, which is empty if this is the last
+	// page.
+	ListByProjectID(
+		ctx context.Context,
+		projectID string,
+		query *pagination.Query,
+	) ([]Environment, string, error)
+
+	// Rename changes the name and the display name of the environment, which
+	// are not changed if they are empty. Unless the time of expiry is zero,
+	// the current name of the environment is kept as an alias until then. The
+	// number of updated environments is returned.
+	Rename(
+		ctx context.Context,
+		environmentID string,
+		name string,
+		displayName string,
+		aliasExpiresAt time.Time,
+	) (uint, error)
+
+	// Delete deletes the environment. The number of deleted environments is
+	// returned.
+	Delete(ctx context.Context, environmentID string) (uint, error)
+
+	// DeleteByProjectID deletes all the environments of the project. The
+	// number of deleted environments is returned.
+	DeleteByProjectID(ctx context.Context, projectID string) (uint, error)
+}
+
+// FlagRepository gets the flags of a project that are served in its
+// environments. It is implemented by the flag repository, whose package
+// depends on this package.
+type FlagRepository interface {
+	// GetIDsByProjectID gets the IDs of all the flags of the project.
+	GetIDsByProjectID(ctx context.Context, projectID string) ([]string, error)
+}
+
+// UsageRepository gets the last time that the flags of an environment were
+// evaluated. It is implemented by the usage repositories of the provider,
+// whose package depends on this package.
+type UsageRepository interface {
+	// GetLastUsed gets the time that the flags of the project were last
+	// evaluated in the environment and reports whether they were evaluated
+	// within the recent evaluation window of the environments. Unlike the
+	// recently used environments, the last usages within the window are
+	// never dropped.
+	GetLastUsed(
+		ctx context.Context,
+		projectKey string,
+		environmentName string,
+	) (time.Time, bool, error)
 }
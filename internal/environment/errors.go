@@ -43,3 +43,32 @@ var ErrEnvironmentIDCast = status.Error(
 	codes.Internal,
 	"could not create a new environment",
 )
+
+// ErrCouldNotUpdate is a GRPC error that is returned when an unknown error
+// occurs while updating an environment.
+var ErrCouldNotUpdate = status.Error(
+	codes.Internal,
+	"error occurred while updating environment",
+)
+
+// ErrNothingToRename is a GRPC error that is returned when renaming an
+// environment without a new name or display name.
+var ErrNothingToRename = status.Error(
+	codes.InvalidArgument,
+	"a new name or display name is required",
+)
+
+// ErrCouldNotDelete is a GRPC error that is returned when an unknown error
+// occurs while deleting an environment.
+var ErrCouldNotDelete = status.Error(
+	codes.Internal,
+	"error occurred while deleting environment",
+)
+
+// ErrRecentlyEvaluated is a GRPC error that is returned when attempting to
+// delete an environment whose flags were evaluated recently without confirming
+// the deletion.
+var ErrRecentlyEvaluated = status.Error(
+	codes.FailedPrecondition,
+	"the flags of the environment were evaluated recently, confirm to delete it",
+)
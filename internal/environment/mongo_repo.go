@@ -5,7 +5,9 @@ import (
 	"errors"
 	"time"
 
+	"github.com/waduhek/flagger/internal/alias"
 	"github.com/waduhek/flagger/internal/logger"
+	"github.com/waduhek/flagger/internal/pagination"
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 	"go.mongodb.org/mongo-driver/mongo"
@@ -15,11 +17,13 @@ import (
 // environmentMongoModel is the MongoDB representation of the `Environment`
 // struct.
 type environmentMongoModel struct {
-	ID        primitive.ObjectID `bson:"_id,omitempty"`
-	Name      string             `bson:"name"`
-	ProjectID primitive.ObjectID `bson:"project_id"`
-	CreatedBy primitive.ObjectID `bson:"created_by"`
-	CreatedAt time.Time          `bson:"created_at"`
+	ID          primitive.ObjectID `bson:"_id,omitempty"`
+	Name        string             `bson:"name"`
+	DisplayName string             `bson:"display_name,omitempty"`
+	Aliases     []alias.Alias      `bson:"aliases,omitempty"`
+	ProjectID   primitive.ObjectID `bson:"project_id"`
+	CreatedBy   primitive.ObjectID `bson:"created_by"`
+	CreatedAt   time.Time          `bson:"created_at"`
 }
 
 const environmentCollection string = "environments"
@@ -46,10 +50,11 @@ func (r *MongoDataRepository) Save(
 	}
 
 	environmentToAdd := &environmentMongoModel{
-		Name:      environment.Name,
-		ProjectID: projectIDObjID,
-		CreatedBy: createdByObjID,
-		CreatedAt: time.Now(),
+		Name:        environment.Name,
+		DisplayName: environment.DisplayName,
+		ProjectID:   projectIDObjID,
+		CreatedBy:   createdByObjID,
+		CreatedAt:   time.Now(),
 	}
 
 	saveResult, saveErr := r.coll.InsertOne(ctx, environmentToAdd)
@@ -137,14 +142,211 @@ func (r *MongoDataRepository) GetByNameAndProjectID(
 	return mapMongoModelToStruct(&decodedEnvironment), nil
 }
 
+func (r *MongoDataRepository) GetIDsByProjectID(
+	ctx context.Context,
+	projectID string,
+) ([]string, error) {
+	projectIDObjID, projectIDErr := primitive.ObjectIDFromHex(projectID)
+	if projectIDErr != nil {
+		r.logger.Error("could not convert project id to object id: %v", projectIDErr)
+		return nil, ErrCouldNotFetch
+	}
+
+	cursor, err := r.coll.Find(
+		ctx,
+		bson.D{{Key: "project_id", Value: projectIDObjID}},
+		options.Find().SetProjection(bson.D{{Key: "_id", Value: 1}}),
+	)
+	if err != nil {
+		r.logger.Error("error while getting environments of project %q: %v", projectID, err)
+		return nil, ErrCouldNotFetch
+	}
+
+	var decodedEnvironments []environmentMongoModel
+	if err = cursor.All(ctx, &decodedEnvironments); err != nil {
+		r.logger.Error("error while decoding environments of project %q: %v", projectID, err)
+		return nil, ErrCouldNotFetch
+	}
+
+	environmentIDs := make([]string, 0, len(decodedEnvironments))
+	for i := range decodedEnvironments {
+		environmentIDs = append(environmentIDs, decodedEnvironments[i].ID.Hex())
+	}
+
+	return environmentIDs, nil
+}
+
+func (r *MongoDataRepository) GetAllByProjectID(
+	ctx context.Context,
+	projectID string,
+) ([]Environment, error) {
+	projectIDObjID, projectIDErr := primitive.ObjectIDFromHex(projectID)
+	if projectIDErr != nil {
+		r.logger.Error("could not convert project id to object id: %v", projectIDErr)
+		return nil, ErrCouldNotFetch
+	}
+
+	cursor, err := r.coll.Find(
+		ctx,
+		bson.D{{Key: "project_id", Value: projectIDObjID}},
+		options.Find().SetSort(bson.D{{Key: "name", Value: 1}}),
+	)
+	if err != nil {
+		r.logger.Error("error while getting environments of project %q: %v", projectID, err)
+		return nil, ErrCouldNotFetch
+	}
+
+	var decodedEnvironments []environmentMongoModel
+	if err = cursor.All(ctx, &decodedEnvironments); err != nil {
+		r.logger.Error("error while decoding environments of project %q: %v", projectID, err)
+		return nil, ErrCouldNotFetch
+	}
+
+	environments := make([]Environment, 0, len(decodedEnvironments))
+	for i := range decodedEnvironments {
+		environments = append(environments, *mapMongoModelToStruct(&decodedEnvironments[i]))
+	}
+
+	return environments, nil
+}
+
+func (r *MongoDataRepository) ListByProjectID(
+	ctx context.Context,
+	projectID string,
+	query *pagination.Query,
+) ([]Environment, string, error) {
+	projectIDObjID, projectIDErr := primitive.ObjectIDFromHex(projectID)
+	if projectIDErr != nil {
+		r.logger.Error("could not convert project id to object id: %v", projectIDErr)
+		return nil, "", ErrCouldNotFetch
+	}
+
+	cursor, err := r.coll.Find(
+		ctx,
+		query.Filter(bson.D{{Key: "project_id", Value: projectIDObjID}}),
+		query.FindOptions(),
+	)
+	if err != nil {
+		r.logger.Error("error while listing environments of project %q: %v", projectID, err)
+		return nil, "", ErrCouldNotFetch
+	}
+
+	var decodedEnvironments []environmentMongoModel
+	if err = cursor.All(ctx, &decodedEnvironments); err != nil {
+		r.logger.Error("error while decoding environments of project %q: %v", projectID, err)
+		return nil, "", ErrCouldNotFetch
+	}
+
+	decodedEnvironments, nextPageToken := pagination.Page(
+		query,
+		decodedEnvironments,
+		func(decodedEnvironment *environmentMongoModel) pagination.Position {
+			return pagination.Position{
+				ID:        decodedEnvironment.ID,
+				Name:      decodedEnvironment.Name,
+				CreatedAt: decodedEnvironment.CreatedAt,
+			}
+		},
+	)
+
+	environments := make([]Environment, 0, len(decodedEnvironments))
+	for i := range decodedEnvironments {
+		environments = append(environments, *mapMongoModelToStruct(&decodedEnvironments[i]))
+	}
+
+	return environments, nextPageToken, nil
+}
+
+func (r *MongoDataRepository) Rename(
+	ctx context.Context,
+	environmentID string,
+	name string,
+	displayName string,
+	aliasExpiresAt time.Time,
+) (uint, error) {
+	environmentIDObjID, environmentIDErr := primitive.ObjectIDFromHex(environmentID)
+	if environmentIDErr != nil {
+		r.logger.Error("could not convert environment id to object id: %v", environmentIDErr)
+		return 0, ErrCouldNotUpdate
+	}
+
+	updateResult, err := r.coll.UpdateByID(
+		ctx,
+		environmentIDObjID,
+		alias.RenameUpdate(name, displayName, aliasExpiresAt),
+	)
+	if err != nil {
+		if mongo.IsDuplicateKeyError(err) {
+			r.logger.Error("an environment with name %q exists", name)
+			return 0, ErrNameTaken
+		}
+
+		r.logger.Error("could not rename environment %q: %v", environmentID, err)
+		return 0, ErrCouldNotUpdate
+	}
+
+	//nolint:gosec // ModifiedCount can't be a negative number.
+	return uint(updateResult.ModifiedCount), nil
+}
+
+func (r *MongoDataRepository) Delete(
+	ctx context.Context,
+	environmentID string,
+) (uint, error) {
+	environmentIDObjID, environmentIDErr := primitive.ObjectIDFromHex(environmentID)
+	if environmentIDErr != nil {
+		r.logger.Error("could not convert environment id to object id: %v", environmentIDErr)
+		return 0, ErrCouldNotDelete
+	}
+
+	deleteResult, err := r.coll.DeleteOne(ctx, bson.D{{Key: "_id", Value: environmentIDObjID}})
+	if err != nil {
+		r.logger.Error("could not delete environment %q: %v", environmentID, err)
+		return 0, ErrCouldNotDelete
+	}
+
+	//nolint:gosec // DeletedCount can't be a negative number.
+	return uint(deleteResult.DeletedCount), nil
+}
+
+func (r *MongoDataRepository) DeleteByProjectID(
+	ctx context.Context,
+	projectID string,
+) (uint, error) {
+	projectIDObjID, projectIDErr := primitive.ObjectIDFromHex(projectID)
+	if projectIDErr != nil {
+		r.logger.Error("could not convert project id to object id: %v", projectIDErr)
+		return 0, ErrCouldNotDelete
+	}
+
+	deleteResult, err := r.coll.DeleteMany(ctx, bson.D{{Key: "project_id", Value: projectIDObjID}})
+	if err != nil {
+		r.logger.Error("could not delete environments of project %q: %v", projectID, err)
+		return 0, ErrCouldNotDelete
+	}
+
+	//nolint:gosec // DeletedCount can't be a negative number.
+	return uint(deleteResult.DeletedCount), nil
+}
+
 func mapMongoModelToStruct(decodedEnvironment *environmentMongoModel) *Environment {
-	return &Environment{
-		ID:        decodedEnvironment.ID.Hex(),
-		Name:      decodedEnvironment.Name,
-		ProjectID: decodedEnvironment.ID.Hex(),
-		CreatedBy: decodedEnvironment.CreatedBy.Hex(),
-		CreatedAt: decodedEnvironment.CreatedAt,
+	environment := &Environment{
+		ID:          decodedEnvironment.ID.Hex(),
+		Name:        decodedEnvironment.Name,
+		DisplayName: decodedEnvironment.DisplayName,
+		Aliases:     decodedEnvironment.Aliases,
+		ProjectID:   decodedEnvironment.ProjectID.Hex(),
+		CreatedBy:   decodedEnvironment.CreatedBy.Hex(),
+		CreatedAt:   decodedEnvironment.CreatedAt,
+	}
+
+	// Environments created before display names were introduced are
+	// displayed by their name.
+	if environment.DisplayName == "" {
+		environment.DisplayName = environment.Name
 	}
+
+	return environment
 }
 
 func setupCollIndexes(
@@ -159,7 +361,35 @@ func setupCollIndexes(
 		Options: options.Index().SetUnique(true),
 	}
 
-	_, err := coll.Indexes().CreateOne(ctx, environmentProjectIndexModel)
+	// The environments of a project are looked up by the ID of the project.
+	projectIndexModel := mongo.IndexModel{
+		Keys: bson.D{{Key: "project_id", Value: 1}},
+	}
+
+	// The environments of a project are listed by their name or by the time
+	// that they were created at.
+	projectNameIndexModel := mongo.IndexModel{
+		Keys: bson.D{
+			{Key: "project_id", Value: 1},
+			{Key: "name", Value: 1},
+		},
+	}
+	projectCreatedAtIndexModel := mongo.IndexModel{
+		Keys: bson.D{
+			{Key: "project_id", Value: 1},
+			{Key: "created_at", Value: 1},
+		},
+	}
+
+	_, err := coll.Indexes().CreateMany(
+		ctx,
+		[]mongo.IndexModel{
+			environmentProjectIndexModel,
+			projectIndexModel,
+			projectNameIndexModel,
+			projectCreatedAtIndexModel,
+		},
+	)
 
 	return err
 }
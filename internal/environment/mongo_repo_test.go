@@ -14,6 +14,7 @@ import (
 
 	"github.com/waduhek/flagger/internal/environment"
 	"github.com/waduhek/flagger/internal/logger"
+	"github.com/waduhek/flagger/internal/pagination"
 )
 
 const mongoDBConnectionString string = "mongodb://localhost:27017"
@@ -198,6 +199,202 @@ func TestGetByNameAndProjectID_NoEnvironment(t *testing.T) {
 	}
 }
 
+func TestListByProjectID(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 1*time.Second)
+	defer cancel()
+
+	for _, name := range []string{"list-a", "list-b", "list-c"} {
+		e := &environment.Environment{
+			Name:      name,
+			ProjectID: dummyObjectID,
+			CreatedBy: dummyObjectID,
+			CreatedAt: time.Now(),
+		}
+
+		if _, saveErr := environmentRepository.Save(ctx, e); saveErr != nil {
+			t.Fatalf("unexpected error when saving environment: %v", saveErr)
+		}
+
+		cleanupEnvironment(t, e)
+	}
+
+	query, queryErr := pagination.NewQuery(2, "", pagination.SortByName, false, "list-")
+	if queryErr != nil {
+		t.Fatalf("unexpected error when creating query: %v", queryErr)
+	}
+
+	firstPage, nextPageToken, listErr := environmentRepository.ListByProjectID(ctx, dummyObjectID, query)
+	if listErr != nil {
+		t.Fatalf("error while listing environments: %v", listErr)
+	}
+
+	if len(firstPage) != 2 || firstPage[0].Name != "list-a" || firstPage[1].Name != "list-b" {
+		t.Fatalf("unexpected first page: %v", firstPage)
+	}
+
+	if nextPageToken == "" {
+		t.Fatalf("expected a next page token")
+	}
+
+	query, queryErr = pagination.NewQuery(2, nextPageToken, pagination.SortByName, false, "list-")
+	if queryErr != nil {
+		t.Fatalf("unexpected error when creating query: %v", queryErr)
+	}
+
+	secondPage, nextPageToken, listErr := environmentRepository.ListByProjectID(ctx, dummyObjectID, query)
+	if listErr != nil {
+		t.Fatalf("error while listing environments: %v", listErr)
+	}
+
+	if len(secondPage) != 1 || secondPage[0].Name != "list-c" {
+		t.Fatalf("unexpected second page: %v", secondPage)
+	}
+
+	if nextPageToken != "" {
+		t.Fatalf("expected no next page token on the last page")
+	}
+}
+
+func TestListByProjectID_InvalidProjectID(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 1*time.Second)
+	defer cancel()
+
+	query, queryErr := pagination.NewQuery(0, "", pagination.SortByName, false, "")
+	if queryErr != nil {
+		t.Fatalf("unexpected error when creating query: %v", queryErr)
+	}
+
+	_, _, listErr := environmentRepository.ListByProjectID(ctx, "invalid_object_id", query)
+	if !errors.Is(listErr, environment.ErrCouldNotFetch) {
+		t.Fatalf("expected error while listing environments")
+	}
+}
+
+func TestGetAllByProjectID(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 1*time.Second)
+	defer cancel()
+
+	savedID, saveErr := environmentRepository.Save(ctx, dummyEnvironmentStruct)
+	if saveErr != nil {
+		t.Fatalf("unexpected error when saving environment: %v", saveErr)
+	}
+
+	cleanupEnvironment(t, dummyEnvironmentStruct)
+
+	environments, getErr := environmentRepository.GetAllByProjectID(ctx, dummyEnvironmentStruct.ProjectID)
+	if getErr != nil {
+		t.Fatalf("error while getting environments: %v", getErr)
+	}
+
+	if len(environments) != 1 || environments[0].ID != savedID {
+		t.Fatalf("unexpected environments: %v", environments)
+	}
+}
+
+func TestRename(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 1*time.Second)
+	defer cancel()
+
+	savedID, saveErr := environmentRepository.Save(ctx, dummyEnvironmentStruct)
+	if saveErr != nil {
+		t.Fatalf("error while saving environment: %v", saveErr)
+	}
+
+	renamedEnvironment := &environment.Environment{Name: "test-renamed"}
+
+	cleanupEnvironment(t, dummyEnvironmentStruct)
+	cleanupEnvironment(t, renamedEnvironment)
+
+	renamedCount, renameErr := environmentRepository.Rename(
+		ctx,
+		savedID,
+		renamedEnvironment.Name,
+		"Test",
+		time.Now().Add(time.Hour),
+	)
+	if renameErr != nil || renamedCount != 1 {
+		t.Fatalf("could not rename environment: %v", renameErr)
+	}
+
+	fetchedEnvironment, getErr := environmentRepository.GetByID(ctx, savedID)
+	if getErr != nil {
+		t.Fatalf("error while getting environment: %v", getErr)
+	}
+
+	if fetchedEnvironment.Name != renamedEnvironment.Name || fetchedEnvironment.DisplayName != "Test" {
+		t.Fatalf("expected the environment to be renamed but got %v", fetchedEnvironment)
+	}
+
+	if len(fetchedEnvironment.Aliases) != 1 ||
+		fetchedEnvironment.Aliases[0].Name != dummyEnvironmentStruct.Name {
+		t.Fatalf("expected the previous name to be an alias but got %v", fetchedEnvironment.Aliases)
+	}
+}
+
+func TestRename_InvalidID(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 1*time.Second)
+	defer cancel()
+
+	_, renameErr := environmentRepository.Rename(ctx, "invalid_object_id", "test", "", time.Time{})
+	if !errors.Is(renameErr, environment.ErrCouldNotUpdate) {
+		t.Fatalf("expected error while renaming environment")
+	}
+}
+
+func TestDelete(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 1*time.Second)
+	defer cancel()
+
+	savedID, saveErr := environmentRepository.Save(ctx, dummyEnvironmentStruct)
+	if saveErr != nil {
+		t.Fatalf("unexpected error when saving environment: %v", saveErr)
+	}
+
+	cleanupEnvironment(t, dummyEnvironmentStruct)
+
+	deletedCount, deleteErr := environmentRepository.Delete(ctx, savedID)
+	if deleteErr != nil || deletedCount != 1 {
+		t.Fatalf("could not delete environment: %v", deleteErr)
+	}
+
+	_, getErr := environmentRepository.GetByID(ctx, savedID)
+	if !errors.Is(getErr, environment.ErrNotFound) {
+		t.Fatalf("expected the deleted environment not to be found")
+	}
+}
+
+func TestDeleteByProjectID(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 1*time.Second)
+	defer cancel()
+
+	savedID, saveErr := environmentRepository.Save(ctx, dummyEnvironmentStruct)
+	if saveErr != nil {
+		t.Fatalf("unexpected error when saving environment: %v", saveErr)
+	}
+
+	cleanupEnvironment(t, dummyEnvironmentStruct)
+
+	deletedCount, deleteErr := environmentRepository.DeleteByProjectID(ctx, dummyEnvironmentStruct.ProjectID)
+	if deleteErr != nil || deletedCount != 1 {
+		t.Fatalf("could not delete environments of project: %v", deleteErr)
+	}
+
+	_, getErr := environmentRepository.GetByID(ctx, savedID)
+	if !errors.Is(getErr, environment.ErrNotFound) {
+		t.Fatalf("expected the deleted environment not to be found")
+	}
+}
+
+func TestDelete_InvalidID(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 1*time.Second)
+	defer cancel()
+
+	_, deleteErr := environmentRepository.Delete(ctx, "invalid_object_id")
+	if !errors.Is(deleteErr, environment.ErrCouldNotDelete) {
+		t.Fatalf("expected error while deleting environment")
+	}
+}
+
 func TestMain(m *testing.M) {
 	client, mongoClientErr := getMongoClient()
 	if mongoClientErr != nil {
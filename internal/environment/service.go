@@ -5,13 +5,18 @@ import (
 	"time"
 
 	"go.mongodb.org/mongo-driver/mongo"
+	"google.golang.org/protobuf/types/known/timestamppb"
 
 	"github.com/waduhek/flagger/proto/environmentpb"
 
 	"github.com/waduhek/flagger/internal/auth"
+	"github.com/waduhek/flagger/internal/changefeed"
 	"github.com/waduhek/flagger/internal/flagsetting"
+	"github.com/waduhek/flagger/internal/flagview"
 	"github.com/waduhek/flagger/internal/logger"
+	"github.com/waduhek/flagger/internal/pagination"
 	"github.com/waduhek/flagger/internal/project"
+	"github.com/waduhek/flagger/internal/rulesetcache"
 	"github.com/waduhek/flagger/internal/user"
 )
 
@@ -20,11 +25,25 @@ type Server struct {
 	mongoClient         *mongo.Client
 	userDataRepo        user.DataRepository
 	projectDataRepo     project.DataRepository
+	flagDataRepo        FlagRepository
 	flagSettingDataRepo flagsetting.DataRepository
+	flagViewDataRepo    flagview.DataRepository
 	environmentDataRepo DataRepository
-	logger              logger.Logger
+	changeFeedRepo      changefeed.Repository
+	cacheInvalidator    rulesetcache.Invalidator
+	cacheWarmer         rulesetcache.Warmer
+	usageRepo           UsageRepository
+	// aliasPeriod is how long the previous name of a renamed environment
+	// still resolves for the SDKs.
+	aliasPeriod time.Duration
+	logger      logger.Logger
 }
 
+// RecentEvaluationWindow is how long after the last evaluation of its flags an
+// environment is considered to be in use. Deleting an environment in use has
+// to be confirmed.
+const RecentEvaluationWindow = 24 * time.Hour
+
 func (s *Server) CreateEnvironment(
 	ctx context.Context,
 	req *environmentpb.CreateEnvironmentRequest,
@@ -81,6 +100,18 @@ func (s *Server) CreateEnvironment(
 		environmentName,
 		projectName,
 	)
+
+	change := &changefeed.Change{EnvironmentName: environmentName}
+
+	// Remove any rule sets cached for the environment before it was created
+	// and publish the new environment so that the clients watching it receive
+	// the flags that are served in it.
+	s.publishChange(ctx, fetchedProject.Key, change)
+
+	// Preload the flags of the new environment ahead of their first
+	// evaluation.
+	s.cacheWarmer.Warm(fetchedProject.Key, change)
+
 	return &environmentpb.CreateEnvironmentResponse{}, nil
 }
 
@@ -96,10 +127,11 @@ func (s *Server) handleCreateEnvrionment(
 
 		// Create a new environment.
 		newEnvironment := Environment{
-			Name:      environmentName,
-			ProjectID: fetchedProject.ID,
-			CreatedBy: user.ID,
-			CreatedAt: time.Now(),
+			Name:        environmentName,
+			DisplayName: req.GetDisplayName(),
+			ProjectID:   fetchedProject.ID,
+			CreatedBy:   user.ID,
+			CreatedAt:   time.Now(),
 		}
 
 		environmentID, envSaveErr := s.environmentDataRepo.Save(ctx, &newEnvironment)
@@ -107,10 +139,23 @@ func (s *Server) handleCreateEnvrionment(
 			return nil, envSaveErr
 		}
 
+		// Mark the project updated so that a flag that is concurrently added
+		// to the project either gets a flag setting in this environment or
+		// is seen by this transaction when it is retried.
+		_, markErr := s.projectDataRepo.MarkUpdated(ctx, fetchedProject.ID)
+		if markErr != nil {
+			return nil, markErr
+		}
+
+		flagIDs, flagIDsErr := s.flagDataRepo.GetIDsByProjectID(ctx, fetchedProject.ID)
+		if flagIDsErr != nil {
+			return nil, flagIDsErr
+		}
+
 		// Create new flag settings for all the flags that are present in the
 		// current project.
 		var flagSettings []flagsetting.FlagSetting
-		for _, flagID := range fetchedProject.Flags {
+		for _, flagID := range flagIDs {
 			flagSetting := flagsetting.FlagSetting{
 				FlagID:        flagID,
 				ProjectID:     fetchedProject.ID,
@@ -125,34 +170,408 @@ func (s *Server) handleCreateEnvrionment(
 
 		// Save the flag settings to the collection.
 		if len(flagSettings) > 0 {
-			insertedFlagSettingIDs, flagSettingSaveErr := s.flagSettingDataRepo.SaveMany(
-				ctx,
-				flagSettings,
-			)
+			_, flagSettingSaveErr := s.flagSettingDataRepo.SaveMany(ctx, flagSettings)
 			if flagSettingSaveErr != nil {
 				s.logger.Error("error while saving flag settings: %v", flagSettingSaveErr)
 				return nil, flagSettingSaveErr
 			}
+		}
 
-			// Update the project with the new flag settings.
-			_, projectFlagSettingErr := s.projectDataRepo.AddFlagSettings(
-				ctx,
-				fetchedProject.ID,
-				insertedFlagSettingIDs...,
-			)
-			if projectFlagSettingErr != nil {
-				return nil, projectFlagSettingErr
-			}
+		// Create the views of all the flags in the environment.
+		_, refreshErr := s.flagViewDataRepo.Refresh(ctx, fetchedProject.ID, environmentID, "")
+		if refreshErr != nil {
+			return nil, refreshErr
+		}
+
+		return nil, nil
+	}
+}
+
+func (s *Server) ListEnvironments(
+	ctx context.Context,
+	req *environmentpb.ListEnvironmentsRequest,
+) (*environmentpb.ListEnvironmentsResponse, error) {
+	jwtClaims, ok := auth.ClaimsFromContext(ctx)
+	if !ok {
+		s.logger.Error("could not find jwt claims in request context")
+		return nil, auth.ErrNoTokenClaims
+	}
+
+	query, err := pagination.NewQuery(
+		req.GetPageSize(),
+		req.GetPageToken(),
+		pagination.SortField(req.GetSortBy()),
+		req.GetDescending(),
+		req.GetNameFilter(),
+	)
+	if err != nil {
+		s.logger.Error("invalid page of environments requested: %v", err)
+		return nil, err
+	}
+
+	fetchedUser, err := s.userDataRepo.GetByUsername(ctx, jwtClaims.Subject)
+	if err != nil {
+		return nil, err
+	}
+
+	// Only the environments of the projects of the user can be listed.
+	fetchedProject, err := s.projectDataRepo.GetByNameAndUserID(
+		ctx,
+		req.GetProjectName(),
+		fetchedUser.ID,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	environments, nextPageToken, err := s.environmentDataRepo.ListByProjectID(
+		ctx,
+		fetchedProject.ID,
+		query,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	response := &environmentpb.ListEnvironmentsResponse{
+		Environments:  make([]*environmentpb.EnvironmentDetails, 0, len(environments)),
+		NextPageToken: nextPageToken,
+	}
+
+	for _, environment := range environments {
+		response.Environments = append(response.Environments, &environmentpb.EnvironmentDetails{
+			Name:        environment.Name,
+			DisplayName: environment.DisplayName,
+			CreatedAt:   timestamppb.New(environment.CreatedAt),
+		})
+	}
+
+	return response, nil
+}
+
+func (s *Server) RenameEnvironment(
+	ctx context.Context,
+	req *environmentpb.RenameEnvironmentRequest,
+) (*environmentpb.RenameEnvironmentResponse, error) {
+	jwtClaims, ok := auth.ClaimsFromContext(ctx)
+	if !ok {
+		s.logger.Error("could not find jwt claims in request context")
+		return nil, auth.ErrNoTokenClaims
+	}
+
+	fetchedUser, err := s.userDataRepo.GetByUsername(ctx, jwtClaims.Subject)
+	if err != nil {
+		return nil, err
+	}
+
+	projectName := req.GetProjectName()
+	environmentName := req.GetEnvironmentName()
+	newEnvironmentName := req.GetNewEnvironmentName()
+	displayName := req.GetDisplayName()
+
+	fetchedProject, err := s.projectDataRepo.GetByNameAndUserID(ctx, projectName, fetchedUser.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	fetchedEnvironment, err := s.environmentDataRepo.GetByNameAndProjectID(
+		ctx,
+		environmentName,
+		fetchedProject.ID,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	isRenamed := newEnvironmentName != "" && newEnvironmentName != fetchedEnvironment.Name
+	if !isRenamed && (displayName == "" || displayName == fetchedEnvironment.DisplayName) {
+		s.logger.Error("no new name or display name was provided for environment %q", environmentName)
+		return nil, ErrNothingToRename
+	}
+
+	// The SDKs can still get the flags of the environment by its previous
+	// name until the alias expires, which gives them time to move to the new
+	// name.
+	var aliasExpiresAt time.Time
+	if isRenamed {
+		aliasExpiresAt = time.Now().Add(s.aliasPeriod)
+	}
+
+	session, err := s.mongoClient.StartSession()
+	if err != nil {
+		s.logger.Error("could not create a new session: %v", err)
+		return nil, ErrTxnSession
+	}
+	defer session.EndSession(ctx)
+
+	_, txnErr := session.WithTransaction(
+		ctx,
+		s.handleRenameEnvironment(
+			fetchedProject,
+			fetchedEnvironment,
+			newEnvironmentName,
+			displayName,
+			aliasExpiresAt,
+		),
+	)
+	if txnErr != nil {
+		s.logger.Error("error while performing environment rename transaction: %v", txnErr)
+		return nil, txnErr
+	}
+
+	response := &environmentpb.RenameEnvironmentResponse{}
+
+	if isRenamed {
+		s.logger.Info(
+			"successfully renamed environment %q to %q in project %q",
+			environmentName,
+			newEnvironmentName,
+			projectName,
+		)
+
+		// The rule sets cached by either name are stale, as the previous name
+		// now resolves to the renamed environment and the new name was not
+		// found.
+		s.publishChange(ctx, fetchedProject.Key, &changefeed.Change{EnvironmentName: environmentName})
+		s.publishChange(ctx, fetchedProject.Key, &changefeed.Change{EnvironmentName: newEnvironmentName})
+
+		response.AliasExpiresAt = timestamppb.New(aliasExpiresAt)
+	}
+
+	return response, nil
+}
+
+// handleRenameEnvironment performs the transaction for renaming the
+// environment and the views of the flags in it.
+func (s *Server) handleRenameEnvironment(
+	fetchedProject *project.Project,
+	fetchedEnvironment *Environment,
+	name string,
+	displayName string,
+	aliasExpiresAt time.Time,
+) func(mongo.SessionContext) (interface{}, error) {
+	return func(ctx mongo.SessionContext) (interface{}, error) {
+		renamedCount, renameErr := s.environmentDataRepo.Rename(
+			ctx,
+			fetchedEnvironment.ID,
+			name,
+			displayName,
+			aliasExpiresAt,
+		)
+		if renameErr != nil {
+			return nil, renameErr
+		}
+
+		if renamedCount == 0 {
+			s.logger.Error("environment %q was not renamed", fetchedEnvironment.Name)
+			return nil, ErrNotFound
+		}
+
+		_, refreshErr := s.flagViewDataRepo.Refresh(
+			ctx,
+			fetchedProject.ID,
+			fetchedEnvironment.ID,
+			"",
+		)
+		if refreshErr != nil {
+			return nil, refreshErr
+		}
+
+		return nil, nil
+	}
+}
+
+// publishChange removes the cached rule sets affected by the change to the
+// environment and publishes the change to the feed of the project so that
+// the clients watching the environment receive it. The change has already
+// been saved, so failures are only logged.
+func (s *Server) publishChange(
+	ctx context.Context,
+	projectKey string,
+	change *changefeed.Change,
+) {
+	invalidateErr := s.cacheInvalidator.Invalidate(ctx, projectKey, change)
+	if invalidateErr != nil {
+		s.logger.Error(
+			"could not invalidate cached rule sets of environment %q: %v",
+			change.EnvironmentName,
+			invalidateErr,
+		)
+	}
+
+	_, publishErr := s.changeFeedRepo.Publish(ctx, projectKey, change)
+	if publishErr != nil {
+		s.logger.Warn(
+			"could not publish change to environment %q: %v",
+			change.EnvironmentName,
+			publishErr,
+		)
+	}
+}
+
+func (s *Server) DeleteEnvironment(
+	ctx context.Context,
+	req *environmentpb.DeleteEnvironmentRequest,
+) (*environmentpb.DeleteEnvironmentResponse, error) {
+	jwtClaims, ok := auth.ClaimsFromContext(ctx)
+	if !ok {
+		s.logger.Error("could not find jwt claims in request context")
+		return nil, auth.ErrNoTokenClaims
+	}
+
+	fetchedUser, err := s.userDataRepo.GetByUsername(ctx, jwtClaims.Subject)
+	if err != nil {
+		return nil, err
+	}
+
+	projectName := req.GetProjectName()
+	environmentName := req.GetEnvironmentName()
+
+	fetchedProject, err := s.projectDataRepo.GetByNameAndUserID(ctx, projectName, fetchedUser.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	fetchedEnvironment, err := s.environmentDataRepo.GetByNameAndProjectID(
+		ctx,
+		environmentName,
+		fetchedProject.ID,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if !req.GetConfirm() {
+		if err = s.checkNotRecentlyEvaluated(ctx, fetchedProject.Key, environmentName); err != nil {
+			return nil, err
+		}
+	}
+
+	session, err := s.mongoClient.StartSession()
+	if err != nil {
+		s.logger.Error("could not create a new session: %v", err)
+		return nil, ErrTxnSession
+	}
+	defer session.EndSession(ctx)
+
+	_, txnErr := session.WithTransaction(
+		ctx,
+		s.handleDeleteEnvironment(fetchedProject, fetchedEnvironment),
+	)
+	if txnErr != nil {
+		s.logger.Error("error while performing environment delete transaction: %v", txnErr)
+		return nil, txnErr
+	}
+
+	s.logger.Info(
+		"successfully deleted environment %q of project %q",
+		environmentName,
+		projectName,
+	)
+
+	change := &changefeed.Change{EnvironmentName: environmentName}
+
+	// Remove the rule sets of the environment from the cache, including the
+	// last known good rule sets so that the flags of the deleted environment
+	// are not served while the database fails, and publish the deletion to
+	// the clients watching the environment. The environment has already been
+	// deleted, so failures are only logged.
+	purgeErr := s.cacheInvalidator.Purge(ctx, fetchedProject.Key, change)
+	if purgeErr != nil {
+		s.logger.Error(
+			"could not purge cached rule sets of environment %q: %v",
+			environmentName,
+			purgeErr,
+		)
+	}
+
+	_, publishErr := s.changeFeedRepo.Publish(ctx, fetchedProject.Key, change)
+	if publishErr != nil {
+		s.logger.Warn(
+			"could not publish change to environment %q: %v",
+			environmentName,
+			publishErr,
+		)
+	}
+
+	return &environmentpb.DeleteEnvironmentResponse{}, nil
+}
+
+// checkNotRecentlyEvaluated returns ErrRecentlyEvaluated if the flags of the
+// project were evaluated in the environment recently. Recent evaluations can't
+// be ruled out if the usage of the environments is not tracked.
+func (s *Server) checkNotRecentlyEvaluated(
+	ctx context.Context,
+	projectKey string,
+	environmentName string,
+) error {
+	if s.usageRepo == nil {
+		s.logger.Error("usage of environment %q is not tracked", environmentName)
+		return ErrRecentlyEvaluated
+	}
+
+	lastUsedAt, isUsed, err := s.usageRepo.GetLastUsed(ctx, projectKey, environmentName)
+	if err != nil {
+		s.logger.Error("could not get the last usage of environment %q: %v", environmentName, err)
+		return ErrCouldNotFetch
+	}
+
+	if isUsed && time.Since(lastUsedAt) < RecentEvaluationWindow {
+		s.logger.Error(
+			"environment %q was last evaluated at %v",
+			environmentName,
+			lastUsedAt,
+		)
+		return ErrRecentlyEvaluated
+	}
+
+	return nil
+}
+
+// handleDeleteEnvironment performs the transaction for deleting the
+// environment along with its flag settings and views.
+func (s *Server) handleDeleteEnvironment(
+	fetchedProject *project.Project,
+	fetchedEnvironment *Environment,
+) func(mongo.SessionContext) (interface{}, error) {
+	return func(ctx mongo.SessionContext) (interface{}, error) {
+		_, deleteSettingsErr := s.flagSettingDataRepo.DeleteByEnvironmentID(
+			ctx,
+			fetchedProject.ID,
+			fetchedEnvironment.ID,
+		)
+		if deleteSettingsErr != nil {
+			return nil, deleteSettingsErr
+		}
+
+		deletedCount, deleteErr := s.environmentDataRepo.Delete(ctx, fetchedEnvironment.ID)
+		if deleteErr != nil {
+			return nil, deleteErr
+		}
+
+		if deletedCount == 0 {
+			s.logger.Error("environment %q was deleted concurrently", fetchedEnvironment.Name)
+			return nil, ErrNotFound
+		}
+
+		// Mark the project updated so that a flag that is concurrently added
+		// to the project doesn't get a flag setting in the deleted
+		// environment.
+		_, markErr := s.projectDataRepo.MarkUpdated(ctx, fetchedProject.ID)
+		if markErr != nil {
+			return nil, markErr
 		}
 
-		// Add the environment to the project.
-		_, projectUpdateErr := s.projectDataRepo.AddEnvironment(
+		// Remove the views of the flags in the environment, as it no longer
+		// has any flag settings.
+		_, refreshErr := s.flagViewDataRepo.Refresh(
 			ctx,
 			fetchedProject.ID,
-			environmentID,
+			fetchedEnvironment.ID,
+			"",
 		)
-		if projectUpdateErr != nil {
-			return nil, projectUpdateErr
+		if refreshErr != nil {
+			return nil, refreshErr
 		}
 
 		return nil, nil
@@ -163,16 +582,30 @@ func NewEnvironmentServer(
 	client *mongo.Client,
 	userDataRepo user.DataRepository,
 	projectDataRepo project.DataRepository,
+	flagDataRepo FlagRepository,
 	flagSettingDataRepo flagsetting.DataRepository,
+	flagViewDataRepo flagview.DataRepository,
 	environmentDataRepo DataRepository,
+	changeFeedRepo changefeed.Repository,
+	cacheInvalidator rulesetcache.Invalidator,
+	cacheWarmer rulesetcache.Warmer,
+	usageRepo UsageRepository,
+	aliasPeriod time.Duration,
 	logger logger.Logger,
 ) *Server {
 	return &Server{
 		mongoClient:         client,
 		userDataRepo:        userDataRepo,
 		projectDataRepo:     projectDataRepo,
+		flagDataRepo:        flagDataRepo,
 		flagSettingDataRepo: flagSettingDataRepo,
+		flagViewDataRepo:    flagViewDataRepo,
 		environmentDataRepo: environmentDataRepo,
+		changeFeedRepo:      changeFeedRepo,
+		cacheInvalidator:    cacheInvalidator,
+		cacheWarmer:         cacheWarmer,
+		usageRepo:           usageRepo,
+		aliasPeriod:         aliasPeriod,
 		logger:              logger,
 	}
 }
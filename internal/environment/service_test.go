@@ -0,0 +1,276 @@
+package environment_test
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"github.com/waduhek/flagger/proto/environmentpb"
+
+	"github.com/waduhek/flagger/internal/auth"
+	"github.com/waduhek/flagger/internal/changefeed"
+	"github.com/waduhek/flagger/internal/environment"
+	"github.com/waduhek/flagger/internal/flagsetting"
+	"github.com/waduhek/flagger/internal/flagview"
+	"github.com/waduhek/flagger/internal/logger"
+	"github.com/waduhek/flagger/internal/project"
+	"github.com/waduhek/flagger/internal/rulesetcache"
+	"github.com/waduhek/flagger/internal/user"
+)
+
+const serviceTestProjectKey = "environment-service-test-project"
+
+// recordingInvalidator records the patterns of the cached rule sets that are
+// invalidated and purged.
+type recordingInvalidator struct {
+	mu       sync.Mutex
+	patterns []string
+}
+
+func (i *recordingInvalidator) Invalidate(_ context.Context, projectKey string, change *changefeed.Change) error {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	i.patterns = append(i.patterns, rulesetcache.Pattern(projectKey, change))
+
+	return nil
+}
+
+func (i *recordingInvalidator) Purge(ctx context.Context, projectKey string, change *changefeed.Change) error {
+	return i.Invalidate(ctx, projectKey, change)
+}
+
+// noopWarmer drops the requests to warm the cache.
+type noopWarmer struct{}
+
+func (noopWarmer) Warm(string, *changefeed.Change) {}
+
+// The fake repositories serve the operations of the tested RPCs from memory.
+// The other operations of the repositories are not implemented.
+type fakeUserRepository struct {
+	user.DataRepository
+}
+
+func (fakeUserRepository) GetByUsername(_ context.Context, username string) (*user.User, error) {
+	return &user.User{ID: primitive.NewObjectID().Hex(), Username: username}, nil
+}
+
+type fakeProjectRepository struct {
+	project.DataRepository
+}
+
+func (fakeProjectRepository) GetByNameAndUserID(
+	_ context.Context,
+	projectName string,
+	_ string,
+) (*project.Project, error) {
+	return &project.Project{ID: primitive.NewObjectID().Hex(), Key: serviceTestProjectKey, Name: projectName}, nil
+}
+
+func (fakeProjectRepository) MarkUpdated(context.Context, string) (uint, error) {
+	return 1, nil
+}
+
+// fakeEnvironmentRepository records the names of the environments that are
+// deleted.
+type fakeEnvironmentRepository struct {
+	environment.DataRepository
+
+	deleted []string
+}
+
+func (*fakeEnvironmentRepository) Save(context.Context, *environment.Environment) (string, error) {
+	return primitive.NewObjectID().Hex(), nil
+}
+
+func (*fakeEnvironmentRepository) GetByNameAndProjectID(
+	_ context.Context,
+	environmentName string,
+	projectID string,
+) (*environment.Environment, error) {
+	return &environment.Environment{ID: environmentName, Name: environmentName, ProjectID: projectID}, nil
+}
+
+func (r *fakeEnvironmentRepository) Delete(_ context.Context, environmentID string) (uint, error) {
+	r.deleted = append(r.deleted, environmentID)
+	return 1, nil
+}
+
+type fakeFlagRepository struct{}
+
+func (fakeFlagRepository) GetIDsByProjectID(context.Context, string) ([]string, error) {
+	return []string{primitive.NewObjectID().Hex(), primitive.NewObjectID().Hex()}, nil
+}
+
+type fakeFlagSettingRepository struct {
+	flagsetting.DataRepository
+}
+
+func (fakeFlagSettingRepository) SaveMany(
+	_ context.Context,
+	flagSettings []flagsetting.FlagSetting,
+) ([]string, error) {
+	ids := make([]string, 0, len(flagSettings))
+	for range flagSettings {
+		ids = append(ids, primitive.NewObjectID().Hex())
+	}
+
+	return ids, nil
+}
+
+func (fakeFlagSettingRepository) DeleteByEnvironmentID(context.Context, string, string) (uint, error) {
+	return 1, nil
+}
+
+type fakeFlagViewRepository struct {
+	flagview.DataRepository
+}
+
+func (fakeFlagViewRepository) Refresh(context.Context, string, string, string) (uint, error) {
+	return 1, nil
+}
+
+// fakeUsageRepository reports that the flags of every environment were last
+// evaluated at the time, unless the time is zero.
+type fakeUsageRepository struct {
+	lastUsedAt time.Time
+}
+
+func (r fakeUsageRepository) GetLastUsed(context.Context, string, string) (time.Time, bool, error) {
+	return r.lastUsedAt, !r.lastUsedAt.IsZero(), nil
+}
+
+// newServiceTest creates an environment server with fake repositories. The
+// transactions of the server don't reach the database as the fake
+// repositories don't use it.
+func newServiceTest(
+	t *testing.T,
+	usageRepo environment.UsageRepository,
+) (*environment.Server, *recordingInvalidator, *fakeEnvironmentRepository) {
+	t.Helper()
+
+	client, err := mongo.Connect(context.Background(), options.Client().ApplyURI(mongoDBConnectionString))
+	if err != nil {
+		t.Fatalf("could not create mongo client: %v", err)
+	}
+
+	t.Cleanup(func() { _ = client.Disconnect(context.Background()) })
+
+	invalidator := &recordingInvalidator{}
+	environmentRepo := &fakeEnvironmentRepository{}
+
+	server := environment.NewEnvironmentServer(
+		client,
+		fakeUserRepository{},
+		fakeProjectRepository{},
+		fakeFlagRepository{},
+		fakeFlagSettingRepository{},
+		fakeFlagViewRepository{},
+		environmentRepo,
+		changefeed.NewMemoryChangeFeedRepository(),
+		invalidator,
+		noopWarmer{},
+		usageRepo,
+		0,
+		&logger.StubLogger{},
+	)
+
+	return server, invalidator, environmentRepo
+}
+
+// authenticatedContext creates the context of a request of an authenticated
+// user.
+func authenticatedContext() context.Context {
+	return auth.InjectClaimsIntoContext(
+		context.Background(),
+		&auth.FlaggerJWTClaims{RegisteredClaims: jwt.RegisteredClaims{Subject: "test"}},
+	)
+}
+
+func TestServer_CreateEnvironment_InvalidatesEnvironment(t *testing.T) {
+	server, invalidator, _ := newServiceTest(t, nil)
+
+	_, err := server.CreateEnvironment(authenticatedContext(), &environmentpb.CreateEnvironmentRequest{
+		ProjectName:     "test",
+		EnvironmentName: "staging",
+	})
+	if err != nil {
+		t.Fatalf("could not create environment: %v", err)
+	}
+
+	// The flags may have been cached as not found in the environment.
+	expected := []string{"ruleset:{" + serviceTestProjectKey + ":staging}:*"}
+	if !slices.Equal(invalidator.patterns, expected) {
+		t.Fatalf("expected %v to be invalidated but got %v", expected, invalidator.patterns)
+	}
+}
+
+func TestServer_DeleteEnvironment_RequiresConfirmationOfRecentlyEvaluated(t *testing.T) {
+	testCases := []struct {
+		Name          string
+		UsageRepo     environment.UsageRepository
+		Confirm       bool
+		ExpectedError error
+	}{
+		{
+			Name:          "recently_evaluated",
+			UsageRepo:     fakeUsageRepository{lastUsedAt: time.Now().Add(-time.Hour)},
+			ExpectedError: environment.ErrRecentlyEvaluated,
+		},
+		{
+			Name:          "usage_not_tracked",
+			UsageRepo:     nil,
+			ExpectedError: environment.ErrRecentlyEvaluated,
+		},
+		{
+			Name:      "recently_evaluated_confirmed",
+			UsageRepo: fakeUsageRepository{lastUsedAt: time.Now().Add(-time.Hour)},
+			Confirm:   true,
+		},
+		{
+			Name:      "evaluated_before_window",
+			UsageRepo: fakeUsageRepository{lastUsedAt: time.Now().Add(-environment.RecentEvaluationWindow)},
+		},
+		{
+			Name:      "never_evaluated",
+			UsageRepo: fakeUsageRepository{},
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.Name, func(t *testing.T) {
+			server, invalidator, environmentRepo := newServiceTest(t, testCase.UsageRepo)
+
+			_, err := server.DeleteEnvironment(authenticatedContext(), &environmentpb.DeleteEnvironmentRequest{
+				ProjectName:     "test",
+				EnvironmentName: "staging",
+				Confirm:         testCase.Confirm,
+			})
+			if !errors.Is(err, testCase.ExpectedError) {
+				t.Fatalf("expected error %v but got %v", testCase.ExpectedError, err)
+			}
+
+			var expectedDeleted, expectedPatterns []string
+			if testCase.ExpectedError == nil {
+				expectedDeleted = []string{"staging"}
+				expectedPatterns = []string{"ruleset:{" + serviceTestProjectKey + ":staging}:*"}
+			}
+
+			if !slices.Equal(environmentRepo.deleted, expectedDeleted) {
+				t.Fatalf("expected %v to be deleted but got %v", expectedDeleted, environmentRepo.deleted)
+			}
+
+			if !slices.Equal(invalidator.patterns, expectedPatterns) {
+				t.Fatalf("expected %v to be purged but got %v", expectedPatterns, invalidator.patterns)
+			}
+		})
+	}
+}
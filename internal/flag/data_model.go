@@ -3,14 +3,33 @@ package flag
 import (
 	"context"
 	"time"
+
+	"github.com/waduhek/flagger/internal/alias"
+	"github.com/waduhek/flagger/internal/pagination"
 )
 
+// Flag is a flag of a project. The clients request the flag by its name, while
+// the display name is only shown to the users. A renamed flag is still served
+// by its aliases until they expire. The metadata of the flag describes it to
+// the users. An archived flag serves its archived variation in every
+// environment until it is restored.
 type Flag struct {
-	ID        string
-	Name      string
-	ProjectID string
-	CreatedBy string
-	CreatedAt time.Time
+	ID                string
+	Name              string
+	DisplayName       string
+	Aliases           []alias.Alias
+	ProjectID         string
+	Type              Type
+	Variations        []Variation
+	DefaultVariation  string
+	OffVariation      string
+	Salt              string
+	Metadata          Metadata
+	IsArchived        bool
+	ArchivedVariation string
+	ArchivedAt        time.Time
+	CreatedBy         string
+	CreatedAt         time.Time
 }
 
 type DataRepository interface {
@@ -27,4 +46,48 @@ type DataRepository interface {
 		flagName string,
 		projectID string,
 	) (*Flag, error)
+
+	// GetIDsByProjectID gets the IDs of all the flags of the project.
+	GetIDsByProjectID(ctx context.Context, projectID string) ([]string, error)
+
+	// ListByProjectID gets a page of the flags of the project that match the
+	// filter along with the token of the next page, which is empty if this is
+	// the last page.
+	ListByProjectID(
+		ctx context.Context,
+		projectID string,
+		filter *ListFilter,
+		query *pagination.Query,
+	) ([]Flag, string, error)
+
+	// Archive archives the flag so that it serves the provided variation in
+	// every environment. The number of updated flags is returned.
+	Archive(ctx context.Context, flagID string, variation string) (uint, error)
+
+	// Restore restores the archived flag. The number of updated flags is
+	// returned.
+	Restore(ctx context.Context, flagID string) (uint, error)
+
+	// Rename changes the name and the display name of the flag, which are not
+	// changed if they are empty. Unless the time of expiry is zero, the
+	// current name of the flag is kept as an alias until then. The number of
+	// updated flags is returned.
+	Rename(
+		ctx context.Context,
+		flagID string,
+		name string,
+		displayName string,
+		aliasExpiresAt time.Time,
+	) (uint, error)
+
+	// UpdateMetadata replaces the metadata of the flag. The number of updated
+	// flags is returned.
+	UpdateMetadata(ctx context.Context, flagID string, metadata *Metadata) (uint, error)
+
+	// Delete deletes the flag. The number of deleted flags is returned.
+	Delete(ctx context.Context, flagID string) (uint, error)
+
+	// DeleteByProjectID deletes all the flags of the project. The number of
+	// deleted flags is returned.
+	DeleteByProjectID(ctx context.Context, projectID string) (uint, error)
 }
@@ -50,3 +50,94 @@ var ErrUpdateStatus = status.Error(
 	codes.Internal,
 	"no flag settings could be updated",
 )
+
+// ErrInvalidFlagType is a GRPC error that is returned when the requested flag
+// type is not supported.
+var ErrInvalidFlagType = status.Error(
+	codes.InvalidArgument,
+	"unsupported flag type",
+)
+
+// ErrInvalidVariations is a GRPC error that is returned when the variations
+// provided for a flag are invalid.
+var ErrInvalidVariations = status.Error(
+	codes.InvalidArgument,
+	"invalid flag variations",
+)
+
+// ErrVariationNotFound is a GRPC error that is returned when the requested
+// variation does not exist on the flag.
+var ErrVariationNotFound = status.Error(
+	codes.InvalidArgument,
+	"variation not found on the flag",
+)
+
+// ErrUpdateVariation is a GRPC error that is returned when the served
+// variation of a flag could not be updated.
+var ErrUpdateVariation = status.Error(
+	codes.Internal,
+	"no flag settings could be updated with the variation",
+)
+
+// ErrUpdateRules is a GRPC error that is returned when the targeting rules of a
+// flag could not be updated.
+var ErrUpdateRules = status.Error(
+	codes.Internal,
+	"no flag settings could be updated with the rules",
+)
+
+// ErrCouldNotUpdate is a GRPC error that is returned when an unknown error
+// occurs while updating a flag.
+var ErrCouldNotUpdate = status.Error(
+	codes.Internal,
+	"error occurred while updating the flag",
+)
+
+// ErrNothingToRename is a GRPC error that is returned when renaming a flag
+// without a new name or display name.
+var ErrNothingToRename = status.Error(
+	codes.InvalidArgument,
+	"a new name or display name is required",
+)
+
+// ErrCouldNotDelete is a GRPC error that is returned when an unknown error
+// occurs while deleting a flag.
+var ErrCouldNotDelete = status.Error(
+	codes.Internal,
+	"error occurred while deleting the flag",
+)
+
+// ErrArchived is a GRPC error that is returned when attempting to update the
+// settings of an archived flag.
+var ErrArchived = status.Error(
+	codes.FailedPrecondition,
+	"the flag is archived",
+)
+
+// ErrNotArchived is a GRPC error that is returned when attempting to restore a
+// flag that is not archived.
+var ErrNotArchived = status.Error(
+	codes.FailedPrecondition,
+	"the flag is not archived",
+)
+
+// ErrInvalidMetadata is a GRPC error that is returned when the description,
+// the owner or the tags of a flag are invalid.
+var ErrInvalidMetadata = status.Error(
+	codes.InvalidArgument,
+	"the description, owner or tags of the flag are invalid",
+)
+
+// ErrInvalidKind is a GRPC error that is returned when an unknown flag kind is
+// provided.
+var ErrInvalidKind = status.Error(
+	codes.InvalidArgument,
+	"unknown flag kind",
+)
+
+// ErrOwnerNotFound is a GRPC error that is returned when the user that is to
+// own a flag was not found.
+var ErrOwnerNotFound = status.Error(
+	codes.NotFound,
+	"the owner of the flag was not found",
+)
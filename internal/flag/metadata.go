@@ -0,0 +1,242 @@
+package flag
+
+import (
+	"encoding/json"
+	"slices"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/waduhek/flagger/proto/flagpb"
+)
+
+// Kind is the purpose of a flag.
+type Kind string
+
+const (
+	KindRelease    Kind = "release"
+	KindExperiment Kind = "experiment"
+	KindOps        Kind = "ops"
+	KindPermission Kind = "permission"
+)
+
+// OwnerType is the type of the owner of a flag.
+type OwnerType string
+
+const (
+	OwnerTypeUser OwnerType = "user"
+	OwnerTypeTeam OwnerType = "team"
+)
+
+// The limits of the metadata of a flag.
+const (
+	maxDescriptionLength = 1000
+	maxTags              = 20
+	maxTagLength         = 50
+)
+
+// Owner is the user or the team that is responsible for a flag. The name of a
+// user owner is the username of the user.
+type Owner struct {
+	Type OwnerType `bson:"type"`
+	Name string    `bson:"name"`
+}
+
+// Metadata describes what a flag does and who is responsible for it. The
+// metadata is only shown to the users and is not used to evaluate the flag.
+type Metadata struct {
+	Description string
+	Owner       *Owner
+	Tags        []string
+	Kind        Kind
+}
+
+// NewMetadata validates the metadata of a flag. The tags are trimmed, sorted
+// and deduplicated, and the kind defaults to a release flag. Fails with
+// ErrInvalidMetadata if the metadata exceeds its limits or the owner has no
+// type or name.
+func NewMetadata(description string, owner *Owner, tags []string, kind Kind) (*Metadata, error) {
+	if utf8.RuneCountInString(description) > maxDescriptionLength {
+		return nil, ErrInvalidMetadata
+	}
+
+	if owner != nil && (owner.Name == "" || (owner.Type != OwnerTypeUser && owner.Type != OwnerTypeTeam)) {
+		return nil, ErrInvalidMetadata
+	}
+
+	tags = normaliseTags(tags)
+	if len(tags) > maxTags {
+		return nil, ErrInvalidMetadata
+	}
+
+	for _, tag := range tags {
+		if utf8.RuneCountInString(tag) > maxTagLength {
+			return nil, ErrInvalidMetadata
+		}
+	}
+
+	if kind == "" {
+		kind = KindRelease
+	}
+
+	metadata := &Metadata{
+		Description: description,
+		Owner:       owner,
+		Tags:        tags,
+		Kind:        kind,
+	}
+
+	return metadata, nil
+}
+
+// ListFilter narrows the listing of the flags of a project. The empty fields
+// don't narrow the listing.
+type ListFilter struct {
+	// Kind only lists the flags of the kind.
+	Kind Kind `json:"k,omitempty"`
+	// Owner only lists the flags owned by the user or the team with the name.
+	Owner string `json:"o,omitempty"`
+	// Tags only lists the flags that have all the tags.
+	Tags []string `json:"t,omitempty"`
+	// Search only lists the flags whose name, display name or description
+	// contain any of the words of the search.
+	Search string `json:"s,omitempty"`
+}
+
+// encode encodes the filter for the page tokens of the listing. An empty
+// filter is encoded as an empty string, so that the page tokens issued before
+// the filters were introduced are still accepted.
+func (f *ListFilter) encode() string {
+	if f.Kind == "" && f.Owner == "" && len(f.Tags) == 0 && f.Search == "" {
+		return ""
+	}
+
+	encodedFilter, _ := json.Marshal(f)
+
+	return string(encodedFilter)
+}
+
+// normaliseTags trims the tags and removes the empty and duplicate tags. The
+// tags are sorted.
+func normaliseTags(tags []string) []string {
+	normalised := make([]string, 0, len(tags))
+
+	for _, tag := range tags {
+		if tag = strings.TrimSpace(tag); tag != "" {
+			normalised = append(normalised, tag)
+		}
+	}
+
+	slices.Sort(normalised)
+
+	return slices.Compact(normalised)
+}
+
+// metadataRequest is a request that sets the metadata of a flag.
+type metadataRequest interface {
+	GetDescription() string
+	GetOwner() *flagpb.FlagOwner
+	GetTags() []string
+	GetKind() flagpb.FlagKind
+}
+
+// metadataFromProto maps the metadata of a request to a `Metadata`.
+func metadataFromProto(req metadataRequest) (*Metadata, error) {
+	kind, ok := kindFromProto(req.GetKind())
+	if !ok {
+		return nil, ErrInvalidKind
+	}
+
+	var owner *Owner
+	if req.GetOwner() != nil {
+		owner = &Owner{
+			Type: ownerTypeFromProto(req.GetOwner().GetType()),
+			Name: req.GetOwner().GetName(),
+		}
+	}
+
+	return NewMetadata(req.GetDescription(), owner, req.GetTags(), kind)
+}
+
+// listFilterFromProto maps the filters of a listing request to a
+// `ListFilter`.
+func listFilterFromProto(req *flagpb.ListFlagsRequest) (*ListFilter, error) {
+	kind, ok := kindFromProto(req.GetKind())
+	if !ok {
+		return nil, ErrInvalidKind
+	}
+
+	filter := &ListFilter{
+		Kind:   kind,
+		Owner:  req.GetOwner(),
+		Tags:   normaliseTags(req.GetTags()),
+		Search: strings.TrimSpace(req.GetSearch()),
+	}
+
+	return filter, nil
+}
+
+// kindFromProto maps the kind of a request to a `Kind`. An unspecified kind is
+// mapped to an empty kind.
+func kindFromProto(kind flagpb.FlagKind) (Kind, bool) {
+	switch kind {
+	case flagpb.FlagKind_FLAG_KIND_UNSPECIFIED:
+		return "", true
+	case flagpb.FlagKind_FLAG_KIND_RELEASE:
+		return KindRelease, true
+	case flagpb.FlagKind_FLAG_KIND_EXPERIMENT:
+		return KindExperiment, true
+	case flagpb.FlagKind_FLAG_KIND_OPS:
+		return KindOps, true
+	case flagpb.FlagKind_FLAG_KIND_PERMISSION:
+		return KindPermission, true
+	default:
+		return "", false
+	}
+}
+
+// kindToProto maps the `Kind` of a flag to the kind of a response.
+func kindToProto(kind Kind) flagpb.FlagKind {
+	switch kind {
+	case KindRelease:
+		return flagpb.FlagKind_FLAG_KIND_RELEASE
+	case KindExperiment:
+		return flagpb.FlagKind_FLAG_KIND_EXPERIMENT
+	case KindOps:
+		return flagpb.FlagKind_FLAG_KIND_OPS
+	case KindPermission:
+		return flagpb.FlagKind_FLAG_KIND_PERMISSION
+	default:
+		return flagpb.FlagKind_FLAG_KIND_UNSPECIFIED
+	}
+}
+
+// ownerTypeFromProto maps the owner type of a request to an `OwnerType`. An
+// unknown owner type is mapped to an empty owner type.
+func ownerTypeFromProto(ownerType flagpb.OwnerType) OwnerType {
+	switch ownerType {
+	case flagpb.OwnerType_OWNER_TYPE_USER:
+		return OwnerTypeUser
+	case flagpb.OwnerType_OWNER_TYPE_TEAM:
+		return OwnerTypeTeam
+	default:
+		return ""
+	}
+}
+
+// ownerToProto maps the `Owner` of a flag to the owner of a response.
+func ownerToProto(owner *Owner) *flagpb.FlagOwner {
+	if owner == nil {
+		return nil
+	}
+
+	ownerType := flagpb.OwnerType_OWNER_TYPE_UNSPECIFIED
+
+	switch owner.Type {
+	case OwnerTypeUser:
+		ownerType = flagpb.OwnerType_OWNER_TYPE_USER
+	case OwnerTypeTeam:
+		ownerType = flagpb.OwnerType_OWNER_TYPE_TEAM
+	}
+
+	return &flagpb.FlagOwner{Type: ownerType, Name: owner.Name}
+}
@@ -0,0 +1,60 @@
+package flag_test
+
+import (
+	"errors"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/waduhek/flagger/internal/flag"
+)
+
+func TestNewMetadata(t *testing.T) {
+	t.Parallel()
+
+	metadata, err := flag.NewMetadata("", nil, []string{" web", "checkout", "", "web"}, "")
+	if err != nil {
+		t.Fatalf("could not create metadata: %v", err)
+	}
+
+	if !slices.Equal(metadata.Tags, []string{"checkout", "web"}) {
+		t.Errorf("expected the tags to be normalised but got %v", metadata.Tags)
+	}
+
+	if metadata.Kind != flag.KindRelease {
+		t.Errorf("expected the kind to default to a release flag but got %q", metadata.Kind)
+	}
+}
+
+func TestNewMetadata_Invalid(t *testing.T) {
+	t.Parallel()
+
+	tooManyTags := make([]string, 0, 21)
+	for i := range 21 {
+		tooManyTags = append(tooManyTags, strings.Repeat("a", i+1))
+	}
+
+	testCases := []struct {
+		Name        string
+		Description string
+		Owner       *flag.Owner
+		Tags        []string
+	}{
+		{Name: "long_description", Description: strings.Repeat("a", 1001)},
+		{Name: "owner_without_name", Owner: &flag.Owner{Type: flag.OwnerTypeTeam}},
+		{Name: "owner_without_type", Owner: &flag.Owner{Name: "payments"}},
+		{Name: "too_many_tags", Tags: tooManyTags},
+		{Name: "long_tag", Tags: []string{strings.Repeat("a", 51)}},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.Name, func(t *testing.T) {
+			t.Parallel()
+
+			_, err := flag.NewMetadata(testCase.Description, testCase.Owner, testCase.Tags, flag.KindOps)
+			if !errors.Is(err, flag.ErrInvalidMetadata) {
+				t.Fatalf("expected ErrInvalidMetadata but got %v", err)
+			}
+		})
+	}
+}
@@ -5,7 +5,9 @@ import (
 	"errors"
 	"time"
 
+	"github.com/waduhek/flagger/internal/alias"
 	"github.com/waduhek/flagger/internal/logger"
+	"github.com/waduhek/flagger/internal/pagination"
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 	"go.mongodb.org/mongo-driver/mongo"
@@ -16,11 +18,25 @@ const flagCollection string = "flags"
 
 // flagMongoModel is the MongoDB representation of the `Flag` structure.
 type flagMongoModel struct {
-	ID        primitive.ObjectID `bson:"_id,omitempty"`
-	Name      string             `bson:"name"`
-	ProjectID primitive.ObjectID `bson:"project_id"`
-	CreatedBy primitive.ObjectID `bson:"created_by"`
-	CreatedAt time.Time          `bson:"created_at"`
+	ID                primitive.ObjectID `bson:"_id,omitempty"`
+	Name              string             `bson:"name"`
+	DisplayName       string             `bson:"display_name,omitempty"`
+	Aliases           []alias.Alias      `bson:"aliases,omitempty"`
+	ProjectID         primitive.ObjectID `bson:"project_id"`
+	Type              Type               `bson:"type"`
+	Variations        []Variation        `bson:"variations"`
+	DefaultVariation  string             `bson:"default_variation"`
+	OffVariation      string             `bson:"off_variation"`
+	Salt              string             `bson:"salt,omitempty"`
+	Description       string             `bson:"description,omitempty"`
+	Owner             *Owner             `bson:"owner,omitempty"`
+	Tags              []string           `bson:"tags,omitempty"`
+	Kind              Kind               `bson:"kind,omitempty"`
+	IsArchived        bool               `bson:"is_archived,omitempty"`
+	ArchivedVariation string             `bson:"archived_variation,omitempty"`
+	ArchivedAt        time.Time          `bson:"archived_at,omitempty"`
+	CreatedBy         primitive.ObjectID `bson:"created_by"`
+	CreatedAt         time.Time          `bson:"created_at"`
 }
 
 type MongoDataRepository struct {
@@ -45,10 +61,20 @@ func (r *MongoDataRepository) Save(
 	}
 
 	flagToSave := &flagMongoModel{
-		Name:      flag.Name,
-		ProjectID: projectIDObjID,
-		CreatedBy: userIDObjID,
-		CreatedAt: time.Now(),
+		Name:             flag.Name,
+		DisplayName:      flag.DisplayName,
+		ProjectID:        projectIDObjID,
+		Type:             flag.Type,
+		Variations:       flag.Variations,
+		DefaultVariation: flag.DefaultVariation,
+		OffVariation:     flag.OffVariation,
+		Salt:             flag.Salt,
+		Description:      flag.Metadata.Description,
+		Owner:            flag.Metadata.Owner,
+		Tags:             flag.Metadata.Tags,
+		Kind:             flag.Metadata.Kind,
+		CreatedBy:        userIDObjID,
+		CreatedAt:        time.Now(),
 	}
 
 	saveResult, saveErr := r.coll.InsertOne(ctx, flagToSave)
@@ -130,14 +156,334 @@ func (r *MongoDataRepository) GetByNameAndProjectID(
 	return mapDecodedFlag(&decodedFlag), nil
 }
 
+func (r *MongoDataRepository) GetIDsByProjectID(
+	ctx context.Context,
+	projectID string,
+) ([]string, error) {
+	projectIDObjID, projectIDErr := primitive.ObjectIDFromHex(projectID)
+	if projectIDErr != nil {
+		r.logger.Error("could not convert project id to object id: %v", projectIDErr)
+		return nil, ErrCouldNotFetch
+	}
+
+	cursor, err := r.coll.Find(
+		ctx,
+		bson.D{{Key: "project_id", Value: projectIDObjID}},
+		options.Find().SetProjection(bson.D{{Key: "_id", Value: 1}}),
+	)
+	if err != nil {
+		r.logger.Error("error while getting flags of project %q: %v", projectID, err)
+		return nil, ErrCouldNotFetch
+	}
+
+	var decodedFlags []flagMongoModel
+	if err = cursor.All(ctx, &decodedFlags); err != nil {
+		r.logger.Error("error while decoding flags of project %q: %v", projectID, err)
+		return nil, ErrCouldNotFetch
+	}
+
+	flagIDs := make([]string, 0, len(decodedFlags))
+	for i := range decodedFlags {
+		flagIDs = append(flagIDs, decodedFlags[i].ID.Hex())
+	}
+
+	return flagIDs, nil
+}
+
+func (r *MongoDataRepository) ListByProjectID(
+	ctx context.Context,
+	projectID string,
+	filter *ListFilter,
+	query *pagination.Query,
+) ([]Flag, string, error) {
+	projectIDObjID, projectIDErr := primitive.ObjectIDFromHex(projectID)
+	if projectIDErr != nil {
+		r.logger.Error("could not convert project id to object id: %v", projectIDErr)
+		return nil, "", ErrCouldNotFetch
+	}
+
+	cursor, err := r.coll.Find(
+		ctx,
+		query.Filter(listFilter(projectIDObjID, filter)),
+		query.FindOptions(),
+	)
+	if err != nil {
+		r.logger.Error("error while listing flags of project %q: %v", projectID, err)
+		return nil, "", ErrCouldNotFetch
+	}
+
+	var decodedFlags []flagMongoModel
+	if err = cursor.All(ctx, &decodedFlags); err != nil {
+		r.logger.Error("error while decoding flags of project %q: %v", projectID, err)
+		return nil, "", ErrCouldNotFetch
+	}
+
+	decodedFlags, nextPageToken := pagination.Page(
+		query,
+		decodedFlags,
+		func(decodedFlag *flagMongoModel) pagination.Position {
+			return pagination.Position{
+				ID:        decodedFlag.ID,
+				Name:      decodedFlag.Name,
+				CreatedAt: decodedFlag.CreatedAt,
+			}
+		},
+	)
+
+	flags := make([]Flag, 0, len(decodedFlags))
+	for i := range decodedFlags {
+		flags = append(flags, *mapDecodedFlag(&decodedFlags[i]))
+	}
+
+	return flags, nextPageToken, nil
+}
+
+func (r *MongoDataRepository) Archive(
+	ctx context.Context,
+	flagID string,
+	variation string,
+) (uint, error) {
+	update := bson.D{
+		{
+			Key: "$set",
+			Value: bson.D{
+				{Key: "is_archived", Value: true},
+				{Key: "archived_variation", Value: variation},
+				{Key: "archived_at", Value: time.Now()},
+			},
+		},
+	}
+
+	return r.updateByID(ctx, flagID, update)
+}
+
+func (r *MongoDataRepository) Restore(
+	ctx context.Context,
+	flagID string,
+) (uint, error) {
+	update := bson.D{
+		{
+			Key: "$unset",
+			Value: bson.D{
+				{Key: "is_archived", Value: ""},
+				{Key: "archived_variation", Value: ""},
+				{Key: "archived_at", Value: ""},
+			},
+		},
+	}
+
+	return r.updateByID(ctx, flagID, update)
+}
+
+func (r *MongoDataRepository) Rename(
+	ctx context.Context,
+	flagID string,
+	name string,
+	displayName string,
+	aliasExpiresAt time.Time,
+) (uint, error) {
+	return r.updateByID(ctx, flagID, alias.RenameUpdate(name, displayName, aliasExpiresAt))
+}
+
+func (r *MongoDataRepository) UpdateMetadata(
+	ctx context.Context,
+	flagID string,
+	metadata *Metadata,
+) (uint, error) {
+	setFields := bson.D{{Key: "kind", Value: metadata.Kind}}
+
+	// The metadata that is not provided is removed, as it is omitted when the
+	// flag is saved.
+	unsetFields := bson.D{}
+
+	if metadata.Description != "" {
+		setFields = append(setFields, bson.E{Key: "description", Value: metadata.Description})
+	} else {
+		unsetFields = append(unsetFields, bson.E{Key: "description", Value: ""})
+	}
+
+	if metadata.Owner != nil {
+		setFields = append(setFields, bson.E{Key: "owner", Value: metadata.Owner})
+	} else {
+		unsetFields = append(unsetFields, bson.E{Key: "owner", Value: ""})
+	}
+
+	if len(metadata.Tags) > 0 {
+		setFields = append(setFields, bson.E{Key: "tags", Value: metadata.Tags})
+	} else {
+		unsetFields = append(unsetFields, bson.E{Key: "tags", Value: ""})
+	}
+
+	update := bson.D{{Key: "$set", Value: setFields}}
+	if len(unsetFields) > 0 {
+		update = append(update, bson.E{Key: "$unset", Value: unsetFields})
+	}
+
+	return r.updateByID(ctx, flagID, update)
+}
+
+// listFilter creates the filter of the flags of the project that match the
+// filter of the listing. The text search uses the text index of the flags.
+func listFilter(projectID primitive.ObjectID, filter *ListFilter) bson.D {
+	query := bson.D{{Key: "project_id", Value: projectID}}
+
+	if filter == nil {
+		return query
+	}
+
+	switch filter.Kind {
+	case "":
+	case KindRelease:
+		// Flags created before kinds were introduced are release flags.
+		query = append(query, bson.E{
+			Key:   "kind",
+			Value: bson.D{{Key: "$in", Value: bson.A{KindRelease, nil}}},
+		})
+	default:
+		query = append(query, bson.E{Key: "kind", Value: filter.Kind})
+	}
+
+	if filter.Owner != "" {
+		query = append(query, bson.E{Key: "owner.name", Value: filter.Owner})
+	}
+
+	if len(filter.Tags) > 0 {
+		query = append(query, bson.E{
+			Key:   "tags",
+			Value: bson.D{{Key: "$all", Value: filter.Tags}},
+		})
+	}
+
+	if filter.Search != "" {
+		query = append(query, bson.E{
+			Key:   "$text",
+			Value: bson.D{{Key: "$search", Value: filter.Search}},
+		})
+	}
+
+	return query
+}
+
+// updateByID applies the update document or pipeline to the flag and returns
+// the number of updated flags.
+func (r *MongoDataRepository) updateByID(
+	ctx context.Context,
+	flagID string,
+	update any,
+) (uint, error) {
+	flagIDObjID, flagIDErr := primitive.ObjectIDFromHex(flagID)
+	if flagIDErr != nil {
+		r.logger.Error("could not convert flag id to object id: %v", flagIDErr)
+		return 0, ErrCouldNotUpdate
+	}
+
+	updateResult, err := r.coll.UpdateByID(ctx, flagIDObjID, update)
+	if err != nil {
+		if mongo.IsDuplicateKeyError(err) {
+			r.logger.Error("flag name is taken: %v", err)
+			return 0, ErrNameTaken
+		}
+
+		r.logger.Error("could not update flag %q: %v", flagID, err)
+		return 0, ErrCouldNotUpdate
+	}
+
+	//nolint:gosec // ModifiedCount can't be a negative number.
+	return uint(updateResult.ModifiedCount), nil
+}
+
+func (r *MongoDataRepository) Delete(
+	ctx context.Context,
+	flagID string,
+) (uint, error) {
+	flagIDObjID, flagIDErr := primitive.ObjectIDFromHex(flagID)
+	if flagIDErr != nil {
+		r.logger.Error("could not convert flag id to object id: %v", flagIDErr)
+		return 0, ErrCouldNotDelete
+	}
+
+	deleteResult, err := r.coll.DeleteOne(ctx, bson.D{{Key: "_id", Value: flagIDObjID}})
+	if err != nil {
+		r.logger.Error("could not delete flag %q: %v", flagID, err)
+		return 0, ErrCouldNotDelete
+	}
+
+	//nolint:gosec // DeletedCount can't be a negative number.
+	return uint(deleteResult.DeletedCount), nil
+}
+
+func (r *MongoDataRepository) DeleteByProjectID(
+	ctx context.Context,
+	projectID string,
+) (uint, error) {
+	projectIDObjID, projectIDErr := primitive.ObjectIDFromHex(projectID)
+	if projectIDErr != nil {
+		r.logger.Error("could not convert project id to object id: %v", projectIDErr)
+		return 0, ErrCouldNotDelete
+	}
+
+	deleteResult, err := r.coll.DeleteMany(ctx, bson.D{{Key: "project_id", Value: projectIDObjID}})
+	if err != nil {
+		r.logger.Error("could not delete flags of project %q: %v", projectID, err)
+		return 0, ErrCouldNotDelete
+	}
+
+	//nolint:gosec // DeletedCount can't be a negative number.
+	return uint(deleteResult.DeletedCount), nil
+}
+
 func mapDecodedFlag(decodedFlag *flagMongoModel) *Flag {
-	return &Flag{
-		ID:        decodedFlag.ID.Hex(),
-		Name:      decodedFlag.Name,
-		ProjectID: decodedFlag.ProjectID.Hex(),
-		CreatedBy: decodedFlag.CreatedBy.Hex(),
-		CreatedAt: decodedFlag.CreatedAt,
+	flag := &Flag{
+		ID:                decodedFlag.ID.Hex(),
+		Name:              decodedFlag.Name,
+		DisplayName:       decodedFlag.DisplayName,
+		Aliases:           decodedFlag.Aliases,
+		ProjectID:         decodedFlag.ProjectID.Hex(),
+		Type:              decodedFlag.Type,
+		Variations:        decodedFlag.Variations,
+		DefaultVariation:  decodedFlag.DefaultVariation,
+		OffVariation:      decodedFlag.OffVariation,
+		Salt:              decodedFlag.Salt,
+		IsArchived:        decodedFlag.IsArchived,
+		ArchivedVariation: decodedFlag.ArchivedVariation,
+		ArchivedAt:        decodedFlag.ArchivedAt,
+		CreatedBy:         decodedFlag.CreatedBy.Hex(),
+		CreatedAt:         decodedFlag.CreatedAt,
+		Metadata: Metadata{
+			Description: decodedFlag.Description,
+			Owner:       decodedFlag.Owner,
+			Tags:        decodedFlag.Tags,
+			Kind:        decodedFlag.Kind,
+		},
 	}
+
+	// Flags created before typed values were introduced are boolean flags that
+	// serve their status.
+	if flag.Type == "" {
+		flag.Type = TypeBoolean
+		flag.Variations = booleanVariations()
+		flag.DefaultVariation = OnVariationKey
+		flag.OffVariation = OffVariationKey
+	}
+
+	// Flags created before display names were introduced are displayed by
+	// their name.
+	if flag.DisplayName == "" {
+		flag.DisplayName = flag.Name
+	}
+
+	// Flags created before kinds were introduced are release flags.
+	if flag.Metadata.Kind == "" {
+		flag.Metadata.Kind = KindRelease
+	}
+
+	// Flags created before percentage rollouts were introduced are salted with
+	// their ID.
+	if flag.Salt == "" {
+		flag.Salt = flag.ID
+	}
+
+	return flag
 }
 
 func setupCollIndexes(ctx context.Context, coll *mongo.Collection) error {
@@ -149,7 +495,72 @@ func setupCollIndexes(ctx context.Context, coll *mongo.Collection) error {
 		Options: options.Index().SetUnique(true),
 	}
 
-	_, err := coll.Indexes().CreateOne(ctx, flagProjectIndexModel)
+	// The flags of a project are looked up by the ID of the project.
+	projectIndexModel := mongo.IndexModel{
+		Keys: bson.D{{Key: "project_id", Value: 1}},
+	}
+
+	// The flags of a project are listed by their name or by the time that
+	// they were created at.
+	projectNameIndexModel := mongo.IndexModel{
+		Keys: bson.D{
+			{Key: "project_id", Value: 1},
+			{Key: "name", Value: 1},
+		},
+	}
+	projectCreatedAtIndexModel := mongo.IndexModel{
+		Keys: bson.D{
+			{Key: "project_id", Value: 1},
+			{Key: "created_at", Value: 1},
+		},
+	}
+
+	// The flags of a project are filtered by their kind, owner and tags.
+	projectKindIndexModel := mongo.IndexModel{
+		Keys: bson.D{
+			{Key: "project_id", Value: 1},
+			{Key: "kind", Value: 1},
+		},
+	}
+	projectOwnerIndexModel := mongo.IndexModel{
+		Keys: bson.D{
+			{Key: "project_id", Value: 1},
+			{Key: "owner.name", Value: 1},
+		},
+	}
+	projectTagsIndexModel := mongo.IndexModel{
+		Keys: bson.D{
+			{Key: "project_id", Value: 1},
+			{Key: "tags", Value: 1},
+		},
+	}
+
+	// The flags of a project are searched by the words of their names and
+	// descriptions. The words are not stemmed, as the names of the flags are
+	// not written in any language.
+	projectTextIndexModel := mongo.IndexModel{
+		Keys: bson.D{
+			{Key: "project_id", Value: 1},
+			{Key: "name", Value: "text"},
+			{Key: "display_name", Value: "text"},
+			{Key: "description", Value: "text"},
+		},
+		Options: options.Index().SetDefaultLanguage("none"),
+	}
+
+	_, err := coll.Indexes().CreateMany(
+		ctx,
+		[]mongo.IndexModel{
+			flagProjectIndexModel,
+			projectIndexModel,
+			projectNameIndexModel,
+			projectCreatedAtIndexModel,
+			projectKindIndexModel,
+			projectOwnerIndexModel,
+			projectTagsIndexModel,
+			projectTextIndexModel,
+		},
+	)
 
 	return err
 }
@@ -5,6 +5,8 @@ import (
 	"errors"
 	"log"
 	"os"
+	"reflect"
+	"slices"
 	"testing"
 	"time"
 
@@ -14,6 +16,7 @@ import (
 
 	"github.com/waduhek/flagger/internal/flag"
 	"github.com/waduhek/flagger/internal/logger"
+	"github.com/waduhek/flagger/internal/pagination"
 )
 
 const mongoDBConnectionString string = "mongodb://localhost:27017"
@@ -164,6 +167,45 @@ func TestGetByNameAndProjectID(t *testing.T) {
 	}
 }
 
+func TestGetByNameAndProjectID_Variations(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 1*time.Second)
+	defer cancel()
+
+	stringFlag := &flag.Flag{
+		Name:      "test_string",
+		ProjectID: dummyObjectID,
+		Type:      flag.TypeString,
+		Variations: []flag.Variation{
+			{Key: "blue", Value: `"blue"`},
+			{Key: "green", Value: `"green"`},
+		},
+		DefaultVariation: "blue",
+		OffVariation:     "green",
+		CreatedBy:        dummyObjectID,
+		CreatedAt:        time.Now(),
+	}
+
+	_, saveErr := flagRepository.Save(ctx, stringFlag)
+	if saveErr != nil {
+		t.Fatalf("error while saving flag: %v", saveErr)
+	}
+
+	cleanupFlag(t, stringFlag)
+
+	gotFlag, getErr := flagRepository.GetByNameAndProjectID(ctx, stringFlag.Name, stringFlag.ProjectID)
+	if getErr != nil {
+		t.Fatalf("error while getting flag details: %v", getErr)
+	}
+
+	if gotFlag.Type != flag.TypeString {
+		t.Fatalf("expected flag type %q but got %q", flag.TypeString, gotFlag.Type)
+	}
+
+	if len(gotFlag.Variations) != len(stringFlag.Variations) {
+		t.Fatalf("expected %d variations but got %d", len(stringFlag.Variations), len(gotFlag.Variations))
+	}
+}
+
 func TestGetByNameAndProjectID_NoFlag(t *testing.T) {
 	ctx, cancel := context.WithTimeout(context.Background(), 1*time.Second)
 	defer cancel()
@@ -184,6 +226,383 @@ func TestGetByNameAndProjectID_InvalidProjectID(t *testing.T) {
 	}
 }
 
+func TestListByProjectID(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 1*time.Second)
+	defer cancel()
+
+	for _, name := range []string{"list-a", "list-b", "other"} {
+		f := &flag.Flag{
+			Name:      name,
+			ProjectID: dummyObjectID,
+			CreatedBy: dummyObjectID,
+			CreatedAt: time.Now(),
+		}
+
+		if _, saveErr := flagRepository.Save(ctx, f); saveErr != nil {
+			t.Fatalf("error while saving flag: %v", saveErr)
+		}
+
+		cleanupFlag(t, f)
+	}
+
+	query, queryErr := pagination.NewQuery(1, "", pagination.SortByName, true, "LIST")
+	if queryErr != nil {
+		t.Fatalf("unexpected error when creating query: %v", queryErr)
+	}
+
+	flags, nextPageToken, listErr := flagRepository.ListByProjectID(ctx, dummyObjectID, nil, query)
+	if listErr != nil {
+		t.Fatalf("error while listing flags: %v", listErr)
+	}
+
+	if len(flags) != 1 || flags[0].Name != "list-b" || nextPageToken == "" {
+		t.Fatalf("unexpected first page: %v", flags)
+	}
+
+	query, queryErr = pagination.NewQuery(1, nextPageToken, pagination.SortByName, true, "LIST")
+	if queryErr != nil {
+		t.Fatalf("unexpected error when creating query: %v", queryErr)
+	}
+
+	flags, nextPageToken, listErr = flagRepository.ListByProjectID(ctx, dummyObjectID, nil, query)
+	if listErr != nil {
+		t.Fatalf("error while listing flags: %v", listErr)
+	}
+
+	if len(flags) != 1 || flags[0].Name != "list-a" || nextPageToken != "" {
+		t.Fatalf("unexpected last page: %v", flags)
+	}
+}
+
+func TestListByProjectID_Filter(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 1*time.Second)
+	defer cancel()
+
+	payments := &flag.Owner{Type: flag.OwnerTypeTeam, Name: "payments"}
+	flags := []*flag.Flag{
+		{
+			Name: "new-checkout",
+			Metadata: flag.Metadata{
+				Description: "Serves the redesigned checkout page",
+				Owner:       payments,
+				Tags:        []string{"checkout", "web"},
+				Kind:        flag.KindRelease,
+			},
+		},
+		{
+			Name: "checkout-kill-switch",
+			Metadata: flag.Metadata{
+				Description: "Disables payments while the provider is down",
+				Owner:       payments,
+				Tags:        []string{"checkout"},
+				Kind:        flag.KindOps,
+			},
+		},
+		{Name: "dark-mode"},
+	}
+
+	for _, f := range flags {
+		f.ProjectID = dummyObjectID
+		f.CreatedBy = dummyObjectID
+
+		if _, saveErr := flagRepository.Save(ctx, f); saveErr != nil {
+			t.Fatalf("error while saving flag: %v", saveErr)
+		}
+
+		cleanupFlag(t, f)
+	}
+
+	testCases := []struct {
+		Name     string
+		Filter   *flag.ListFilter
+		Expected []string
+	}{
+		{
+			Name:     "kind",
+			Filter:   &flag.ListFilter{Kind: flag.KindOps},
+			Expected: []string{"checkout-kill-switch"},
+		},
+		{
+			Name:     "release_kind_includes_flags_without_kind",
+			Filter:   &flag.ListFilter{Kind: flag.KindRelease},
+			Expected: []string{"dark-mode", "new-checkout"},
+		},
+		{
+			Name:     "owner_and_tags",
+			Filter:   &flag.ListFilter{Owner: "payments", Tags: []string{"checkout", "web"}},
+			Expected: []string{"new-checkout"},
+		},
+		{
+			Name:     "search_names_and_descriptions",
+			Filter:   &flag.ListFilter{Search: "PAYMENTS dark"},
+			Expected: []string{"checkout-kill-switch", "dark-mode"},
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.Name, func(t *testing.T) {
+			query, queryErr := pagination.NewQuery(0, "", pagination.SortByName, false, "")
+			if queryErr != nil {
+				t.Fatalf("unexpected error when creating query: %v", queryErr)
+			}
+
+			listedFlags, _, listErr := flagRepository.ListByProjectID(ctx, dummyObjectID, testCase.Filter, query)
+			if listErr != nil {
+				t.Fatalf("error while listing flags: %v", listErr)
+			}
+
+			names := make([]string, 0, len(listedFlags))
+			for _, listedFlag := range listedFlags {
+				names = append(names, listedFlag.Name)
+			}
+
+			if !slices.Equal(names, testCase.Expected) {
+				t.Fatalf("expected flags %v but got %v", testCase.Expected, names)
+			}
+		})
+	}
+}
+
+func TestListByProjectID_InvalidProjectID(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 1*time.Second)
+	defer cancel()
+
+	query, queryErr := pagination.NewQuery(0, "", pagination.SortByName, false, "")
+	if queryErr != nil {
+		t.Fatalf("unexpected error when creating query: %v", queryErr)
+	}
+
+	_, _, listErr := flagRepository.ListByProjectID(ctx, "invalid_object_id", nil, query)
+	if !errors.Is(listErr, flag.ErrCouldNotFetch) {
+		t.Fatalf("expected error while listing flags")
+	}
+}
+
+func TestArchiveAndRestore(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 1*time.Second)
+	defer cancel()
+
+	savedID, saveErr := flagRepository.Save(ctx, dummyFlag)
+	if saveErr != nil {
+		t.Fatalf("error while saving flag: %v", saveErr)
+	}
+
+	cleanupFlag(t, dummyFlag)
+
+	archivedCount, archiveErr := flagRepository.Archive(ctx, savedID, flag.OffVariationKey)
+	if archiveErr != nil || archivedCount != 1 {
+		t.Fatalf("could not archive flag: %v", archiveErr)
+	}
+
+	archivedFlag, getErr := flagRepository.GetByID(ctx, savedID)
+	if getErr != nil {
+		t.Fatalf("error while getting flag: %v", getErr)
+	}
+
+	if !archivedFlag.IsArchived ||
+		archivedFlag.ArchivedVariation != flag.OffVariationKey ||
+		archivedFlag.ArchivedAt.IsZero() {
+		t.Fatalf("expected the flag to be archived but got %v", archivedFlag)
+	}
+
+	restoredCount, restoreErr := flagRepository.Restore(ctx, savedID)
+	if restoreErr != nil || restoredCount != 1 {
+		t.Fatalf("could not restore flag: %v", restoreErr)
+	}
+
+	restoredFlag, getErr := flagRepository.GetByID(ctx, savedID)
+	if getErr != nil {
+		t.Fatalf("error while getting flag: %v", getErr)
+	}
+
+	if restoredFlag.IsArchived || restoredFlag.ArchivedVariation != "" {
+		t.Fatalf("expected the flag to be restored but got %v", restoredFlag)
+	}
+}
+
+func TestUpdateMetadata(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 1*time.Second)
+	defer cancel()
+
+	savedID, saveErr := flagRepository.Save(ctx, dummyFlag)
+	if saveErr != nil {
+		t.Fatalf("error while saving flag: %v", saveErr)
+	}
+
+	cleanupFlag(t, dummyFlag)
+
+	metadata := &flag.Metadata{
+		Description: "A flag for testing",
+		Owner:       &flag.Owner{Type: flag.OwnerTypeUser, Name: "test"},
+		Tags:        []string{"test"},
+		Kind:        flag.KindExperiment,
+	}
+
+	updatedCount, updateErr := flagRepository.UpdateMetadata(ctx, savedID, metadata)
+	if updateErr != nil || updatedCount != 1 {
+		t.Fatalf("could not update flag metadata: %v", updateErr)
+	}
+
+	updatedFlag, getErr := flagRepository.GetByID(ctx, savedID)
+	if getErr != nil {
+		t.Fatalf("error while getting flag: %v", getErr)
+	}
+
+	if !reflect.DeepEqual(updatedFlag.Metadata, *metadata) {
+		t.Fatalf("expected metadata %v but got %v", *metadata, updatedFlag.Metadata)
+	}
+
+	// The metadata that is not provided is removed.
+	updatedCount, updateErr = flagRepository.UpdateMetadata(ctx, savedID, &flag.Metadata{Kind: flag.KindOps})
+	if updateErr != nil || updatedCount != 1 {
+		t.Fatalf("could not update flag metadata: %v", updateErr)
+	}
+
+	updatedFlag, getErr = flagRepository.GetByID(ctx, savedID)
+	if getErr != nil {
+		t.Fatalf("error while getting flag: %v", getErr)
+	}
+
+	if !reflect.DeepEqual(updatedFlag.Metadata, flag.Metadata{Kind: flag.KindOps}) {
+		t.Fatalf("expected the metadata to be removed but got %v", updatedFlag.Metadata)
+	}
+}
+
+func TestRename(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 1*time.Second)
+	defer cancel()
+
+	savedID, saveErr := flagRepository.Save(ctx, dummyFlag)
+	if saveErr != nil {
+		t.Fatalf("error while saving flag: %v", saveErr)
+	}
+
+	renamedFlag := &flag.Flag{Name: "test-renamed"}
+
+	cleanupFlag(t, dummyFlag)
+	cleanupFlag(t, renamedFlag)
+
+	aliasExpiresAt := time.Now().Add(time.Hour)
+
+	renamedCount, renameErr := flagRepository.Rename(ctx, savedID, renamedFlag.Name, "Test", aliasExpiresAt)
+	if renameErr != nil || renamedCount != 1 {
+		t.Fatalf("could not rename flag: %v", renameErr)
+	}
+
+	fetchedFlag, getErr := flagRepository.GetByID(ctx, savedID)
+	if getErr != nil {
+		t.Fatalf("error while getting flag: %v", getErr)
+	}
+
+	if fetchedFlag.Name != renamedFlag.Name || fetchedFlag.DisplayName != "Test" {
+		t.Fatalf("expected the flag to be renamed but got %v", fetchedFlag)
+	}
+
+	if len(fetchedFlag.Aliases) != 1 || fetchedFlag.Aliases[0].Name != dummyFlag.Name {
+		t.Fatalf("expected the previous name to be an alias but got %v", fetchedFlag.Aliases)
+	}
+
+	// Renaming the flag back to its previous name replaces the alias.
+	renamedCount, renameErr = flagRepository.Rename(ctx, savedID, dummyFlag.Name, "", aliasExpiresAt)
+	if renameErr != nil || renamedCount != 1 {
+		t.Fatalf("could not rename flag: %v", renameErr)
+	}
+
+	fetchedFlag, getErr = flagRepository.GetByID(ctx, savedID)
+	if getErr != nil {
+		t.Fatalf("error while getting flag: %v", getErr)
+	}
+
+	if fetchedFlag.Name != dummyFlag.Name || fetchedFlag.DisplayName != "Test" ||
+		len(fetchedFlag.Aliases) != 1 || fetchedFlag.Aliases[0].Name != renamedFlag.Name {
+		t.Fatalf("expected the flag to be renamed back but got %v", fetchedFlag)
+	}
+}
+
+func TestRename_NameTaken(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 1*time.Second)
+	defer cancel()
+
+	otherFlag := &flag.Flag{
+		Name:      "test-other",
+		ProjectID: dummyObjectID,
+		CreatedBy: dummyObjectID,
+		CreatedAt: time.Now(),
+	}
+
+	savedID, saveErr := flagRepository.Save(ctx, dummyFlag)
+	if saveErr != nil {
+		t.Fatalf("error while saving flag: %v", saveErr)
+	}
+
+	cleanupFlag(t, dummyFlag)
+
+	if _, saveErr = flagRepository.Save(ctx, otherFlag); saveErr != nil {
+		t.Fatalf("error while saving flag: %v", saveErr)
+	}
+
+	cleanupFlag(t, otherFlag)
+
+	_, renameErr := flagRepository.Rename(ctx, savedID, otherFlag.Name, "", time.Now().Add(time.Hour))
+	if !errors.Is(renameErr, flag.ErrNameTaken) {
+		t.Fatalf("expected ErrNameTaken but got %v", renameErr)
+	}
+}
+
+func TestDelete(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 1*time.Second)
+	defer cancel()
+
+	savedID, saveErr := flagRepository.Save(ctx, dummyFlag)
+	if saveErr != nil {
+		t.Fatalf("error while saving flag: %v", saveErr)
+	}
+
+	cleanupFlag(t, dummyFlag)
+
+	deletedCount, deleteErr := flagRepository.Delete(ctx, savedID)
+	if deleteErr != nil || deletedCount != 1 {
+		t.Fatalf("could not delete flag: %v", deleteErr)
+	}
+
+	_, getErr := flagRepository.GetByID(ctx, savedID)
+	if !errors.Is(getErr, flag.ErrNotFound) {
+		t.Fatalf("expected the deleted flag not to be found")
+	}
+}
+
+func TestDelete_InvalidFlagID(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 1*time.Second)
+	defer cancel()
+
+	_, deleteErr := flagRepository.Delete(ctx, "invalid_object_id")
+	if !errors.Is(deleteErr, flag.ErrCouldNotDelete) {
+		t.Fatalf("expected error while deleting flag")
+	}
+}
+
+func TestDeleteByProjectID(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 1*time.Second)
+	defer cancel()
+
+	savedID, saveErr := flagRepository.Save(ctx, dummyFlag)
+	if saveErr != nil {
+		t.Fatalf("error while saving flag: %v", saveErr)
+	}
+
+	cleanupFlag(t, dummyFlag)
+
+	deletedCount, deleteErr := flagRepository.DeleteByProjectID(ctx, dummyFlag.ProjectID)
+	if deleteErr != nil || deletedCount != 1 {
+		t.Fatalf("could not delete flags of project: %v", deleteErr)
+	}
+
+	_, getErr := flagRepository.GetByID(ctx, savedID)
+	if !errors.Is(getErr, flag.ErrNotFound) {
+		t.Fatalf("expected the deleted flag not to be found")
+	}
+}
+
 func TestMain(m *testing.M) {
 	client, mongoClientErr := getMongoClient()
 	if mongoClientErr != nil {
@@ -0,0 +1,129 @@
+package flag
+
+import (
+	"github.com/waduhek/flagger/proto/flagpb"
+
+	"github.com/waduhek/flagger/internal/targeting"
+)
+
+// operatorsFromProto maps the operators of a request to the operators of the
+// targeting rules.
+//
+//nolint:gochecknoglobals // This is a read-only lookup table.
+var operatorsFromProto = map[flagpb.RuleOperator]targeting.Operator{
+	flagpb.RuleOperator_RULE_OPERATOR_EQUALS:                targeting.OperatorEquals,
+	flagpb.RuleOperator_RULE_OPERATOR_NOT_EQUALS:            targeting.OperatorNotEquals,
+	flagpb.RuleOperator_RULE_OPERATOR_IN:                    targeting.OperatorIn,
+	flagpb.RuleOperator_RULE_OPERATOR_NOT_IN:                targeting.OperatorNotIn,
+	flagpb.RuleOperator_RULE_OPERATOR_CONTAINS:              targeting.OperatorContains,
+	flagpb.RuleOperator_RULE_OPERATOR_STARTS_WITH:           targeting.OperatorStartsWith,
+	flagpb.RuleOperator_RULE_OPERATOR_ENDS_WITH:             targeting.OperatorEndsWith,
+	flagpb.RuleOperator_RULE_OPERATOR_MATCHES:               targeting.OperatorMatches,
+	flagpb.RuleOperator_RULE_OPERATOR_LESS_THAN:             targeting.OperatorLessThan,
+	flagpb.RuleOperator_RULE_OPERATOR_LESS_THAN_OR_EQUAL:    targeting.OperatorLessThanOrEqual,
+	flagpb.RuleOperator_RULE_OPERATOR_GREATER_THAN:          targeting.OperatorGreaterThan,
+	flagpb.RuleOperator_RULE_OPERATOR_GREATER_THAN_OR_EQUAL: targeting.OperatorGreaterThanOrEqual,
+	flagpb.RuleOperator_RULE_OPERATOR_SEMVER_EQUAL:          targeting.OperatorSemverEqual,
+	flagpb.RuleOperator_RULE_OPERATOR_SEMVER_LESS_THAN:      targeting.OperatorSemverLessThan,
+	flagpb.RuleOperator_RULE_OPERATOR_SEMVER_GREATER_THAN:   targeting.OperatorSemverGreaterThan,
+	flagpb.RuleOperator_RULE_OPERATOR_BEFORE:                targeting.OperatorBefore,
+	flagpb.RuleOperator_RULE_OPERATOR_AFTER:                 targeting.OperatorAfter,
+}
+
+// rulesFromProto maps the rules of a request to targeting rules. Unknown
+// operators are mapped to an empty operator which fails validation.
+func rulesFromProto(rules []*flagpb.Rule) []targeting.Rule {
+	mapped := make([]targeting.Rule, 0, len(rules))
+
+	for _, rule := range rules {
+		conditions := make([]targeting.Condition, 0, len(rule.GetConditions()))
+
+		for _, condition := range rule.GetConditions() {
+			conditions = append(conditions, targeting.Condition{
+				Attribute: condition.GetAttribute(),
+				Operator:  operatorsFromProto[condition.GetOperator()],
+				Values:    condition.GetValues(),
+			})
+		}
+
+		mapped = append(mapped, targeting.Rule{
+			Conditions: conditions,
+			Variation:  rule.GetVariation(),
+		})
+	}
+
+	return mapped
+}
+
+// rulesToProto maps targeting rules to the rules of a response.
+func rulesToProto(rules []targeting.Rule) []*flagpb.Rule {
+	protoOperators := make(map[targeting.Operator]flagpb.RuleOperator, len(operatorsFromProto))
+	for protoOperator, operator := range operatorsFromProto {
+		protoOperators[operator] = protoOperator
+	}
+
+	mapped := make([]*flagpb.Rule, 0, len(rules))
+
+	for _, rule := range rules {
+		conditions := make([]*flagpb.Condition, 0, len(rule.Conditions))
+
+		for _, condition := range rule.Conditions {
+			conditions = append(conditions, &flagpb.Condition{
+				Attribute: condition.Attribute,
+				Operator:  protoOperators[condition.Operator],
+				Values:    condition.Values,
+			})
+		}
+
+		mapped = append(mapped, &flagpb.Rule{
+			Conditions: conditions,
+			Variation:  rule.Variation,
+		})
+	}
+
+	return mapped
+}
+
+// rolloutFromProto maps the rollout of a request to the weighted variations of
+// a percentage rollout.
+func rolloutFromProto(
+	rollout []*flagpb.WeightedVariation,
+) []targeting.WeightedVariation {
+	mapped := make([]targeting.WeightedVariation, 0, len(rollout))
+
+	for _, weightedVariation := range rollout {
+		mapped = append(mapped, targeting.WeightedVariation{
+			Variation: weightedVariation.GetVariation(),
+			Weight:    weightedVariation.GetWeight(),
+		})
+	}
+
+	return mapped
+}
+
+// rolloutToProto maps the weighted variations of a percentage rollout to the
+// rollout of a response.
+func rolloutToProto(
+	rollout []targeting.WeightedVariation,
+) []*flagpb.WeightedVariation {
+	mapped := make([]*flagpb.WeightedVariation, 0, len(rollout))
+
+	for _, weightedVariation := range rollout {
+		mapped = append(mapped, &flagpb.WeightedVariation{
+			Variation: weightedVariation.Variation,
+			Weight:    weightedVariation.Weight,
+		})
+	}
+
+	return mapped
+}
+
+// variationKeys returns the keys of all the variations of the flag.
+func (f *Flag) variationKeys() []string {
+	keys := make([]string, 0, len(f.Variations))
+	for _, variation := range f.Variations {
+		keys = append(keys, variation.Key)
+	}
+
+	return keys
+}
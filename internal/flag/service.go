@@ -2,17 +2,24 @@ package flag
 
 import (
 	"context"
+	"errors"
 	"time"
 
 	"go.mongodb.org/mongo-driver/mongo"
+	"google.golang.org/protobuf/types/known/timestamppb"
 
 	"github.com/waduhek/flagger/proto/flagpb"
 
 	"github.com/waduhek/flagger/internal/auth"
+	"github.com/waduhek/flagger/internal/changefeed"
 	"github.com/waduhek/flagger/internal/environment"
 	"github.com/waduhek/flagger/internal/flagsetting"
+	"github.com/waduhek/flagger/internal/flagview"
 	"github.com/waduhek/flagger/internal/logger"
+	"github.com/waduhek/flagger/internal/pagination"
 	"github.com/waduhek/flagger/internal/project"
+	"github.com/waduhek/flagger/internal/rulesetcache"
+	"github.com/waduhek/flagger/internal/targeting"
 	"github.com/waduhek/flagger/internal/user"
 )
 
@@ -24,7 +31,14 @@ type Server struct {
 	environmentDataRepo environment.DataRepository
 	flagDataRepo        DataRepository
 	flagSettingDataRepo flagsetting.DataRepository
-	logger              logger.Logger
+	flagViewDataRepo    flagview.DataRepository
+	changeFeedRepo      changefeed.Repository
+	cacheInvalidator    rulesetcache.Invalidator
+	cacheWarmer         rulesetcache.Warmer
+	// aliasPeriod is how long the previous name of a renamed flag still
+	// resolves for the SDKs.
+	aliasPeriod time.Duration
+	logger      logger.Logger
 }
 
 type mongoTxnCallback func(ctx mongo.SessionContext) (interface{}, error)
@@ -50,6 +64,22 @@ func (s *Server) CreateFlag(
 	projectName := req.GetProjectName()
 	flagName := req.GetFlagName()
 
+	// Validate the type and the variations of the flag before doing anything
+	// else.
+	newFlag, err := newFlagFromRequest(req)
+	if err != nil {
+		s.logger.Error("invalid type or variations for flag %q: %v", flagName, err)
+		return nil, err
+	}
+
+	metadata, err := s.getMetadata(ctx, req)
+	if err != nil {
+		s.logger.Error("invalid metadata for flag %q: %v", flagName, err)
+		return nil, err
+	}
+
+	newFlag.Metadata = *metadata
+
 	// Get the project that the flag is to be added to. If the project does not
 	// belong to the currently authenticated user, or if the project doesn't
 	// exist, return an error.
@@ -62,16 +92,6 @@ func (s *Server) CreateFlag(
 		return nil, err
 	}
 
-	// If there are no environments configured for the project, don't allow any
-	// flags to be created.
-	if len(fetchedProject.Environments) == 0 {
-		s.logger.Error(
-			"no environments are configured for the project %q cannot create flag",
-			projectName,
-		)
-		return nil, ErrNoEnvironments
-	}
-
 	// Start a transaction to save the flag.
 	txnSession, err := s.mongoClient.StartSession()
 	if err != nil {
@@ -81,7 +101,7 @@ func (s *Server) CreateFlag(
 
 	_, txnErr := txnSession.WithTransaction(
 		ctx,
-		s.handleCreateFlag(req, fetchedUser, fetchedProject),
+		s.handleCreateFlag(newFlag, fetchedUser, fetchedProject),
 	)
 	if txnErr != nil {
 		s.logger.Error("could not complete flag save transaction: %v", err)
@@ -93,36 +113,59 @@ func (s *Server) CreateFlag(
 		flagName,
 		projectName,
 	)
+	// The new flag is served in all the environments of the project, so it is
+	// preloaded into the cache ahead of its first evaluation.
+	change := &changefeed.Change{FlagName: flagName}
+	s.publishChange(ctx, fetchedProject.Key, change)
+	s.cacheWarmer.Warm(fetchedProject.Key, change)
+
 	return &flagpb.CreateFlagResponse{}, nil
 }
 
 // handleCreateFlag performs the transaction for saving the flag in the DB.
 func (s *Server) handleCreateFlag(
-	req *flagpb.CreateFlagRequest,
+	newFlag *Flag,
 	user *user.User,
 	fetchedProject *project.Project,
 ) mongoTxnCallback {
 	return func(ctx mongo.SessionContext) (interface{}, error) {
-		flagName := req.GetFlagName()
-
 		// Save the details of the flag.
-		newFlag := &Flag{
-			Name:      flagName,
-			ProjectID: fetchedProject.ID,
-			CreatedBy: user.ID,
-			CreatedAt: time.Now(),
-		}
+		newFlag.ProjectID = fetchedProject.ID
+		newFlag.CreatedBy = user.ID
+		newFlag.CreatedAt = time.Now()
 
 		savedFlagID, err := s.flagDataRepo.Save(ctx, newFlag)
 		if err != nil {
 			return nil, err
 		}
 
+		// Mark the project updated so that an environment that is
+		// concurrently added to the project either gets a flag setting for
+		// this flag or is seen by this transaction when it is retried.
+		_, markErr := s.projectDataRepo.MarkUpdated(ctx, fetchedProject.ID)
+		if markErr != nil {
+			return nil, markErr
+		}
+
 		// Get all the environments that the project has.
-		projectEnvIDs := fetchedProject.Environments
+		projectEnvIDs, err := s.environmentDataRepo.GetIDsByProjectID(ctx, fetchedProject.ID)
+		if err != nil {
+			return nil, err
+		}
+
+		// If there are no environments configured for the project, don't
+		// allow any flags to be created.
+		if len(projectEnvIDs) == 0 {
+			s.logger.Error(
+				"no environments are configured for the project %q cannot create flag",
+				fetchedProject.Name,
+			)
+			return nil, ErrNoEnvironments
+		}
 
 		// Create flag settings in all the environments created in the project.
-		// By default the flags will be enabled in all the environments.
+		// By default the flags will be enabled in all the environments and
+		// will serve the default variation of the flag.
 		var flagSettings []flagsetting.FlagSetting
 
 		for _, envID := range projectEnvIDs {
@@ -138,33 +181,16 @@ func (s *Server) handleCreateFlag(
 			flagSettings = append(flagSettings, setting)
 		}
 
-		flagSettingIDs, err := s.flagSettingDataRepo.SaveMany(
-			ctx,
-			flagSettings,
-		)
+		_, err = s.flagSettingDataRepo.SaveMany(ctx, flagSettings)
 		if err != nil {
 			s.logger.Error("error while saving flag settings: %v", err)
 			return nil, err
 		}
 
-		// Add the flag's object ID to the list of flags in the project.
-		_, projectFlagUpdateErr := s.projectDataRepo.AddFlag(
-			ctx,
-			fetchedProject.ID,
-			savedFlagID,
-		)
-		if projectFlagUpdateErr != nil {
-			return nil, projectFlagUpdateErr
-		}
-
-		// Add all the object IDs of the flag settings to the project.
-		_, projectSettingErr := s.projectDataRepo.AddFlagSettings(
-			ctx,
-			fetchedProject.ID,
-			flagSettingIDs...,
-		)
-		if projectSettingErr != nil {
-			return nil, projectSettingErr
+		// Create the views of the flag in all the environments.
+		_, refreshErr := s.flagViewDataRepo.Refresh(ctx, fetchedProject.ID, "", savedFlagID)
+		if refreshErr != nil {
+			return nil, refreshErr
 		}
 
 		return nil, nil
@@ -224,13 +250,142 @@ func (s *Server) UpdateFlagStatus(
 		return nil, err
 	}
 
-	// Update the flag setting to the desired value.
-	updatedCount, err := s.flagSettingDataRepo.UpdateIsActive(
+	if flag.IsArchived {
+		s.logger.Error("cannot update status of archived flag %q", flagName)
+		return nil, ErrArchived
+	}
+
+	// The rollout is only replaced when the request sets it. It can only split
+	// the evaluation contexts between variations declared on the flag.
+	var rollout []targeting.WeightedVariation
+	if req.GetRollout() != nil {
+		rollout = rolloutFromProto(req.GetRollout().GetVariations())
+		if err = targeting.ValidateRollout(rollout, flag.variationKeys()); err != nil {
+			s.logger.Error("invalid rollout for flag %q: %v", flagName, err)
+			return nil, err
+		}
+	}
+
+	txnSession, err := s.mongoClient.StartSession()
+	if err != nil {
+		s.logger.Error("could not start transaction to update flag status: %v", err)
+		return nil, ErrTxnSession
+	}
+	defer txnSession.EndSession(ctx)
+
+	txnResult, txnErr := txnSession.WithTransaction(
 		ctx,
-		fetchedProject.ID,
-		fetchedEnvironment.ID,
-		flag.ID,
-		isActive,
+		s.handleUpdateFlagStatus(
+			fetchedProject.ID,
+			fetchedEnvironment.ID,
+			flag.ID,
+			isActive,
+			rollout,
+		),
+	)
+	if txnErr != nil {
+		s.logger.Error("could not complete flag status update transaction: %v", txnErr)
+		return nil, txnErr
+	}
+
+	if updatedCount, isCount := txnResult.(uint); !isCount || updatedCount == 0 {
+		s.logger.Error(
+			"no flag settings were updated for project %q, environment %q, flag %q",
+			projectName,
+			environmentName,
+			flagName,
+		)
+
+		return nil, ErrUpdateStatus
+	}
+
+	s.publishChange(ctx, fetchedProject.Key, &changefeed.Change{
+		EnvironmentName: environmentName,
+		FlagName:        flagName,
+	})
+
+	return &flagpb.UpdateFlagStatusResponse{}, nil
+}
+
+// handleUpdateFlagStatus performs the transaction for updating the status of
+// a flag setting. The rollout of the flag setting is replaced along with the
+// status when it is not nil. The number of updated flag settings is returned
+// from the transaction.
+func (s *Server) handleUpdateFlagStatus(
+	projectID string,
+	environmentID string,
+	flagID string,
+	isActive bool,
+	rollout []targeting.WeightedVariation,
+) mongoTxnCallback {
+	return func(ctx mongo.SessionContext) (interface{}, error) {
+		var updatedCount uint
+		var err error
+
+		if rollout == nil {
+			updatedCount, err = s.flagSettingDataRepo.UpdateIsActive(
+				ctx,
+				projectID,
+				environmentID,
+				flagID,
+				isActive,
+			)
+		} else {
+			updatedCount, err = s.flagSettingDataRepo.UpdateIsActiveAndRollout(
+				ctx,
+				projectID,
+				environmentID,
+				flagID,
+				isActive,
+				rollout,
+			)
+		}
+
+		if err != nil {
+			return nil, err
+		}
+
+		return s.refreshFlagView(ctx, projectID, environmentID, flagID, updatedCount)
+	}
+}
+
+func (s *Server) UpdateFlagVariation(
+	ctx context.Context,
+	req *flagpb.UpdateFlagVariationRequest,
+) (*flagpb.UpdateFlagVariationResponse, error) {
+	projectName := req.GetProjectName()
+	environmentName := req.GetEnvironmentName()
+	flagName := req.GetFlagName()
+	variation := req.GetVariation()
+
+	target, err := s.getFlagTarget(ctx, projectName, environmentName, flagName)
+	if err != nil {
+		return nil, err
+	}
+
+	if target.flag.IsArchived {
+		s.logger.Error("cannot update variation of archived flag %q", flagName)
+		return nil, ErrArchived
+	}
+
+	// Only variations that have been declared on the flag can be served.
+	if _, hasVariation := target.flag.VariationByKey(variation); !hasVariation {
+		s.logger.Error("variation %q not found on flag %q", variation, flagName)
+		return nil, ErrVariationNotFound
+	}
+
+	updatedCount, err := s.updateFlagSetting(
+		ctx,
+		target,
+		func(ctx mongo.SessionContext) (uint, error) {
+			return s.flagSettingDataRepo.UpdateVariation(
+				ctx,
+				target.project.ID,
+				target.environment.ID,
+				target.flag.ID,
+				variation,
+			)
+		},
 	)
 	if err != nil {
 		return nil, err
@@ -244,10 +399,697 @@ func (s *Server) UpdateFlagStatus(
 			flagName,
 		)
 
-		return nil, ErrUpdateStatus
+		return nil, ErrUpdateVariation
 	}
 
-	return &flagpb.UpdateFlagStatusResponse{}, nil
+	s.publishChange(ctx, target.project.Key, &changefeed.Change{
+		EnvironmentName: environmentName,
+		FlagName:        flagName,
+	})
+
+	return &flagpb.UpdateFlagVariationResponse{}, nil
+}
+
+func (s *Server) GetFlagRules(
+	ctx context.Context,
+	req *flagpb.GetFlagRulesRequest,
+) (*flagpb.GetFlagRulesResponse, error) {
+	target, err := s.getFlagTarget(
+		ctx,
+		req.GetProjectName(),
+		req.GetEnvironmentName(),
+		req.GetFlagName(),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	setting, err := s.flagSettingDataRepo.Get(
+		ctx,
+		target.project.ID,
+		target.environment.ID,
+		target.flag.ID,
+	)
+	if err != nil {
+		s.logger.Error("could not get flag setting: %v", err)
+		return nil, flagsetting.ErrCouldNotGet
+	}
+
+	response := &flagpb.GetFlagRulesResponse{
+		Rules: rulesToProto(setting.Rules),
+	}
+
+	return response, nil
+}
+
+func (s *Server) UpdateFlagRules(
+	ctx context.Context,
+	req *flagpb.UpdateFlagRulesRequest,
+) (*flagpb.UpdateFlagRulesResponse, error) {
+	projectName := req.GetProjectName()
+	environmentName := req.GetEnvironmentName()
+	flagName := req.GetFlagName()
+
+	target, err := s.getFlagTarget(ctx, projectName, environmentName, flagName)
+	if err != nil {
+		return nil, err
+	}
+
+	if target.flag.IsArchived {
+		s.logger.Error("cannot update rules of archived flag %q", flagName)
+		return nil, ErrArchived
+	}
+
+	rules := rulesFromProto(req.GetRules())
+
+	// Every rule must be valid and serve a variation of the flag.
+	if validationErr := targeting.Validate(
+		rules,
+		target.flag.variationKeys(),
+	); validationErr != nil {
+		s.logger.Error("invalid rules for flag %q: %v", flagName, validationErr)
+		return nil, validationErr
+	}
+
+	updatedCount, err := s.updateFlagSetting(
+		ctx,
+		target,
+		func(ctx mongo.SessionContext) (uint, error) {
+			return s.flagSettingDataRepo.UpdateRules(
+				ctx,
+				target.project.ID,
+				target.environment.ID,
+				target.flag.ID,
+				rules,
+			)
+		},
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if updatedCount == 0 {
+		s.logger.Error(
+			"no flag settings were updated for project %q, environment %q, flag %q",
+			projectName,
+			environmentName,
+			flagName,
+		)
+
+		return nil, ErrUpdateRules
+	}
+
+	s.publishChange(ctx, target.project.Key, &changefeed.Change{
+		EnvironmentName: environmentName,
+		FlagName:        flagName,
+	})
+
+	return &flagpb.UpdateFlagRulesResponse{}, nil
+}
+
+func (s *Server) ListFlags(
+	ctx context.Context,
+	req *flagpb.ListFlagsRequest,
+) (*flagpb.ListFlagsResponse, error) {
+	query, err := pagination.NewQuery(
+		req.GetPageSize(),
+		req.GetPageToken(),
+		pagination.SortField(req.GetSortBy()),
+		req.GetDescending(),
+		req.GetNameFilter(),
+	)
+	if err != nil {
+		s.logger.Error("invalid page of flags requested: %v", err)
+		return nil, err
+	}
+
+	filter, err := listFilterFromProto(req)
+	if err != nil {
+		s.logger.Error("invalid filter of flags requested: %v", err)
+		return nil, err
+	}
+
+	// The page tokens are only valid for the same filter.
+	if err = query.SetFilters(filter.encode()); err != nil {
+		s.logger.Error("page token was issued for another filter of flags")
+		return nil, err
+	}
+
+	fetchedProject, err := s.getProject(ctx, req.GetProjectName())
+	if err != nil {
+		return nil, err
+	}
+
+	flags, nextPageToken, err := s.flagDataRepo.ListByProjectID(ctx, fetchedProject.ID, filter, query)
+	if err != nil {
+		return nil, err
+	}
+
+	response := &flagpb.ListFlagsResponse{
+		Flags:         make([]*flagpb.FlagDetails, 0, len(flags)),
+		NextPageToken: nextPageToken,
+	}
+
+	for i := range flags {
+		details, detailsErr := flagToProto(&flags[i])
+		if detailsErr != nil {
+			s.logger.Error("could not map flag %q: %v", flags[i].Name, detailsErr)
+			return nil, ErrCouldNotFetch
+		}
+
+		response.Flags = append(response.Flags, details)
+	}
+
+	return response, nil
+}
+
+func (s *Server) GetFlag(
+	ctx context.Context,
+	req *flagpb.GetFlagRequest,
+) (*flagpb.GetFlagResponse, error) {
+	fetchedProject, err := s.getProject(ctx, req.GetProjectName())
+	if err != nil {
+		return nil, err
+	}
+
+	fetchedFlag, err := s.flagDataRepo.GetByNameAndProjectID(
+		ctx,
+		req.GetFlagName(),
+		fetchedProject.ID,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	details, err := flagToProto(fetchedFlag)
+	if err != nil {
+		s.logger.Error("could not map flag %q: %v", fetchedFlag.Name, err)
+		return nil, ErrCouldNotFetch
+	}
+
+	environments, err := s.environmentDataRepo.GetAllByProjectID(ctx, fetchedProject.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	settings, err := s.flagSettingDataRepo.GetByFlagID(ctx, fetchedProject.ID, fetchedFlag.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	settingsByEnvironmentID := make(map[string]*flagsetting.FlagSetting, len(settings))
+	for i := range settings {
+		settingsByEnvironmentID[settings[i].EnvironmentID] = &settings[i]
+	}
+
+	response := &flagpb.GetFlagResponse{
+		Flag:         details,
+		Environments: make([]*flagpb.FlagEnvironmentStatus, 0, len(environments)),
+	}
+
+	// The environments are sorted by their name.
+	for _, environment := range environments {
+		setting, ok := settingsByEnvironmentID[environment.ID]
+		if !ok {
+			s.logger.Warn("flag %q has no setting in environment %q", fetchedFlag.Name, environment.Name)
+			continue
+		}
+
+		// A setting without a variation serves the default variation of the
+		// flag.
+		variation := setting.Variation
+		if variation == "" {
+			variation = fetchedFlag.DefaultVariation
+		}
+
+		response.Environments = append(response.Environments, &flagpb.FlagEnvironmentStatus{
+			EnvironmentName: environment.Name,
+			IsActive:        setting.IsActive,
+			Variation:       variation,
+			Rules:           rulesToProto(setting.Rules),
+			Rollout:         rolloutToProto(setting.Rollout),
+			Version:         setting.Version,
+			UpdatedAt:       timestamppb.New(setting.UpdatedAt),
+		})
+	}
+
+	return response, nil
+}
+
+func (s *Server) ArchiveFlag(
+	ctx context.Context,
+	req *flagpb.ArchiveFlagRequest,
+) (*flagpb.ArchiveFlagResponse, error) {
+	projectName := req.GetProjectName()
+	flagName := req.GetFlagName()
+
+	fetchedProject, fetchedFlag, err := s.getProjectFlag(ctx, projectName, flagName)
+	if err != nil {
+		return nil, err
+	}
+
+	// The archived variation defaults to the off variation of the flag and
+	// must have been declared on the flag.
+	variation := req.GetVariation()
+	if variation == "" {
+		variation = fetchedFlag.OffVariation
+	}
+
+	if _, hasVariation := fetchedFlag.VariationByKey(variation); !hasVariation {
+		s.logger.Error("variation %q not found on flag %q", variation, flagName)
+		return nil, ErrVariationNotFound
+	}
+
+	err = s.updateFlag(ctx, fetchedProject, fetchedFlag, func(ctx mongo.SessionContext) (uint, error) {
+		return s.flagDataRepo.Archive(ctx, fetchedFlag.ID, variation)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	s.logger.Info("archived the flag %q in project %q", flagName, projectName)
+	s.publishChange(ctx, fetchedProject.Key, &changefeed.Change{FlagName: flagName})
+
+	return &flagpb.ArchiveFlagResponse{}, nil
+}
+
+func (s *Server) RestoreFlag(
+	ctx context.Context,
+	req *flagpb.RestoreFlagRequest,
+) (*flagpb.RestoreFlagResponse, error) {
+	projectName := req.GetProjectName()
+	flagName := req.GetFlagName()
+
+	fetchedProject, fetchedFlag, err := s.getProjectFlag(ctx, projectName, flagName)
+	if err != nil {
+		return nil, err
+	}
+
+	if !fetchedFlag.IsArchived {
+		s.logger.Error("cannot restore flag %q as it is not archived", flagName)
+		return nil, ErrNotArchived
+	}
+
+	err = s.updateFlag(ctx, fetchedProject, fetchedFlag, func(ctx mongo.SessionContext) (uint, error) {
+		return s.flagDataRepo.Restore(ctx, fetchedFlag.ID)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	s.logger.Info("restored the flag %q in project %q", flagName, projectName)
+	s.publishChange(ctx, fetchedProject.Key, &changefeed.Change{FlagName: flagName})
+
+	return &flagpb.RestoreFlagResponse{}, nil
+}
+
+func (s *Server) RenameFlag(
+	ctx context.Context,
+	req *flagpb.RenameFlagRequest,
+) (*flagpb.RenameFlagResponse, error) {
+	projectName := req.GetProjectName()
+	flagName := req.GetFlagName()
+	newFlagName := req.GetNewFlagName()
+	displayName := req.GetDisplayName()
+
+	fetchedProject, fetchedFlag, err := s.getProjectFlag(ctx, projectName, flagName)
+	if err != nil {
+		return nil, err
+	}
+
+	isRenamed := newFlagName != "" && newFlagName != fetchedFlag.Name
+	if !isRenamed && (displayName == "" || displayName == fetchedFlag.DisplayName) {
+		s.logger.Error("no new name or display name was provided for flag %q", flagName)
+		return nil, ErrNothingToRename
+	}
+
+	// The SDKs can still get the flag by its previous name until the alias
+	// expires, which gives them time to move to the new name.
+	var aliasExpiresAt time.Time
+	if isRenamed {
+		aliasExpiresAt = time.Now().Add(s.aliasPeriod)
+	}
+
+	err = s.updateFlag(ctx, fetchedProject, fetchedFlag, func(ctx mongo.SessionContext) (uint, error) {
+		return s.flagDataRepo.Rename(ctx, fetchedFlag.ID, newFlagName, displayName, aliasExpiresAt)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	response := &flagpb.RenameFlagResponse{}
+
+	if isRenamed {
+		s.logger.Info("renamed the flag %q to %q in project %q", flagName, newFlagName, projectName)
+
+		// The rule sets cached by either name are stale, as the previous name
+		// now resolves to the renamed flag and the new name was not found.
+		s.publishChange(ctx, fetchedProject.Key, &changefeed.Change{FlagName: flagName})
+		s.publishChange(ctx, fetchedProject.Key, &changefeed.Change{FlagName: newFlagName})
+
+		response.AliasExpiresAt = timestamppb.New(aliasExpiresAt)
+	}
+
+	return response, nil
+}
+
+func (s *Server) UpdateFlagMetadata(
+	ctx context.Context,
+	req *flagpb.UpdateFlagMetadataRequest,
+) (*flagpb.UpdateFlagMetadataResponse, error) {
+	projectName := req.GetProjectName()
+	flagName := req.GetFlagName()
+
+	metadata, err := s.getMetadata(ctx, req)
+	if err != nil {
+		s.logger.Error("invalid metadata for flag %q: %v", flagName, err)
+		return nil, err
+	}
+
+	_, fetchedFlag, err := s.getProjectFlag(ctx, projectName, flagName)
+	if err != nil {
+		return nil, err
+	}
+
+	// The metadata is not evaluated, so the views and the cached rule sets of
+	// the flag are not affected by the update.
+	if _, err = s.flagDataRepo.UpdateMetadata(ctx, fetchedFlag.ID, metadata); err != nil {
+		return nil, err
+	}
+
+	updatedFlag, err := s.flagDataRepo.GetByID(ctx, fetchedFlag.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	details, err := flagToProto(updatedFlag)
+	if err != nil {
+		s.logger.Error("could not map flag %q: %v", updatedFlag.Name, err)
+		return nil, ErrCouldNotFetch
+	}
+
+	s.logger.Info("updated the metadata of the flag %q in project %q", flagName, projectName)
+
+	return &flagpb.UpdateFlagMetadataResponse{Flag: details}, nil
+}
+
+func (s *Server) DeleteFlag(
+	ctx context.Context,
+	req *flagpb.DeleteFlagRequest,
+) (*flagpb.DeleteFlagResponse, error) {
+	projectName := req.GetProjectName()
+	flagName := req.GetFlagName()
+
+	fetchedProject, fetchedFlag, err := s.getProjectFlag(ctx, projectName, flagName)
+	if err != nil {
+		return nil, err
+	}
+
+	txnSession, err := s.mongoClient.StartSession()
+	if err != nil {
+		s.logger.Error("could not start transaction to delete flag: %v", err)
+		return nil, ErrTxnSession
+	}
+	defer txnSession.EndSession(ctx)
+
+	_, txnErr := txnSession.WithTransaction(ctx, s.handleDeleteFlag(fetchedProject, fetchedFlag))
+	if txnErr != nil {
+		s.logger.Error("could not complete flag delete transaction: %v", txnErr)
+		return nil, txnErr
+	}
+
+	s.logger.Info("deleted the flag %q in project %q", flagName, projectName)
+
+	// The deleted flag must not be served from the last known good rule sets
+	// while the database fails either.
+	change := &changefeed.Change{FlagName: flagName}
+	if purgeErr := s.cacheInvalidator.Purge(ctx, fetchedProject.Key, change); purgeErr != nil {
+		s.logger.Error("could not purge cached rule sets of flag %q: %v", flagName, purgeErr)
+	}
+
+	s.publishChange(ctx, fetchedProject.Key, change)
+
+	return &flagpb.DeleteFlagResponse{}, nil
+}
+
+// handleDeleteFlag performs the transaction for deleting the flag along with
+// its settings and views in all the environments.
+func (s *Server) handleDeleteFlag(
+	fetchedProject *project.Project,
+	fetchedFlag *Flag,
+) mongoTxnCallback {
+	return func(ctx mongo.SessionContext) (interface{}, error) {
+		if _, err := s.flagSettingDataRepo.DeleteByFlagID(ctx, fetchedProject.ID, fetchedFlag.ID); err != nil {
+			return nil, err
+		}
+
+		deletedCount, err := s.flagDataRepo.Delete(ctx, fetchedFlag.ID)
+		if err != nil {
+			return nil, err
+		}
+
+		if deletedCount == 0 {
+			s.logger.Error("flag %q was deleted concurrently", fetchedFlag.Name)
+			return nil, ErrNotFound
+		}
+
+		// Mark the project updated so that an environment that is
+		// concurrently added to the project doesn't get a setting for the
+		// deleted flag.
+		if _, err = s.projectDataRepo.MarkUpdated(ctx, fetchedProject.ID); err != nil {
+			return nil, err
+		}
+
+		// Remove the views of the flag, as it no longer has any settings.
+		if _, err = s.flagViewDataRepo.Refresh(ctx, fetchedProject.ID, "", fetchedFlag.ID); err != nil {
+			return nil, err
+		}
+
+		return nil, nil
+	}
+}
+
+// flagTarget is a flag along with the project and the environment that an
+// update to its settings applies to.
+type flagTarget struct {
+	project     *project.Project
+	environment *environment.Environment
+	flag        *Flag
+}
+
+// getFlagTarget fetches the project of the currently authenticated user along
+// with the environment and the flag in the project.
+func (s *Server) getFlagTarget(
+	ctx context.Context,
+	projectName string,
+	environmentName string,
+	flagName string,
+) (*flagTarget, error) {
+	fetchedProject, err := s.getProject(ctx, projectName)
+	if err != nil {
+		return nil, err
+	}
+
+	fetchedEnvironment, err := s.environmentDataRepo.GetByNameAndProjectID(
+		ctx,
+		environmentName,
+		fetchedProject.ID,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	fetchedFlag, err := s.flagDataRepo.GetByNameAndProjectID(
+		ctx,
+		flagName,
+		fetchedProject.ID,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	target := &flagTarget{
+		project:     fetchedProject,
+		environment: fetchedEnvironment,
+		flag:        fetchedFlag,
+	}
+
+	return target, nil
+}
+
+// getProjectFlag fetches the project of the currently authenticated user along
+// with the flag in the project.
+func (s *Server) getProjectFlag(
+	ctx context.Context,
+	projectName string,
+	flagName string,
+) (*project.Project, *Flag, error) {
+	fetchedProject, err := s.getProject(ctx, projectName)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	fetchedFlag, err := s.flagDataRepo.GetByNameAndProjectID(ctx, flagName, fetchedProject.ID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return fetchedProject, fetchedFlag, nil
+}
+
+// getProject fetches the project of the currently authenticated user.
+func (s *Server) getProject(ctx context.Context, projectName string) (*project.Project, error) {
+	// Get the details of the currently authenticated user from the JWT.
+	jwtClaims, ok := auth.ClaimsFromContext(ctx)
+	if !ok {
+		s.logger.Error("could not get token claims")
+		return nil, auth.ErrNoTokenClaims
+	}
+
+	fetchedUser, err := s.userDataRepo.GetByUsername(ctx, jwtClaims.Subject)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.projectDataRepo.GetByNameAndUserID(ctx, projectName, fetchedUser.ID)
+}
+
+// getMetadata validates the metadata of the request. A user that is to own the
+// flag must exist.
+func (s *Server) getMetadata(ctx context.Context, req metadataRequest) (*Metadata, error) {
+	metadata, err := metadataFromProto(req)
+	if err != nil {
+		return nil, err
+	}
+
+	if metadata.Owner != nil && metadata.Owner.Type == OwnerTypeUser {
+		_, err = s.userDataRepo.GetByUsername(ctx, metadata.Owner.Name)
+		if errors.Is(err, user.ErrNotFound) {
+			return nil, ErrOwnerNotFound
+		}
+
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return metadata, nil
+}
+
+// updateFlagSetting performs the update of the flag setting of the target and
+// the refresh of its view in a transaction. The number of updated flag settings
+// is returned.
+func (s *Server) updateFlagSetting(
+	ctx context.Context,
+	target *flagTarget,
+	update func(ctx mongo.SessionContext) (uint, error),
+) (uint, error) {
+	txnSession, err := s.mongoClient.StartSession()
+	if err != nil {
+		s.logger.Error("could not start transaction to update flag setting: %v", err)
+		return 0, ErrTxnSession
+	}
+	defer txnSession.EndSession(ctx)
+
+	txnResult, txnErr := txnSession.WithTransaction(
+		ctx,
+		func(ctx mongo.SessionContext) (interface{}, error) {
+			updatedCount, updateErr := update(ctx)
+			if updateErr != nil {
+				return nil, updateErr
+			}
+
+			return s.refreshFlagView(
+				ctx,
+				target.project.ID,
+				target.environment.ID,
+				target.flag.ID,
+				updatedCount,
+			)
+		},
+	)
+	if txnErr != nil {
+		s.logger.Error("could not complete flag setting update transaction: %v", txnErr)
+		return 0, txnErr
+	}
+
+	updatedCount, _ := txnResult.(uint)
+
+	return updatedCount, nil
+}
+
+// updateFlag performs the update of the flag and the refresh of its views in
+// all the environments in a transaction. Returns ErrNotFound if the flag was
+// not updated.
+func (s *Server) updateFlag(
+	ctx context.Context,
+	fetchedProject *project.Project,
+	fetchedFlag *Flag,
+	update func(ctx mongo.SessionContext) (uint, error),
+) error {
+	txnSession, err := s.mongoClient.StartSession()
+	if err != nil {
+		s.logger.Error("could not start transaction to update flag: %v", err)
+		return ErrTxnSession
+	}
+	defer txnSession.EndSession(ctx)
+
+	_, txnErr := txnSession.WithTransaction(
+		ctx,
+		func(ctx mongo.SessionContext) (interface{}, error) {
+			updatedCount, updateErr := update(ctx)
+			if updateErr != nil {
+				return nil, updateErr
+			}
+
+			if updatedCount == 0 {
+				s.logger.Error("flag %q was not updated", fetchedFlag.Name)
+				return nil, ErrNotFound
+			}
+
+			return s.flagViewDataRepo.Refresh(ctx, fetchedProject.ID, "", fetchedFlag.ID)
+		},
+	)
+	if txnErr != nil {
+		s.logger.Error("could not complete flag update transaction: %v", txnErr)
+		return txnErr
+	}
+
+	return nil
+}
+
+// refreshFlagView refreshes the view of the flag setting and passes on the
+// number of updated flag settings.
+func (s *Server) refreshFlagView(
+	ctx context.Context,
+	projectID string,
+	environmentID string,
+	flagID string,
+	updatedCount uint,
+) (uint, error) {
+	if _, err := s.flagViewDataRepo.Refresh(ctx, projectID, environmentID, flagID); err != nil {
+		return 0, err
+	}
+
+	return updatedCount, nil
+}
+
+// publishChange removes the cached rule sets affected by the change, so that
+// the next evaluation of the flag reads the change, and publishes the change
+// to the feed of the project so that the clients watching the flags receive
+// it. The change has already been saved, so failures are only logged.
+func (s *Server) publishChange(
+	ctx context.Context,
+	projectKey string,
+	change *changefeed.Change,
+) {
+	if err := s.cacheInvalidator.Invalidate(ctx, projectKey, change); err != nil {
+		s.logger.Error("could not invalidate cached rule sets of flag %q: %v", change.FlagName, err)
+	}
+
+	if _, err := s.changeFeedRepo.Publish(ctx, projectKey, change); err != nil {
+		s.logger.Warn("could not publish change to flag %q: %v", change.FlagName, err)
+	}
 }
 
 // NewFlagServer creates a new `FlagServer` for serving GRPC requests.
@@ -258,6 +1100,11 @@ func NewFlagServer(
 	environmentDataRepo environment.DataRepository,
 	flagDataRepo DataRepository,
 	flagSettingDataRepo flagsetting.DataRepository,
+	flagViewDataRepo flagview.DataRepository,
+	changeFeedRepo changefeed.Repository,
+	cacheInvalidator rulesetcache.Invalidator,
+	cacheWarmer rulesetcache.Warmer,
+	aliasPeriod time.Duration,
 	logger logger.Logger,
 ) *Server {
 	return &Server{
@@ -267,6 +1114,11 @@ func NewFlagServer(
 		environmentDataRepo: environmentDataRepo,
 		flagDataRepo:        flagDataRepo,
 		flagSettingDataRepo: flagSettingDataRepo,
+		flagViewDataRepo:    flagViewDataRepo,
+		changeFeedRepo:      changeFeedRepo,
+		cacheInvalidator:    cacheInvalidator,
+		cacheWarmer:         cacheWarmer,
+		aliasPeriod:         aliasPeriod,
 		logger:              logger,
 	}
 }
@@ -0,0 +1,283 @@
+package flag_test
+
+import (
+	"context"
+	"slices"
+	"sync"
+	"testing"
+
+	"github.com/golang-jwt/jwt/v5"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"github.com/waduhek/flagger/proto/flagpb"
+
+	"github.com/waduhek/flagger/internal/auth"
+	"github.com/waduhek/flagger/internal/changefeed"
+	"github.com/waduhek/flagger/internal/environment"
+	"github.com/waduhek/flagger/internal/flag"
+	"github.com/waduhek/flagger/internal/flagsetting"
+	"github.com/waduhek/flagger/internal/flagview"
+	"github.com/waduhek/flagger/internal/logger"
+	"github.com/waduhek/flagger/internal/project"
+	"github.com/waduhek/flagger/internal/rulesetcache"
+	"github.com/waduhek/flagger/internal/targeting"
+	"github.com/waduhek/flagger/internal/user"
+)
+
+const serviceTestProjectKey = "flag-service-test-project"
+
+// recordingInvalidator records the patterns of the cached rule sets that are
+// invalidated and purged.
+type recordingInvalidator struct {
+	mu       sync.Mutex
+	patterns []string
+}
+
+func (i *recordingInvalidator) Invalidate(_ context.Context, projectKey string, change *changefeed.Change) error {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	i.patterns = append(i.patterns, rulesetcache.Pattern(projectKey, change))
+
+	return nil
+}
+
+func (i *recordingInvalidator) Purge(ctx context.Context, projectKey string, change *changefeed.Change) error {
+	return i.Invalidate(ctx, projectKey, change)
+}
+
+// noopWarmer drops the requests to warm the cache.
+type noopWarmer struct{}
+
+func (noopWarmer) Warm(string, *changefeed.Change) {}
+
+// The fake repositories serve the operations of the tested RPCs from memory.
+// The other operations of the repositories are not implemented.
+type fakeUserRepository struct {
+	user.DataRepository
+}
+
+func (fakeUserRepository) GetByUsername(_ context.Context, username string) (*user.User, error) {
+	return &user.User{ID: primitive.NewObjectID().Hex(), Username: username}, nil
+}
+
+type fakeProjectRepository struct {
+	project.DataRepository
+}
+
+func (fakeProjectRepository) GetByNameAndUserID(
+	_ context.Context,
+	projectName string,
+	_ string,
+) (*project.Project, error) {
+	return &project.Project{ID: primitive.NewObjectID().Hex(), Key: serviceTestProjectKey, Name: projectName}, nil
+}
+
+func (fakeProjectRepository) MarkUpdated(context.Context, string) (uint, error) {
+	return 1, nil
+}
+
+type fakeEnvironmentRepository struct {
+	environment.DataRepository
+}
+
+func (fakeEnvironmentRepository) GetIDsByProjectID(context.Context, string) ([]string, error) {
+	return []string{primitive.NewObjectID().Hex(), primitive.NewObjectID().Hex()}, nil
+}
+
+func (fakeEnvironmentRepository) GetByNameAndProjectID(
+	_ context.Context,
+	environmentName string,
+	projectID string,
+) (*environment.Environment, error) {
+	return &environment.Environment{ID: primitive.NewObjectID().Hex(), Name: environmentName, ProjectID: projectID}, nil
+}
+
+type fakeFlagRepository struct {
+	flag.DataRepository
+}
+
+func (fakeFlagRepository) Save(context.Context, *flag.Flag) (string, error) {
+	return primitive.NewObjectID().Hex(), nil
+}
+
+func (fakeFlagRepository) GetByNameAndProjectID(
+	_ context.Context,
+	flagName string,
+	projectID string,
+) (*flag.Flag, error) {
+	fetchedFlag := &flag.Flag{
+		ID:        primitive.NewObjectID().Hex(),
+		Name:      flagName,
+		ProjectID: projectID,
+		Type:      flag.TypeBoolean,
+		Variations: []flag.Variation{
+			{Key: flag.OnVariationKey, Value: "true"},
+			{Key: flag.OffVariationKey, Value: "false"},
+		},
+		DefaultVariation: flag.OnVariationKey,
+		OffVariation:     flag.OffVariationKey,
+	}
+
+	return fetchedFlag, nil
+}
+
+// fakeFlagSettingRepository records the fields of the flag settings that are
+// updated.
+type fakeFlagSettingRepository struct {
+	flagsetting.DataRepository
+
+	updates []string
+}
+
+func (r *fakeFlagSettingRepository) SaveMany(
+	_ context.Context,
+	flagSettings []flagsetting.FlagSetting,
+) ([]string, error) {
+	ids := make([]string, 0, len(flagSettings))
+	for range flagSettings {
+		ids = append(ids, primitive.NewObjectID().Hex())
+	}
+
+	return ids, nil
+}
+
+func (r *fakeFlagSettingRepository) UpdateIsActive(context.Context, string, string, string, bool) (uint, error) {
+	r.updates = append(r.updates, "is_active")
+	return 1, nil
+}
+
+func (r *fakeFlagSettingRepository) UpdateIsActiveAndRollout(
+	context.Context,
+	string,
+	string,
+	string,
+	bool,
+	[]targeting.WeightedVariation,
+) (uint, error) {
+	r.updates = append(r.updates, "is_active,rollout")
+	return 1, nil
+}
+
+type fakeFlagViewRepository struct {
+	flagview.DataRepository
+}
+
+func (fakeFlagViewRepository) Refresh(context.Context, string, string, string) (uint, error) {
+	return 1, nil
+}
+
+// newServiceTest creates a flag server with fake repositories. The
+// transactions of the server don't reach the database as the fake
+// repositories don't use it.
+func newServiceTest(t *testing.T) (*flag.Server, *recordingInvalidator, *fakeFlagSettingRepository) {
+	t.Helper()
+
+	client, err := mongo.Connect(context.Background(), options.Client().ApplyURI(mongoDBConnectionString))
+	if err != nil {
+		t.Fatalf("could not create mongo client: %v", err)
+	}
+
+	t.Cleanup(func() { _ = client.Disconnect(context.Background()) })
+
+	invalidator := &recordingInvalidator{}
+	flagSettingRepo := &fakeFlagSettingRepository{}
+
+	server := flag.NewFlagServer(
+		client,
+		fakeUserRepository{},
+		fakeProjectRepository{},
+		fakeEnvironmentRepository{},
+		fakeFlagRepository{},
+		flagSettingRepo,
+		fakeFlagViewRepository{},
+		changefeed.NewMemoryChangeFeedRepository(),
+		invalidator,
+		noopWarmer{},
+		0,
+		&logger.StubLogger{},
+	)
+
+	return server, invalidator, flagSettingRepo
+}
+
+// authenticatedContext creates the context of a request of an authenticated
+// user.
+func authenticatedContext() context.Context {
+	return auth.InjectClaimsIntoContext(
+		context.Background(),
+		&auth.FlaggerJWTClaims{RegisteredClaims: jwt.RegisteredClaims{Subject: "test"}},
+	)
+}
+
+func TestServer_CreateFlag_InvalidatesFlagInAllEnvironments(t *testing.T) {
+	server, invalidator, _ := newServiceTest(t)
+
+	_, err := server.CreateFlag(authenticatedContext(), &flagpb.CreateFlagRequest{
+		ProjectName: "test",
+		FlagName:    "new-checkout",
+	})
+	if err != nil {
+		t.Fatalf("could not create flag: %v", err)
+	}
+
+	// The flag may have been cached as not found in any environment.
+	expected := []string{"ruleset:{" + serviceTestProjectKey + ":*}:new-checkout"}
+	if !slices.Equal(invalidator.patterns, expected) {
+		t.Fatalf("expected %v to be invalidated but got %v", expected, invalidator.patterns)
+	}
+}
+
+func TestServer_UpdateFlagStatus_InvalidatesFlagInEnvironment(t *testing.T) {
+	testCases := []struct {
+		Name            string
+		Rollout         *flagpb.Rollout
+		ExpectedUpdates []string
+	}{
+		{
+			Name:            "status",
+			Rollout:         nil,
+			ExpectedUpdates: []string{"is_active"},
+		},
+		{
+			Name: "status_and_rollout",
+			Rollout: &flagpb.Rollout{
+				Variations: []*flagpb.WeightedVariation{
+					{Variation: flag.OnVariationKey, Weight: 50000},
+					{Variation: flag.OffVariationKey, Weight: 50000},
+				},
+			},
+			ExpectedUpdates: []string{"is_active,rollout"},
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.Name, func(t *testing.T) {
+			server, invalidator, flagSettingRepo := newServiceTest(t)
+
+			_, err := server.UpdateFlagStatus(authenticatedContext(), &flagpb.UpdateFlagStatusRequest{
+				ProjectName:     "test",
+				EnvironmentName: "production",
+				FlagName:        "new-checkout",
+				IsActive:        false,
+				Rollout:         testCase.Rollout,
+			})
+			if err != nil {
+				t.Fatalf("could not update flag status: %v", err)
+			}
+
+			// The rollout is only replaced when it is set, with a single
+			// update of the flag setting.
+			if !slices.Equal(flagSettingRepo.updates, testCase.ExpectedUpdates) {
+				t.Fatalf("expected updates %v but got %v", testCase.ExpectedUpdates, flagSettingRepo.updates)
+			}
+
+			expected := []string{"ruleset:{" + serviceTestProjectKey + ":production}:new-checkout"}
+			if !slices.Equal(invalidator.patterns, expected) {
+				t.Fatalf("expected %v to be invalidated but got %v", expected, invalidator.patterns)
+			}
+		})
+	}
+}
@@ -0,0 +1,251 @@
+package flag
+
+import (
+	"crypto/rand"
+	"encoding/json"
+
+	"google.golang.org/protobuf/types/known/structpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"github.com/waduhek/flagger/proto/flagpb"
+)
+
+// Type is the type of the value that a flag serves.
+type Type string
+
+const (
+	TypeBoolean Type = "boolean"
+	TypeString  Type = "string"
+	TypeNumber  Type = "number"
+	TypeJSON    Type = "json"
+)
+
+// The keys of the variations that are created for boolean flags when no
+// variations are provided.
+const (
+	OnVariationKey  = "on"
+	OffVariationKey = "off"
+)
+
+// Variation is a named value that a flag can serve. The value of the variation
+// is stored as its JSON encoding.
+type Variation struct {
+	Key   string `bson:"key"   json:"key"`
+	Value string `bson:"value" json:"value"`
+}
+
+// VariationByKey finds the variation of the flag with the provided key.
+func (f *Flag) VariationByKey(key string) (*Variation, bool) {
+	for i := range f.Variations {
+		if f.Variations[i].Key == key {
+			return &f.Variations[i], true
+		}
+	}
+
+	return nil, false
+}
+
+// ValidateValue checks that the JSON encoded value matches the provided flag
+// type.
+func ValidateValue(flagType Type, value string) bool {
+	switch flagType {
+	case TypeBoolean:
+		var decoded bool
+		return json.Unmarshal([]byte(value), &decoded) == nil
+	case TypeString:
+		var decoded string
+		return json.Unmarshal([]byte(value), &decoded) == nil
+	case TypeNumber:
+		var decoded float64
+		return json.Unmarshal([]byte(value), &decoded) == nil
+	case TypeJSON:
+		return json.Valid([]byte(value))
+	default:
+		return false
+	}
+}
+
+// validateVariations checks that the variations are valid for the type of the
+// flag and that the default and off variations refer to one of them.
+func validateVariations(flag *Flag) bool {
+	if len(flag.Variations) == 0 {
+		return false
+	}
+
+	seenKeys := make(map[string]struct{}, len(flag.Variations))
+	for _, variation := range flag.Variations {
+		if variation.Key == "" || !ValidateValue(flag.Type, variation.Value) {
+			return false
+		}
+
+		if _, ok := seenKeys[variation.Key]; ok {
+			return false
+		}
+		seenKeys[variation.Key] = struct{}{}
+	}
+
+	_, hasDefault := flag.VariationByKey(flag.DefaultVariation)
+	_, hasOff := flag.VariationByKey(flag.OffVariation)
+
+	return hasDefault && hasOff
+}
+
+// booleanVariations are the variations created for boolean flags when none are
+// provided.
+func booleanVariations() []Variation {
+	return []Variation{
+		{Key: OnVariationKey, Value: "true"},
+		{Key: OffVariationKey, Value: "false"},
+	}
+}
+
+// typeFromProto maps the flag type of a request to a `Type`.
+func typeFromProto(flagType flagpb.FlagType) (Type, bool) {
+	switch flagType {
+	case flagpb.FlagType_FLAG_TYPE_BOOLEAN:
+		return TypeBoolean, true
+	case flagpb.FlagType_FLAG_TYPE_STRING:
+		return TypeString, true
+	case flagpb.FlagType_FLAG_TYPE_NUMBER:
+		return TypeNumber, true
+	case flagpb.FlagType_FLAG_TYPE_JSON:
+		return TypeJSON, true
+	default:
+		return "", false
+	}
+}
+
+// typeToProto maps the `Type` of a flag to the flag type of a response.
+func typeToProto(flagType Type) flagpb.FlagType {
+	switch flagType {
+	case TypeString:
+		return flagpb.FlagType_FLAG_TYPE_STRING
+	case TypeNumber:
+		return flagpb.FlagType_FLAG_TYPE_NUMBER
+	case TypeJSON:
+		return flagpb.FlagType_FLAG_TYPE_JSON
+	default:
+		return flagpb.FlagType_FLAG_TYPE_BOOLEAN
+	}
+}
+
+// variationsFromProto maps the variations of a request to `Variation`s by
+// encoding their values as JSON.
+func variationsFromProto(variations []*flagpb.Variation) ([]Variation, error) {
+	mapped := make([]Variation, 0, len(variations))
+
+	for _, variation := range variations {
+		encodedValue, err := json.Marshal(variation.GetValue().AsInterface())
+		if err != nil {
+			return nil, err
+		}
+
+		mapped = append(
+			mapped,
+			Variation{Key: variation.GetKey(), Value: string(encodedValue)},
+		)
+	}
+
+	return mapped, nil
+}
+
+// variationsToProto maps `Variation`s to the variations of a response by
+// decoding their JSON encoded values.
+func variationsToProto(variations []Variation) ([]*flagpb.Variation, error) {
+	mapped := make([]*flagpb.Variation, 0, len(variations))
+
+	for _, variation := range variations {
+		var decodedValue any
+		if err := json.Unmarshal([]byte(variation.Value), &decodedValue); err != nil {
+			return nil, err
+		}
+
+		value, err := structpb.NewValue(decodedValue)
+		if err != nil {
+			return nil, err
+		}
+
+		mapped = append(mapped, &flagpb.Variation{Key: variation.Key, Value: value})
+	}
+
+	return mapped, nil
+}
+
+// flagToProto maps the flag to its details in a response.
+func flagToProto(flag *Flag) (*flagpb.FlagDetails, error) {
+	variations, err := variationsToProto(flag.Variations)
+	if err != nil {
+		return nil, err
+	}
+
+	details := &flagpb.FlagDetails{
+		Name:             flag.Name,
+		DisplayName:      flag.DisplayName,
+		FlagType:         typeToProto(flag.Type),
+		Variations:       variations,
+		DefaultVariation: flag.DefaultVariation,
+		OffVariation:     flag.OffVariation,
+		CreatedAt:        timestamppb.New(flag.CreatedAt),
+		Description:      flag.Metadata.Description,
+		Owner:            ownerToProto(flag.Metadata.Owner),
+		Tags:             flag.Metadata.Tags,
+		Kind:             kindToProto(flag.Metadata.Kind),
+	}
+
+	if flag.IsArchived {
+		details.IsArchived = true
+		details.ArchivedVariation = flag.ArchivedVariation
+		details.ArchivedAt = timestamppb.New(flag.ArchivedAt)
+	}
+
+	return details, nil
+}
+
+// newFlagFromRequest creates a new `Flag` with its type and variations from
+// the create request. Missing variations of boolean flags and missing default
+// and off variations are filled in.
+func newFlagFromRequest(req *flagpb.CreateFlagRequest) (*Flag, error) {
+	flagType, ok := typeFromProto(req.GetFlagType())
+	if !ok {
+		return nil, ErrInvalidFlagType
+	}
+
+	variations, err := variationsFromProto(req.GetVariations())
+	if err != nil {
+		return nil, ErrInvalidVariations
+	}
+
+	if flagType == TypeBoolean && len(variations) == 0 {
+		variations = booleanVariations()
+	}
+
+	newFlag := &Flag{
+		Name:             req.GetFlagName(),
+		DisplayName:      req.GetDisplayName(),
+		Type:             flagType,
+		Variations:       variations,
+		DefaultVariation: req.GetDefaultVariation(),
+		OffVariation:     req.GetOffVariation(),
+		// The salt randomises the buckets of the percentage rollouts of the
+		// flag so that the same contexts aren't rolled out first on all flags.
+		// The buckets are hashed from the salt instead of the name of the
+		// flag, so that renaming the flag keeps them.
+		Salt: rand.Text(),
+	}
+
+	if len(variations) > 0 {
+		if newFlag.DefaultVariation == "" {
+			newFlag.DefaultVariation = variations[0].Key
+		}
+
+		if newFlag.OffVariation == "" {
+			newFlag.OffVariation = variations[len(variations)-1].Key
+		}
+	}
+
+	if !validateVariations(newFlag) {
+		return nil, ErrInvalidVariations
+	}
+
+	return newFlag, nil
+}
@@ -0,0 +1,62 @@
+package flag_test
+
+import (
+	"testing"
+
+	"github.com/waduhek/flagger/internal/flag"
+)
+
+func TestValidateValue(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		Name     string
+		Type     flag.Type
+		Value    string
+		Expected bool
+	}{
+		{Name: "boolean", Type: flag.TypeBoolean, Value: "true", Expected: true},
+		{Name: "boolean_string", Type: flag.TypeBoolean, Value: `"true"`, Expected: false},
+		{Name: "string", Type: flag.TypeString, Value: `"banner"`, Expected: true},
+		{Name: "string_number", Type: flag.TypeString, Value: "10", Expected: false},
+		{Name: "number", Type: flag.TypeNumber, Value: "2.5", Expected: true},
+		{Name: "number_boolean", Type: flag.TypeNumber, Value: "false", Expected: false},
+		{Name: "json_object", Type: flag.TypeJSON, Value: `{"timeout": 10}`, Expected: true},
+		{Name: "json_invalid", Type: flag.TypeJSON, Value: `{"timeout": }`, Expected: false},
+		{Name: "unknown_type", Type: flag.Type("unknown"), Value: "true", Expected: false},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.Name, func(t *testing.T) {
+			t.Parallel()
+
+			got := flag.ValidateValue(testCase.Type, testCase.Value)
+			if got != testCase.Expected {
+				t.Fatalf("expected %v for value %q but got %v", testCase.Expected, testCase.Value, got)
+			}
+		})
+	}
+}
+
+func TestVariationByKey(t *testing.T) {
+	testFlag := &flag.Flag{
+		Type: flag.TypeNumber,
+		Variations: []flag.Variation{
+			{Key: "short", Value: "5"},
+			{Key: "long", Value: "30"},
+		},
+	}
+
+	variation, ok := testFlag.VariationByKey("long")
+	if !ok {
+		t.Fatal("expected variation to be found")
+	}
+
+	if variation.Value != "30" {
+		t.Fatalf("expected value of variation to be 30 but got %q", variation.Value)
+	}
+
+	if _, ok = testFlag.VariationByKey("missing"); ok {
+		t.Fatal("did not expect missing variation to be found")
+	}
+}
@@ -3,14 +3,22 @@ package flagsetting
 import (
 	"context"
 	"time"
+
+	"github.com/waduhek/flagger/internal/targeting"
 )
 
+// FlagSetting is the setting of a flag in an environment. The version of the
+// setting starts at 1 and is incremented by every update to the setting.
 type FlagSetting struct {
 	ID            string
 	ProjectID     string
 	EnvironmentID string
 	FlagID        string
 	IsActive      bool
+	Variation     string
+	Rules         []targeting.Rule
+	Rollout       []targeting.WeightedVariation
+	Version       int64
 	CreatedAt     time.Time
 	UpdatedAt     time.Time
 }
@@ -37,6 +45,14 @@ type DataRepository interface {
 		flagID string,
 	) (*FlagSetting, error)
 
+	// GetByFlagID gets the settings of the flag in all the environments of the
+	// project.
+	GetByFlagID(
+		ctx context.Context,
+		projectID string,
+		flagID string,
+	) ([]FlagSetting, error)
+
 	// UpdateIsActive updates a flag setting's `IsActive` field to the provided
 	// value.
 	UpdateIsActive(
@@ -46,4 +62,59 @@ type DataRepository interface {
 		flagID string,
 		isActive bool,
 	) (uint, error)
+
+	// UpdateVariation updates the key of the variation that is served by a
+	// flag setting while it is active. An empty variation serves the flag's
+	// default variation.
+	UpdateVariation(
+		ctx context.Context,
+		projectID string,
+		environmentID string,
+		flagID string,
+		variation string,
+	) (uint, error)
+
+	// UpdateIsActiveAndRollout updates a flag setting's `IsActive` field and
+	// replaces the percentage rollout of the variations served by it while it
+	// is active in a single update. An empty rollout disables the percentage
+	// rollout.
+	UpdateIsActiveAndRollout(
+		ctx context.Context,
+		projectID string,
+		environmentID string,
+		flagID string,
+		isActive bool,
+		rollout []targeting.WeightedVariation,
+	) (uint, error)
+
+	// UpdateRules replaces the ordered targeting rules of a flag setting.
+	UpdateRules(
+		ctx context.Context,
+		projectID string,
+		environmentID string,
+		flagID string,
+		rules []targeting.Rule,
+	) (uint, error)
+
+	// DeleteByFlagID deletes the settings of the flag in all the environments
+	// of the project. The number of deleted flag settings is returned.
+	DeleteByFlagID(
+		ctx context.Context,
+		projectID string,
+		flagID string,
+	) (uint, error)
+
+	// DeleteByEnvironmentID deletes the settings of all the flags of the
+	// project in the environment. The number of deleted flag settings is
+	// returned.
+	DeleteByEnvironmentID(
+		ctx context.Context,
+		projectID string,
+		environmentID string,
+	) (uint, error)
+
+	// DeleteByProjectID deletes the settings of all the flags of the project
+	// in all its environments. The number of deleted flag settings is
+	// returned.
+	DeleteByProjectID(ctx context.Context, projectID string) (uint, error)
 }
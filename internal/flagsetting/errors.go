@@ -25,3 +25,31 @@ var ErrStatusUpdate = status.Error(
 	codes.Internal,
 	"error occurred while updating the flag setting",
 )
+
+// ErrVariationUpdate is a GRPC error that is returned when an error occurs
+// while updating the variation served by the flag setting.
+var ErrVariationUpdate = status.Error(
+	codes.Internal,
+	"error occurred while updating the flag setting variation",
+)
+
+// ErrRulesUpdate is a GRPC error that is returned when an error occurs while
+// updating the targeting rules of the flag setting.
+var ErrRulesUpdate = status.Error(
+	codes.Internal,
+	"error occurred while updating the flag setting rules",
+)
+
+// ErrRolloutUpdate is a GRPC error that is returned when an error occurs while
+// updating the percentage rollout of the flag setting.
+var ErrRolloutUpdate = status.Error(
+	codes.Internal,
+	"error occurred while updating the flag setting rollout",
+)
+
+// ErrCouldNotDelete is a GRPC error that is returned when an error occurs while
+// deleting flag settings.
+var ErrCouldNotDelete = status.Error(
+	codes.Internal,
+	"could not delete flag settings",
+)
@@ -10,6 +10,7 @@ import (
 	"go.mongodb.org/mongo-driver/mongo/options"
 
 	"github.com/waduhek/flagger/internal/logger"
+	"github.com/waduhek/flagger/internal/targeting"
 )
 
 const flagSettingCollection string = "flag_settings"
@@ -17,13 +18,17 @@ const flagSettingCollection string = "flag_settings"
 // flagSettingMongoModel is the MongoDB representation of the `FlagSetting`
 // struct.
 type flagSettingMongoModel struct {
-	ID            primitive.ObjectID `bson:"_id,omitempty"`
-	ProjectID     primitive.ObjectID `bson:"project_id"`
-	EnvironmentID primitive.ObjectID `bson:"environment_id"`
-	FlagID        primitive.ObjectID `bson:"flag_id"`
-	IsActive      bool               `bson:"is_active"`
-	CreatedAt     time.Time          `bson:"created_at"`
-	UpdatedAt     time.Time          `bson:"updated_at"`
+	ID            primitive.ObjectID            `bson:"_id,omitempty"`
+	ProjectID     primitive.ObjectID            `bson:"project_id"`
+	EnvironmentID primitive.ObjectID            `bson:"environment_id"`
+	FlagID        primitive.ObjectID            `bson:"flag_id"`
+	IsActive      bool                          `bson:"is_active"`
+	Variation     string                        `bson:"variation,omitempty"`
+	Rules         []targeting.Rule              `bson:"rules,omitempty"`
+	Rollout       []targeting.WeightedVariation `bson:"rollout,omitempty"`
+	Version       int64                         `bson:"version"`
+	CreatedAt     time.Time                     `bson:"created_at"`
+	UpdatedAt     time.Time                     `bson:"updated_at"`
 }
 
 type MongoDataRepository struct {
@@ -123,6 +128,10 @@ func (r *MongoDataRepository) createNewFlagSetting(flagSetting *FlagSetting) (*f
 		EnvironmentID: environmentIDObjID,
 		FlagID:        flagIDObjID,
 		IsActive:      flagSetting.IsActive,
+		Variation:     flagSetting.Variation,
+		Rules:         flagSetting.Rules,
+		Rollout:       flagSetting.Rollout,
+		Version:       1,
 		CreatedAt:     time.Now(),
 		UpdatedAt:     time.Now(),
 	}
@@ -167,17 +176,49 @@ func (r *MongoDataRepository) Get(
 		return nil, err
 	}
 
-	flagSetting := &FlagSetting{
-		ID:            decodedFlagSetting.ID.Hex(),
-		ProjectID:     decodedFlagSetting.ProjectID.Hex(),
-		EnvironmentID: decodedFlagSetting.EnvironmentID.Hex(),
-		FlagID:        decodedFlagSetting.FlagID.Hex(),
-		IsActive:      decodedFlagSetting.IsActive,
-		CreatedAt:     decodedFlagSetting.CreatedAt,
-		UpdatedAt:     decodedFlagSetting.UpdatedAt,
+	return mapMongoModelToStruct(&decodedFlagSetting), nil
+}
+
+func (r *MongoDataRepository) GetByFlagID(
+	ctx context.Context,
+	projectID string,
+	flagID string,
+) ([]FlagSetting, error) {
+	projectIDObjID, projectIDErr := primitive.ObjectIDFromHex(projectID)
+	if projectIDErr != nil {
+		r.logger.Error("could not convert project id to object id: %v", projectIDErr)
+		return nil, ErrCouldNotGet
 	}
 
-	return flagSetting, err
+	flagIDObjID, flagIDErr := primitive.ObjectIDFromHex(flagID)
+	if flagIDErr != nil {
+		r.logger.Error("could not convert flag id to object id: %v", flagIDErr)
+		return nil, ErrCouldNotGet
+	}
+
+	query := bson.D{
+		{Key: "project_id", Value: projectIDObjID},
+		{Key: "flag_id", Value: flagIDObjID},
+	}
+
+	cursor, err := r.coll.Find(ctx, query)
+	if err != nil {
+		r.logger.Error("error while getting settings of flag %q: %v", flagID, err)
+		return nil, ErrCouldNotGet
+	}
+
+	var decodedFlagSettings []flagSettingMongoModel
+	if err = cursor.All(ctx, &decodedFlagSettings); err != nil {
+		r.logger.Error("error while decoding settings of flag %q: %v", flagID, err)
+		return nil, ErrCouldNotGet
+	}
+
+	flagSettings := make([]FlagSetting, 0, len(decodedFlagSettings))
+	for i := range decodedFlagSettings {
+		flagSettings = append(flagSettings, *mapMongoModelToStruct(&decodedFlagSettings[i]))
+	}
+
+	return flagSettings, nil
 }
 
 func (r *MongoDataRepository) UpdateIsActive(
@@ -211,9 +252,10 @@ func (r *MongoDataRepository) UpdateIsActive(
 		{Key: "flag_id", Value: flagIDObjID},
 	}
 
-	update := bson.D{{
-		Key: "$set", Value: bson.D{{Key: "is_active", Value: isActive}},
-	}}
+	update := bson.D{
+		{Key: "$set", Value: bson.D{{Key: "is_active", Value: isActive}}},
+		{Key: "$inc", Value: bson.D{{Key: "version", Value: 1}}},
+	}
 
 	updateResult, updateErr := r.coll.UpdateOne(ctx, filter, update)
 	if updateErr != nil {
@@ -225,6 +267,250 @@ func (r *MongoDataRepository) UpdateIsActive(
 	return uint(updateResult.ModifiedCount), nil
 }
 
+func (r *MongoDataRepository) UpdateVariation(
+	ctx context.Context,
+	projectID string,
+	environmentID string,
+	flagID string,
+	variation string,
+) (uint, error) {
+	projectIDObjID, projectIDErr := primitive.ObjectIDFromHex(projectID)
+	if projectIDErr != nil {
+		r.logger.Error("could not convert project id to object id: %v", projectIDErr)
+		return 0, ErrVariationUpdate
+	}
+
+	environmentIDObjID, environmentIDErr := primitive.ObjectIDFromHex(environmentID)
+	if environmentIDErr != nil {
+		r.logger.Error("could not convert environment id to object id: %v", environmentIDErr)
+		return 0, ErrVariationUpdate
+	}
+
+	flagIDObjID, flagIDErr := primitive.ObjectIDFromHex(flagID)
+	if flagIDErr != nil {
+		r.logger.Error("could not convert flag id to object id: %v", flagIDErr)
+		return 0, ErrVariationUpdate
+	}
+
+	filter := bson.D{
+		{Key: "project_id", Value: projectIDObjID},
+		{Key: "environment_id", Value: environmentIDObjID},
+		{Key: "flag_id", Value: flagIDObjID},
+	}
+
+	update := bson.D{
+		{
+			Key: "$set",
+			Value: bson.D{
+				{Key: "variation", Value: variation},
+				{Key: "updated_at", Value: time.Now()},
+			},
+		},
+		{Key: "$inc", Value: bson.D{{Key: "version", Value: 1}}},
+	}
+
+	updateResult, updateErr := r.coll.UpdateOne(ctx, filter, update)
+	if updateErr != nil {
+		r.logger.Error("could not update variation of flag setting: %v", updateErr)
+		return 0, ErrVariationUpdate
+	}
+
+	//nolint:gosec // ModifiedCount can't be a negative number.
+	return uint(updateResult.ModifiedCount), nil
+}
+
+func (r *MongoDataRepository) UpdateIsActiveAndRollout(
+	ctx context.Context,
+	projectID string,
+	environmentID string,
+	flagID string,
+	isActive bool,
+	rollout []targeting.WeightedVariation,
+) (uint, error) {
+	projectIDObjID, projectIDErr := primitive.ObjectIDFromHex(projectID)
+	if projectIDErr != nil {
+		r.logger.Error("could not convert project id to object id: %v", projectIDErr)
+		return 0, ErrRolloutUpdate
+	}
+
+	environmentIDObjID, environmentIDErr := primitive.ObjectIDFromHex(environmentID)
+	if environmentIDErr != nil {
+		r.logger.Error("could not convert environment id to object id: %v", environmentIDErr)
+		return 0, ErrRolloutUpdate
+	}
+
+	flagIDObjID, flagIDErr := primitive.ObjectIDFromHex(flagID)
+	if flagIDErr != nil {
+		r.logger.Error("could not convert flag id to object id: %v", flagIDErr)
+		return 0, ErrRolloutUpdate
+	}
+
+	// Store an empty array rather than null when the rollout is removed.
+	if rollout == nil {
+		rollout = []targeting.WeightedVariation{}
+	}
+
+	filter := bson.D{
+		{Key: "project_id", Value: projectIDObjID},
+		{Key: "environment_id", Value: environmentIDObjID},
+		{Key: "flag_id", Value: flagIDObjID},
+	}
+
+	update := bson.D{
+		{
+			Key: "$set",
+			Value: bson.D{
+				{Key: "is_active", Value: isActive},
+				{Key: "rollout", Value: rollout},
+				{Key: "updated_at", Value: time.Now()},
+			},
+		},
+		{Key: "$inc", Value: bson.D{{Key: "version", Value: 1}}},
+	}
+
+	updateResult, updateErr := r.coll.UpdateOne(ctx, filter, update)
+	if updateErr != nil {
+		r.logger.Error("could not update status and rollout of flag setting: %v", updateErr)
+		return 0, ErrRolloutUpdate
+	}
+
+	//nolint:gosec // ModifiedCount can't be a negative number.
+	return uint(updateResult.ModifiedCount), nil
+}
+
+func (r *MongoDataRepository) UpdateRules(
+	ctx context.Context,
+	projectID string,
+	environmentID string,
+	flagID string,
+	rules []targeting.Rule,
+) (uint, error) {
+	projectIDObjID, projectIDErr := primitive.ObjectIDFromHex(projectID)
+	if projectIDErr != nil {
+		r.logger.Error("could not convert project id to object id: %v", projectIDErr)
+		return 0, ErrRulesUpdate
+	}
+
+	environmentIDObjID, environmentIDErr := primitive.ObjectIDFromHex(environmentID)
+	if environmentIDErr != nil {
+		r.logger.Error("could not convert environment id to object id: %v", environmentIDErr)
+		return 0, ErrRulesUpdate
+	}
+
+	flagIDObjID, flagIDErr := primitive.ObjectIDFromHex(flagID)
+	if flagIDErr != nil {
+		r.logger.Error("could not convert flag id to object id: %v", flagIDErr)
+		return 0, ErrRulesUpdate
+	}
+
+	// Store an empty array rather than null when all the rules are removed.
+	if rules == nil {
+		rules = []targeting.Rule{}
+	}
+
+	filter := bson.D{
+		{Key: "project_id", Value: projectIDObjID},
+		{Key: "environment_id", Value: environmentIDObjID},
+		{Key: "flag_id", Value: flagIDObjID},
+	}
+
+	update := bson.D{
+		{
+			Key: "$set",
+			Value: bson.D{
+				{Key: "rules", Value: rules},
+				{Key: "updated_at", Value: time.Now()},
+			},
+		},
+		{Key: "$inc", Value: bson.D{{Key: "version", Value: 1}}},
+	}
+
+	updateResult, updateErr := r.coll.UpdateOne(ctx, filter, update)
+	if updateErr != nil {
+		r.logger.Error("could not update rules of flag setting: %v", updateErr)
+		return 0, ErrRulesUpdate
+	}
+
+	//nolint:gosec // ModifiedCount can't be a negative number.
+	return uint(updateResult.ModifiedCount), nil
+}
+
+func (r *MongoDataRepository) DeleteByFlagID(
+	ctx context.Context,
+	projectID string,
+	flagID string,
+) (uint, error) {
+	return r.deleteMany(ctx, projectID, "flag_id", flagID)
+}
+
+func (r *MongoDataRepository) DeleteByEnvironmentID(
+	ctx context.Context,
+	projectID string,
+	environmentID string,
+) (uint, error) {
+	return r.deleteMany(ctx, projectID, "environment_id", environmentID)
+}
+
+func (r *MongoDataRepository) DeleteByProjectID(
+	ctx context.Context,
+	projectID string,
+) (uint, error) {
+	return r.deleteMany(ctx, projectID, "", "")
+}
+
+// deleteMany deletes the flag settings of the project whose field holds the
+// ID. All the flag settings of the project are deleted if the field is empty.
+// The number of deleted flag settings is returned.
+func (r *MongoDataRepository) deleteMany(
+	ctx context.Context,
+	projectID string,
+	field string,
+	id string,
+) (uint, error) {
+	projectIDObjID, projectIDErr := primitive.ObjectIDFromHex(projectID)
+	if projectIDErr != nil {
+		r.logger.Error("could not convert project id to object id: %v", projectIDErr)
+		return 0, ErrCouldNotDelete
+	}
+
+	filter := bson.D{{Key: "project_id", Value: projectIDObjID}}
+
+	if field != "" {
+		idObjID, idErr := primitive.ObjectIDFromHex(id)
+		if idErr != nil {
+			r.logger.Error("could not convert %v to object id: %v", field, idErr)
+			return 0, ErrCouldNotDelete
+		}
+
+		filter = append(filter, bson.E{Key: field, Value: idObjID})
+	}
+
+	deleteResult, err := r.coll.DeleteMany(ctx, filter)
+	if err != nil {
+		r.logger.Error("could not delete flag settings of project %q: %v", projectID, err)
+		return 0, ErrCouldNotDelete
+	}
+
+	//nolint:gosec // DeletedCount can't be a negative number.
+	return uint(deleteResult.DeletedCount), nil
+}
+
+func mapMongoModelToStruct(decodedFlagSetting *flagSettingMongoModel) *FlagSetting {
+	return &FlagSetting{
+		ID:            decodedFlagSetting.ID.Hex(),
+		ProjectID:     decodedFlagSetting.ProjectID.Hex(),
+		EnvironmentID: decodedFlagSetting.EnvironmentID.Hex(),
+		FlagID:        decodedFlagSetting.FlagID.Hex(),
+		IsActive:      decodedFlagSetting.IsActive,
+		Variation:     decodedFlagSetting.Variation,
+		Rules:         decodedFlagSetting.Rules,
+		Rollout:       decodedFlagSetting.Rollout,
+		Version:       decodedFlagSetting.Version,
+		CreatedAt:     decodedFlagSetting.CreatedAt,
+		UpdatedAt:     decodedFlagSetting.UpdatedAt,
+	}
+}
+
 func setupIndexes(ctx context.Context, coll *mongo.Collection) error {
 	projectEnvFlagIndexModel := mongo.IndexModel{
 		Keys: bson.D{
@@ -235,7 +521,18 @@ func setupIndexes(ctx context.Context, coll *mongo.Collection) error {
 		Options: options.Index().SetUnique(true),
 	}
 
-	_, err := coll.Indexes().CreateOne(ctx, projectEnvFlagIndexModel)
+	// The settings of a flag in all the environments are looked up together.
+	projectFlagIndexModel := mongo.IndexModel{
+		Keys: bson.D{
+			{Key: "project_id", Value: 1},
+			{Key: "flag_id", Value: 1},
+		},
+	}
+
+	_, err := coll.Indexes().CreateMany(
+		ctx,
+		[]mongo.IndexModel{projectEnvFlagIndexModel, projectFlagIndexModel},
+	)
 
 	return err
 }
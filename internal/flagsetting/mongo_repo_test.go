@@ -10,6 +10,7 @@ import (
 
 	"github.com/waduhek/flagger/internal/flagsetting"
 	"github.com/waduhek/flagger/internal/logger"
+	"github.com/waduhek/flagger/internal/targeting"
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 	"go.mongodb.org/mongo-driver/mongo"
@@ -301,6 +302,315 @@ func TestUpdateIsActive_InvalidID(t *testing.T) {
 	}
 }
 
+func TestUpdateVariation(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 1*time.Second)
+	defer cancel()
+
+	_, saveErr := flagsettingRepository.Save(ctx, dummyFlagSetting)
+	if saveErr != nil {
+		t.Fatalf("error while saving flag setting: %v", saveErr)
+	}
+
+	cleanupFlagSetting(t, dummyFlagSetting)
+
+	updatedCount, updateErr := flagsettingRepository.UpdateVariation(
+		ctx,
+		dummyFlagSetting.ProjectID,
+		dummyFlagSetting.EnvironmentID,
+		dummyFlagSetting.FlagID,
+		"variation",
+	)
+	if updateErr != nil {
+		t.Fatalf("error while updating variation of flag setting: %v", updateErr)
+	}
+
+	if updatedCount != 1 {
+		t.Fatalf("expected 1 flag setting to be updated but got %v", updatedCount)
+	}
+
+	gotFlagSetting, getErr := flagsettingRepository.Get(
+		ctx,
+		dummyFlagSetting.ProjectID,
+		dummyFlagSetting.EnvironmentID,
+		dummyFlagSetting.FlagID,
+	)
+	if getErr != nil {
+		t.Fatalf("error while getting flag setting: %v", getErr)
+	}
+
+	if gotFlagSetting.Variation != "variation" {
+		t.Fatalf("expected variation to be updated but got %q", gotFlagSetting.Variation)
+	}
+}
+
+func TestUpdateVariation_InvalidID(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		Name          string
+		ProjectID     string
+		EnvironmentID string
+		FlagID        string
+	}{
+		{
+			Name:          "invalid_project_id",
+			ProjectID:     "invalid_object_id",
+			EnvironmentID: dummyObjectID,
+			FlagID:        dummyObjectID,
+		},
+		{
+			Name:          "invalid_environment_id",
+			ProjectID:     dummyObjectID,
+			EnvironmentID: "invalid_object_id",
+			FlagID:        dummyObjectID,
+		},
+		{
+			Name:          "invalid_flag_id",
+			ProjectID:     dummyObjectID,
+			EnvironmentID: dummyObjectID,
+			FlagID:        "invalid_object_id",
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.Name, func(t *testing.T) {
+			t.Parallel()
+
+			ctx, cancel := context.WithTimeout(context.Background(), 1*time.Second)
+			defer cancel()
+
+			_, updateErr := flagsettingRepository.UpdateVariation(
+				ctx,
+				testCase.ProjectID,
+				testCase.EnvironmentID,
+				testCase.FlagID,
+				"variation",
+			)
+			if !errors.Is(updateErr, flagsetting.ErrVariationUpdate) {
+				t.Fatalf("expected ErrVariationUpdate error when updating setting variation")
+			}
+		})
+	}
+}
+
+func TestUpdateRules(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 1*time.Second)
+	defer cancel()
+
+	_, saveErr := flagsettingRepository.Save(ctx, dummyFlagSetting)
+	if saveErr != nil {
+		t.Fatalf("error while saving flag setting: %v", saveErr)
+	}
+
+	cleanupFlagSetting(t, dummyFlagSetting)
+
+	rules := []targeting.Rule{
+		{
+			Conditions: []targeting.Condition{
+				{Attribute: "country", Operator: targeting.OperatorIn, Values: []string{"IN", "US"}},
+			},
+			Variation: "on",
+		},
+		{Variation: "off"},
+	}
+
+	updatedCount, updateErr := flagsettingRepository.UpdateRules(
+		ctx,
+		dummyFlagSetting.ProjectID,
+		dummyFlagSetting.EnvironmentID,
+		dummyFlagSetting.FlagID,
+		rules,
+	)
+	if updateErr != nil {
+		t.Fatalf("error while updating rules of flag setting: %v", updateErr)
+	}
+
+	if updatedCount != 1 {
+		t.Fatalf("expected 1 flag setting to be updated but got %v", updatedCount)
+	}
+
+	gotFlagSetting, getErr := flagsettingRepository.Get(
+		ctx,
+		dummyFlagSetting.ProjectID,
+		dummyFlagSetting.EnvironmentID,
+		dummyFlagSetting.FlagID,
+	)
+	if getErr != nil {
+		t.Fatalf("error while getting flag setting: %v", getErr)
+	}
+
+	if len(gotFlagSetting.Rules) != len(rules) {
+		t.Fatalf("expected %d rules but got %d", len(rules), len(gotFlagSetting.Rules))
+	}
+
+	if gotFlagSetting.Rules[0].Conditions[0].Operator != targeting.OperatorIn {
+		t.Fatal("expected the order of the rules to be preserved")
+	}
+}
+
+func TestUpdateIsActiveAndRollout(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 1*time.Second)
+	defer cancel()
+
+	_, saveErr := flagsettingRepository.Save(ctx, dummyFlagSetting)
+	if saveErr != nil {
+		t.Fatalf("error while saving flag setting: %v", saveErr)
+	}
+
+	cleanupFlagSetting(t, dummyFlagSetting)
+
+	rollout := []targeting.WeightedVariation{
+		{Variation: "on", Weight: 5000},
+		{Variation: "off", Weight: 95000},
+	}
+
+	updatedCount, updateErr := flagsettingRepository.UpdateIsActiveAndRollout(
+		ctx,
+		dummyFlagSetting.ProjectID,
+		dummyFlagSetting.EnvironmentID,
+		dummyFlagSetting.FlagID,
+		false,
+		rollout,
+	)
+	if updateErr != nil {
+		t.Fatalf("error while updating status and rollout of flag setting: %v", updateErr)
+	}
+
+	if updatedCount != 1 {
+		t.Fatalf("expected 1 flag setting to be updated but got %v", updatedCount)
+	}
+
+	gotFlagSetting, getErr := flagsettingRepository.Get(
+		ctx,
+		dummyFlagSetting.ProjectID,
+		dummyFlagSetting.EnvironmentID,
+		dummyFlagSetting.FlagID,
+	)
+	if getErr != nil {
+		t.Fatalf("error while getting flag setting: %v", getErr)
+	}
+
+	if gotFlagSetting.IsActive {
+		t.Fatal("expected the flag setting to be inactive")
+	}
+
+	if len(gotFlagSetting.Rollout) != len(rollout) {
+		t.Fatalf("expected %d weighted variations but got %d", len(rollout), len(gotFlagSetting.Rollout))
+	}
+
+	if gotFlagSetting.Rollout[0].Weight != rollout[0].Weight {
+		t.Fatalf("expected weight %d but got %d", rollout[0].Weight, gotFlagSetting.Rollout[0].Weight)
+	}
+
+	if gotFlagSetting.Version != 2 {
+		t.Fatalf("expected the update to increment the version to 2 but got %d", gotFlagSetting.Version)
+	}
+}
+
+func TestGetByFlagID(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 1*time.Second)
+	defer cancel()
+
+	savedID, saveErr := flagsettingRepository.Save(ctx, dummyFlagSetting)
+	if saveErr != nil {
+		t.Fatalf("error while saving flag setting: %v", saveErr)
+	}
+
+	cleanupFlagSetting(t, dummyFlagSetting)
+
+	flagSettings, getErr := flagsettingRepository.GetByFlagID(
+		ctx,
+		dummyFlagSetting.ProjectID,
+		dummyFlagSetting.FlagID,
+	)
+	if getErr != nil {
+		t.Fatalf("error while getting flag settings: %v", getErr)
+	}
+
+	if len(flagSettings) != 1 || flagSettings[0].ID != savedID {
+		t.Fatalf("got unexpected flag settings: %v", flagSettings)
+	}
+}
+
+func TestGetByFlagID_InvalidID(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 1*time.Second)
+	defer cancel()
+
+	_, getErr := flagsettingRepository.GetByFlagID(ctx, "invalid_object_id", dummyObjectID)
+	if !errors.Is(getErr, flagsetting.ErrCouldNotGet) {
+		t.Fatalf("expected error while getting flag settings")
+	}
+}
+
+func TestDeleteByFlagID(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 1*time.Second)
+	defer cancel()
+
+	_, saveErr := flagsettingRepository.Save(ctx, dummyFlagSetting)
+	if saveErr != nil {
+		t.Fatalf("error while saving flag setting: %v", saveErr)
+	}
+
+	cleanupFlagSetting(t, dummyFlagSetting)
+
+	deletedCount, deleteErr := flagsettingRepository.DeleteByFlagID(
+		ctx,
+		dummyFlagSetting.ProjectID,
+		dummyFlagSetting.FlagID,
+	)
+	if deleteErr != nil || deletedCount != 1 {
+		t.Fatalf("could not delete flag settings: %v", deleteErr)
+	}
+
+	_, getErr := flagsettingRepository.Get(
+		ctx,
+		dummyFlagSetting.ProjectID,
+		dummyFlagSetting.EnvironmentID,
+		dummyFlagSetting.FlagID,
+	)
+	if getErr == nil {
+		t.Fatalf("expected the deleted flag setting not to be found")
+	}
+}
+
+func TestDeleteByEnvironmentID(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 1*time.Second)
+	defer cancel()
+
+	_, saveErr := flagsettingRepository.Save(ctx, dummyFlagSetting)
+	if saveErr != nil {
+		t.Fatalf("error while saving flag setting: %v", saveErr)
+	}
+
+	cleanupFlagSetting(t, dummyFlagSetting)
+
+	deletedCount, deleteErr := flagsettingRepository.DeleteByEnvironmentID(
+		ctx,
+		dummyFlagSetting.ProjectID,
+		dummyFlagSetting.EnvironmentID,
+	)
+	if deleteErr != nil || deletedCount != 1 {
+		t.Fatalf("could not delete flag settings: %v", deleteErr)
+	}
+}
+
+func TestDeleteByProjectID(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 1*time.Second)
+	defer cancel()
+
+	_, saveErr := flagsettingRepository.Save(ctx, dummyFlagSetting)
+	if saveErr != nil {
+		t.Fatalf("error while saving flag setting: %v", saveErr)
+	}
+
+	cleanupFlagSetting(t, dummyFlagSetting)
+
+	deletedCount, deleteErr := flagsettingRepository.DeleteByProjectID(ctx, dummyFlagSetting.ProjectID)
+	if deleteErr != nil || deletedCount != 1 {
+		t.Fatalf("could not delete flag settings: %v", deleteErr)
+	}
+}
+
 func TestMain(m *testing.M) {
 	client, mongoClientErr := getMongoClient()
 	if mongoClientErr != nil {
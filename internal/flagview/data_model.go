@@ -0,0 +1,27 @@
+// Package flagview maintains the materialised views of the flags that the
+// provider evaluates the flags from. A view holds everything about a flag in an
+// environment, so that the provider reads it without joining the projects,
+// environments, flags and flag settings. The views are refreshed in the same
+// transactions that change their sources.
+package flagview
+
+import "context"
+
+type DataRepository interface {
+	// Refresh recomputes the views of the flag settings of the project in the
+	// environment for the flag and removes the views of the flag settings that
+	// no longer exist. An empty environment ID refreshes the views of all the
+	// environments and an empty flag ID refreshes the views of all the flags.
+	// The number of refreshed views is returned.
+	Refresh(
+		ctx context.Context,
+		projectID string,
+		environmentID string,
+		flagID string,
+	) (uint, error)
+
+	// Rebuild recomputes the views of all the flag settings of all the projects
+	// and removes the views of the flag settings that no longer exist. The
+	// number of rebuilt views is returned.
+	Rebuild(ctx context.Context) (uint, error)
+}
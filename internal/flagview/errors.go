@@ -0,0 +1,13 @@
+package flagview
+
+import (
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// ErrCouldNotRefresh is a GRPC error that is returned when an error occurs
+// while refreshing the views of the flags.
+var ErrCouldNotRefresh = status.Error(
+	codes.Internal,
+	"could not refresh flag views",
+)
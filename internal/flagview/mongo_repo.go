@@ -0,0 +1,327 @@
+package flagview
+
+import (
+	"context"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"github.com/waduhek/flagger/internal/logger"
+	"github.com/waduhek/flagger/internal/project"
+)
+
+// FlagViewCollection is the collection of the views of the flags. A view is
+// stored with the ID of its flag setting and contains the project along with
+// the environment, the flag and the flag setting.
+const FlagViewCollection string = "flag_views"
+
+type MongoDataRepository struct {
+	flagSettingColl *mongo.Collection
+	viewColl        *mongo.Collection
+	logger          logger.Logger
+}
+
+func (r *MongoDataRepository) Refresh(
+	ctx context.Context,
+	projectID string,
+	environmentID string,
+	flagID string,
+) (uint, error) {
+	scope, err := newRefreshScope(projectID, environmentID, flagID)
+	if err != nil {
+		r.logger.Error("could not convert ids to object ids: %v", err)
+		return 0, ErrCouldNotRefresh
+	}
+
+	return r.refresh(ctx, scope)
+}
+
+func (r *MongoDataRepository) Rebuild(ctx context.Context) (uint, error) {
+	projectIDs, err := r.flagSettingColl.Distinct(ctx, "project_id", bson.D{})
+	if err != nil {
+		r.logger.Error("could not get the projects with flag settings: %v", err)
+		return 0, ErrCouldNotRefresh
+	}
+
+	var rebuiltCount uint
+
+	for _, projectID := range projectIDs {
+		projectObjID, ok := projectID.(primitive.ObjectID)
+		if !ok {
+			r.logger.Error("could not assert project id %v as object id", projectID)
+			return rebuiltCount, ErrCouldNotRefresh
+		}
+
+		refreshedCount, refreshErr := r.refresh(ctx, &refreshScope{projectID: projectObjID})
+		if refreshErr != nil {
+			return rebuiltCount, refreshErr
+		}
+
+		rebuiltCount += refreshedCount
+	}
+
+	// Remove the views of the projects that no longer have any flag settings.
+	_, err = r.viewColl.DeleteMany(
+		ctx,
+		bson.D{{Key: "project_id", Value: bson.D{{Key: "$nin", Value: projectIDs}}}},
+	)
+	if err != nil {
+		r.logger.Error("could not remove the views of removed projects: %v", err)
+		return rebuiltCount, ErrCouldNotRefresh
+	}
+
+	return rebuiltCount, nil
+}
+
+// refresh recomputes the views of the flag settings in the scope. The views in
+// the scope whose flag settings no longer exist are removed before the views
+// are replaced, so that a view that was recreated with a new flag setting does
+// not conflict with its stale view.
+func (r *MongoDataRepository) refresh(
+	ctx context.Context,
+	scope *refreshScope,
+) (uint, error) {
+	cursor, err := r.flagSettingColl.Aggregate(ctx, newFlagViewPipeline(scope.flagSettingFilter()))
+	if err != nil {
+		r.logger.Error("could not compute flag views: %v", err)
+		return 0, ErrCouldNotRefresh
+	}
+	defer cursor.Close(ctx)
+
+	viewIDs := bson.A{}
+	writes := []mongo.WriteModel{}
+
+	for cursor.Next(ctx) {
+		viewID, ok := cursor.Current.Lookup("_id").ObjectIDOK()
+		if !ok {
+			r.logger.Error("could not assert flag view id as object id")
+			return 0, ErrCouldNotRefresh
+		}
+
+		// The current document is only valid until the cursor is advanced.
+		view := make(bson.Raw, len(cursor.Current))
+		copy(view, cursor.Current)
+
+		viewIDs = append(viewIDs, viewID)
+		writes = append(
+			writes,
+			mongo.NewReplaceOneModel().
+				SetFilter(bson.D{{Key: "_id", Value: viewID}}).
+				SetReplacement(view).
+				SetUpsert(true),
+		)
+	}
+
+	if err = cursor.Err(); err != nil {
+		r.logger.Error("error while reading flag views: %v", err)
+		return 0, ErrCouldNotRefresh
+	}
+
+	staleFilter := append(scope.viewFilter(), bson.E{
+		Key:   "_id",
+		Value: bson.D{{Key: "$nin", Value: viewIDs}},
+	})
+
+	if _, err = r.viewColl.DeleteMany(ctx, staleFilter); err != nil {
+		r.logger.Error("could not remove stale flag views: %v", err)
+		return 0, ErrCouldNotRefresh
+	}
+
+	if len(writes) == 0 {
+		return 0, nil
+	}
+
+	if _, err = r.viewColl.BulkWrite(ctx, writes); err != nil {
+		r.logger.Error("could not save flag views: %v", err)
+		return 0, ErrCouldNotRefresh
+	}
+
+	//nolint:gosec // The number of writes can't be a negative number.
+	return uint(len(writes)), nil
+}
+
+// refreshScope selects the flag settings whose views are refreshed. A zero
+// environment or flag ID selects all the environments or flags of the project.
+type refreshScope struct {
+	projectID     primitive.ObjectID
+	environmentID primitive.ObjectID
+	flagID        primitive.ObjectID
+}
+
+func newRefreshScope(
+	projectID string,
+	environmentID string,
+	flagID string,
+) (*refreshScope, error) {
+	scope := &refreshScope{}
+
+	var err error
+
+	scope.projectID, err = primitive.ObjectIDFromHex(projectID)
+	if err != nil {
+		return nil, err
+	}
+
+	if environmentID != "" {
+		scope.environmentID, err = primitive.ObjectIDFromHex(environmentID)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if flagID != "" {
+		scope.flagID, err = primitive.ObjectIDFromHex(flagID)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return scope, nil
+}
+
+// flagSettingFilter creates the filter of the flag settings in the scope.
+func (s *refreshScope) flagSettingFilter() bson.D {
+	return s.filter("project_id", "environment_id", "flag_id")
+}
+
+// viewFilter creates the filter of the views in the scope.
+func (s *refreshScope) viewFilter() bson.D {
+	return s.filter("project_id", "environment._id", "flag._id")
+}
+
+func (s *refreshScope) filter(projectField, environmentField, flagField string) bson.D {
+	filter := bson.D{{Key: projectField, Value: s.projectID}}
+
+	if !s.environmentID.IsZero() {
+		filter = append(filter, bson.E{Key: environmentField, Value: s.environmentID})
+	}
+
+	if !s.flagID.IsZero() {
+		filter = append(filter, bson.E{Key: flagField, Value: s.flagID})
+	}
+
+	return filter
+}
+
+// newFlagViewPipeline creates the aggregation pipeline that computes the views
+// of the flag settings matching the filter from the flag settings collection.
+func newFlagViewPipeline(flagSettingFilter bson.D) bson.A {
+	lookup := func(from string, localField string, as string) bson.A {
+		return bson.A{
+			bson.D{
+				{
+					Key: "$lookup",
+					Value: bson.D{
+						{Key: "from", Value: from},
+						{Key: "localField", Value: localField},
+						{Key: "foreignField", Value: "_id"},
+						{Key: "as", Value: as},
+					},
+				},
+			},
+			bson.D{
+				{
+					Key:   "$unwind",
+					Value: bson.D{{Key: "path", Value: "$" + as}},
+				},
+			},
+		}
+	}
+
+	pipeline := bson.A{bson.D{{Key: "$match", Value: flagSettingFilter}}}
+	pipeline = append(pipeline, lookup(project.ProjectCollection, "project_id", "project")...)
+	// The flags of archived projects are not served, so they have no views.
+	pipeline = append(pipeline, bson.D{
+		{Key: "$match", Value: bson.D{{Key: "project.is_archived", Value: bson.D{{Key: "$ne", Value: true}}}}},
+	})
+	pipeline = append(pipeline, lookup("environments", "environment_id", "environment")...)
+	pipeline = append(pipeline, lookup("flags", "flag_id", "flag")...)
+
+	return append(pipeline, bson.D{
+		{
+			Key: "$project",
+			Value: bson.D{
+				{Key: "_id", Value: "$_id"},
+				{Key: "project_id", Value: "$project_id"},
+				{Key: "key", Value: "$project.key"},
+				{Key: "name", Value: "$project.name"},
+				{Key: "environment", Value: bson.D{
+					{Key: "_id", Value: "$environment._id"},
+					{Key: "name", Value: "$environment.name"},
+					{Key: "aliases", Value: "$environment.aliases"},
+				}},
+				{Key: "flag", Value: bson.D{
+					{Key: "_id", Value: "$flag._id"},
+					{Key: "name", Value: "$flag.name"},
+					{Key: "aliases", Value: "$flag.aliases"},
+					{Key: "type", Value: "$flag.type"},
+					{Key: "variations", Value: "$flag.variations"},
+					{Key: "default_variation", Value: "$flag.default_variation"},
+					{Key: "off_variation", Value: "$flag.off_variation"},
+					{Key: "salt", Value: "$flag.salt"},
+					{Key: "is_archived", Value: "$flag.is_archived"},
+					{Key: "archived_variation", Value: "$flag.archived_variation"},
+				}},
+				{Key: "flag_setting", Value: bson.D{
+					{Key: "_id", Value: "$_id"},
+					{Key: "is_active", Value: "$is_active"},
+					{Key: "variation", Value: "$variation"},
+					{Key: "rules", Value: "$rules"},
+					{Key: "rollout", Value: "$rollout"},
+					{Key: "version", Value: "$version"},
+					{Key: "updated_at", Value: "$updated_at"},
+				}},
+				{Key: "created_by", Value: "$project.created_by"},
+				{Key: "created_at", Value: "$project.created_at"},
+				{Key: "updated_at", Value: "$project.updated_at"},
+			},
+		},
+	})
+}
+
+func setupCollIndexes(ctx context.Context, coll *mongo.Collection) error {
+	viewIndexModels := []mongo.IndexModel{
+		{
+			Keys: bson.D{
+				{Key: "key", Value: 1},
+				{Key: "environment.name", Value: 1},
+				{Key: "flag.name", Value: 1},
+			},
+			Options: options.Index().SetUnique(true),
+		},
+		{
+			Keys: bson.D{
+				{Key: "project_id", Value: 1},
+				{Key: "environment._id", Value: 1},
+				{Key: "flag._id", Value: 1},
+			},
+		},
+	}
+
+	_, err := coll.Indexes().CreateMany(ctx, viewIndexModels)
+
+	return err
+}
+
+// NewFlagViewRepository creates a new repository that refreshes the views of
+// the flags from the flag settings.
+func NewFlagViewRepository(
+	ctx context.Context,
+	db *mongo.Database,
+	logger logger.Logger,
+) (*MongoDataRepository, error) {
+	viewColl := db.Collection(FlagViewCollection)
+
+	err := setupCollIndexes(ctx, viewColl)
+	if err != nil {
+		return nil, err
+	}
+
+	return &MongoDataRepository{
+		flagSettingColl: db.Collection("flag_settings"),
+		viewColl:        viewColl,
+		logger:          logger,
+	}, nil
+}
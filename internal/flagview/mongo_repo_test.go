@@ -0,0 +1,314 @@
+package flagview_test
+
+import (
+	"context"
+	"log"
+	"os"
+	"testing"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"github.com/waduhek/flagger/internal/flagview"
+	"github.com/waduhek/flagger/internal/logger"
+)
+
+const mongoDBConnectionString string = "mongodb://localhost:27017"
+
+var mongoDatabase *mongo.Database
+
+var flagViewRepository flagview.DataRepository
+
+// viewSources are the IDs of the documents that the views of a project are
+// computed from.
+type viewSources struct {
+	projectID     primitive.ObjectID
+	environmentID primitive.ObjectID
+	flagIDs       []primitive.ObjectID
+	settingIDs    []primitive.ObjectID
+}
+
+func TestRefresh(t *testing.T) {
+	sources := insertViewSources(t, "view-refresh", "production", "new-checkout", "dark-mode")
+
+	ctx, cancel := context.WithTimeout(context.Background(), 1*time.Second)
+	defer cancel()
+
+	refreshedCount, err := flagViewRepository.Refresh(
+		ctx,
+		sources.projectID.Hex(),
+		sources.environmentID.Hex(),
+		sources.flagIDs[0].Hex(),
+	)
+	if err != nil {
+		t.Fatalf("error while refreshing flag views: %v", err)
+	}
+
+	if refreshedCount != 1 {
+		t.Fatalf("expected 1 refreshed view but got %d", refreshedCount)
+	}
+
+	var view bson.M
+
+	err = mongoDatabase.Collection(flagview.FlagViewCollection).FindOne(ctx, bson.D{
+		{Key: "key", Value: "view-refresh"},
+		{Key: "environment.name", Value: "production"},
+		{Key: "flag.name", Value: "new-checkout"},
+	}).Decode(&view)
+	if err != nil {
+		t.Fatalf("error while finding the refreshed view: %v", err)
+	}
+
+	if view["_id"] != sources.settingIDs[0] {
+		t.Errorf("expected view of flag setting %v but got %v", sources.settingIDs[0], view["_id"])
+	}
+
+	flagSetting, _ := view["flag_setting"].(bson.M)
+	if flagSetting["is_active"] != true {
+		t.Errorf("expected the view to be active but got %v", flagSetting)
+	}
+
+	refreshedCount, err = flagViewRepository.Refresh(ctx, sources.projectID.Hex(), "", "")
+	if err != nil {
+		t.Fatalf("error while refreshing flag views of the project: %v", err)
+	}
+
+	if refreshedCount != 2 {
+		t.Errorf("expected 2 refreshed views but got %d", refreshedCount)
+	}
+}
+
+func TestRefresh_RemovesStaleViews(t *testing.T) {
+	sources := insertViewSources(t, "view-stale", "production", "new-checkout")
+
+	ctx, cancel := context.WithTimeout(context.Background(), 1*time.Second)
+	defer cancel()
+
+	if _, err := flagViewRepository.Refresh(ctx, sources.projectID.Hex(), "", ""); err != nil {
+		t.Fatalf("error while refreshing flag views: %v", err)
+	}
+
+	_, err := mongoDatabase.Collection("flag_settings").DeleteOne(
+		ctx,
+		bson.D{{Key: "_id", Value: sources.settingIDs[0]}},
+	)
+	if err != nil {
+		t.Fatalf("error while deleting flag setting: %v", err)
+	}
+
+	if _, err = flagViewRepository.Refresh(ctx, sources.projectID.Hex(), "", ""); err != nil {
+		t.Fatalf("error while refreshing flag views: %v", err)
+	}
+
+	viewCount, err := mongoDatabase.Collection(flagview.FlagViewCollection).CountDocuments(
+		ctx,
+		bson.D{{Key: "project_id", Value: sources.projectID}},
+	)
+	if err != nil {
+		t.Fatalf("error while counting flag views: %v", err)
+	}
+
+	if viewCount != 0 {
+		t.Errorf("expected the stale view to be removed but found %d views", viewCount)
+	}
+}
+
+func TestRefresh_RemovesViewsOfArchivedProjects(t *testing.T) {
+	sources := insertViewSources(t, "view-archived", "production", "new-checkout")
+
+	ctx, cancel := context.WithTimeout(context.Background(), 1*time.Second)
+	defer cancel()
+
+	if _, err := flagViewRepository.Refresh(ctx, sources.projectID.Hex(), "", ""); err != nil {
+		t.Fatalf("error while refreshing flag views: %v", err)
+	}
+
+	_, err := mongoDatabase.Collection("projects").UpdateByID(
+		ctx,
+		sources.projectID,
+		bson.D{{Key: "$set", Value: bson.D{{Key: "is_archived", Value: true}}}},
+	)
+	if err != nil {
+		t.Fatalf("error while archiving project: %v", err)
+	}
+
+	refreshedCount, err := flagViewRepository.Refresh(ctx, sources.projectID.Hex(), "", "")
+	if err != nil {
+		t.Fatalf("error while refreshing flag views: %v", err)
+	}
+
+	if refreshedCount != 0 {
+		t.Errorf("expected 0 refreshed views but got %d", refreshedCount)
+	}
+
+	viewCount, err := mongoDatabase.Collection(flagview.FlagViewCollection).CountDocuments(
+		ctx,
+		bson.D{{Key: "project_id", Value: sources.projectID}},
+	)
+	if err != nil {
+		t.Fatalf("error while counting flag views: %v", err)
+	}
+
+	if viewCount != 0 {
+		t.Errorf("expected the views of the archived project to be removed but found %d views", viewCount)
+	}
+}
+
+func TestRefresh_InvalidID(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 1*time.Second)
+	defer cancel()
+
+	_, err := flagViewRepository.Refresh(ctx, "invalid_object_id", "", "")
+	if err == nil {
+		t.Error("expected error when refreshing with an invalid project id")
+	}
+}
+
+func TestRebuild(t *testing.T) {
+	sources := insertViewSources(t, "view-rebuild", "staging", "new-checkout", "dark-mode")
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	if _, err := flagViewRepository.Rebuild(ctx); err != nil {
+		t.Fatalf("error while rebuilding flag views: %v", err)
+	}
+
+	viewCount, err := mongoDatabase.Collection(flagview.FlagViewCollection).CountDocuments(
+		ctx,
+		bson.D{{Key: "project_id", Value: sources.projectID}},
+	)
+	if err != nil {
+		t.Fatalf("error while counting flag views: %v", err)
+	}
+
+	if viewCount != 2 {
+		t.Errorf("expected 2 rebuilt views but found %d", viewCount)
+	}
+}
+
+// insertViewSources inserts a project with an environment and the flags
+// along with their flag settings and removes them along with their views when
+// the test completes.
+func insertViewSources(
+	t *testing.T,
+	projectKey string,
+	environmentName string,
+	flagNames ...string,
+) *viewSources {
+	t.Helper()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 1*time.Second)
+	defer cancel()
+
+	sources := &viewSources{
+		projectID:     primitive.NewObjectID(),
+		environmentID: primitive.NewObjectID(),
+	}
+
+	t.Cleanup(func() { cleanupViewSources(sources) })
+
+	insert := func(collection string, document bson.D) {
+		if _, err := mongoDatabase.Collection(collection).InsertOne(ctx, document); err != nil {
+			t.Fatalf("error while inserting into %v: %v", collection, err)
+		}
+	}
+
+	insert("projects", bson.D{
+		{Key: "_id", Value: sources.projectID},
+		{Key: "key", Value: projectKey},
+		{Key: "name", Value: projectKey},
+	})
+	insert("environments", bson.D{
+		{Key: "_id", Value: sources.environmentID},
+		{Key: "name", Value: environmentName},
+		{Key: "project_id", Value: sources.projectID},
+	})
+
+	for _, flagName := range flagNames {
+		flagID := primitive.NewObjectID()
+		settingID := primitive.NewObjectID()
+
+		insert("flags", bson.D{
+			{Key: "_id", Value: flagID},
+			{Key: "name", Value: flagName},
+			{Key: "project_id", Value: sources.projectID},
+		})
+		insert("flag_settings", bson.D{
+			{Key: "_id", Value: settingID},
+			{Key: "project_id", Value: sources.projectID},
+			{Key: "environment_id", Value: sources.environmentID},
+			{Key: "flag_id", Value: flagID},
+			{Key: "is_active", Value: true},
+			{Key: "version", Value: 1},
+		})
+
+		sources.flagIDs = append(sources.flagIDs, flagID)
+		sources.settingIDs = append(sources.settingIDs, settingID)
+	}
+
+	return sources
+}
+
+func cleanupViewSources(sources *viewSources) {
+	ctx, cancel := context.WithTimeout(context.Background(), 1*time.Second)
+	defer cancel()
+
+	byProject := bson.D{{Key: "project_id", Value: sources.projectID}}
+
+	_, _ = mongoDatabase.Collection("projects").DeleteOne(ctx, bson.D{{Key: "_id", Value: sources.projectID}})
+	_, _ = mongoDatabase.Collection("environments").DeleteMany(ctx, byProject)
+	_, _ = mongoDatabase.Collection("flags").DeleteMany(ctx, byProject)
+	_, _ = mongoDatabase.Collection("flag_settings").DeleteMany(ctx, byProject)
+	_, _ = mongoDatabase.Collection(flagview.FlagViewCollection).DeleteMany(ctx, byProject)
+}
+
+func TestMain(m *testing.M) {
+	client, mongoClientErr := getMongoClient()
+	if mongoClientErr != nil {
+		os.Exit(1)
+	}
+
+	mongoDatabase = client.Database("test")
+
+	ctx, cancel := context.WithTimeout(context.Background(), 1*time.Second)
+
+	viewRepo, repositoryErr := flagview.NewFlagViewRepository(ctx, mongoDatabase, &logger.StubLogger{})
+
+	cancel()
+
+	if repositoryErr != nil {
+		log.Fatalf("error while creating flag view repository: %v\n", repositoryErr)
+	}
+
+	flagViewRepository = viewRepo
+
+	code := m.Run()
+
+	_ = client.Disconnect(context.Background())
+
+	os.Exit(code)
+}
+
+func getMongoClient() (*mongo.Client, error) {
+	serverAPI := options.ServerAPI(options.ServerAPIVersion1)
+	opts := options.
+		Client().
+		ApplyURI(mongoDBConnectionString).
+		SetServerAPIOptions(serverAPI)
+
+	ctx := context.Background()
+	ctx, cancel := context.WithTimeout(ctx, 1*time.Second)
+	defer cancel()
+
+	client, err := mongo.Connect(ctx, opts)
+	if err != nil {
+		log.Printf("error while connecting to mongodb: %v\n", err)
+		return nil, err
+	}
+
+	return client, nil
+}
@@ -0,0 +1,28 @@
+package pagination
+
+import (
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// ErrInvalidPageToken is a GRPC error that is returned when the page token of
+// a listing could not be decoded or was issued for a listing with a different
+// order or filter.
+var ErrInvalidPageToken = status.Error(
+	codes.InvalidArgument,
+	"invalid page token",
+)
+
+// ErrInvalidPageSize is a GRPC error that is returned when the requested page
+// size is negative.
+var ErrInvalidPageSize = status.Error(
+	codes.InvalidArgument,
+	"page size cannot be negative",
+)
+
+// ErrInvalidSortField is a GRPC error that is returned when a listing is
+// requested to be sorted by an unknown field.
+var ErrInvalidSortField = status.Error(
+	codes.InvalidArgument,
+	"unknown sort field",
+)
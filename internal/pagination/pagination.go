@@ -0,0 +1,243 @@
+// Package pagination pages through the listings of the documents of a
+// collection with opaque cursors. A cursor records the position of the last
+// document of a page in the order of the listing, so that the next page starts
+// right after it even when documents are added or removed in between.
+package pagination
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"regexp"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// DefaultPageSize is the number of documents in a page when no page size is
+// requested.
+const DefaultPageSize = 50
+
+// MaxPageSize is the largest number of documents in a page.
+const MaxPageSize = 500
+
+// SortField is the field that a listing is sorted by. The values match the
+// SortField enums of the management APIs.
+type SortField int32
+
+const (
+	// SortByName sorts the documents by their name.
+	SortByName SortField = iota
+	// SortByCreatedAt sorts the documents by the time that they were created
+	// at.
+	SortByCreatedAt
+)
+
+// Position is the position of a document in a listing.
+type Position struct {
+	ID        primitive.ObjectID
+	Name      string
+	CreatedAt time.Time
+}
+
+// cursor is the decoded form of a page token. The order and the filter of the
+// listing are recorded so that a token is not used with another listing.
+type cursor struct {
+	SortBy     SortField          `json:"s"`
+	Descending bool               `json:"d,omitempty"`
+	NameFilter string             `json:"f,omitempty"`
+	Filters    string             `json:"x,omitempty"`
+	ID         primitive.ObjectID `json:"i"`
+	Name       string             `json:"n,omitempty"`
+	CreatedAt  time.Time          `json:"c"`
+}
+
+// Query is a request for a page of a listing.
+type Query struct {
+	// PageSize is the largest number of documents in the page.
+	PageSize int
+	// SortBy is the field that the documents are sorted by. Documents with
+	// the same value are sorted by their ID.
+	SortBy SortField
+	// Descending sorts the documents in descending order.
+	Descending bool
+	// NameFilter only lists the documents whose name contains it, ignoring
+	// the case.
+	NameFilter string
+	// filters are the encoded filters of the listing other than the name
+	// filter.
+	filters string
+	// after is the position of the last document of the previous page.
+	after *cursor
+}
+
+// NewQuery creates a query for the page of the listing that follows the page
+// token. A page size of 0 requests the default page size and larger page sizes
+// are capped. An empty page token requests the first page.
+func NewQuery(
+	pageSize int32,
+	pageToken string,
+	sortBy SortField,
+	descending bool,
+	nameFilter string,
+) (*Query, error) {
+	if pageSize < 0 {
+		return nil, ErrInvalidPageSize
+	}
+
+	if sortBy != SortByName && sortBy != SortByCreatedAt {
+		return nil, ErrInvalidSortField
+	}
+
+	query := &Query{
+		PageSize:   DefaultPageSize,
+		SortBy:     sortBy,
+		Descending: descending,
+		NameFilter: nameFilter,
+	}
+
+	if pageSize > 0 {
+		query.PageSize = min(int(pageSize), MaxPageSize)
+	}
+
+	if pageToken == "" {
+		return query, nil
+	}
+
+	after, err := decodeCursor(pageToken)
+	if err != nil {
+		return nil, ErrInvalidPageToken
+	}
+
+	if after.SortBy != sortBy || after.Descending != descending || after.NameFilter != nameFilter {
+		return nil, ErrInvalidPageToken
+	}
+
+	query.after = after
+
+	return query, nil
+}
+
+// SetFilters records the encoded filters of the listing other than the name
+// filter in the page tokens, so that a page token is not used with other
+// filters. Fails with ErrInvalidPageToken if the page token of the query was
+// issued for other filters.
+func (q *Query) SetFilters(filters string) error {
+	if q.after != nil && q.after.Filters != filters {
+		return ErrInvalidPageToken
+	}
+
+	q.filters = filters
+
+	return nil
+}
+
+// Filter adds the conditions of the name filter and of the position of the
+// page to the filter of the listing.
+func (q *Query) Filter(filter bson.D) bson.D {
+	pageFilter := append(bson.D{}, filter...)
+
+	if q.NameFilter != "" {
+		pageFilter = append(pageFilter, bson.E{
+			Key: "name",
+			Value: primitive.Regex{
+				Pattern: regexp.QuoteMeta(q.NameFilter),
+				Options: "i",
+			},
+		})
+	}
+
+	if q.after == nil {
+		return pageFilter
+	}
+
+	comparison := "$gt"
+	if q.Descending {
+		comparison = "$lt"
+	}
+
+	sortKey, sortValue := q.sortKey(), any(q.after.Name)
+	if q.SortBy == SortByCreatedAt {
+		sortValue = q.after.CreatedAt
+	}
+
+	// Documents with the same value of the sort field are ordered by their ID.
+	return append(pageFilter, bson.E{
+		Key: "$or",
+		Value: bson.A{
+			bson.D{{Key: sortKey, Value: bson.D{{Key: comparison, Value: sortValue}}}},
+			bson.D{
+				{Key: sortKey, Value: sortValue},
+				{Key: "_id", Value: bson.D{{Key: comparison, Value: q.after.ID}}},
+			},
+		},
+	})
+}
+
+// FindOptions sorts the documents of the listing and limits them to one more
+// than the page size, which tells whether there is a next page.
+func (q *Query) FindOptions() *options.FindOptions {
+	order := 1
+	if q.Descending {
+		order = -1
+	}
+
+	return options.Find().
+		SetSort(bson.D{{Key: q.sortKey(), Value: order}, {Key: "_id", Value: order}}).
+		SetLimit(int64(q.PageSize) + 1)
+}
+
+// Page trims the documents fetched with the find options to the page size and
+// creates the token of the next page from the position of the last document
+// of the page. The token is empty if this is the last page.
+func Page[T any](q *Query, documents []T, position func(*T) Position) ([]T, string) {
+	if len(documents) <= q.PageSize {
+		return documents, ""
+	}
+
+	documents = documents[:q.PageSize]
+	last := position(&documents[len(documents)-1])
+
+	return documents, encodeCursor(&cursor{
+		SortBy:     q.SortBy,
+		Descending: q.Descending,
+		NameFilter: q.NameFilter,
+		Filters:    q.filters,
+		ID:         last.ID,
+		Name:       last.Name,
+		CreatedAt:  last.CreatedAt,
+	})
+}
+
+// sortKey gets the key of the field of the documents that the listing is
+// sorted by.
+func (q *Query) sortKey() string {
+	if q.SortBy == SortByCreatedAt {
+		return "created_at"
+	}
+
+	return "name"
+}
+
+// encodeCursor encodes the cursor as an opaque page token.
+func encodeCursor(c *cursor) string {
+	encodedCursor, _ := json.Marshal(c)
+
+	return base64.RawURLEncoding.EncodeToString(encodedCursor)
+}
+
+// decodeCursor decodes the cursor from a page token.
+func decodeCursor(pageToken string) (*cursor, error) {
+	encodedCursor, err := base64.RawURLEncoding.DecodeString(pageToken)
+	if err != nil {
+		return nil, err
+	}
+
+	var c cursor
+	if err = json.Unmarshal(encodedCursor, &c); err != nil {
+		return nil, err
+	}
+
+	return &c, nil
+}
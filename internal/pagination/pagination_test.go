@@ -0,0 +1,161 @@
+package pagination_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"github.com/waduhek/flagger/internal/pagination"
+)
+
+type document struct {
+	id        primitive.ObjectID
+	name      string
+	createdAt time.Time
+}
+
+func documentPosition(d *document) pagination.Position {
+	return pagination.Position{ID: d.id, Name: d.name, CreatedAt: d.createdAt}
+}
+
+func newDocuments(n int) []document {
+	documents := make([]document, 0, n)
+	for i := range n {
+		documents = append(documents, document{
+			id:        primitive.NewObjectID(),
+			name:      string(rune('a' + i)),
+			createdAt: time.UnixMilli(int64(i) * 1000).UTC(),
+		})
+	}
+
+	return documents
+}
+
+func TestNewQuery_Defaults(t *testing.T) {
+	query, err := pagination.NewQuery(0, "", pagination.SortByName, false, "")
+	if err != nil {
+		t.Fatalf("could not create query: %v", err)
+	}
+
+	if query.PageSize != pagination.DefaultPageSize {
+		t.Errorf("expected the default page size but got %d", query.PageSize)
+	}
+
+	query, err = pagination.NewQuery(pagination.MaxPageSize+1, "", pagination.SortByName, false, "")
+	if err != nil {
+		t.Fatalf("could not create query: %v", err)
+	}
+
+	if query.PageSize != pagination.MaxPageSize {
+		t.Errorf("expected the page size to be capped but got %d", query.PageSize)
+	}
+}
+
+func TestNewQuery_Invalid(t *testing.T) {
+	query, _ := pagination.NewQuery(2, "", pagination.SortByCreatedAt, true, "flag")
+	_, pageToken := pagination.Page(query, newDocuments(3), documentPosition)
+
+	tests := []struct {
+		name       string
+		pageSize   int32
+		pageToken  string
+		sortBy     pagination.SortField
+		descending bool
+		nameFilter string
+		want       error
+	}{
+		{"negative_page_size", -1, "", pagination.SortByName, false, "", pagination.ErrInvalidPageSize},
+		{"unknown_sort_field", 0, "", pagination.SortField(7), false, "", pagination.ErrInvalidSortField},
+		{"malformed_token", 0, "not a token", pagination.SortByName, false, "", pagination.ErrInvalidPageToken},
+		{"other_sort_field", 2, pageToken, pagination.SortByName, true, "flag", pagination.ErrInvalidPageToken},
+		{"other_order", 2, pageToken, pagination.SortByCreatedAt, false, "flag", pagination.ErrInvalidPageToken},
+		{"other_filter", 2, pageToken, pagination.SortByCreatedAt, true, "", pagination.ErrInvalidPageToken},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(subT *testing.T) {
+			_, err := pagination.NewQuery(
+				test.pageSize,
+				test.pageToken,
+				test.sortBy,
+				test.descending,
+				test.nameFilter,
+			)
+			if !errors.Is(err, test.want) {
+				subT.Errorf("expected %v but got %v", test.want, err)
+			}
+		})
+	}
+}
+
+func TestPage(t *testing.T) {
+	query, _ := pagination.NewQuery(2, "", pagination.SortByCreatedAt, false, "")
+	documents := newDocuments(3)
+
+	page, pageToken := pagination.Page(query, documents, documentPosition)
+	if len(page) != 2 || pageToken == "" {
+		t.Fatalf("expected a page of 2 documents with a next page but got %d %q", len(page), pageToken)
+	}
+
+	nextQuery, err := pagination.NewQuery(2, pageToken, pagination.SortByCreatedAt, false, "")
+	if err != nil {
+		t.Fatalf("could not create query of the next page: %v", err)
+	}
+
+	filter := nextQuery.Filter(bson.D{{Key: "project_id", Value: "project"}})
+	if len(filter) != 2 || filter[1].Key != "$or" {
+		t.Fatalf("expected the filter to start after the last document but got %v", filter)
+	}
+
+	after, _ := filter[1].Value.(bson.A)
+	sameValue, _ := after[1].(bson.D)
+
+	if sameValue[0].Value != documents[1].createdAt {
+		t.Errorf("expected the next page to start after %v but got %v", documents[1].createdAt, sameValue[0].Value)
+	}
+
+	page, pageToken = pagination.Page(nextQuery, documents[2:], documentPosition)
+	if len(page) != 1 || pageToken != "" {
+		t.Fatalf("expected the last page to have 1 document but got %d %q", len(page), pageToken)
+	}
+}
+
+func TestQuery_NameFilter(t *testing.T) {
+	query, _ := pagination.NewQuery(0, "", pagination.SortByName, false, "new.checkout")
+
+	filter := query.Filter(bson.D{})
+	if len(filter) != 1 || filter[0].Key != "name" {
+		t.Fatalf("expected a filter on the name but got %v", filter)
+	}
+
+	regex, _ := filter[0].Value.(primitive.Regex)
+	if regex.Pattern != `new\.checkout` || regex.Options != "i" {
+		t.Errorf("expected a case insensitive literal match but got %v", regex)
+	}
+}
+
+func TestQuery_SetFilters(t *testing.T) {
+	query, _ := pagination.NewQuery(2, "", pagination.SortByName, false, "")
+	if err := query.SetFilters(`{"kind":"ops"}`); err != nil {
+		t.Fatalf("could not set the filters of the first page: %v", err)
+	}
+
+	_, pageToken := pagination.Page(query, newDocuments(3), documentPosition)
+
+	nextQuery, err := pagination.NewQuery(2, pageToken, pagination.SortByName, false, "")
+	if err != nil {
+		t.Fatalf("could not create query of the next page: %v", err)
+	}
+
+	if err = nextQuery.SetFilters(`{"kind":"ops"}`); err != nil {
+		t.Errorf("expected the page token to be accepted with the same filters but got %v", err)
+	}
+
+	otherQuery, _ := pagination.NewQuery(2, pageToken, pagination.SortByName, false, "")
+	if err = otherQuery.SetFilters(`{"kind":"release"}`); !errors.Is(err, pagination.ErrInvalidPageToken) {
+		t.Errorf("expected ErrInvalidPageToken with other filters but got %v", err)
+	}
+}
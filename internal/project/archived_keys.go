@@ -0,0 +1,117 @@
+package project
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/waduhek/flagger/internal/logger"
+)
+
+// ArchivedKeyRepository gets the keys of the archived projects.
+type ArchivedKeyRepository interface {
+	// GetArchivedKeys gets the keys of all the archived projects.
+	GetArchivedKeys(ctx context.Context) ([]string, error)
+}
+
+// ArchivedKeys is the set of the keys of the archived projects, which are
+// rejected by the project key interceptors. Projects archived by this replica
+// are added to the set immediately, while projects archived by the other
+// replicas are added when the set is refreshed.
+type ArchivedKeys struct {
+	repo   ArchivedKeyRepository
+	mu     sync.RWMutex
+	keys   map[string]struct{}
+	logger logger.Logger
+}
+
+// Contains checks if the project key is the key of an archived project. A nil
+// set doesn't contain any keys.
+func (a *ArchivedKeys) Contains(projectKey string) bool {
+	if a == nil {
+		return false
+	}
+
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+
+	_, ok := a.keys[projectKey]
+
+	return ok
+}
+
+// Add adds the key of a project that was archived to the set.
+func (a *ArchivedKeys) Add(projectKey string) {
+	if a == nil {
+		return
+	}
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	a.keys[projectKey] = struct{}{}
+}
+
+// Remove removes the key of a project that was restored or purged from the
+// set.
+func (a *ArchivedKeys) Remove(projectKey string) {
+	if a == nil {
+		return
+	}
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	delete(a.keys, projectKey)
+}
+
+// Refresh replaces the set with the keys of the projects that are currently
+// archived.
+func (a *ArchivedKeys) Refresh(ctx context.Context) error {
+	archivedKeys, err := a.repo.GetArchivedKeys(ctx)
+	if err != nil {
+		return err
+	}
+
+	keys := make(map[string]struct{}, len(archivedKeys))
+	for _, key := range archivedKeys {
+		keys[key] = struct{}{}
+	}
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	a.keys = keys
+
+	return nil
+}
+
+// StartRefreshing refreshes the set in the background at every interval until
+// the context is done.
+func (a *ArchivedKeys) StartRefreshing(ctx context.Context, interval time.Duration) {
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				if err := a.Refresh(ctx); err != nil {
+					a.logger.Warn("could not refresh the keys of archived projects: %v", err)
+				}
+			}
+		}
+	}()
+}
+
+// NewArchivedKeys creates an empty set of the keys of the archived projects,
+// which is filled when it is refreshed.
+func NewArchivedKeys(repo ArchivedKeyRepository, logger logger.Logger) *ArchivedKeys {
+	return &ArchivedKeys{
+		repo:   repo,
+		keys:   map[string]struct{}{},
+		logger: logger,
+	}
+}
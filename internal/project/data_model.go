@@ -3,18 +3,28 @@ package project
 import (
 	"context"
 	"time"
+
+	"github.com/waduhek/flagger/internal/pagination"
 )
 
+// Project is a collection of flags that are served in the environments of the
+// project. The environments, flags and flag settings of a project refer to it
+// by its ID. The clients identify the project by its key, which never changes,
+// so the name and the display name of the project can be changed freely. The
+// environments and flags of an archived project can't be accessed and its
+// flags are not served. A deleted project is archived until it is purged after
+// the time to purge it.
 type Project struct {
-	ID           string
-	Key          string
-	Name         string
-	Environments []string
-	Flags        []string
-	FlagSettings []string
-	CreatedBy    string
-	CreatedAt    time.Time
-	UpdatedAt    time.Time
+	ID          string
+	Key         string
+	Name        string
+	DisplayName string
+	IsArchived  bool
+	ArchivedAt  time.Time
+	PurgeAfter  time.Time
+	CreatedBy   string
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
 }
 
 // DataRepository is an interface to the operations that can be performed on
@@ -24,32 +34,81 @@ type DataRepository interface {
 	Save(ctx context.Context, project *Project) (string, error)
 
 	// GetByNameAndUserID gets the project by the name and the ID of the user
-	// that created the project.
+	// that created the project. Archived projects are not found.
 	GetByNameAndUserID(
 		ctx context.Context,
 		projectName string,
 		userID string,
 	) (*Project, error)
 
-	// AddEnvironment adds a new environment ID to the project by the project
-	// ID.
-	AddEnvironment(
+	// GetArchivedByNameAndUserID gets the archived project by the name and the
+	// ID of the user that created the project.
+	GetArchivedByNameAndUserID(
 		ctx context.Context,
-		projectID string,
-		environmentID string,
-	) (uint, error)
+		projectName string,
+		userID string,
+	) (*Project, error)
 
-	// AddFlag adds a new flag ID to the project by the project ID.
-	AddFlag(
+	// ListByUserID gets a page of the active or the archived projects created
+	// by the user along with the token of the next page, which is empty if
+	// this is the last page.
+	ListByUserID(
 		ctx context.Context,
-		projectID string,
-		flagID string,
-	) (uint, error)
+		userID string,
+		archived bool,
+		query *pagination.Query,
+	) ([]Project, string, error)
+
+	// MarkUpdated sets the time at which the project was last updated to the
+	// current time. Transactions that add environments or flags to the
+	// project mark it updated, so that concurrent transactions conflict and
+	// are retried with the environments and flags added by each other.
+	MarkUpdated(ctx context.Context, projectID string) (uint, error)
 
-	// AddFlagSettings adds new flag setting IDs to the project.
-	AddFlagSettings(
+	// Archive archives the project. The time to purge the project is set if it
+	// is not zero. The number of updated projects is returned.
+	Archive(ctx context.Context, projectID string, purgeAfter time.Time) (uint, error)
+
+	// Restore restores the archived project. The number of updated projects is
+	// returned.
+	Restore(ctx context.Context, projectID string) (uint, error)
+
+	// GetArchivedKeys gets the keys of all the archived projects.
+	GetArchivedKeys(ctx context.Context) ([]string, error)
+
+	// GetPurgeable gets the deleted projects whose time to purge has passed.
+	GetPurgeable(ctx context.Context, now time.Time) ([]Project, error)
+
+	// Rename changes the name and the display name of the project, which are
+	// not changed if they are empty. The number of updated projects is
+	// returned.
+	Rename(ctx context.Context, projectID string, name string, displayName string) (uint, error)
+
+	// Delete deletes the project. The number of deleted projects is returned.
+	Delete(ctx context.Context, projectID string) (uint, error)
+}
+
+// ResourceRepository deletes the resources of a project, such as its
+// environments, flags and flag settings. It is implemented by the repositories
+// of the resources, whose packages depend on this package.
+type ResourceRepository interface {
+	// DeleteByProjectID deletes all the resources of the project. The number
+	// of deleted resources is returned.
+	DeleteByProjectID(ctx context.Context, projectID string) (uint, error)
+}
+
+// ViewRepository refreshes the views of the flags of a project. It is
+// implemented by the flag view repository, whose package depends on this
+// package.
+type ViewRepository interface {
+	// Refresh recomputes the views of the flag settings of the project in the
+	// environment for the flag. An empty environment ID refreshes the views of
+	// all the environments and an empty flag ID refreshes the views of all the
+	// flags. The number of refreshed views is returned.
+	Refresh(
 		ctx context.Context,
 		projectID string,
-		flagSettingIDs ...string,
+		environmentID string,
+		flagID string,
 	) (uint, error)
 }
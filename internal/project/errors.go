@@ -32,25 +32,11 @@ var ErrCouldNotFetch = status.Error(
 	"error occurred while fetching project",
 )
 
-// ErrAddEnvironment is a GRPC error that is returned when an error occurs while
-// updating the environments array for a project.
-var ErrAddEnvironment = status.Error(
+// ErrMarkUpdated is a GRPC error that is returned when an error occurs while
+// updating the time at which a project was last updated.
+var ErrMarkUpdated = status.Error(
 	codes.Internal,
-	"could not create a new environment",
-)
-
-// ErrAddFlag is a GRPC error that is returned when an error occurs while
-// updating the flags array for a project.
-var ErrAddFlag = status.Error(
-	codes.Internal,
-	"could not update project with the flag",
-)
-
-// ErrAddFlagSetting is a GRPC error that is returned when an error occurs while
-// updating the flag settings array of a project.
-var ErrAddFlagSetting = status.Error(
-	codes.Internal,
-	"error while updating project with flag settings",
+	"could not update project",
 )
 
 // ErrMetadataNotFound is a GRPC error that is returned when trying to
@@ -81,3 +67,59 @@ var ErrProjectIDConvert = status.Error(
 	codes.Internal,
 	"could not convert project ID to expected type",
 )
+
+// ErrCouldNotArchive is a GRPC error that is returned when an error occurs
+// while archiving a project.
+var ErrCouldNotArchive = status.Error(
+	codes.Internal,
+	"could not archive project",
+)
+
+// ErrCouldNotRestore is a GRPC error that is returned when an error occurs
+// while restoring a project.
+var ErrCouldNotRestore = status.Error(
+	codes.Internal,
+	"could not restore project",
+)
+
+// ErrCouldNotRename is a GRPC error that is returned when an error occurs
+// while renaming a project.
+var ErrCouldNotRename = status.Error(
+	codes.Internal,
+	"could not rename project",
+)
+
+// ErrNothingToRename is a GRPC error that is returned when renaming a project
+// without a new name or display name.
+var ErrNothingToRename = status.Error(
+	codes.InvalidArgument,
+	"a new name or display name is required",
+)
+
+// ErrCouldNotDelete is a GRPC error that is returned when an error occurs
+// while deleting a project.
+var ErrCouldNotDelete = status.Error(
+	codes.Internal,
+	"could not delete project",
+)
+
+// ErrTxnSession is a GRPC error that is returned when a new session for
+// starting a transaction could not be created.
+var ErrTxnSession = status.Error(
+	codes.Internal,
+	"could not create a transaction session",
+)
+
+// ErrRetentionExpired is a GRPC error that is returned when attempting to
+// restore a deleted project after its retention window has passed.
+var ErrRetentionExpired = status.Error(
+	codes.FailedPrecondition,
+	"the retention window of the deleted project has passed",
+)
+
+// ErrProjectArchived is a GRPC error that is returned when the key of an
+// archived project is used to evaluate its flags.
+var ErrProjectArchived = status.Error(
+	codes.PermissionDenied,
+	"the project is archived",
+)
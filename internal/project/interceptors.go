@@ -10,10 +10,12 @@ import (
 )
 
 // KeyUnaryInterceptor intercepts an incoming request to the provided server
-// path and ensures that the request contains the project key in the metadata.
+// path and ensures that the request contains the project key in the metadata
+// and that the project is not archived.
 func KeyUnaryInterceptor(
 	logger logger.Logger,
 	serverPath string,
+	archivedKeys *ArchivedKeys,
 ) grpc.UnaryServerInterceptor {
 	return func(
 		ctx context.Context,
@@ -30,6 +32,52 @@ func KeyUnaryInterceptor(
 			return nil, err
 		}
 
+		if err = CheckNotArchived(newCtx, logger, archivedKeys); err != nil {
+			return nil, err
+		}
+
 		return handler(newCtx, req)
 	}
 }
+
+// KeyStreamInterceptor intercepts an incoming stream to the provided server
+// path and ensures that the stream contains the project key in the metadata
+// and that the project is not archived.
+func KeyStreamInterceptor(
+	logger logger.Logger,
+	serverPath string,
+	archivedKeys *ArchivedKeys,
+) grpc.StreamServerInterceptor {
+	return func(
+		srv any,
+		stream grpc.ServerStream,
+		info *grpc.StreamServerInfo,
+		handler grpc.StreamHandler,
+	) error {
+		if !strings.HasPrefix(info.FullMethod, serverPath) {
+			return handler(srv, stream)
+		}
+
+		newCtx, err := AuthoriseProject(stream.Context(), logger)
+		if err != nil {
+			return err
+		}
+
+		if err = CheckNotArchived(newCtx, logger, archivedKeys); err != nil {
+			return err
+		}
+
+		return handler(srv, &keyServerStream{ServerStream: stream, ctx: newCtx})
+	}
+}
+
+// keyServerStream is a server stream with a context that contains the project
+// key.
+type keyServerStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *keyServerStream) Context() context.Context {
+	return s.ctx
+}
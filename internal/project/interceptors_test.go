@@ -0,0 +1,69 @@
+package project_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+
+	"github.com/waduhek/flagger/internal/logger"
+	"github.com/waduhek/flagger/internal/project"
+)
+
+// fakeArchivedKeyRepository serves the keys of the archived projects from
+// memory.
+type fakeArchivedKeyRepository struct {
+	keys []string
+}
+
+func (r *fakeArchivedKeyRepository) GetArchivedKeys(context.Context) ([]string, error) {
+	return r.keys, nil
+}
+
+func TestKeyUnaryInterceptor_ArchivedProject(t *testing.T) {
+	repo := &fakeArchivedKeyRepository{keys: []string{"archived-key"}}
+	archivedKeys := project.NewArchivedKeys(repo, &logger.StubLogger{})
+
+	if err := archivedKeys.Refresh(context.Background()); err != nil {
+		t.Fatalf("error while refreshing archived keys: %v", err)
+	}
+
+	interceptor := project.KeyUnaryInterceptor(&logger.StubLogger{}, "/providerpb.FlagProvider/", archivedKeys)
+	info := &grpc.UnaryServerInfo{FullMethod: "/providerpb.FlagProvider/GetFlag"}
+	handler := func(context.Context, any) (any, error) {
+		return "served", nil
+	}
+
+	intercept := func(projectKey string) error {
+		ctx := metadata.NewIncomingContext(
+			context.Background(),
+			metadata.Pairs("x-flagger-token", projectKey),
+		)
+
+		_, err := interceptor(ctx, nil, info, handler)
+
+		return err
+	}
+
+	if err := intercept("active-key"); err != nil {
+		t.Fatalf("expected the key of an active project to be accepted but got %v", err)
+	}
+
+	if err := intercept("archived-key"); !errors.Is(err, project.ErrProjectArchived) {
+		t.Fatalf("expected ErrProjectArchived but got %v", err)
+	}
+
+	archivedKeys.Remove("archived-key")
+
+	if err := intercept("archived-key"); err != nil {
+		t.Fatalf("expected the key of a restored project to be accepted but got %v", err)
+	}
+
+	archivedKeys.Add("active-key")
+
+	if err := intercept("active-key"); !errors.Is(err, project.ErrProjectArchived) {
+		t.Fatalf("expected ErrProjectArchived but got %v", err)
+	}
+}
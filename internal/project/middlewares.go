@@ -2,6 +2,8 @@ package project
 
 import (
 	"context"
+	"net/http"
+	"strings"
 
 	"google.golang.org/grpc/metadata"
 
@@ -43,3 +45,49 @@ func AuthoriseProject(
 
 	return injectProjectKeyIntoContext(ctx, projectToken), nil
 }
+
+// AuthoriseHTTPRequest checks if the project token is present in the headers of
+// an HTTP request, either in the same header as the GRPC metadata or as a
+// bearer token. If the project token exists, it adds the token to the returned
+// context.
+func AuthoriseHTTPRequest(
+	r *http.Request,
+	logger logger.Logger,
+) (context.Context, error) {
+	projectToken := r.Header.Get(projectTokenMetadataKey)
+
+	if projectToken == "" {
+		bearerToken, ok := strings.CutPrefix(
+			r.Header.Get("Authorization"),
+			"Bearer ",
+		)
+		if ok {
+			projectToken = bearerToken
+		}
+	}
+
+	if projectToken == "" {
+		logger.Error("could not find the project token")
+		return nil, ErrProjectKeyNotFound
+	}
+
+	return injectProjectKeyIntoContext(r.Context(), projectToken), nil
+}
+
+// CheckNotArchived checks that the project key in the context, which was added
+// by AuthoriseProject or AuthoriseHTTPRequest, is not the key of an archived
+// project.
+func CheckNotArchived(
+	ctx context.Context,
+	logger logger.Logger,
+	archivedKeys *ArchivedKeys,
+) error {
+	projectKey, _ := KeyFromContext(ctx)
+
+	if archivedKeys.Contains(projectKey) {
+		logger.Error("project key of an archived project was used")
+		return ErrProjectArchived
+	}
+
+	return nil
+}
@@ -0,0 +1,115 @@
+package project
+
+import (
+	"context"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+
+	"github.com/waduhek/flagger/internal/logger"
+)
+
+// legacyProjectMongoModel is the MongoDB representation of a project from when
+// the projects kept the IDs of their environments, flags and flag settings in
+// arrays.
+type legacyProjectMongoModel struct {
+	ID           primitive.ObjectID   `bson:"_id"`
+	Environments []primitive.ObjectID `bson:"environments"`
+	Flags        []primitive.ObjectID `bson:"flags"`
+	FlagSettings []primitive.ObjectID `bson:"flag_settings"`
+}
+
+// MigrateProjectArrays backfills the project ID of the environments, flags and
+// flag settings in the arrays of the projects and then drops the arrays. The
+// arrays of a project are only dropped once all of its documents refer to it,
+// so an interrupted migration can be run again. The number of migrated
+// projects is returned.
+func MigrateProjectArrays(
+	ctx context.Context,
+	db *mongo.Database,
+	logger logger.Logger,
+) (uint, error) {
+	projectColl := db.Collection(ProjectCollection)
+
+	cursor, err := projectColl.Find(ctx, bson.D{
+		{Key: "$or", Value: bson.A{
+			bson.D{{Key: "environments", Value: bson.D{{Key: "$exists", Value: true}}}},
+			bson.D{{Key: "flags", Value: bson.D{{Key: "$exists", Value: true}}}},
+			bson.D{{Key: "flag_settings", Value: bson.D{{Key: "$exists", Value: true}}}},
+		}},
+	})
+	if err != nil {
+		logger.Error("error while finding projects to migrate: %v", err)
+		return 0, err
+	}
+	defer cursor.Close(ctx)
+
+	var migratedCount uint
+
+	for cursor.Next(ctx) {
+		var legacyProject legacyProjectMongoModel
+		if err = cursor.Decode(&legacyProject); err != nil {
+			logger.Error("error while decoding project to migrate: %v", err)
+			return migratedCount, err
+		}
+
+		if err = migrateProject(ctx, db, &legacyProject); err != nil {
+			logger.Error("error while migrating project %q: %v", legacyProject.ID.Hex(), err)
+			return migratedCount, err
+		}
+
+		migratedCount++
+	}
+
+	if err = cursor.Err(); err != nil {
+		logger.Error("error while reading projects to migrate: %v", err)
+		return migratedCount, err
+	}
+
+	return migratedCount, nil
+}
+
+// migrateProject backfills the project ID of the documents in the arrays of
+// the project and drops the arrays.
+func migrateProject(
+	ctx context.Context,
+	db *mongo.Database,
+	legacyProject *legacyProjectMongoModel,
+) error {
+	references := map[string][]primitive.ObjectID{
+		"environments":  legacyProject.Environments,
+		"flags":         legacyProject.Flags,
+		"flag_settings": legacyProject.FlagSettings,
+	}
+
+	for collection, ids := range references {
+		if len(ids) == 0 {
+			continue
+		}
+
+		_, err := db.Collection(collection).UpdateMany(
+			ctx,
+			bson.D{
+				{Key: "_id", Value: bson.D{{Key: "$in", Value: ids}}},
+				{Key: "project_id", Value: bson.D{{Key: "$exists", Value: false}}},
+			},
+			bson.D{{Key: "$set", Value: bson.D{{Key: "project_id", Value: legacyProject.ID}}}},
+		)
+		if err != nil {
+			return err
+		}
+	}
+
+	_, err := db.Collection(ProjectCollection).UpdateOne(
+		ctx,
+		bson.D{{Key: "_id", Value: legacyProject.ID}},
+		bson.D{{Key: "$unset", Value: bson.D{
+			{Key: "environments", Value: ""},
+			{Key: "flags", Value: ""},
+			{Key: "flag_settings", Value: ""},
+		}}},
+	)
+
+	return err
+}
@@ -0,0 +1,79 @@
+package project_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"github.com/waduhek/flagger/internal/logger"
+	"github.com/waduhek/flagger/internal/project"
+)
+
+func TestMigrateProjectArrays(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 1*time.Second)
+	defer cancel()
+
+	db := projectCollection.Database()
+	projectID := primitive.NewObjectID()
+	environmentID := primitive.NewObjectID()
+
+	t.Cleanup(func() {
+		cleanupCtx, cleanupCancel := context.WithTimeout(context.Background(), 1*time.Second)
+		defer cleanupCancel()
+
+		_, _ = projectCollection.DeleteOne(cleanupCtx, bson.D{{Key: "_id", Value: projectID}})
+		_, _ = db.Collection("environments").DeleteOne(cleanupCtx, bson.D{{Key: "_id", Value: environmentID}})
+	})
+
+	_, err := projectCollection.InsertOne(ctx, bson.D{
+		{Key: "_id", Value: projectID},
+		{Key: "key", Value: "migration-test"},
+		{Key: "name", Value: "migration-test"},
+		{Key: "environments", Value: bson.A{environmentID}},
+		{Key: "flags", Value: bson.A{}},
+		{Key: "flag_settings", Value: bson.A{}},
+	})
+	if err != nil {
+		t.Fatalf("error while inserting legacy project: %v", err)
+	}
+
+	_, err = db.Collection("environments").InsertOne(ctx, bson.D{
+		{Key: "_id", Value: environmentID},
+		{Key: "name", Value: "production"},
+	})
+	if err != nil {
+		t.Fatalf("error while inserting legacy environment: %v", err)
+	}
+
+	if _, err = project.MigrateProjectArrays(ctx, db, &logger.StubLogger{}); err != nil {
+		t.Fatalf("error while migrating projects: %v", err)
+	}
+
+	var migratedEnvironment bson.M
+
+	err = db.Collection("environments").FindOne(ctx, bson.D{{Key: "_id", Value: environmentID}}).
+		Decode(&migratedEnvironment)
+	if err != nil {
+		t.Fatalf("error while getting migrated environment: %v", err)
+	}
+
+	if migratedEnvironment["project_id"] != projectID {
+		t.Errorf("expected project id %v to be backfilled but got %v", projectID, migratedEnvironment["project_id"])
+	}
+
+	var migratedProject bson.M
+
+	err = projectCollection.FindOne(ctx, bson.D{{Key: "_id", Value: projectID}}).Decode(&migratedProject)
+	if err != nil {
+		t.Fatalf("error while getting migrated project: %v", err)
+	}
+
+	for _, field := range []string{"environments", "flags", "flag_settings"} {
+		if _, ok := migratedProject[field]; ok {
+			t.Errorf("expected field %q to be dropped", field)
+		}
+	}
+}
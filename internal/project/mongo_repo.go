@@ -10,7 +10,9 @@ import (
 	"go.mongodb.org/mongo-driver/mongo"
 	"go.mongodb.org/mongo-driver/mongo/options"
 
+	"github.com/waduhek/flagger/internal/alias"
 	"github.com/waduhek/flagger/internal/logger"
+	"github.com/waduhek/flagger/internal/pagination"
 	"github.com/waduhek/flagger/internal/user"
 )
 
@@ -18,15 +20,26 @@ const ProjectCollection string = "projects"
 
 // projectMongoModel is the MongoDB representation of the `Project` struct.
 type projectMongoModel struct {
-	ID           primitive.ObjectID   `bson:"_id,omitempty"`
-	Key          string               `bson:"key"`
-	Name         string               `bson:"name"`
-	Environments []primitive.ObjectID `bson:"environments,omitempty"`
-	Flags        []primitive.ObjectID `bson:"flags,omitempty"`
-	FlagSettings []primitive.ObjectID `bson:"flag_settings,omitempty"`
-	CreatedBy    primitive.ObjectID   `bson:"created_by"`
-	CreatedAt    time.Time            `bson:"created_at"`
-	UpdatedAt    time.Time            `bson:"updated_at"`
+	ID          primitive.ObjectID `bson:"_id,omitempty"`
+	Key         string             `bson:"key"`
+	Name        string             `bson:"name"`
+	DisplayName string             `bson:"display_name,omitempty"`
+	IsArchived  bool               `bson:"is_archived,omitempty"`
+	ArchivedAt  time.Time          `bson:"archived_at,omitempty"`
+	PurgeAfter  time.Time          `bson:"purge_after,omitempty"`
+	CreatedBy   primitive.ObjectID `bson:"created_by"`
+	CreatedAt   time.Time          `bson:"created_at"`
+	UpdatedAt   time.Time          `bson:"updated_at"`
+}
+
+// archivedFilter creates the filter of the archived or the active projects.
+// Projects created before projects could be archived don't have the field.
+func archivedFilter(archived bool) bson.E {
+	if archived {
+		return bson.E{Key: "is_archived", Value: true}
+	}
+
+	return bson.E{Key: "is_archived", Value: bson.D{{Key: "$ne", Value: true}}}
 }
 
 type MongoDataRepository struct {
@@ -38,26 +51,6 @@ func (p *MongoDataRepository) Save(
 	ctx context.Context,
 	project *Project,
 ) (string, error) {
-	mappedEnvironmentIDs, mapEnvironmentIDErr :=
-		mapStringSliceToObjectIDs(project.Environments)
-	if mapEnvironmentIDErr != nil {
-		p.logger.Error("could not map environment ids to object ids: %v", mapEnvironmentIDErr)
-		return "", ErrCouldNotSave
-	}
-
-	mappedFlagIDs, mapFlagIDErr := mapStringSliceToObjectIDs(project.Flags)
-	if mapFlagIDErr != nil {
-		p.logger.Error("could not map flag ids to object ids: %v", mapFlagIDErr)
-		return "", ErrCouldNotSave
-	}
-
-	mappedFlagSettingIDs, mapFlagSettingIDErr :=
-		mapStringSliceToObjectIDs(project.FlagSettings)
-	if mapFlagSettingIDErr != nil {
-		p.logger.Error("could not map flag setting ids to object ids: %v", mapFlagSettingIDErr)
-		return "", ErrCouldNotSave
-	}
-
 	createdByObjID, createdByObjIDErr := primitive.ObjectIDFromHex(project.CreatedBy)
 	if createdByObjIDErr != nil {
 		p.logger.Error("could not convert created by to object id: %v", createdByObjIDErr)
@@ -65,14 +58,12 @@ func (p *MongoDataRepository) Save(
 	}
 
 	projectToAdd := &projectMongoModel{
-		Key:          project.Key,
-		Name:         project.Name,
-		Environments: mappedEnvironmentIDs,
-		Flags:        mappedFlagIDs,
-		FlagSettings: mappedFlagSettingIDs,
-		CreatedBy:    createdByObjID,
-		CreatedAt:    time.Now(),
-		UpdatedAt:    time.Now(),
+		Key:         project.Key,
+		Name:        project.Name,
+		DisplayName: project.DisplayName,
+		CreatedBy:   createdByObjID,
+		CreatedAt:   time.Now(),
+		UpdatedAt:   time.Now(),
 	}
 
 	saveResult, saveErr := p.coll.InsertOne(ctx, projectToAdd)
@@ -102,6 +93,25 @@ func (p *MongoDataRepository) GetByNameAndUserID(
 	ctx context.Context,
 	projectName string,
 	userID string,
+) (*Project, error) {
+	return p.getByNameAndUserID(ctx, projectName, userID, false)
+}
+
+func (p *MongoDataRepository) GetArchivedByNameAndUserID(
+	ctx context.Context,
+	projectName string,
+	userID string,
+) (*Project, error) {
+	return p.getByNameAndUserID(ctx, projectName, userID, true)
+}
+
+// getByNameAndUserID gets the archived or the active project by the name and
+// the ID of the user that created the project.
+func (p *MongoDataRepository) getByNameAndUserID(
+	ctx context.Context,
+	projectName string,
+	userID string,
+	archived bool,
 ) (*Project, error) {
 	userIDObjectID, userIDErr := primitive.ObjectIDFromHex(userID)
 	if userIDErr != nil {
@@ -112,6 +122,7 @@ func (p *MongoDataRepository) GetByNameAndUserID(
 	query := bson.D{
 		{Key: "created_by", Value: userIDObjectID},
 		{Key: "name", Value: projectName},
+		archivedFilter(archived),
 	}
 
 	var decodedProject projectMongoModel
@@ -131,25 +142,60 @@ func (p *MongoDataRepository) GetByNameAndUserID(
 		return nil, ErrCouldNotFetch
 	}
 
-	project := &Project{
-		ID:           decodedProject.ID.Hex(),
-		Key:          decodedProject.Key,
-		Name:         decodedProject.Name,
-		Environments: mapObjectIDsToStringSlice(decodedProject.Environments),
-		Flags:        mapObjectIDsToStringSlice(decodedProject.Flags),
-		FlagSettings: mapObjectIDsToStringSlice(decodedProject.FlagSettings),
-		CreatedBy:    decodedProject.CreatedBy.Hex(),
-		CreatedAt:    decodedProject.CreatedAt,
-		UpdatedAt:    decodedProject.UpdatedAt,
-	}
-
-	return project, nil
+	return mapMongoModelToStruct(&decodedProject), nil
 }
 
-func (p *MongoDataRepository) AddEnvironment(
+func (p *MongoDataRepository) ListByUserID(
+	ctx context.Context,
+	userID string,
+	archived bool,
+	query *pagination.Query,
+) ([]Project, string, error) {
+	userIDObjectID, userIDErr := primitive.ObjectIDFromHex(userID)
+	if userIDErr != nil {
+		p.logger.Error("could not convert user ID to ObjectID")
+		return nil, "", user.ErrUserIDConvert
+	}
+
+	cursor, err := p.coll.Find(
+		ctx,
+		query.Filter(bson.D{{Key: "created_by", Value: userIDObjectID}, archivedFilter(archived)}),
+		query.FindOptions(),
+	)
+	if err != nil {
+		p.logger.Error("error while listing projects of user %q: %v", userID, err)
+		return nil, "", ErrCouldNotFetch
+	}
+
+	var decodedProjects []projectMongoModel
+	if err = cursor.All(ctx, &decodedProjects); err != nil {
+		p.logger.Error("error while decoding projects of user %q: %v", userID, err)
+		return nil, "", ErrCouldNotFetch
+	}
+
+	decodedProjects, nextPageToken := pagination.Page(
+		query,
+		decodedProjects,
+		func(decodedProject *projectMongoModel) pagination.Position {
+			return pagination.Position{
+				ID:        decodedProject.ID,
+				Name:      decodedProject.Name,
+				CreatedAt: decodedProject.CreatedAt,
+			}
+		},
+	)
+
+	projects := make([]Project, 0, len(decodedProjects))
+	for i := range decodedProjects {
+		projects = append(projects, *mapMongoModelToStruct(&decodedProjects[i]))
+	}
+
+	return projects, nextPageToken, nil
+}
+
+func (p *MongoDataRepository) MarkUpdated(
 	ctx context.Context,
 	projectID string,
-	environmentID string,
 ) (uint, error) {
 	projectIDObjID, projectIDConvertErr := primitive.ObjectIDFromHex(projectID)
 	if projectIDConvertErr != nil {
@@ -157,20 +203,8 @@ func (p *MongoDataRepository) AddEnvironment(
 		return 0, ErrProjectIDConvert
 	}
 
-	environmentIDObjID, environmentIDObjIDErr := primitive.ObjectIDFromHex(environmentID)
-	if environmentIDObjIDErr != nil {
-		p.logger.Error("could not convert environment id to object id")
-		return 0, ErrProjectIDConvert
-	}
-
-	filterQuery := bson.D{
-		{Key: "_id", Value: projectIDObjID},
-	}
+	filterQuery := bson.D{{Key: "_id", Value: projectIDObjID}}
 	updateQuery := bson.D{
-		{
-			Key:   "$push",
-			Value: bson.D{{Key: "environments", Value: environmentIDObjID}},
-		},
 		{
 			Key:   "$set",
 			Value: bson.D{{Key: "updated_at", Value: time.Now()}},
@@ -179,114 +213,212 @@ func (p *MongoDataRepository) AddEnvironment(
 
 	updateResult, updateErr := p.coll.UpdateOne(ctx, filterQuery, updateQuery)
 	if updateErr != nil {
-		p.logger.Error(
-			"error while adding environment %q to project %q: %v",
-			environmentID,
-			projectIDObjID,
-			updateErr,
-		)
-		return 0, ErrAddEnvironment
+		p.logger.Error("error while marking project %q updated: %v", projectID, updateErr)
+		return 0, ErrMarkUpdated
 	}
 
 	//nolint:gosec // ModifiedCount can't be a negative number.
 	return uint(updateResult.ModifiedCount), nil
 }
 
-func (p *MongoDataRepository) AddFlag(
+func (p *MongoDataRepository) Archive(
 	ctx context.Context,
 	projectID string,
-	flagID string,
+	purgeAfter time.Time,
 ) (uint, error) {
-	projectIDObjID, projectIDObjIDErr := primitive.ObjectIDFromHex(projectID)
-	if projectIDObjIDErr != nil {
-		p.logger.Error("error while converting project id to object id: %v", projectIDObjIDErr)
-		return 0, ErrAddFlag
+	projectIDObjID, projectIDConvertErr := primitive.ObjectIDFromHex(projectID)
+	if projectIDConvertErr != nil {
+		p.logger.Error("could not convert project ID to ObjectID")
+		return 0, ErrProjectIDConvert
 	}
 
-	flagIDObjID, flagIDObjIDErr := primitive.ObjectIDFromHex(flagID)
-	if flagIDObjIDErr != nil {
-		p.logger.Error("error while converting flag id to object id: %v", flagIDObjIDErr)
-		return 0, ErrAddFlag
+	fields := bson.D{{Key: "is_archived", Value: true}}
+	if !purgeAfter.IsZero() {
+		fields = append(fields, bson.E{Key: "purge_after", Value: purgeAfter})
 	}
 
-	filterQuery := bson.D{{Key: "_id", Value: projectIDObjID}}
-	updateQuery := bson.D{
-		{
-			Key:   "$push",
-			Value: bson.D{{Key: "flags", Value: flagIDObjID}},
-		},
-		{
-			Key:   "$set",
-			Value: bson.D{{Key: "updated_at", Value: time.Now()}},
-		},
+	// The time of archiving an archived project that is deleted is kept.
+	updateQuery := bson.A{
+		bson.D{{Key: "$set", Value: fields}},
+		bson.D{{
+			Key: "$set",
+			Value: bson.D{{
+				Key:   "archived_at",
+				Value: bson.D{{Key: "$ifNull", Value: bson.A{"$archived_at", "$$NOW"}}},
+			}},
+		}},
 	}
 
-	updateResult, updateErr := p.coll.UpdateOne(ctx, filterQuery, updateQuery)
+	updateResult, updateErr := p.coll.UpdateByID(ctx, projectIDObjID, updateQuery)
 	if updateErr != nil {
-		p.logger.Error(
-			"error while updating project with the flag: %v",
-			updateErr,
-		)
-
-		return 0, ErrAddFlag
+		p.logger.Error("error while archiving project %q: %v", projectID, updateErr)
+		return 0, ErrCouldNotArchive
 	}
 
 	//nolint:gosec // ModifiedCount can't be a negative number.
 	return uint(updateResult.ModifiedCount), nil
 }
 
-func (p *MongoDataRepository) AddFlagSettings(
+func (p *MongoDataRepository) Restore(
 	ctx context.Context,
 	projectID string,
-	flagSettingIDs ...string,
 ) (uint, error) {
-	projectIDObjID, projectIDObjIDErr := primitive.ObjectIDFromHex(projectID)
-	if projectIDObjIDErr != nil {
-		p.logger.Error("error while converting project id to object id: %v", projectIDObjIDErr)
-		return 0, ErrAddFlagSetting
-	}
-
-	flagSettingIDObjIDs := make([]primitive.ObjectID, 0, len(flagSettingIDs))
-	for _, flagSettingID := range flagSettingIDs {
-		flagSettingIDObjID, flagSettingIDObjIDErr := primitive.ObjectIDFromHex(flagSettingID)
-		if flagSettingIDObjIDErr != nil {
-			p.logger.Error("error while converting flag setting id to object id: %v", flagSettingIDObjIDErr)
-			return 0, ErrAddFlagSetting
-		}
-
-		flagSettingIDObjIDs = append(flagSettingIDObjIDs, flagSettingIDObjID)
+	projectIDObjID, projectIDConvertErr := primitive.ObjectIDFromHex(projectID)
+	if projectIDConvertErr != nil {
+		p.logger.Error("could not convert project ID to ObjectID")
+		return 0, ErrProjectIDConvert
 	}
 
-	filter := bson.D{{Key: "_id", Value: projectIDObjID}}
-	update := bson.D{
+	updateQuery := bson.D{
 		{
-			Key: "$push",
+			Key: "$unset",
 			Value: bson.D{
-				{
-					Key:   "flag_settings",
-					Value: bson.D{{Key: "$each", Value: flagSettingIDObjIDs}},
-				},
+				{Key: "is_archived", Value: ""},
+				{Key: "archived_at", Value: ""},
+				{Key: "purge_after", Value: ""},
 			},
 		},
-		{
-			Key:   "$set",
-			Value: bson.D{{Key: "updated_at", Value: time.Now()}},
-		},
 	}
 
-	updateResult, updateErr := p.coll.UpdateOne(ctx, filter, update)
+	updateResult, updateErr := p.coll.UpdateByID(ctx, projectIDObjID, updateQuery)
 	if updateErr != nil {
-		p.logger.Error(
-			"error while updating project with flag settings: %v",
-			updateErr,
-		)
-		return 0, ErrAddFlagSetting
+		p.logger.Error("error while restoring project %q: %v", projectID, updateErr)
+		return 0, ErrCouldNotRestore
 	}
 
 	//nolint:gosec // ModifiedCount can't be a negative number.
 	return uint(updateResult.ModifiedCount), nil
 }
 
+func (p *MongoDataRepository) GetArchivedKeys(ctx context.Context) ([]string, error) {
+	cursor, err := p.coll.Find(
+		ctx,
+		bson.D{archivedFilter(true)},
+		options.Find().SetProjection(bson.D{{Key: "key", Value: 1}}),
+	)
+	if err != nil {
+		p.logger.Error("error while getting archived projects: %v", err)
+		return nil, ErrCouldNotFetch
+	}
+
+	var decodedProjects []projectMongoModel
+	if err = cursor.All(ctx, &decodedProjects); err != nil {
+		p.logger.Error("error while decoding archived projects: %v", err)
+		return nil, ErrCouldNotFetch
+	}
+
+	keys := make([]string, 0, len(decodedProjects))
+	for i := range decodedProjects {
+		keys = append(keys, decodedProjects[i].Key)
+	}
+
+	return keys, nil
+}
+
+func (p *MongoDataRepository) GetPurgeable(
+	ctx context.Context,
+	now time.Time,
+) ([]Project, error) {
+	query := bson.D{
+		archivedFilter(true),
+		{Key: "purge_after", Value: bson.D{{Key: "$lte", Value: now}}},
+	}
+
+	cursor, err := p.coll.Find(ctx, query)
+	if err != nil {
+		p.logger.Error("error while getting purgeable projects: %v", err)
+		return nil, ErrCouldNotFetch
+	}
+
+	var decodedProjects []projectMongoModel
+	if err = cursor.All(ctx, &decodedProjects); err != nil {
+		p.logger.Error("error while decoding purgeable projects: %v", err)
+		return nil, ErrCouldNotFetch
+	}
+
+	projects := make([]Project, 0, len(decodedProjects))
+	for i := range decodedProjects {
+		projects = append(projects, *mapMongoModelToStruct(&decodedProjects[i]))
+	}
+
+	return projects, nil
+}
+
+func (p *MongoDataRepository) Rename(
+	ctx context.Context,
+	projectID string,
+	name string,
+	displayName string,
+) (uint, error) {
+	projectIDObjID, projectIDConvertErr := primitive.ObjectIDFromHex(projectID)
+	if projectIDConvertErr != nil {
+		p.logger.Error("could not convert project ID to ObjectID")
+		return 0, ErrProjectIDConvert
+	}
+
+	// The projects are not served by their name, so they don't keep aliases.
+	updateResult, updateErr := p.coll.UpdateByID(
+		ctx,
+		projectIDObjID,
+		alias.RenameUpdate(name, displayName, time.Time{}),
+	)
+	if updateErr != nil {
+		if mongo.IsDuplicateKeyError(updateErr) {
+			p.logger.Error("a project with name %q exists", name)
+			return 0, ErrNameTaken
+		}
+
+		p.logger.Error("error while renaming project %q: %v", projectID, updateErr)
+		return 0, ErrCouldNotRename
+	}
+
+	//nolint:gosec // ModifiedCount can't be a negative number.
+	return uint(updateResult.ModifiedCount), nil
+}
+
+func (p *MongoDataRepository) Delete(
+	ctx context.Context,
+	projectID string,
+) (uint, error) {
+	projectIDObjID, projectIDConvertErr := primitive.ObjectIDFromHex(projectID)
+	if projectIDConvertErr != nil {
+		p.logger.Error("could not convert project ID to ObjectID")
+		return 0, ErrProjectIDConvert
+	}
+
+	deleteResult, deleteErr := p.coll.DeleteOne(ctx, bson.D{{Key: "_id", Value: projectIDObjID}})
+	if deleteErr != nil {
+		p.logger.Error("error while deleting project %q: %v", projectID, deleteErr)
+		return 0, ErrCouldNotDelete
+	}
+
+	//nolint:gosec // DeletedCount can't be a negative number.
+	return uint(deleteResult.DeletedCount), nil
+}
+
+func mapMongoModelToStruct(decodedProject *projectMongoModel) *Project {
+	project := &Project{
+		ID:          decodedProject.ID.Hex(),
+		Key:         decodedProject.Key,
+		Name:        decodedProject.Name,
+		DisplayName: decodedProject.DisplayName,
+		IsArchived:  decodedProject.IsArchived,
+		ArchivedAt:  decodedProject.ArchivedAt,
+		PurgeAfter:  decodedProject.PurgeAfter,
+		CreatedBy:   decodedProject.CreatedBy.Hex(),
+		CreatedAt:   decodedProject.CreatedAt,
+		UpdatedAt:   decodedProject.UpdatedAt,
+	}
+
+	// Projects created before display names were introduced are displayed by
+	// their name.
+	if project.DisplayName == "" {
+		project.DisplayName = project.Name
+	}
+
+	return project
+}
+
 func setupProjectCollIndexes(
 	ctx context.Context,
 	coll *mongo.Collection,
@@ -305,42 +437,41 @@ func setupProjectCollIndexes(
 		Options: options.Index().SetUnique(true),
 	}
 
+	// The projects of a user are listed by their name or by the time that
+	// they were created at.
+	userCreatedAtIndexModel := mongo.IndexModel{
+		Keys: bson.D{
+			{Key: "created_by", Value: 1},
+			{Key: "created_at", Value: 1},
+		},
+	}
+	userNameIndexModel := mongo.IndexModel{
+		Keys: bson.D{
+			{Key: "created_by", Value: 1},
+			{Key: "name", Value: 1},
+		},
+	}
+
+	// The deleted projects are purged once their time to purge has passed.
+	purgeAfterIndexModel := mongo.IndexModel{
+		Keys:    bson.D{{Key: "purge_after", Value: 1}},
+		Options: options.Index().SetSparse(true),
+	}
+
 	_, err := coll.Indexes().CreateMany(
 		ctx,
 		[]mongo.IndexModel{
 			projectUserIndexModel,
 			projectKeyIndexModel,
+			userCreatedAtIndexModel,
+			userNameIndexModel,
+			purgeAfterIndexModel,
 		},
 	)
 
 	return err
 }
 
-func mapStringSliceToObjectIDs(s []string) ([]primitive.ObjectID, error) {
-	mappedObjectIDs := make([]primitive.ObjectID, 0, len(s))
-
-	for _, id := range s {
-		currentID, err := primitive.ObjectIDFromHex(id)
-		if err != nil {
-			return []primitive.ObjectID{}, err
-		}
-
-		mappedObjectIDs = append(mappedObjectIDs, currentID)
-	}
-
-	return mappedObjectIDs, nil
-}
-
-func mapObjectIDsToStringSlice(s []primitive.ObjectID) []string {
-	mappedStrings := make([]string, 0, len(s))
-
-	for _, id := range s {
-		mappedStrings = append(mappedStrings, id.Hex())
-	}
-
-	return mappedStrings
-}
-
 func NewProjectRepository(
 	ctx context.Context,
 	db *mongo.Database,
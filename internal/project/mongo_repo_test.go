@@ -5,6 +5,7 @@ import (
 	"errors"
 	"log"
 	"os"
+	"slices"
 	"testing"
 	"time"
 
@@ -13,6 +14,7 @@ import (
 	"go.mongodb.org/mongo-driver/mongo/options"
 
 	"github.com/waduhek/flagger/internal/logger"
+	"github.com/waduhek/flagger/internal/pagination"
 	"github.com/waduhek/flagger/internal/project"
 	"github.com/waduhek/flagger/internal/user"
 )
@@ -22,14 +24,11 @@ const mongoDBConnectionString string = "mongodb://localhost:27017"
 const dummyObjectID string = "66a4836693cca0acf7482f8b"
 
 var dummyProject = &project.Project{
-	Name:         "test",
-	Key:          "test",
-	Flags:        []string{},
-	Environments: []string{},
-	FlagSettings: []string{},
-	CreatedBy:    dummyObjectID,
-	CreatedAt:    time.Now(),
-	UpdatedAt:    time.Now(),
+	Name:      "test",
+	Key:       "test",
+	CreatedBy: dummyObjectID,
+	CreatedAt: time.Now(),
+	UpdatedAt: time.Now(),
 }
 
 var projectCollection *mongo.Collection
@@ -72,56 +71,14 @@ func TestSave_InvalidIDs(t *testing.T) {
 		Name    string
 		Project *project.Project
 	}{
-		{
-			Name: "invalid_environment_id",
-			Project: &project.Project{
-				Name:         "test",
-				Key:          "test",
-				Environments: []string{"invalid_object_id"},
-				Flags:        []string{dummyObjectID},
-				FlagSettings: []string{dummyObjectID},
-				CreatedBy:    dummyObjectID,
-				CreatedAt:    time.Now(),
-				UpdatedAt:    time.Now(),
-			},
-		},
-		{
-			Name: "invalid_flag_id",
-			Project: &project.Project{
-				Name:         "test",
-				Key:          "test",
-				Environments: []string{dummyObjectID},
-				Flags:        []string{"invalid_object_id"},
-				FlagSettings: []string{dummyObjectID},
-				CreatedBy:    dummyObjectID,
-				CreatedAt:    time.Now(),
-				UpdatedAt:    time.Now(),
-			},
-		},
-		{
-			Name: "invalid_flagsetting_id",
-			Project: &project.Project{
-				Name:         "test",
-				Key:          "test",
-				Environments: []string{dummyObjectID},
-				Flags:        []string{dummyObjectID},
-				FlagSettings: []string{"invalid_object_id"},
-				CreatedBy:    dummyObjectID,
-				CreatedAt:    time.Now(),
-				UpdatedAt:    time.Now(),
-			},
-		},
 		{
 			Name: "invalid_createdby_id",
 			Project: &project.Project{
-				Name:         "test",
-				Key:          "test",
-				Environments: []string{dummyObjectID},
-				Flags:        []string{dummyObjectID},
-				FlagSettings: []string{dummyObjectID},
-				CreatedBy:    "invalid_object_id",
-				CreatedAt:    time.Now(),
-				UpdatedAt:    time.Now(),
+				Name:      "test",
+				Key:       "test",
+				CreatedBy: "invalid_object_id",
+				CreatedAt: time.Now(),
+				UpdatedAt: time.Now(),
 			},
 		},
 	}
@@ -194,7 +151,7 @@ func TestGetByNameAndUserID_NotFound(t *testing.T) {
 	}
 }
 
-func TestAddEnvironment(t *testing.T) {
+func TestMarkUpdated(t *testing.T) {
 	ctx, cancel := context.WithTimeout(context.Background(), 1*time.Second)
 	defer cancel()
 
@@ -205,154 +162,228 @@ func TestAddEnvironment(t *testing.T) {
 
 	cleanupProject(t, dummyProject)
 
-	_, updateErr := projectRepository.AddEnvironment(ctx, savedID, dummyObjectID)
+	updatedCount, updateErr := projectRepository.MarkUpdated(ctx, savedID)
 	if updateErr != nil {
-		t.Fatalf("got error while adding environment to project: %v", updateErr)
+		t.Fatalf("got error while marking project updated: %v", updateErr)
+	}
+
+	if updatedCount != 1 {
+		t.Fatalf("expected 1 updated project but got %d", updatedCount)
 	}
 }
 
-func TestAddEnvironment_InvalidIDs(t *testing.T) {
-	t.Parallel()
+func TestMarkUpdated_InvalidID(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 1*time.Second)
+	defer cancel()
 
-	testCases := []struct {
-		Name          string
-		ProjectID     string
-		EnvironmentID string
-	}{
-		{
-			Name:          "invalid_project_id",
-			ProjectID:     "invalid_object_id",
-			EnvironmentID: dummyObjectID,
-		},
-		{
-			Name:          "invalid_environment_id",
-			ProjectID:     dummyObjectID,
-			EnvironmentID: "invalid_environment_id",
-		},
+	_, updateErr := projectRepository.MarkUpdated(ctx, "invalid_object_id")
+	if !errors.Is(updateErr, project.ErrProjectIDConvert) {
+		t.Fatalf("expected ErrProjectIDConvert error but got %v", updateErr)
 	}
-	for _, testCase := range testCases {
-		t.Run(testCase.Name, func(t *testing.T) {
-			t.Parallel()
+}
 
-			ctx, cancel := context.WithTimeout(context.Background(), 1*time.Second)
-			defer cancel()
+func TestListByUserID(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 1*time.Second)
+	defer cancel()
 
-			_, updateErr := projectRepository.AddEnvironment(
-				ctx,
-				testCase.ProjectID,
-				testCase.EnvironmentID,
-			)
+	for i, name := range []string{"list-a", "list-b"} {
+		p := &project.Project{
+			Name:      name,
+			Key:       name,
+			CreatedBy: dummyObjectID,
+			CreatedAt: time.Now().Add(time.Duration(i) * time.Second),
+			UpdatedAt: time.Now(),
+		}
 
-			if !errors.Is(updateErr, project.ErrProjectIDConvert) {
-				t.Fatalf("expected ErrProjectIDConvert error but got %v", updateErr)
-			}
-		})
+		if _, saveErr := projectRepository.Save(ctx, p); saveErr != nil {
+			t.Fatalf("error while saving project: %v", saveErr)
+		}
+
+		cleanupProject(t, p)
+	}
+
+	query, queryErr := pagination.NewQuery(0, "", pagination.SortByCreatedAt, true, "list-")
+	if queryErr != nil {
+		t.Fatalf("unexpected error when creating query: %v", queryErr)
+	}
+
+	projects, nextPageToken, listErr := projectRepository.ListByUserID(ctx, dummyObjectID, false, query)
+	if listErr != nil {
+		t.Fatalf("error while listing projects: %v", listErr)
+	}
+
+	if len(projects) != 2 || projects[0].Name != "list-b" || projects[1].Name != "list-a" {
+		t.Fatalf("unexpected projects: %v", projects)
+	}
+
+	if nextPageToken != "" {
+		t.Fatalf("expected no next page token")
 	}
 }
 
-func TestAddFlag(t *testing.T) {
+func TestListByUserID_InvalidUserID(t *testing.T) {
 	ctx, cancel := context.WithTimeout(context.Background(), 1*time.Second)
 	defer cancel()
 
-	savedID, saveErr := projectRepository.Save(ctx, dummyProject)
+	query, queryErr := pagination.NewQuery(0, "", pagination.SortByName, false, "")
+	if queryErr != nil {
+		t.Fatalf("unexpected error when creating query: %v", queryErr)
+	}
+
+	_, _, listErr := projectRepository.ListByUserID(ctx, "invalid_object_id", false, query)
+	if !errors.Is(listErr, user.ErrUserIDConvert) {
+		t.Fatalf("expected user ID conversion error")
+	}
+}
+
+func TestArchiveAndRestore(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 1*time.Second)
+	defer cancel()
+
+	p := &project.Project{Name: "archive", Key: "archive", CreatedBy: dummyObjectID}
+
+	projectID, saveErr := projectRepository.Save(ctx, p)
 	if saveErr != nil {
-		t.Fatalf("got error while saving project: %v", saveErr)
+		t.Fatalf("error while saving project: %v", saveErr)
 	}
 
-	cleanupProject(t, dummyProject)
+	cleanupProject(t, p)
 
-	_, updateErr := projectRepository.AddFlag(ctx, savedID, dummyObjectID)
-	if updateErr != nil {
-		t.Fatalf("got error while adding flag to project: %v", updateErr)
+	updatedCount, archiveErr := projectRepository.Archive(ctx, projectID, time.Time{})
+	if archiveErr != nil {
+		t.Fatalf("error while archiving project: %v", archiveErr)
+	}
+
+	if updatedCount != 1 {
+		t.Fatalf("expected 1 archived project but got %d", updatedCount)
+	}
+
+	_, getErr := projectRepository.GetByNameAndUserID(ctx, p.Name, dummyObjectID)
+	if !errors.Is(getErr, project.ErrNotFound) {
+		t.Fatalf("expected archived project to not be found but got %v", getErr)
+	}
+
+	archivedProject, getErr := projectRepository.GetArchivedByNameAndUserID(ctx, p.Name, dummyObjectID)
+	if getErr != nil {
+		t.Fatalf("error while getting archived project: %v", getErr)
+	}
+
+	if !archivedProject.IsArchived || archivedProject.ArchivedAt.IsZero() || !archivedProject.PurgeAfter.IsZero() {
+		t.Fatalf("unexpected archived project: %v", archivedProject)
+	}
+
+	archivedKeys, keysErr := projectRepository.GetArchivedKeys(ctx)
+	if keysErr != nil {
+		t.Fatalf("error while getting archived keys: %v", keysErr)
+	}
+
+	if !slices.Contains(archivedKeys, p.Key) {
+		t.Fatalf("expected archived keys %v to contain %q", archivedKeys, p.Key)
+	}
+
+	updatedCount, restoreErr := projectRepository.Restore(ctx, projectID)
+	if restoreErr != nil {
+		t.Fatalf("error while restoring project: %v", restoreErr)
+	}
+
+	if updatedCount != 1 {
+		t.Fatalf("expected 1 restored project but got %d", updatedCount)
+	}
+
+	restoredProject, getErr := projectRepository.GetByNameAndUserID(ctx, p.Name, dummyObjectID)
+	if getErr != nil {
+		t.Fatalf("error while getting restored project: %v", getErr)
+	}
+
+	if restoredProject.IsArchived || !restoredProject.ArchivedAt.IsZero() {
+		t.Fatalf("unexpected restored project: %v", restoredProject)
 	}
 }
 
-func TestAddFlag_InvalidIDs(t *testing.T) {
-	t.Parallel()
+func TestGetPurgeableAndDelete(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 1*time.Second)
+	defer cancel()
 
-	testCases := []struct {
-		Name      string
-		ProjectID string
-		FlagID    string
-	}{
-		{
-			Name:      "invalid_project_id",
-			ProjectID: "invalid_object_id",
-			FlagID:    dummyObjectID,
-		},
-		{
-			Name:      "invalid_environment_id",
-			ProjectID: dummyObjectID,
-			FlagID:    "invalid_environment_id",
-		},
+	p := &project.Project{Name: "purge", Key: "purge", CreatedBy: dummyObjectID}
+
+	projectID, saveErr := projectRepository.Save(ctx, p)
+	if saveErr != nil {
+		t.Fatalf("error while saving project: %v", saveErr)
 	}
-	for _, testCase := range testCases {
-		t.Run(testCase.Name, func(t *testing.T) {
-			t.Parallel()
 
-			ctx, cancel := context.WithTimeout(context.Background(), 1*time.Second)
-			defer cancel()
+	cleanupProject(t, p)
 
-			_, updateErr := projectRepository.AddFlag(ctx, testCase.ProjectID, testCase.FlagID)
-			if !errors.Is(updateErr, project.ErrAddFlag) {
-				t.Fatalf("expected ErrAddFlag error but got %v", updateErr)
-			}
-		})
+	purgeAfter := time.Now().Add(time.Hour)
+
+	if _, archiveErr := projectRepository.Archive(ctx, projectID, purgeAfter); archiveErr != nil {
+		t.Fatalf("error while deleting project: %v", archiveErr)
+	}
+
+	isPurgeable := func(now time.Time) bool {
+		projects, err := projectRepository.GetPurgeable(ctx, now)
+		if err != nil {
+			t.Fatalf("error while getting purgeable projects: %v", err)
+		}
+
+		return slices.ContainsFunc(projects, func(p project.Project) bool { return p.ID == projectID })
+	}
+
+	if isPurgeable(time.Now()) {
+		t.Fatalf("expected project to not be purgeable before its time to purge")
+	}
+
+	if !isPurgeable(purgeAfter.Add(time.Second)) {
+		t.Fatalf("expected project to be purgeable after its time to purge")
+	}
+
+	deletedCount, deleteErr := projectRepository.Delete(ctx, projectID)
+	if deleteErr != nil {
+		t.Fatalf("error while purging project: %v", deleteErr)
+	}
+
+	if deletedCount != 1 {
+		t.Fatalf("expected 1 purged project but got %d", deletedCount)
 	}
 }
 
-func TestAddFlagSettings(t *testing.T) {
+func TestRename(t *testing.T) {
 	ctx, cancel := context.WithTimeout(context.Background(), 1*time.Second)
 	defer cancel()
 
 	savedID, saveErr := projectRepository.Save(ctx, dummyProject)
 	if saveErr != nil {
-		t.Fatalf("got error while saving project: %v", saveErr)
+		t.Fatalf("error while saving project: %v", saveErr)
 	}
 
 	cleanupProject(t, dummyProject)
 
-	_, updateErr := projectRepository.AddFlagSettings(ctx, savedID, dummyObjectID)
-	if updateErr != nil {
-		t.Fatalf("got error while adding flag settings to project: %v", updateErr)
+	renamedCount, renameErr := projectRepository.Rename(ctx, savedID, "test-renamed", "Test")
+	if renameErr != nil || renamedCount != 1 {
+		t.Fatalf("could not rename project: %v", renameErr)
 	}
-}
 
-func TestAddFlagSettings_InvalidIDs(t *testing.T) {
-	t.Parallel()
+	_, getErr := projectRepository.GetByNameAndUserID(ctx, dummyProject.Name, dummyObjectID)
+	if !errors.Is(getErr, project.ErrNotFound) {
+		t.Fatalf("expected the project not to be found by its previous name")
+	}
 
-	testCases := []struct {
-		Name           string
-		ProjectID      string
-		FlagSettingIDs []string
-	}{
-		{
-			Name:           "invalid_project_id",
-			ProjectID:      "invalid_object_id",
-			FlagSettingIDs: []string{dummyObjectID},
-		},
-		{
-			Name:           "invalid_environment_id",
-			ProjectID:      dummyObjectID,
-			FlagSettingIDs: []string{"invalid_environment_id"},
-		},
+	renamedProject, getErr := projectRepository.GetByNameAndUserID(ctx, "test-renamed", dummyObjectID)
+	if getErr != nil {
+		t.Fatalf("error while getting project: %v", getErr)
 	}
-	for _, testCase := range testCases {
-		t.Run(testCase.Name, func(t *testing.T) {
-			t.Parallel()
 
-			ctx, cancel := context.WithTimeout(context.Background(), 1*time.Second)
-			defer cancel()
+	if renamedProject.Key != dummyProject.Key || renamedProject.DisplayName != "Test" {
+		t.Fatalf("expected the project to keep its key but got %v", renamedProject)
+	}
+}
 
-			_, updateErr := projectRepository.AddFlagSettings(
-				ctx,
-				testCase.ProjectID,
-				testCase.FlagSettingIDs...,
-			)
-			if !errors.Is(updateErr, project.ErrAddFlagSetting) {
-				t.Fatalf("expected ErrAddFlagSetting error but got %v", updateErr)
-			}
-		})
+func TestDelete_InvalidProjectID(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 1*time.Second)
+	defer cancel()
+
+	_, deleteErr := projectRepository.Delete(ctx, "invalid_object_id")
+	if !errors.Is(deleteErr, project.ErrProjectIDConvert) {
+		t.Fatalf("expected ErrProjectIDConvert error but got %v", deleteErr)
 	}
 }
 
@@ -0,0 +1,112 @@
+package project
+
+import (
+	"context"
+	"time"
+
+	"go.mongodb.org/mongo-driver/mongo"
+
+	"github.com/waduhek/flagger/internal/changefeed"
+)
+
+// StartPurging purges the deleted projects whose retention window has passed
+// in the background at every interval until the context is done.
+func (p *Server) StartPurging(ctx context.Context, interval time.Duration) {
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				p.purgeDeleted(ctx)
+			}
+		}
+	}()
+}
+
+// purgeDeleted purges the deleted projects whose retention window has passed.
+// A project that could not be purged is purged in the next run.
+func (p *Server) purgeDeleted(ctx context.Context) {
+	projects, err := p.projectDataRepo.GetPurgeable(ctx, time.Now())
+	if err != nil {
+		p.logger.Error("could not get the deleted projects to purge: %v", err)
+		return
+	}
+
+	for i := range projects {
+		if err = p.purge(ctx, &projects[i]); err != nil {
+			p.logger.Error("could not purge project %q: %v", projects[i].Name, err)
+			continue
+		}
+
+		p.logger.Info("successfully purged project %q", projects[i].Name)
+	}
+}
+
+// purge deletes the project along with its environments, flags, flag settings
+// and views.
+func (p *Server) purge(ctx context.Context, project *Project) error {
+	session, err := p.mongoClient.StartSession()
+	if err != nil {
+		p.logger.Error("could not create a new session: %v", err)
+		return ErrTxnSession
+	}
+	defer session.EndSession(ctx)
+
+	if _, err = session.WithTransaction(ctx, p.handlePurgeProject(project)); err != nil {
+		return err
+	}
+
+	p.archivedKeys.Remove(project.Key)
+
+	// The rule sets were purged from the cache when the project was deleted,
+	// but they are purged again in case they were cached since.
+	purgeErr := p.cacheInvalidator.Purge(ctx, project.Key, &changefeed.Change{})
+	if purgeErr != nil {
+		p.logger.Error("could not purge cached rule sets of project %q: %v", project.Name, purgeErr)
+	}
+
+	return nil
+}
+
+// handlePurgeProject performs the transaction for deleting the project along
+// with all its resources and views.
+func (p *Server) handlePurgeProject(
+	project *Project,
+) func(mongo.SessionContext) (interface{}, error) {
+	return func(ctx mongo.SessionContext) (interface{}, error) {
+		resourceRepos := []ResourceRepository{
+			p.flagSettingDataRepo,
+			p.flagDataRepo,
+			p.environmentDataRepo,
+		}
+
+		for _, resourceRepo := range resourceRepos {
+			if _, deleteErr := resourceRepo.DeleteByProjectID(ctx, project.ID); deleteErr != nil {
+				return nil, deleteErr
+			}
+		}
+
+		deletedCount, deleteErr := p.projectDataRepo.Delete(ctx, project.ID)
+		if deleteErr != nil {
+			return nil, deleteErr
+		}
+
+		if deletedCount == 0 {
+			p.logger.Error("project %q was purged concurrently", project.Name)
+			return nil, ErrNotFound
+		}
+
+		// Remove any views of the flags of the project, which no longer has
+		// any flag settings.
+		_, refreshErr := p.flagViewDataRepo.Refresh(ctx, project.ID, "", "")
+		if refreshErr != nil {
+			return nil, refreshErr
+		}
+
+		return nil, nil
+	}
+}
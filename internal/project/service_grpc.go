@@ -2,11 +2,20 @@ package project
 
 import (
 	"context"
+	"errors"
+	"time"
+
+	"go.mongodb.org/mongo-driver/mongo"
+
+	"google.golang.org/protobuf/types/known/timestamppb"
 
 	"github.com/waduhek/flagger/proto/projectpb"
 
 	"github.com/waduhek/flagger/internal/auth"
+	"github.com/waduhek/flagger/internal/changefeed"
 	"github.com/waduhek/flagger/internal/logger"
+	"github.com/waduhek/flagger/internal/pagination"
+	"github.com/waduhek/flagger/internal/rulesetcache"
 	"github.com/waduhek/flagger/internal/user"
 )
 
@@ -18,9 +27,18 @@ const projectSaveRetries uint = 5
 
 type Server struct {
 	projectpb.UnimplementedProjectServer
-	projectDataRepo DataRepository
-	userDataRepo    user.DataRepository
-	logger          logger.Logger
+	mongoClient         *mongo.Client
+	projectDataRepo     DataRepository
+	userDataRepo        user.DataRepository
+	flagSettingDataRepo ResourceRepository
+	flagDataRepo        ResourceRepository
+	environmentDataRepo ResourceRepository
+	flagViewDataRepo    ViewRepository
+	changeFeedRepo      changefeed.Repository
+	cacheInvalidator    rulesetcache.Invalidator
+	archivedKeys        *ArchivedKeys
+	retention           time.Duration
+	logger              logger.Logger
 }
 
 func (p *Server) CreateNewProject(
@@ -43,9 +61,10 @@ func (p *Server) CreateNewProject(
 	projectName := req.GetProjectName()
 
 	newProject := Project{
-		Name:      projectName,
-		Key:       generateProjectKey(projectKeyLen),
-		CreatedBy: fetchedUser.ID,
+		Name:        projectName,
+		DisplayName: req.GetDisplayName(),
+		Key:         generateProjectKey(projectKeyLen),
+		CreatedBy:   fetchedUser.ID,
 	}
 
 	var projectErr error
@@ -102,15 +121,455 @@ func (p *Server) GetProjectKey(
 	return &response, nil
 }
 
-// NewProjectServer creates a new server for the project service.
+func (p *Server) ListProjects(
+	ctx context.Context,
+	req *projectpb.ListProjectsRequest,
+) (*projectpb.ListProjectsResponse, error) {
+	jwtClaims, ok := auth.ClaimsFromContext(ctx)
+	if !ok {
+		p.logger.Error("could not find claims from token")
+		return nil, auth.ErrNoTokenClaims
+	}
+
+	query, err := pagination.NewQuery(
+		req.GetPageSize(),
+		req.GetPageToken(),
+		pagination.SortField(req.GetSortBy()),
+		req.GetDescending(),
+		req.GetNameFilter(),
+	)
+	if err != nil {
+		p.logger.Error("invalid page of projects requested: %v", err)
+		return nil, err
+	}
+
+	fetchedUser, err := p.userDataRepo.GetByUsername(ctx, jwtClaims.Subject)
+	if err != nil {
+		return nil, err
+	}
+
+	projects, nextPageToken, err := p.projectDataRepo.ListByUserID(
+		ctx,
+		fetchedUser.ID,
+		req.GetArchived(),
+		query,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	response := &projectpb.ListProjectsResponse{
+		Projects:      make([]*projectpb.ProjectDetails, 0, len(projects)),
+		NextPageToken: nextPageToken,
+	}
+
+	for i := range projects {
+		response.Projects = append(response.Projects, projectToProto(&projects[i]))
+	}
+
+	return response, nil
+}
+
+func (p *Server) GetProject(
+	ctx context.Context,
+	req *projectpb.GetProjectRequest,
+) (*projectpb.GetProjectResponse, error) {
+	jwtClaims, ok := auth.ClaimsFromContext(ctx)
+	if !ok {
+		p.logger.Error("could not find claims from token")
+		return nil, auth.ErrNoTokenClaims
+	}
+
+	fetchedUser, err := p.userDataRepo.GetByUsername(ctx, jwtClaims.Subject)
+	if err != nil {
+		return nil, err
+	}
+
+	project, err := p.projectDataRepo.GetByNameAndUserID(
+		ctx,
+		req.GetProjectName(),
+		fetchedUser.ID,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return &projectpb.GetProjectResponse{Project: projectToProto(project)}, nil
+}
+
+func (p *Server) RenameProject(
+	ctx context.Context,
+	req *projectpb.RenameProjectRequest,
+) (*projectpb.RenameProjectResponse, error) {
+	jwtClaims, ok := auth.ClaimsFromContext(ctx)
+	if !ok {
+		p.logger.Error("could not find claims from token")
+		return nil, auth.ErrNoTokenClaims
+	}
+
+	fetchedUser, err := p.userDataRepo.GetByUsername(ctx, jwtClaims.Subject)
+	if err != nil {
+		return nil, err
+	}
+
+	projectName := req.GetProjectName()
+	newProjectName := req.GetNewProjectName()
+	displayName := req.GetDisplayName()
+
+	project, err := p.projectDataRepo.GetByNameAndUserID(ctx, projectName, fetchedUser.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	isRenamed := newProjectName != "" && newProjectName != project.Name
+	if !isRenamed && (displayName == "" || displayName == project.DisplayName) {
+		p.logger.Error("no new name or display name was provided for project %q", projectName)
+		return nil, ErrNothingToRename
+	}
+
+	session, err := p.mongoClient.StartSession()
+	if err != nil {
+		p.logger.Error("could not create a new session: %v", err)
+		return nil, ErrTxnSession
+	}
+	defer session.EndSession(ctx)
+
+	_, txnErr := session.WithTransaction(
+		ctx,
+		p.handleRenameProject(project, newProjectName, displayName),
+	)
+	if txnErr != nil {
+		p.logger.Error("error while performing project rename transaction: %v", txnErr)
+		return nil, txnErr
+	}
+
+	// The SDKs identify the project by its key, which is not changed, so the
+	// cached rule sets are still served.
+	if isRenamed {
+		p.logger.Info("successfully renamed project %q to %q", projectName, newProjectName)
+	}
+
+	return &projectpb.RenameProjectResponse{}, nil
+}
+
+func (p *Server) ArchiveProject(
+	ctx context.Context,
+	req *projectpb.ArchiveProjectRequest,
+) (*projectpb.ArchiveProjectResponse, error) {
+	jwtClaims, ok := auth.ClaimsFromContext(ctx)
+	if !ok {
+		p.logger.Error("could not find claims from token")
+		return nil, auth.ErrNoTokenClaims
+	}
+
+	fetchedUser, err := p.userDataRepo.GetByUsername(ctx, jwtClaims.Subject)
+	if err != nil {
+		return nil, err
+	}
+
+	project, err := p.projectDataRepo.GetByNameAndUserID(
+		ctx,
+		req.GetProjectName(),
+		fetchedUser.ID,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = p.archive(ctx, project, time.Time{}); err != nil {
+		return nil, err
+	}
+
+	p.logger.Info("successfully archived project %q", project.Name)
+
+	return &projectpb.ArchiveProjectResponse{}, nil
+}
+
+func (p *Server) RestoreProject(
+	ctx context.Context,
+	req *projectpb.RestoreProjectRequest,
+) (*projectpb.RestoreProjectResponse, error) {
+	jwtClaims, ok := auth.ClaimsFromContext(ctx)
+	if !ok {
+		p.logger.Error("could not find claims from token")
+		return nil, auth.ErrNoTokenClaims
+	}
+
+	fetchedUser, err := p.userDataRepo.GetByUsername(ctx, jwtClaims.Subject)
+	if err != nil {
+		return nil, err
+	}
+
+	project, err := p.projectDataRepo.GetArchivedByNameAndUserID(
+		ctx,
+		req.GetProjectName(),
+		fetchedUser.ID,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	// A deleted project can only be restored until it is due to be purged.
+	if !project.PurgeAfter.IsZero() && !time.Now().Before(project.PurgeAfter) {
+		p.logger.Error("retention window of project %q has passed", project.Name)
+		return nil, ErrRetentionExpired
+	}
+
+	session, err := p.mongoClient.StartSession()
+	if err != nil {
+		p.logger.Error("could not create a new session: %v", err)
+		return nil, ErrTxnSession
+	}
+	defer session.EndSession(ctx)
+
+	_, txnErr := session.WithTransaction(ctx, p.handleRestoreProject(project))
+	if txnErr != nil {
+		p.logger.Error("error while performing project restore transaction: %v", txnErr)
+		return nil, txnErr
+	}
+
+	p.archivedKeys.Remove(project.Key)
+
+	p.logger.Info("successfully restored project %q", project.Name)
+
+	change := &changefeed.Change{}
+
+	// Remove the rule sets of the missing flags that were cached while the
+	// project was archived and publish the restoration to the clients watching
+	// the project. The project has already been restored, so failures are
+	// only logged.
+	invalidateErr := p.cacheInvalidator.Invalidate(ctx, project.Key, change)
+	if invalidateErr != nil {
+		p.logger.Error("could not invalidate cached rule sets of project %q: %v", project.Name, invalidateErr)
+	}
+
+	p.publishChange(ctx, project, change)
+
+	return &projectpb.RestoreProjectResponse{}, nil
+}
+
+func (p *Server) DeleteProject(
+	ctx context.Context,
+	req *projectpb.DeleteProjectRequest,
+) (*projectpb.DeleteProjectResponse, error) {
+	jwtClaims, ok := auth.ClaimsFromContext(ctx)
+	if !ok {
+		p.logger.Error("could not find claims from token")
+		return nil, auth.ErrNoTokenClaims
+	}
+
+	fetchedUser, err := p.userDataRepo.GetByUsername(ctx, jwtClaims.Subject)
+	if err != nil {
+		return nil, err
+	}
+
+	projectName := req.GetProjectName()
+
+	// Both active and archived projects can be deleted.
+	project, err := p.projectDataRepo.GetByNameAndUserID(ctx, projectName, fetchedUser.ID)
+	if errors.Is(err, ErrNotFound) {
+		project, err = p.projectDataRepo.GetArchivedByNameAndUserID(ctx, projectName, fetchedUser.ID)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	// Deleting a project that was already deleted doesn't extend its
+	// retention window.
+	if !project.PurgeAfter.IsZero() {
+		return &projectpb.DeleteProjectResponse{PurgeAfter: timestamppb.New(project.PurgeAfter)}, nil
+	}
+
+	purgeAfter := time.Now().Add(p.retention)
+
+	if err = p.archive(ctx, project, purgeAfter); err != nil {
+		return nil, err
+	}
+
+	p.logger.Info("successfully deleted project %q, which will be purged after %v", projectName, purgeAfter)
+
+	return &projectpb.DeleteProjectResponse{PurgeAfter: timestamppb.New(purgeAfter)}, nil
+}
+
+// archive archives the project and removes the views of its flags, so that
+// they are no longer served. The time to purge the project is set if it is not
+// zero.
+func (p *Server) archive(ctx context.Context, project *Project, purgeAfter time.Time) error {
+	session, err := p.mongoClient.StartSession()
+	if err != nil {
+		p.logger.Error("could not create a new session: %v", err)
+		return ErrTxnSession
+	}
+	defer session.EndSession(ctx)
+
+	_, txnErr := session.WithTransaction(ctx, p.handleArchiveProject(project, purgeAfter))
+	if txnErr != nil {
+		p.logger.Error("error while performing project archive transaction: %v", txnErr)
+		return txnErr
+	}
+
+	// The project key is rejected immediately by this replica. The other
+	// replicas reject it once they refresh the keys of the archived projects,
+	// but its flags are not found as soon as they are removed from the cache.
+	p.archivedKeys.Add(project.Key)
+
+	change := &changefeed.Change{}
+
+	// Remove the rule sets of all the flags of the project from the cache,
+	// including the last known good rule sets so that the flags are not served
+	// while the database fails, and publish the archival to the clients
+	// watching the project. The project has already been archived, so
+	// failures are only logged.
+	purgeErr := p.cacheInvalidator.Purge(ctx, project.Key, change)
+	if purgeErr != nil {
+		p.logger.Error("could not purge cached rule sets of project %q: %v", project.Name, purgeErr)
+	}
+
+	p.publishChange(ctx, project, change)
+
+	return nil
+}
+
+// handleArchiveProject performs the transaction for archiving the project
+// along with removing the views of its flags.
+func (p *Server) handleArchiveProject(
+	project *Project,
+	purgeAfter time.Time,
+) func(mongo.SessionContext) (interface{}, error) {
+	return func(ctx mongo.SessionContext) (interface{}, error) {
+		// Archiving updates the project, so that environments and flags that
+		// are concurrently added to the project conflict with the archival.
+		updatedCount, archiveErr := p.projectDataRepo.Archive(ctx, project.ID, purgeAfter)
+		if archiveErr != nil {
+			return nil, archiveErr
+		}
+
+		if updatedCount == 0 {
+			p.logger.Error("project %q was archived concurrently", project.Name)
+			return nil, ErrNotFound
+		}
+
+		_, refreshErr := p.flagViewDataRepo.Refresh(ctx, project.ID, "", "")
+		if refreshErr != nil {
+			return nil, refreshErr
+		}
+
+		return nil, nil
+	}
+}
+
+// handleRenameProject performs the transaction for renaming the project along
+// with the views of its flags.
+func (p *Server) handleRenameProject(
+	project *Project,
+	name string,
+	displayName string,
+) func(mongo.SessionContext) (interface{}, error) {
+	return func(ctx mongo.SessionContext) (interface{}, error) {
+		renamedCount, renameErr := p.projectDataRepo.Rename(ctx, project.ID, name, displayName)
+		if renameErr != nil {
+			return nil, renameErr
+		}
+
+		if renamedCount == 0 {
+			p.logger.Error("project %q was not renamed", project.Name)
+			return nil, ErrNotFound
+		}
+
+		_, refreshErr := p.flagViewDataRepo.Refresh(ctx, project.ID, "", "")
+		if refreshErr != nil {
+			return nil, refreshErr
+		}
+
+		return nil, nil
+	}
+}
+
+// handleRestoreProject performs the transaction for restoring the project
+// along with recomputing the views of its flags.
+func (p *Server) handleRestoreProject(
+	project *Project,
+) func(mongo.SessionContext) (interface{}, error) {
+	return func(ctx mongo.SessionContext) (interface{}, error) {
+		updatedCount, restoreErr := p.projectDataRepo.Restore(ctx, project.ID)
+		if restoreErr != nil {
+			return nil, restoreErr
+		}
+
+		if updatedCount == 0 {
+			p.logger.Error("project %q was restored or purged concurrently", project.Name)
+			return nil, ErrNotFound
+		}
+
+		_, refreshErr := p.flagViewDataRepo.Refresh(ctx, project.ID, "", "")
+		if refreshErr != nil {
+			return nil, refreshErr
+		}
+
+		return nil, nil
+	}
+}
+
+// publishChange publishes the change to the clients watching the project.
+// Failures are only logged.
+func (p *Server) publishChange(ctx context.Context, project *Project, change *changefeed.Change) {
+	_, publishErr := p.changeFeedRepo.Publish(ctx, project.Key, change)
+	if publishErr != nil {
+		p.logger.Warn("could not publish change to project %q: %v", project.Name, publishErr)
+	}
+}
+
+// projectToProto maps the project to its details in a response. The project
+// key is left out as it is only returned by GetProjectKey.
+func projectToProto(project *Project) *projectpb.ProjectDetails {
+	details := &projectpb.ProjectDetails{
+		Name:        project.Name,
+		DisplayName: project.DisplayName,
+		CreatedAt:   timestamppb.New(project.CreatedAt),
+		UpdatedAt:   timestamppb.New(project.UpdatedAt),
+		IsArchived:  project.IsArchived,
+	}
+
+	if !project.ArchivedAt.IsZero() {
+		details.ArchivedAt = timestamppb.New(project.ArchivedAt)
+	}
+
+	if !project.PurgeAfter.IsZero() {
+		details.PurgeAfter = timestamppb.New(project.PurgeAfter)
+	}
+
+	return details
+}
+
+// NewProjectServer creates a new server for the project service. Deleted
+// projects can be restored until the retention window has passed after they
+// were deleted.
 func NewProjectServer(
+	client *mongo.Client,
 	projectDataRepo DataRepository,
 	userDataRepo user.DataRepository,
+	flagSettingDataRepo ResourceRepository,
+	flagDataRepo ResourceRepository,
+	environmentDataRepo ResourceRepository,
+	flagViewDataRepo ViewRepository,
+	changeFeedRepo changefeed.Repository,
+	cacheInvalidator rulesetcache.Invalidator,
+	archivedKeys *ArchivedKeys,
+	retention time.Duration,
 	logger logger.Logger,
 ) *Server {
 	return &Server{
-		projectDataRepo: projectDataRepo,
-		userDataRepo:    userDataRepo,
-		logger:          logger,
+		mongoClient:         client,
+		projectDataRepo:     projectDataRepo,
+		userDataRepo:        userDataRepo,
+		flagSettingDataRepo: flagSettingDataRepo,
+		flagDataRepo:        flagDataRepo,
+		environmentDataRepo: environmentDataRepo,
+		flagViewDataRepo:    flagViewDataRepo,
+		changeFeedRepo:      changeFeedRepo,
+		cacheInvalidator:    cacheInvalidator,
+		archivedKeys:        archivedKeys,
+		retention:           retention,
+		logger:              logger,
 	}
 }
@@ -0,0 +1,338 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/waduhek/flagger/internal/changefeed"
+	"github.com/waduhek/flagger/internal/logger"
+)
+
+// BreakerState is the state of the circuit breaker of the cache.
+type BreakerState int
+
+const (
+	// BreakerClosed is the state in which requests are sent to the cache.
+	BreakerClosed BreakerState = iota
+	// BreakerOpen is the state in which requests are rejected without being
+	// sent to the cache, after the cache has failed repeatedly.
+	BreakerOpen
+	// BreakerHalfOpen is the state in which a single trial request is sent to
+	// the cache to check whether it has recovered.
+	BreakerHalfOpen
+)
+
+func (s BreakerState) String() string {
+	switch s {
+	case BreakerClosed:
+		return "closed"
+	case BreakerOpen:
+		return "open"
+	case BreakerHalfOpen:
+		return "half-open"
+	default:
+		return "unknown"
+	}
+}
+
+// CacheStats are the statistics of the circuit breaker of the cache.
+type CacheStats struct {
+	// State is the current state of the circuit breaker.
+	State BreakerState
+	// Failures is the number of requests to the cache that failed.
+	Failures uint64
+	// ShortCircuits is the number of requests that were rejected without
+	// being sent to the cache while the circuit breaker was open.
+	ShortCircuits uint64
+	// Opens is the number of times that the circuit breaker was opened.
+	Opens uint64
+}
+
+// BreakerCacheRepository is a CacheRepository that stops sending requests to
+// a failing cache. The circuit breaker opens after consecutive failures of the
+// cache and rejects all requests with ErrCacheUnavailable until the cooldown
+// has passed, after which a single trial request decides whether the cache has
+// recovered.
+type BreakerCacheRepository struct {
+	cacheRepo        CacheRepository
+	failureThreshold uint
+	cooldown         time.Duration
+	logger           logger.Logger
+
+	mu                  sync.Mutex
+	stats               CacheStats
+	consecutiveFailures uint
+	openedAt            time.Time
+	isTrialInFlight     bool
+	// generation is incremented whenever the state of the circuit breaker
+	// changes.
+	generation uint64
+}
+
+func (r *BreakerCacheRepository) GetFlagRuleSet(
+	ctx context.Context,
+	params *cacheParameters,
+) (*FlagRuleSet, bool, error) {
+	ticket, ok := r.allow()
+	if !ok {
+		return nil, false, ErrCacheUnavailable
+	}
+
+	ruleSet, isCached, err := r.cacheRepo.GetFlagRuleSet(ctx, params)
+	r.record(ctx, ticket, err)
+
+	return ruleSet, isCached, err
+}
+
+func (r *BreakerCacheRepository) GetFlagRuleSets(
+	ctx context.Context,
+	params []cacheParameters,
+) ([]*FlagRuleSet, error) {
+	ticket, ok := r.allow()
+	if !ok {
+		return nil, ErrCacheUnavailable
+	}
+
+	ruleSets, err := r.cacheRepo.GetFlagRuleSets(ctx, params)
+	r.record(ctx, ticket, err)
+
+	return ruleSets, err
+}
+
+func (r *BreakerCacheRepository) GetLastKnownGoodFlagRuleSets(
+	ctx context.Context,
+	params []cacheParameters,
+) ([]*FlagRuleSet, error) {
+	ticket, ok := r.allow()
+	if !ok {
+		return nil, ErrCacheUnavailable
+	}
+
+	ruleSets, err := r.cacheRepo.GetLastKnownGoodFlagRuleSets(ctx, params)
+	r.record(ctx, ticket, err)
+
+	return ruleSets, err
+}
+
+func (r *BreakerCacheRepository) CacheFlagRuleSet(
+	ctx context.Context,
+	params *cacheParameters,
+	ruleSet *FlagRuleSet,
+) error {
+	ticket, ok := r.allow()
+	if !ok {
+		return ErrCacheUnavailable
+	}
+
+	err := r.cacheRepo.CacheFlagRuleSet(ctx, params, ruleSet)
+	r.record(ctx, ticket, err)
+
+	return err
+}
+
+func (r *BreakerCacheRepository) CacheFlagRuleSets(
+	ctx context.Context,
+	params []cacheParameters,
+	ruleSets []*FlagRuleSet,
+) error {
+	ticket, ok := r.allow()
+	if !ok {
+		return ErrCacheUnavailable
+	}
+
+	err := r.cacheRepo.CacheFlagRuleSets(ctx, params, ruleSets)
+	r.record(ctx, ticket, err)
+
+	return err
+}
+
+func (r *BreakerCacheRepository) GetFlagNames(
+	ctx context.Context,
+	projectKey string,
+	environmentName string,
+) ([]string, bool, error) {
+	ticket, ok := r.allow()
+	if !ok {
+		return nil, false, ErrCacheUnavailable
+	}
+
+	flagNames, isCached, err := r.cacheRepo.GetFlagNames(ctx, projectKey, environmentName)
+	r.record(ctx, ticket, err)
+
+	return flagNames, isCached, err
+}
+
+func (r *BreakerCacheRepository) CacheFlagNames(
+	ctx context.Context,
+	projectKey string,
+	environmentName string,
+	flagNames []string,
+) error {
+	ticket, ok := r.allow()
+	if !ok {
+		return ErrCacheUnavailable
+	}
+
+	err := r.cacheRepo.CacheFlagNames(ctx, projectKey, environmentName, flagNames)
+	r.record(ctx, ticket, err)
+
+	return err
+}
+
+// Invalidate removes the affected rule sets from the cache even while the
+// circuit breaker is open, as a rule set that is not removed would be served
+// after the cache recovers. The result does not affect the circuit breaker.
+func (r *BreakerCacheRepository) Invalidate(
+	ctx context.Context,
+	projectKey string,
+	change *changefeed.Change,
+) error {
+	return r.cacheRepo.Invalidate(ctx, projectKey, change)
+}
+
+// Purge removes the affected rule sets and their last known good rule sets
+// from the cache even while the circuit breaker is open. The result does not
+// affect the circuit breaker.
+func (r *BreakerCacheRepository) Purge(
+	ctx context.Context,
+	projectKey string,
+	change *changefeed.Change,
+) error {
+	return r.cacheRepo.Purge(ctx, projectKey, change)
+}
+
+// Stats gets the current statistics of the circuit breaker.
+func (r *BreakerCacheRepository) Stats() CacheStats {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return r.stats
+}
+
+// IsDegraded checks if the cache is currently being bypassed because it has
+// been failing.
+func (r *BreakerCacheRepository) IsDegraded() bool {
+	return r.Stats().State != BreakerClosed
+}
+
+// breakerTicket is the permission of a request to be sent to the cache.
+type breakerTicket struct {
+	// isTrial is whether the request is the trial request of the half-open
+	// circuit breaker.
+	isTrial bool
+	// generation is the generation of the state of the circuit breaker in
+	// which the request was allowed.
+	generation uint64
+}
+
+// allow checks if a request can be sent to the cache. Only a single trial
+// request is allowed once the cooldown of the open circuit breaker has passed.
+func (r *BreakerCacheRepository) allow() (breakerTicket, bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	switch r.stats.State {
+	case BreakerClosed:
+		return breakerTicket{generation: r.generation}, true
+	case BreakerOpen:
+		if time.Since(r.openedAt) >= r.cooldown {
+			r.setState(BreakerHalfOpen)
+			r.isTrialInFlight = true
+
+			return breakerTicket{isTrial: true, generation: r.generation}, true
+		}
+	case BreakerHalfOpen:
+		if !r.isTrialInFlight {
+			r.isTrialInFlight = true
+			return breakerTicket{isTrial: true, generation: r.generation}, true
+		}
+	}
+
+	r.stats.ShortCircuits++
+
+	return breakerTicket{}, false
+}
+
+// record records the result of a request to the cache. A request that failed
+// because its context was cancelled or timed out is not a failure of the cache.
+// Only the trial request moves the circuit breaker out of the half-open state,
+// and a request that was allowed in an earlier state of the circuit breaker
+// only updates the statistics.
+func (r *BreakerCacheRepository) record(ctx context.Context, ticket breakerTicket, err error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if ticket.isTrial {
+		r.isTrialInFlight = false
+	}
+
+	if isCancelled(ctx, err) {
+		return
+	}
+
+	if err != nil {
+		r.stats.Failures++
+	}
+
+	if ticket.generation != r.generation {
+		return
+	}
+
+	if err == nil {
+		if ticket.isTrial {
+			r.logger.Info("cache has recovered. closing circuit breaker")
+			r.setState(BreakerClosed)
+		}
+
+		r.consecutiveFailures = 0
+
+		return
+	}
+
+	r.consecutiveFailures++
+
+	if ticket.isTrial || r.consecutiveFailures >= r.failureThreshold {
+		r.logger.Warn(
+			"cache failed %d consecutive times: %v. opening circuit breaker for %v",
+			r.consecutiveFailures,
+			err,
+			r.cooldown,
+		)
+
+		r.setState(BreakerOpen)
+		r.stats.Opens++
+		r.openedAt = time.Now()
+	}
+}
+
+// setState moves the circuit breaker to the state, which starts a new
+// generation of its state.
+func (r *BreakerCacheRepository) setState(state BreakerState) {
+	r.stats.State = state
+	r.generation++
+}
+
+// isCancelled checks whether the request failed because its context was
+// cancelled or timed out.
+func isCancelled(ctx context.Context, err error) bool {
+	return ctx.Err() != nil && (errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded))
+}
+
+// NewBreakerCacheRepository creates a cache repository that opens its circuit
+// breaker after the provided number of consecutive failures of the cache and
+// retries the cache after the cooldown.
+func NewBreakerCacheRepository(
+	cacheRepo CacheRepository,
+	failureThreshold uint,
+	cooldown time.Duration,
+	logger logger.Logger,
+) *BreakerCacheRepository {
+	return &BreakerCacheRepository{
+		cacheRepo:        cacheRepo,
+		failureThreshold: max(failureThreshold, 1),
+		cooldown:         cooldown,
+		logger:           logger,
+	}
+}
@@ -0,0 +1,322 @@
+package provider_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+
+	"github.com/waduhek/flagger/internal/logger"
+	"github.com/waduhek/flagger/internal/provider"
+)
+
+// unreachableRedisAddr is the address of a Redis server that refuses all
+// connections.
+const unreachableRedisAddr = "localhost:1"
+
+func evaluateNewCheckout(t *testing.T, handler http.Handler) {
+	t.Helper()
+
+	recorder := httptest.NewRecorder()
+	handler.ServeHTTP(recorder, newOFREPRequest("/ofrep/v1/evaluate/flags/new-checkout", `{}`))
+
+	if recorder.Code != http.StatusOK {
+		t.Fatalf("expected status %d but got %d: %s", http.StatusOK, recorder.Code, recorder.Body)
+	}
+}
+
+func getHealth(t *testing.T, handler http.Handler) map[string]string {
+	t.Helper()
+
+	recorder := httptest.NewRecorder()
+	handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/healthz", nil))
+
+	if recorder.Code != http.StatusOK {
+		t.Fatalf("expected status %d but got %d", http.StatusOK, recorder.Code)
+	}
+
+	var health map[string]string
+	if err := json.NewDecoder(recorder.Body).Decode(&health); err != nil {
+		t.Fatalf("could not decode health: %v", err)
+	}
+
+	return health
+}
+
+// errCacheDown is returned by the connections of the switchable Redis client
+// while the cache is down.
+var errCacheDown = errors.New("connection refused")
+
+// switchableConn is a connection that fails while the cache is down.
+type switchableConn struct {
+	net.Conn
+	isCacheUp *atomic.Bool
+}
+
+func (c *switchableConn) Read(b []byte) (int, error) {
+	if !c.isCacheUp.Load() {
+		return 0, errCacheDown
+	}
+
+	return c.Conn.Read(b)
+}
+
+func (c *switchableConn) Write(b []byte) (int, error) {
+	if !c.isCacheUp.Load() {
+		return 0, errCacheDown
+	}
+
+	return c.Conn.Write(b)
+}
+
+// newSwitchableRedisClient creates a client of the Redis server at
+// localhost:6379 that can only be used while the returned switch is on. The
+// switch is on initially.
+func newSwitchableRedisClient(t *testing.T) (*redis.Client, *atomic.Bool) {
+	t.Helper()
+
+	t.Setenv("FLAGGER_CACHE_TTL", "1m")
+
+	var isCacheUp atomic.Bool
+	isCacheUp.Store(true)
+
+	rdb := redis.NewClient(&redis.Options{
+		Addr:       "localhost:6379",
+		MaxRetries: -1,
+		Dialer: func(ctx context.Context, network string, addr string) (net.Conn, error) {
+			if !isCacheUp.Load() {
+				return nil, errCacheDown
+			}
+
+			var dialer net.Dialer
+
+			conn, err := dialer.DialContext(ctx, network, addr)
+			if err != nil {
+				return nil, err
+			}
+
+			return &switchableConn{Conn: conn, isCacheUp: &isCacheUp}, nil
+		},
+	})
+	t.Cleanup(func() {
+		rdb.Close()
+	})
+
+	return rdb, &isCacheUp
+}
+
+func TestBreakerCacheRepository_FallsThroughWhenCacheIsDown(t *testing.T) {
+	cacheRepo := provider.NewBreakerCacheRepository(
+		newRedisCacheRepository(t, unreachableRedisAddr),
+		2,
+		time.Hour,
+		&logger.StubLogger{},
+	)
+	handler := provider.NewOFREPHandler(newTestServer(t, cacheRepo), nil, &logger.StubLogger{})
+	healthHandler := provider.NewHealthHandler(cacheRepo)
+
+	if health := getHealth(t, healthHandler); health["status"] != "ok" {
+		t.Fatalf("expected a healthy provider before the cache failed but got %v", health)
+	}
+
+	// Every evaluation is served from the database while the cache is down.
+	for range 3 {
+		evaluateNewCheckout(t, handler)
+	}
+
+	stats := cacheRepo.Stats()
+	if stats.State != provider.BreakerOpen || stats.Opens != 1 {
+		t.Fatalf("expected the circuit breaker to be open but got %+v", stats)
+	}
+
+	if stats.Failures != 2 || stats.ShortCircuits == 0 {
+		t.Fatalf("expected the cache to be bypassed after 2 failures but got %+v", stats)
+	}
+
+	health := getHealth(t, healthHandler)
+	if health["status"] != "degraded" || health["cache"] != "open" {
+		t.Fatalf("expected a degraded provider but got %v", health)
+	}
+
+	recorder := httptest.NewRecorder()
+	healthHandler.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+
+	if !strings.Contains(recorder.Body.String(), "\nflagger_degraded 1\n") {
+		t.Fatalf("expected degraded metric but got %s", recorder.Body)
+	}
+}
+
+func TestBreakerCacheRepository_ClosesWhenCacheRecovers(t *testing.T) {
+	rdb, isCacheUp := newSwitchableRedisClient(t)
+	isCacheUp.Store(false)
+
+	cacheRepo := provider.NewBreakerCacheRepository(
+		provider.NewProviderCacheRepository(rdb),
+		1,
+		10*time.Millisecond,
+		&logger.StubLogger{},
+	)
+	handler := provider.NewOFREPHandler(newTestServer(t, cacheRepo), nil, &logger.StubLogger{})
+
+	evaluateNewCheckout(t, handler)
+
+	if state := cacheRepo.Stats().State; state != provider.BreakerOpen {
+		t.Fatalf("expected the circuit breaker to be open but got %v", state)
+	}
+
+	// The trial request after the cooldown fails and opens the circuit
+	// breaker again.
+	time.Sleep(20 * time.Millisecond)
+	evaluateNewCheckout(t, handler)
+
+	if stats := cacheRepo.Stats(); stats.State != provider.BreakerOpen || stats.Opens != 2 {
+		t.Fatalf("expected the circuit breaker to be opened again but got %+v", stats)
+	}
+
+	// The trial request after the cooldown succeeds once the cache is back.
+	isCacheUp.Store(true)
+	time.Sleep(20 * time.Millisecond)
+	evaluateNewCheckout(t, handler)
+
+	if state := cacheRepo.Stats().State; state != provider.BreakerClosed {
+		t.Fatalf("expected the circuit breaker to be closed but got %v", state)
+	}
+
+	if health := getHealth(t, provider.NewHealthHandler(cacheRepo)); health["status"] != "ok" {
+		t.Fatalf("expected a healthy provider after the cache recovered but got %v", health)
+	}
+}
+
+// fakeBreakerCacheRepository is a cache whose requests for the names of the
+// flags of an environment with a gate wait for the gate and then succeed. The
+// requests of the other environments fail with the error of their context or
+// with the error. The other operations of the cache are not implemented.
+type fakeBreakerCacheRepository struct {
+	provider.CacheRepository
+
+	gates   map[string]chan struct{}
+	entered chan string
+	err     error
+}
+
+func (r *fakeBreakerCacheRepository) GetFlagNames(
+	ctx context.Context,
+	_ string,
+	environmentName string,
+) ([]string, bool, error) {
+	if gate, ok := r.gates[environmentName]; ok {
+		r.entered <- environmentName
+		<-gate
+
+		return nil, false, nil
+	}
+
+	if err := ctx.Err(); err != nil {
+		return nil, false, err
+	}
+
+	return nil, false, r.err
+}
+
+func TestBreakerCacheRepository_IgnoresCancelledRequests(t *testing.T) {
+	cacheRepo := provider.NewBreakerCacheRepository(
+		&fakeBreakerCacheRepository{},
+		1,
+		time.Hour,
+		&logger.StubLogger{},
+	)
+
+	cancelledCtx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	timedOutCtx, cancelTimeout := context.WithTimeout(context.Background(), 0)
+	defer cancelTimeout()
+
+	for _, ctx := range []context.Context{cancelledCtx, timedOutCtx} {
+		if _, _, err := cacheRepo.GetFlagNames(ctx, "project", "production"); err == nil {
+			t.Fatal("expected the request of the cancelled context to fail")
+		}
+	}
+
+	if stats := cacheRepo.Stats(); stats.State != provider.BreakerClosed || stats.Failures != 0 {
+		t.Fatalf("expected the cancelled requests not to count as failures but got %+v", stats)
+	}
+}
+
+func TestBreakerCacheRepository_IgnoresLateRequests(t *testing.T) {
+	fakeRepo := &fakeBreakerCacheRepository{
+		gates: map[string]chan struct{}{
+			"slow":   make(chan struct{}),
+			"slower": make(chan struct{}),
+			"trial":  make(chan struct{}),
+		},
+		entered: make(chan string),
+		err:     errCacheDown,
+	}
+	cacheRepo := provider.NewBreakerCacheRepository(fakeRepo, 1, 10*time.Millisecond, &logger.StubLogger{})
+
+	results := make(chan error)
+	getFlagNames := func(environmentName string) {
+		_, _, err := cacheRepo.GetFlagNames(context.Background(), "project", environmentName)
+		results <- err
+	}
+
+	// The slow requests are sent while the circuit breaker is closed.
+	for _, environmentName := range []string{"slow", "slower"} {
+		go getFlagNames(environmentName)
+		<-fakeRepo.entered
+	}
+
+	if _, _, err := cacheRepo.GetFlagNames(context.Background(), "project", "production"); err == nil {
+		t.Fatal("expected the request to fail")
+	}
+
+	// A late success does not close the open circuit breaker.
+	fakeRepo.gates["slow"] <- struct{}{}
+	if err := <-results; err != nil {
+		t.Fatalf("expected the slow request to succeed but got %v", err)
+	}
+
+	if state := cacheRepo.Stats().State; state != provider.BreakerOpen {
+		t.Fatalf("expected the late success not to close the circuit breaker but got %v", state)
+	}
+
+	time.Sleep(20 * time.Millisecond)
+
+	go getFlagNames("trial")
+	<-fakeRepo.entered
+
+	// A late success while the trial is in flight does not close the circuit
+	// breaker nor allow another trial.
+	fakeRepo.gates["slower"] <- struct{}{}
+	if err := <-results; err != nil {
+		t.Fatalf("expected the slower request to succeed but got %v", err)
+	}
+
+	_, _, err := cacheRepo.GetFlagNames(context.Background(), "project", "production")
+	if !errors.Is(err, provider.ErrCacheUnavailable) {
+		t.Fatalf("expected a second trial not to be allowed but got %v", err)
+	}
+
+	if state := cacheRepo.Stats().State; state != provider.BreakerHalfOpen {
+		t.Fatalf("expected the circuit breaker to stay half-open but got %v", state)
+	}
+
+	// Only the success of the trial closes the circuit breaker.
+	fakeRepo.gates["trial"] <- struct{}{}
+	if err := <-results; err != nil {
+		t.Fatalf("expected the trial to succeed but got %v", err)
+	}
+
+	if stats := cacheRepo.Stats(); stats.State != provider.BreakerClosed || stats.Opens != 1 {
+		t.Fatalf("expected the trial to close the circuit breaker but got %+v", stats)
+	}
+}
@@ -1,8 +1,13 @@
 package provider
 
-import "context"
+import (
+	"context"
+	"time"
 
-// cacheParameters are the keys used for caching the flag status.
+	"github.com/waduhek/flagger/internal/rulesetcache"
+)
+
+// cacheParameters are the keys used for caching the flag rule set.
 type cacheParameters struct {
 	ProjectKey      string
 	EnvironmentName string
@@ -10,21 +15,109 @@ type cacheParameters struct {
 }
 
 // CacheRepository provides the interface for acessing the cache for storing
-// flag statuses.
+// flag rule sets.
 type CacheRepository interface {
-	// IsStatusCached checks if the flag status is already cached
-	IsFlagStatusCached(
+	rulesetcache.Invalidator
+
+	// GetFlagRuleSet gets the currently cached rule set of the flag in a
+	// single round trip and reports whether it was found in the cache. A miss
+	// is not an error.
+	GetFlagRuleSet(
 		ctx context.Context,
 		params *cacheParameters,
-	) (bool, error)
+	) (*FlagRuleSet, bool, error)
+
+	// GetFlagRuleSets gets the currently cached rule sets of multiple flags in
+	// a single round trip. The rule sets are returned in the order of the
+	// parameters with a nil rule set for every flag that is not cached.
+	GetFlagRuleSets(
+		ctx context.Context,
+		params []cacheParameters,
+	) ([]*FlagRuleSet, error)
 
-	// GetFlagStatus gets the currently cached value of the flag status.
-	GetFlagStatus(ctx context.Context, params *cacheParameters) (bool, error)
+	// GetLastKnownGoodFlagRuleSets gets the last known good rule sets of
+	// multiple flags in a single round trip. The rule sets are returned in the
+	// order of the parameters with a nil rule set for every flag that is not
+	// known. They are kept for much longer than the cached rule sets and are
+	// not removed by invalidations, so that they can be served while the
+	// database fails.
+	GetLastKnownGoodFlagRuleSets(
+		ctx context.Context,
+		params []cacheParameters,
+	) ([]*FlagRuleSet, error)
 
-	// CacheFlagStatus caches the value of the flag status.
-	CacheFlagStatus(
+	// CacheFlagRuleSet caches the rule set of the flag. A rule set that is not
+	// stale is also kept as the last known good rule set of the flag.
+	CacheFlagRuleSet(
 		ctx context.Context,
 		params *cacheParameters,
-		status bool,
+		ruleSet *FlagRuleSet,
+	) error
+
+	// CacheFlagRuleSets caches the rule sets of multiple flags in a single
+	// round trip. The rule sets must be in the order of the parameters. The
+	// rule sets that are not stale are also kept as the last known good rule
+	// sets of the flags.
+	CacheFlagRuleSets(
+		ctx context.Context,
+		params []cacheParameters,
+		ruleSets []*FlagRuleSet,
 	) error
+
+	// GetFlagNames gets the cached names of all the flags of the project in
+	// the environment in a single round trip and reports whether they were
+	// found in the cache. A miss is not an error.
+	GetFlagNames(
+		ctx context.Context,
+		projectKey string,
+		environmentName string,
+	) ([]string, bool, error)
+
+	// CacheFlagNames caches the names of all the flags of the project in the
+	// environment. The names are invalidated by the changes that can add,
+	// remove or rename flags of the environment.
+	CacheFlagNames(
+		ctx context.Context,
+		projectKey string,
+		environmentName string,
+		flagNames []string,
+	) error
+}
+
+// EnvironmentUsage is the last time that the flags of a project were evaluated
+// in an environment.
+type EnvironmentUsage struct {
+	ProjectKey      string
+	EnvironmentName string
+	UsedAt          time.Time
+}
+
+// UsageRepository records the environments of the projects that the flags are
+// evaluated in, so that the cache can be warmed with the flags of the recently
+// used environments. Only a bounded number of the most recently used
+// environments are kept for warming, but the last usage of every environment
+// is kept for the recent evaluation window of the environments.
+type UsageRepository interface {
+	// RecordUsage records that the flags of the project were evaluated in the
+	// environment at the time.
+	RecordUsage(ctx context.Context, usage *EnvironmentUsage) error
+
+	// GetRecentlyUsed gets up to the limit of the most recently used
+	// environments that were used after the time, most recent first.
+	GetRecentlyUsed(
+		ctx context.Context,
+		since time.Time,
+		limit int,
+	) ([]EnvironmentUsage, error)
+
+	// GetLastUsed gets the time that the flags of the project were last
+	// evaluated in the environment and reports whether they were evaluated
+	// within the recent evaluation window of the environments. Unlike the
+	// recently used environments, the last usages within the window are
+	// never dropped.
+	GetLastUsed(
+		ctx context.Context,
+		projectKey string,
+		environmentName string,
+	) (time.Time, bool, error)
 }
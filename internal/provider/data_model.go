@@ -6,23 +6,81 @@ import (
 
 	"go.mongodb.org/mongo-driver/bson/primitive"
 
-	"github.com/waduhek/flagger/internal/environment"
 	"github.com/waduhek/flagger/internal/flag"
-	"github.com/waduhek/flagger/internal/flagsetting"
+	"github.com/waduhek/flagger/internal/targeting"
 )
 
 // FlagDetails contains the details of a particular flag in a particular
 // environment.
 type FlagDetails struct {
-	ID          primitive.ObjectID      `bson:"_id"`
-	Key         string                  `bson:"key"`
-	Name        string                  `bson:"name"`
-	Environment environment.Environment `bson:"environment"`
-	Flag        flag.Flag               `bson:"flag"`
-	FlagSetting flagsetting.FlagSetting `bson:"flag_setting"`
-	CreatedBy   primitive.ObjectID      `bson:"created_by"`
-	CreatedAt   time.Time               `bson:"created_at"`
-	UpdatedAt   time.Time               `bson:"updated_at"`
+	ID          primitive.ObjectID `bson:"project_id"`
+	Key         string             `bson:"key"`
+	Name        string             `bson:"name"`
+	Environment EnvironmentDetails `bson:"environment"`
+	Flag        FlagDefinition     `bson:"flag"`
+	FlagSetting FlagSettingDetails `bson:"flag_setting"`
+	CreatedBy   primitive.ObjectID `bson:"created_by"`
+	CreatedAt   time.Time          `bson:"created_at"`
+	UpdatedAt   time.Time          `bson:"updated_at"`
+}
+
+// EnvironmentDetails contains the details of the environment that a flag was
+// fetched from.
+type EnvironmentDetails struct {
+	ID   primitive.ObjectID `bson:"_id"`
+	Name string             `bson:"name"`
+}
+
+// FlagDefinition contains the type and the variations of a flag. An archived
+// flag serves its archived variation in every environment.
+type FlagDefinition struct {
+	ID                primitive.ObjectID `bson:"_id"                json:"id"`
+	Name              string             `bson:"name"               json:"name"`
+	Type              flag.Type          `bson:"type"               json:"type"`
+	Variations        []flag.Variation   `bson:"variations"         json:"variations"`
+	DefaultVariation  string             `bson:"default_variation"  json:"default_variation"`
+	OffVariation      string             `bson:"off_variation"      json:"off_variation"`
+	Salt              string             `bson:"salt"               json:"salt"`
+	IsArchived        bool               `bson:"is_archived"        json:"is_archived,omitempty"`
+	ArchivedVariation string             `bson:"archived_variation" json:"archived_variation,omitempty"`
+}
+
+// FlagSettingDetails contains the settings of a flag in an environment.
+type FlagSettingDetails struct {
+	ID        primitive.ObjectID            `bson:"_id"        json:"id"`
+	IsActive  bool                          `bson:"is_active"  json:"is_active"`
+	Variation string                        `bson:"variation"  json:"variation"`
+	Rules     []targeting.Rule              `bson:"rules"      json:"rules"`
+	Rollout   []targeting.WeightedVariation `bson:"rollout" json:"rollout"`
+	Version   int64                         `bson:"version" json:"version"`
+	UpdatedAt time.Time                     `bson:"updated_at" json:"updated_at"`
+}
+
+// FlagRuleSet contains everything that is required to evaluate a flag in an
+// environment for any evaluation context. Whether the rule set was read from
+// the cache is not stored in the cache itself. A rule set that is not found
+// records that the flag does not exist in the environment, so that requests
+// for unknown flags are answered from the cache. A rule set that is not found
+// by the previous names of a renamed flag or environment records their current
+// names. A stale rule set is the last known good rule set of the flag that is
+// served while the database fails.
+type FlagRuleSet struct {
+	Flag     FlagDefinition     `json:"flag"`
+	Setting  FlagSettingDetails `json:"setting"`
+	NotFound bool               `json:"not_found,omitempty"`
+	Renamed  *RenamedFlag       `json:"renamed,omitempty"`
+	Stale    bool               `json:"stale,omitempty"`
+	cached   bool
+	// flagNames are the names of all the flags of an environment that are
+	// kept along with the rule sets by the in-memory caches.
+	flagNames []string
+}
+
+// RenamedFlag contains the current names of a flag and its environment that
+// were requested by an alias.
+type RenamedFlag struct {
+	EnvironmentName string `json:"environment_name"`
+	FlagName        string `json:"flag_name"`
 }
 
 type DataRepository interface {
@@ -34,4 +92,25 @@ type DataRepository interface {
 		environmentName string,
 		flagName string,
 	) ([]FlagDetails, error)
+
+	// GetRenamedFlagDetails gets the details of a flag by the project key
+	// along with the names or the aliases of the environment and the flag
+	// that have not expired.
+	GetRenamedFlagDetails(
+		ctx context.Context,
+		projectKey string,
+		environmentName string,
+		flagName string,
+	) ([]FlagDetails, error)
+
+	// GetFlagDetailsByProjectKeyAndNames gets the details of all the flags
+	// with the provided names by the project key and environment name. The
+	// details of all the flags of the project are returned when no names are
+	// provided.
+	GetFlagDetailsByProjectKeyAndNames(
+		ctx context.Context,
+		projectKey string,
+		environmentName string,
+		flagNames []string,
+	) ([]FlagDetails, error)
 }
@@ -12,6 +12,13 @@ var ErrFetchFlagDetails = status.Error(
 	"error occurred while fetching flag details",
 )
 
+// ErrFlagNotFound is a GRPC error that is returned when the requested flag does
+// not exist in the environment.
+var ErrFlagNotFound = status.Error(
+	codes.NotFound,
+	"flag not found",
+)
+
 // ErrIncorrectFlagDetailCount is a GRPC error that is returned when the number
 // of flag details does not match the expected count.
 var ErrIncorrectFlagDetailCount = status.Error(
@@ -19,9 +26,37 @@ var ErrIncorrectFlagDetailCount = status.Error(
 	"an unexpected number of flags were found",
 )
 
-// ErrStatusCache is a GRPC error that is returned when an error has occurred
-// while checking the cache for the flag status.
-var ErrStatusCache = status.Error(
+// ErrCacheUnavailable is a GRPC error that is returned when the cache is not
+// used because its circuit breaker is open.
+var ErrCacheUnavailable = status.Error(
+	codes.Unavailable,
+	"the flag rule set cache is unavailable",
+)
+
+// ErrInvalidFlagValue is a GRPC error that is returned when the value served by
+// a flag could not be decoded to the type of the flag.
+var ErrInvalidFlagValue = status.Error(
+	codes.Internal,
+	"the flag has an invalid value",
+)
+
+// ErrWatchChanges is a GRPC error that is returned when an error occurs while
+// reading the changes to the watched flags.
+var ErrWatchChanges = status.Error(
+	codes.Unavailable,
+	"error occurred while watching the flags for changes",
+)
+
+// ErrVariationNotFound is a GRPC error that is returned when the variation
+// selected for a flag is not one of the variations of the flag.
+var ErrVariationNotFound = status.Error(
+	codes.Internal,
+	"the selected variation does not exist on the flag",
+)
+
+// ErrFlagTypeMismatch is a GRPC error that is returned when the value of the
+// served variation does not match the type of the flag.
+var ErrFlagTypeMismatch = status.Error(
 	codes.Internal,
-	"error occurred while checking flag status cache",
+	"the value of the variation does not match the type of the flag",
 )
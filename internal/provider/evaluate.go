@@ -0,0 +1,292 @@
+package provider
+
+import (
+	"encoding/json"
+	"errors"
+	"strconv"
+
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/structpb"
+
+	"github.com/waduhek/flagger/proto/providerpb"
+
+	"github.com/waduhek/flagger/internal/flag"
+	"github.com/waduhek/flagger/internal/targeting"
+)
+
+// FlagStatus is the evaluated status of a flag in an environment. The value
+// of the served variation is stored as its JSON encoding.
+type FlagStatus struct {
+	IsActive  bool              `json:"is_active"`
+	Type      flag.Type         `json:"type"`
+	Variation string            `json:"variation"`
+	Value     string            `json:"value"`
+	Reason    providerpb.Reason `json:"reason"`
+	Version   int64             `json:"version"`
+	Cached    bool              `json:"cached"`
+}
+
+// newFlagRuleSet creates the rule set of the flag from its details.
+func newFlagRuleSet(details *FlagDetails) *FlagRuleSet {
+	ruleSet := &FlagRuleSet{
+		Flag:    details.Flag,
+		Setting: details.FlagSetting,
+	}
+
+	// Flags created before typed values were introduced are boolean flags that
+	// serve their status. They are given on and off variations so that they
+	// can be rolled out by percentage as well.
+	if len(ruleSet.Flag.Variations) == 0 {
+		ruleSet.Flag.Type = flag.TypeBoolean
+		ruleSet.Flag.Variations = []flag.Variation{
+			{Key: flag.OnVariationKey, Value: "true"},
+			{Key: flag.OffVariationKey, Value: "false"},
+		}
+		ruleSet.Flag.DefaultVariation = flag.OnVariationKey
+		ruleSet.Flag.OffVariation = flag.OffVariationKey
+	}
+
+	// Flags created before percentage rollouts were introduced are salted with
+	// their ID.
+	if ruleSet.Flag.Salt == "" {
+		ruleSet.Flag.Salt = ruleSet.Flag.ID.Hex()
+	}
+
+	return ruleSet
+}
+
+// newEvaluationContext maps the evaluation context of a request to the context
+// that the targeting rules are evaluated against.
+func newEvaluationContext(
+	evalCtx *providerpb.EvaluationContext,
+) *targeting.Context {
+	return &targeting.Context{
+		TargetingKey: evalCtx.GetTargetingKey(),
+		Attributes:   evalCtx.GetAttributes().AsMap(),
+	}
+}
+
+// evaluateFlag selects the variation that is to be served by the flag
+// according to its settings in the environment. While the flag is active, the
+// first targeting rule that matches the evaluation context decides the
+// variation. When none of the rules match, the percentage rollout decides the
+// variation by the bucket of the targeting key. An archived flag serves its
+// archived variation regardless of its settings.
+func evaluateFlag(
+	ruleSet *FlagRuleSet,
+	evalCtx *targeting.Context,
+) (*FlagStatus, error) {
+	definition := ruleSet.Flag
+	setting := ruleSet.Setting
+
+	// Rule sets of flags created before typed values were introduced that were
+	// cached without any variations serve their status as a boolean.
+	if len(definition.Variations) == 0 {
+		variation := flag.OffVariationKey
+		reason := providerpb.Reason_REASON_DISABLED
+
+		if setting.IsActive {
+			variation = flag.OnVariationKey
+			reason = providerpb.Reason_REASON_STATIC
+		}
+
+		if ruleSet.Stale {
+			reason = providerpb.Reason_REASON_STALE
+		}
+
+		status := &FlagStatus{
+			IsActive:  setting.IsActive,
+			Type:      flag.TypeBoolean,
+			Variation: variation,
+			Value:     strconv.FormatBool(setting.IsActive),
+			Reason:    reason,
+			Version:   setting.Version,
+			Cached:    ruleSet.cached,
+		}
+
+		return status, nil
+	}
+
+	variationKey := definition.OffVariation
+	reason := providerpb.Reason_REASON_DISABLED
+	isActive := setting.IsActive
+
+	if definition.IsArchived {
+		// An archived flag is inactive in every environment and serves its
+		// archived variation, or its off variation if it doesn't have one.
+		isActive = false
+		if definition.ArchivedVariation != "" {
+			variationKey = definition.ArchivedVariation
+		}
+	} else if setting.IsActive {
+		variationKey = setting.Variation
+		if variationKey == "" {
+			variationKey = definition.DefaultVariation
+		}
+
+		// A flag without any targeting rules or rollout serves the same
+		// variation to every evaluation context.
+		reason = providerpb.Reason_REASON_STATIC
+		if len(setting.Rules) > 0 || len(setting.Rollout) > 0 {
+			reason = providerpb.Reason_REASON_DEFAULT
+		}
+
+		if ruleIndex, matched := targeting.Evaluate(
+			setting.Rules,
+			evalCtx,
+		); matched {
+			variationKey = setting.Rules[ruleIndex].Variation
+			reason = providerpb.Reason_REASON_TARGETING_MATCH
+		} else if rolloutVariation, inRollout := rollVariation(
+			ruleSet,
+			evalCtx,
+		); inRollout {
+			variationKey = rolloutVariation
+			reason = providerpb.Reason_REASON_SPLIT
+		}
+	}
+
+	flagDefinition := flag.Flag{
+		Type:       definition.Type,
+		Variations: definition.Variations,
+	}
+
+	variation, ok := flagDefinition.VariationByKey(variationKey)
+	if !ok {
+		return nil, ErrVariationNotFound
+	}
+
+	if !flag.ValidateValue(definition.Type, variation.Value) {
+		return nil, ErrFlagTypeMismatch
+	}
+
+	// The variation of a stale rule set may no longer be the one that the
+	// flag serves.
+	if ruleSet.Stale {
+		reason = providerpb.Reason_REASON_STALE
+	}
+
+	status := &FlagStatus{
+		IsActive:  isActive,
+		Type:      definition.Type,
+		Variation: variation.Key,
+		Value:     variation.Value,
+		Reason:    reason,
+		Version:   setting.Version,
+		Cached:    ruleSet.cached,
+	}
+
+	return status, nil
+}
+
+// rollVariation selects the variation of the percentage rollout for the
+// targeting key of the evaluation context. Returns false if the flag doesn't
+// have a rollout or if the evaluation context doesn't have a targeting key.
+func rollVariation(
+	ruleSet *FlagRuleSet,
+	evalCtx *targeting.Context,
+) (string, bool) {
+	if len(ruleSet.Setting.Rollout) == 0 || evalCtx == nil ||
+		evalCtx.TargetingKey == "" {
+		return "", false
+	}
+
+	bucket := targeting.Bucket(ruleSet.Flag.Salt, evalCtx.TargetingKey)
+
+	return targeting.SelectVariation(ruleSet.Setting.Rollout, bucket)
+}
+
+// newGetFlagResponse creates the response for the flag status with the value
+// decoded to the type of the flag.
+func newGetFlagResponse(
+	status *FlagStatus,
+) (*providerpb.GetFlagResponse, error) {
+	response := &providerpb.GetFlagResponse{
+		Status:    status.IsActive,
+		Variation: status.Variation,
+		Reason:    status.Reason,
+		Version:   status.Version,
+		Cached:    status.Cached,
+	}
+
+	switch status.Type {
+	case flag.TypeBoolean:
+		var value bool
+		if err := json.Unmarshal([]byte(status.Value), &value); err != nil {
+			return nil, ErrInvalidFlagValue
+		}
+
+		response.Value = &providerpb.GetFlagResponse_BoolValue{BoolValue: value}
+	case flag.TypeString:
+		var value string
+		if err := json.Unmarshal([]byte(status.Value), &value); err != nil {
+			return nil, ErrInvalidFlagValue
+		}
+
+		response.Value = &providerpb.GetFlagResponse_StringValue{
+			StringValue: value,
+		}
+	case flag.TypeNumber:
+		var value float64
+		if err := json.Unmarshal([]byte(status.Value), &value); err != nil {
+			return nil, ErrInvalidFlagValue
+		}
+
+		response.Value = &providerpb.GetFlagResponse_NumberValue{
+			NumberValue: value,
+		}
+	case flag.TypeJSON:
+		var value any
+		if err := json.Unmarshal([]byte(status.Value), &value); err != nil {
+			return nil, ErrInvalidFlagValue
+		}
+
+		jsonValue, err := structpb.NewValue(value)
+		if err != nil {
+			return nil, ErrInvalidFlagValue
+		}
+
+		response.Value = &providerpb.GetFlagResponse_JsonValue{
+			JsonValue: jsonValue,
+		}
+	default:
+		return nil, ErrInvalidFlagValue
+	}
+
+	return response, nil
+}
+
+// newErrorResponse creates the response for a flag that could not be
+// evaluated. The version of the rule set is included if it was found.
+func newErrorResponse(
+	ruleSet *FlagRuleSet,
+	err error,
+) *providerpb.GetFlagResponse {
+	response := &providerpb.GetFlagResponse{
+		Reason:       providerpb.Reason_REASON_ERROR,
+		ErrorCode:    errorCodeOf(err),
+		ErrorMessage: status.Convert(err).Message(),
+	}
+
+	if ruleSet != nil {
+		response.Version = ruleSet.Setting.Version
+		response.Cached = ruleSet.cached
+	}
+
+	return response
+}
+
+// errorCodeOf maps an error that occurred while evaluating a flag to its
+// error code.
+func errorCodeOf(err error) providerpb.ErrorCode {
+	switch {
+	case errors.Is(err, ErrFlagNotFound):
+		return providerpb.ErrorCode_ERROR_CODE_FLAG_NOT_FOUND
+	case errors.Is(err, ErrInvalidFlagValue):
+		return providerpb.ErrorCode_ERROR_CODE_PARSE_ERROR
+	case errors.Is(err, ErrFlagTypeMismatch):
+		return providerpb.ErrorCode_ERROR_CODE_TYPE_MISMATCH
+	default:
+		return providerpb.ErrorCode_ERROR_CODE_GENERAL
+	}
+}
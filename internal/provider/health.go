@@ -0,0 +1,104 @@
+package provider
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+)
+
+// healthResponse is the response of the health endpoint.
+type healthResponse struct {
+	Status string `json:"status"`
+	Cache  string `json:"cache"`
+}
+
+// HealthHandler serves the health and the metrics of the provider over HTTP.
+// The provider is degraded while the cache is bypassed because its circuit
+// breaker is open, but it keeps serving flags from the database. A provider
+// without a circuit breaker is never degraded.
+type HealthHandler struct {
+	cacheRepo *BreakerCacheRepository
+	mux       *http.ServeMux
+}
+
+func (h *HealthHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	h.mux.ServeHTTP(w, r)
+}
+
+// health responds with the status of the provider. A degraded provider is
+// still healthy as it can serve flags.
+func (h *HealthHandler) health(w http.ResponseWriter, _ *http.Request) {
+	stats := h.stats()
+
+	response := healthResponse{Status: "ok", Cache: stats.State.String()}
+	if stats.State != BreakerClosed {
+		response.Status = "degraded"
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+
+	_ = json.NewEncoder(w).Encode(response)
+}
+
+// metrics responds with the metrics of the cache in the Prometheus text
+// format.
+func (h *HealthHandler) metrics(w http.ResponseWriter, _ *http.Request) {
+	stats := h.stats()
+
+	degraded := 0
+	if stats.State != BreakerClosed {
+		degraded = 1
+	}
+
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4")
+	w.WriteHeader(http.StatusOK)
+
+	writeMetric(w, "flagger_degraded", "gauge",
+		"Whether flags are served without the cache.", degraded)
+	writeMetric(w, "flagger_cache_breaker_state", "gauge",
+		"The state of the circuit breaker of the cache (0 closed, 1 open, 2 half-open).",
+		int(stats.State))
+	writeMetric(w, "flagger_cache_failures_total", "counter",
+		"The number of requests to the cache that failed.", stats.Failures)
+	writeMetric(w, "flagger_cache_short_circuits_total", "counter",
+		"The number of requests that bypassed the cache while its circuit breaker was open.",
+		stats.ShortCircuits)
+	writeMetric(w, "flagger_cache_breaker_opens_total", "counter",
+		"The number of times that the circuit breaker of the cache was opened.", stats.Opens)
+}
+
+// stats gets the statistics of the circuit breaker of the cache.
+func (h *HealthHandler) stats() CacheStats {
+	if h.cacheRepo == nil {
+		return CacheStats{State: BreakerClosed}
+	}
+
+	return h.cacheRepo.Stats()
+}
+
+// writeMetric writes a metric without labels in the Prometheus text format.
+func writeMetric[T int | uint64](
+	w http.ResponseWriter,
+	name string,
+	metricType string,
+	help string,
+	value T,
+) {
+	_, _ = fmt.Fprintf(w, "# HELP %s %s\n# TYPE %s %s\n%s %d\n", name, help, name, metricType, name, value)
+}
+
+// NewHealthHandler creates a handler that serves the health of the provider at
+// "/healthz" and its metrics at "/metrics". The circuit breaker of the cache
+// is nil if the cache does not have one.
+func NewHealthHandler(cacheRepo *BreakerCacheRepository) *HealthHandler {
+	h := &HealthHandler{
+		cacheRepo: cacheRepo,
+		mux:       http.NewServeMux(),
+	}
+
+	h.mux.HandleFunc("GET /healthz", h.health)
+	h.mux.HandleFunc("GET /metrics", h.metrics)
+
+	return h
+}
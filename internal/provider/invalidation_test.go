@@ -0,0 +1,242 @@
+package provider_test
+
+import (
+	"context"
+	"testing"
+
+	"google.golang.org/grpc/metadata"
+
+	"github.com/waduhek/flagger/proto/providerpb"
+
+	"github.com/waduhek/flagger/internal/changefeed"
+	"github.com/waduhek/flagger/internal/logger"
+	"github.com/waduhek/flagger/internal/project"
+	"github.com/waduhek/flagger/internal/provider"
+)
+
+const invalidationProjectKey = "invalidation-test-project"
+
+// newInvalidationTest creates a provider server for flags that are cached in
+// Redis for longer than the test runs, so that only an invalidation can make
+// it read the changes to the flags.
+func newInvalidationTest(
+	t *testing.T,
+) (*provider.FlagProviderServer, *provider.RedisCacheRepository, *fakeProviderRepository) {
+	t.Helper()
+
+	cacheRepo := newRedisCacheRepository(t, "localhost:6379")
+	t.Setenv("FLAGGER_CACHE_TTL", "1m")
+
+	repo := newInvalidationRepository(t, cacheRepo)
+	server := provider.NewFlagProviderServer(repo, cacheRepo, nil, nil, &logger.StubLogger{})
+
+	return server, cacheRepo, repo
+}
+
+// newInvalidationRepository creates a repository of the flags of the test
+// project in two environments and removes the rule sets of the project that
+// were cached by previous runs of the tests.
+func newInvalidationRepository(
+	t *testing.T,
+	cacheRepo provider.CacheRepository,
+) *fakeProviderRepository {
+	t.Helper()
+
+	repo := &fakeProviderRepository{}
+	for _, environmentName := range []string{"production", "staging"} {
+		for _, flagName := range []string{"kill-switch", "new-checkout"} {
+			details := newFlagDetails(flagName, true)
+			details.Key = invalidationProjectKey
+			details.Environment.Name = environmentName
+
+			repo.flagDetails = append(repo.flagDetails, details)
+		}
+	}
+
+	err := cacheRepo.Purge(context.Background(), invalidationProjectKey, &changefeed.Change{})
+	if err != nil {
+		t.Fatalf("could not purge the rule sets of the project: %v", err)
+	}
+
+	return repo
+}
+
+// authorisedContext creates the context of a request of the test project.
+func authorisedContext(t *testing.T) context.Context {
+	t.Helper()
+
+	ctx := metadata.NewIncomingContext(
+		context.Background(),
+		metadata.Pairs("x-flagger-token", invalidationProjectKey),
+	)
+
+	ctx, err := project.AuthoriseProject(ctx, &logger.StubLogger{})
+	if err != nil {
+		t.Fatalf("could not authorise project: %v", err)
+	}
+
+	return ctx
+}
+
+// getFlag gets the status of the flag in the environment and fails the test if
+// the flag could not be evaluated.
+func getFlag(
+	t *testing.T,
+	server *provider.FlagProviderServer,
+	environmentName string,
+	flagName string,
+) *providerpb.GetFlagResponse {
+	t.Helper()
+
+	response, err := server.GetFlag(authorisedContext(t), &providerpb.GetFlagRequest{
+		Environment: environmentName,
+		FlagName:    flagName,
+	})
+	if err != nil {
+		t.Fatalf("could not get flag %q in %q: %v", flagName, environmentName, err)
+	}
+
+	if response.GetErrorCode() != providerpb.ErrorCode_ERROR_CODE_UNSPECIFIED {
+		t.Fatalf("could not evaluate flag %q in %q: %v", flagName, environmentName, response)
+	}
+
+	return response
+}
+
+// toggleFlag updates whether the flag is active in all the environments, as
+// UpdateFlagStatus would in the database.
+func toggleFlag(repo *fakeProviderRepository, flagName string, isActive bool) {
+	for i := range repo.flagDetails {
+		if repo.flagDetails[i].Flag.Name == flagName {
+			repo.flagDetails[i].FlagSetting.IsActive = isActive
+			repo.flagDetails[i].FlagSetting.Version++
+		}
+	}
+}
+
+func TestRedisCacheRepository_Invalidate_ToggleIsVisibleOnNextGetFlag(t *testing.T) {
+	server, cacheRepo, repo := newInvalidationTest(t)
+
+	getFlag(t, server, "production", "kill-switch")
+
+	if response := getFlag(t, server, "production", "kill-switch"); !response.GetCached() {
+		t.Fatal("expected the rule set of the flag to be cached")
+	}
+
+	toggleFlag(repo, "kill-switch", false)
+
+	if response := getFlag(t, server, "production", "kill-switch"); !response.GetStatus() {
+		t.Fatal("expected the cached status to be served before the invalidation")
+	}
+
+	err := cacheRepo.Invalidate(context.Background(), invalidationProjectKey, &changefeed.Change{
+		EnvironmentName: "production",
+		FlagName:        "kill-switch",
+	})
+	if err != nil {
+		t.Fatalf("could not invalidate the rule set of the flag: %v", err)
+	}
+
+	response := getFlag(t, server, "production", "kill-switch")
+	if response.GetStatus() || response.GetReason() != providerpb.Reason_REASON_DISABLED ||
+		response.GetCached() || response.GetVersion() != 2 {
+		t.Fatalf("expected the toggle to be visible on the next call but got %v", response)
+	}
+}
+
+func TestRedisCacheRepository_Invalidate_Wildcards(t *testing.T) {
+	testCases := []struct {
+		Name             string
+		Change           *changefeed.Change
+		ExpectedUncached map[string][]string
+	}{
+		{
+			Name:   "flag_in_all_environments",
+			Change: &changefeed.Change{FlagName: "kill-switch"},
+			ExpectedUncached: map[string][]string{
+				"production": {"kill-switch"},
+				"staging":    {"kill-switch"},
+			},
+		},
+		{
+			Name:   "all_flags_in_environment",
+			Change: &changefeed.Change{EnvironmentName: "staging"},
+			ExpectedUncached: map[string][]string{
+				"staging": {"kill-switch", "new-checkout"},
+			},
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.Name, func(t *testing.T) {
+			server, cacheRepo, _ := newInvalidationTest(t)
+
+			for _, environmentName := range []string{"production", "staging"} {
+				for _, flagName := range []string{"kill-switch", "new-checkout"} {
+					getFlag(t, server, environmentName, flagName)
+				}
+			}
+
+			err := cacheRepo.Invalidate(context.Background(), invalidationProjectKey, testCase.Change)
+			if err != nil {
+				t.Fatalf("could not invalidate the rule sets: %v", err)
+			}
+
+			for _, environmentName := range []string{"production", "staging"} {
+				for _, flagName := range []string{"kill-switch", "new-checkout"} {
+					isUncached := false
+					for _, uncachedFlagName := range testCase.ExpectedUncached[environmentName] {
+						isUncached = isUncached || uncachedFlagName == flagName
+					}
+
+					response := getFlag(t, server, environmentName, flagName)
+					if response.GetCached() == isUncached {
+						t.Errorf(
+							"expected cached to be %v for flag %q in %q",
+							!isUncached,
+							flagName,
+							environmentName,
+						)
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestRedisCacheRepository_Purge_RemovesLastKnownGoodRuleSets(t *testing.T) {
+	server, cacheRepo, repo := newInvalidationTest(t)
+
+	getFlag(t, server, "production", "kill-switch")
+	getFlag(t, server, "production", "new-checkout")
+
+	// Deleting a flag purges it, while updating a flag only invalidates it.
+	err := cacheRepo.Purge(context.Background(), invalidationProjectKey, &changefeed.Change{
+		FlagName: "kill-switch",
+	})
+	if err != nil {
+		t.Fatalf("could not purge the flag: %v", err)
+	}
+
+	err = cacheRepo.Invalidate(context.Background(), invalidationProjectKey, &changefeed.Change{
+		FlagName: "new-checkout",
+	})
+	if err != nil {
+		t.Fatalf("could not invalidate the flag: %v", err)
+	}
+
+	repo.isUnavailable.Store(true)
+
+	_, err = server.GetFlag(authorisedContext(t), &providerpb.GetFlagRequest{
+		Environment: "production",
+		FlagName:    "kill-switch",
+	})
+	if err == nil {
+		t.Fatal("expected the purged flag not to be served stale")
+	}
+
+	response := getFlag(t, server, "production", "new-checkout")
+	if response.GetReason() != providerpb.Reason_REASON_STALE {
+		t.Fatalf("expected the invalidated flag to be served stale but got %v", response)
+	}
+}
@@ -0,0 +1,270 @@
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+
+	"github.com/waduhek/flagger/internal/changefeed"
+	"github.com/waduhek/flagger/internal/logger"
+)
+
+// invalidationChannel is the Redis pub/sub channel that the invalidations of
+// the cached rule sets are broadcast to all the replicas on.
+const invalidationChannel = "ruleset-invalidations"
+
+// invalidationMessage is a message broadcast on the invalidation channel.
+type invalidationMessage struct {
+	ProjectKey      string `json:"project_key"`
+	EnvironmentName string `json:"environment_name"`
+	FlagName        string `json:"flag_name"`
+}
+
+// LocalCacheRepository is a CacheRepository that keeps the most recently used
+// rule sets in memory in front of another cache. Invalidations are broadcast
+// to the local caches of all the replicas over Redis pub/sub. As broadcasts can
+// be missed while a replica is disconnected from Redis, the rule sets are
+// never served from memory for longer than the maximum staleness.
+type LocalCacheRepository struct {
+	cacheRepo    CacheRepository
+	rdb          redis.UniversalClient
+	maxStaleness time.Duration
+	logger       logger.Logger
+	lru          *ruleSetLRU
+	flagNames    *ruleSetLRU
+}
+
+func (r *LocalCacheRepository) GetFlagRuleSet(
+	ctx context.Context,
+	params *cacheParameters,
+) (*FlagRuleSet, bool, error) {
+	if ruleSet, ok := r.lru.get(params); ok {
+		return ruleSet, true, nil
+	}
+
+	ruleSet, isCached, err := r.cacheRepo.GetFlagRuleSet(ctx, params)
+	if err != nil || !isCached {
+		return nil, false, err
+	}
+
+	r.lru.set(params, ruleSet, r.maxStaleness)
+
+	return ruleSet, true, nil
+}
+
+func (r *LocalCacheRepository) GetFlagRuleSets(
+	ctx context.Context,
+	params []cacheParameters,
+) ([]*FlagRuleSet, error) {
+	ruleSets := make([]*FlagRuleSet, len(params))
+	missingIndexes := make([]int, 0, len(params))
+	missingParams := make([]cacheParameters, 0, len(params))
+
+	for i := range params {
+		ruleSet, ok := r.lru.get(&params[i])
+		if !ok {
+			missingIndexes = append(missingIndexes, i)
+			missingParams = append(missingParams, params[i])
+
+			continue
+		}
+
+		ruleSets[i] = ruleSet
+	}
+
+	if len(missingParams) == 0 {
+		return ruleSets, nil
+	}
+
+	cachedRuleSets, err := r.cacheRepo.GetFlagRuleSets(ctx, missingParams)
+	if err != nil {
+		return nil, err
+	}
+
+	for i, cachedRuleSet := range cachedRuleSets {
+		if cachedRuleSet == nil {
+			continue
+		}
+
+		r.lru.set(&missingParams[i], cachedRuleSet, r.maxStaleness)
+		ruleSets[missingIndexes[i]] = cachedRuleSet
+	}
+
+	return ruleSets, nil
+}
+
+// GetLastKnownGoodFlagRuleSets gets the last known good rule sets from the
+// cache behind the local cache, as they are only read while the database
+// fails.
+func (r *LocalCacheRepository) GetLastKnownGoodFlagRuleSets(
+	ctx context.Context,
+	params []cacheParameters,
+) ([]*FlagRuleSet, error) {
+	return r.cacheRepo.GetLastKnownGoodFlagRuleSets(ctx, params)
+}
+
+func (r *LocalCacheRepository) CacheFlagRuleSet(
+	ctx context.Context,
+	params *cacheParameters,
+	ruleSet *FlagRuleSet,
+) error {
+	r.lru.set(params, ruleSet, r.maxStaleness)
+
+	return r.cacheRepo.CacheFlagRuleSet(ctx, params, ruleSet)
+}
+
+func (r *LocalCacheRepository) CacheFlagRuleSets(
+	ctx context.Context,
+	params []cacheParameters,
+	ruleSets []*FlagRuleSet,
+) error {
+	for i := range params {
+		r.lru.set(&params[i], ruleSets[i], r.maxStaleness)
+	}
+
+	return r.cacheRepo.CacheFlagRuleSets(ctx, params, ruleSets)
+}
+
+func (r *LocalCacheRepository) GetFlagNames(
+	ctx context.Context,
+	projectKey string,
+	environmentName string,
+) ([]string, bool, error) {
+	params := cacheParameters{ProjectKey: projectKey, EnvironmentName: environmentName}
+
+	if ruleSet, ok := r.flagNames.get(&params); ok {
+		return ruleSet.flagNames, true, nil
+	}
+
+	flagNames, isCached, err := r.cacheRepo.GetFlagNames(ctx, projectKey, environmentName)
+	if err != nil || !isCached {
+		return nil, false, err
+	}
+
+	r.flagNames.set(&params, &FlagRuleSet{flagNames: flagNames}, r.maxStaleness)
+
+	return flagNames, true, nil
+}
+
+func (r *LocalCacheRepository) CacheFlagNames(
+	ctx context.Context,
+	projectKey string,
+	environmentName string,
+	flagNames []string,
+) error {
+	r.flagNames.set(
+		&cacheParameters{ProjectKey: projectKey, EnvironmentName: environmentName},
+		&FlagRuleSet{flagNames: flagNames},
+		r.maxStaleness,
+	)
+
+	return r.cacheRepo.CacheFlagNames(ctx, projectKey, environmentName, flagNames)
+}
+
+// Invalidate removes the affected rule sets from the local cache and the cache
+// behind it and broadcasts the invalidation to the local caches of the other
+// replicas.
+func (r *LocalCacheRepository) Invalidate(
+	ctx context.Context,
+	projectKey string,
+	change *changefeed.Change,
+) error {
+	r.lru.evict(projectKey, change)
+	r.flagNames.evictFlagNames(projectKey, change)
+
+	return r.broadcast(ctx, projectKey, change, r.cacheRepo.Invalidate(ctx, projectKey, change))
+}
+
+// Purge removes the affected rule sets from the local cache, purges them along
+// with their last known good rule sets from the cache behind it and broadcasts
+// the invalidation to the local caches of the other replicas.
+func (r *LocalCacheRepository) Purge(
+	ctx context.Context,
+	projectKey string,
+	change *changefeed.Change,
+) error {
+	r.lru.evict(projectKey, change)
+	r.flagNames.evictFlagNames(projectKey, change)
+
+	return r.broadcast(ctx, projectKey, change, r.cacheRepo.Purge(ctx, projectKey, change))
+}
+
+// broadcast publishes the invalidation of the affected rule sets to the local
+// caches of the other replicas. The error of removing the rule sets from the
+// cache behind the local cache is joined with the error of publishing.
+func (r *LocalCacheRepository) broadcast(
+	ctx context.Context,
+	projectKey string,
+	change *changefeed.Change,
+	invalidateErr error,
+) error {
+	message, err := json.Marshal(&invalidationMessage{
+		ProjectKey:      projectKey,
+		EnvironmentName: change.EnvironmentName,
+		FlagName:        change.FlagName,
+	})
+	if err != nil {
+		return errors.Join(invalidateErr, err)
+	}
+
+	publishErr := r.rdb.Publish(ctx, invalidationChannel, message).Err()
+
+	return errors.Join(invalidateErr, publishErr)
+}
+
+// ListenForInvalidations evicts the rule sets invalidated by the other
+// replicas from the local cache until the context is cancelled.
+func (r *LocalCacheRepository) ListenForInvalidations(ctx context.Context) {
+	pubsub := r.rdb.Subscribe(ctx, invalidationChannel)
+	defer pubsub.Close()
+
+	messages := pubsub.Channel()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case message, ok := <-messages:
+			if !ok {
+				return
+			}
+
+			var invalidation invalidationMessage
+			if err := json.Unmarshal([]byte(message.Payload), &invalidation); err != nil {
+				r.logger.Warn("could not decode invalidation %q: %v", message.Payload, err)
+				continue
+			}
+
+			change := &changefeed.Change{
+				EnvironmentName: invalidation.EnvironmentName,
+				FlagName:        invalidation.FlagName,
+			}
+
+			r.lru.evict(invalidation.ProjectKey, change)
+			r.flagNames.evictFlagNames(invalidation.ProjectKey, change)
+		}
+	}
+}
+
+// NewLocalCacheRepository creates a cache repository that keeps up to the
+// provided number of rule sets in memory for at most the maximum staleness in
+// front of the cache. Invalidations are broadcast over the Redis client.
+func NewLocalCacheRepository(
+	cacheRepo CacheRepository,
+	rdb redis.UniversalClient,
+	size int,
+	maxStaleness time.Duration,
+	logger logger.Logger,
+) *LocalCacheRepository {
+	return &LocalCacheRepository{
+		cacheRepo:    cacheRepo,
+		rdb:          rdb,
+		maxStaleness: maxStaleness,
+		logger:       logger,
+		lru:          newRuleSetLRU(size),
+		flagNames:    newRuleSetLRU(size),
+	}
+}
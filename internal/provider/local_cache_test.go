@@ -0,0 +1,105 @@
+package provider_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+
+	"github.com/waduhek/flagger/internal/changefeed"
+	"github.com/waduhek/flagger/internal/logger"
+	"github.com/waduhek/flagger/internal/provider"
+)
+
+// newLocalCacheServer creates a provider server with a local cache in front of
+// the Redis cache of the client.
+func newLocalCacheServer(
+	t *testing.T,
+	rdb *redis.Client,
+	repo *fakeProviderRepository,
+	size int,
+	maxStaleness time.Duration,
+) (*provider.FlagProviderServer, *provider.LocalCacheRepository) {
+	t.Helper()
+
+	cacheRepo := provider.NewLocalCacheRepository(
+		provider.NewProviderCacheRepository(rdb),
+		rdb,
+		size,
+		maxStaleness,
+		&logger.StubLogger{},
+	)
+
+	server := provider.NewFlagProviderServer(repo, cacheRepo, nil, nil, &logger.StubLogger{})
+
+	return server, cacheRepo
+}
+
+func TestLocalCacheRepository_ServesFromMemory(t *testing.T) {
+	rdb, isCacheUp := newSwitchableRedisClient(t)
+	repo := newInvalidationRepository(t, provider.NewProviderCacheRepository(rdb))
+
+	server, _ := newLocalCacheServer(t, rdb, repo, 1, 100*time.Millisecond)
+
+	getFlag(t, server, "production", "new-checkout")
+	getFlag(t, server, "production", "kill-switch")
+
+	isCacheUp.Store(false)
+
+	if response := getFlag(t, server, "production", "kill-switch"); !response.GetCached() {
+		t.Fatal("expected the most recently used rule set to be served from memory")
+	}
+
+	if response := getFlag(t, server, "production", "new-checkout"); response.GetCached() {
+		t.Fatal("expected the least recently used rule set to be evicted from memory")
+	}
+
+	time.Sleep(150 * time.Millisecond)
+
+	if response := getFlag(t, server, "production", "kill-switch"); response.GetCached() {
+		t.Fatal("expected the stale rule set not to be served from memory")
+	}
+}
+
+func TestLocalCacheRepository_InvalidatesOtherReplicas(t *testing.T) {
+	rdb, _ := newSwitchableRedisClient(t)
+	repo := newInvalidationRepository(t, provider.NewProviderCacheRepository(rdb))
+
+	mutatingServer, mutatingCacheRepo := newLocalCacheServer(t, rdb, repo, 10, time.Minute)
+	otherServer, otherCacheRepo := newLocalCacheServer(t, rdb, repo, 10, time.Minute)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	go otherCacheRepo.ListenForInvalidations(ctx)
+
+	getFlag(t, mutatingServer, "production", "kill-switch")
+	getFlag(t, otherServer, "production", "kill-switch")
+
+	toggleFlag(repo, "kill-switch", false)
+
+	// The subscription of the other replica is set up in the background, so
+	// the invalidation is repeated until the other replica receives it.
+	deadline := time.Now().Add(5 * time.Second)
+
+	for getFlag(t, otherServer, "production", "kill-switch").GetStatus() {
+		if time.Now().After(deadline) {
+			t.Fatal("expected the other replica to evict the toggled flag")
+		}
+
+		err := mutatingCacheRepo.Invalidate(ctx, invalidationProjectKey, &changefeed.Change{
+			EnvironmentName: "production",
+			FlagName:        "kill-switch",
+		})
+		if err != nil {
+			t.Fatalf("could not invalidate the rule set of the flag: %v", err)
+		}
+
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	if getFlag(t, mutatingServer, "production", "kill-switch").GetStatus() {
+		t.Fatal("expected the mutating replica to evict the toggled flag")
+	}
+}
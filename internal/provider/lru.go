@@ -0,0 +1,124 @@
+package provider
+
+import (
+	"container/list"
+	"sync"
+	"time"
+
+	"github.com/waduhek/flagger/internal/changefeed"
+	"github.com/waduhek/flagger/internal/rulesetcache"
+)
+
+// lruEntry is a rule set that is cached in memory.
+type lruEntry struct {
+	params    cacheParameters
+	ruleSet   FlagRuleSet
+	expiresAt time.Time
+}
+
+// ruleSetLRU keeps a bounded number of rule sets in memory until they expire,
+// removing the least recently used rule set when it is full.
+type ruleSetLRU struct {
+	size int
+
+	mu      sync.Mutex
+	entries map[string]*list.Element
+	lru     *list.List
+}
+
+// get gets a copy of the rule set if it has not expired.
+func (c *ruleSetLRU) get(params *cacheParameters) (*FlagRuleSet, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	element, ok := c.entries[genFlagRuleSetCacheKey(params)]
+	if !ok {
+		return nil, false
+	}
+
+	entry, _ := element.Value.(*lruEntry)
+	if time.Now().After(entry.expiresAt) {
+		c.remove(element)
+		return nil, false
+	}
+
+	c.lru.MoveToFront(element)
+
+	ruleSet := entry.ruleSet
+
+	return &ruleSet, true
+}
+
+// set stores a copy of the rule set until the TTL passes, removing the least
+// recently used rule set if the cache is full.
+func (c *ruleSetLRU) set(params *cacheParameters, ruleSet *FlagRuleSet, ttl time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	entry := &lruEntry{
+		params:    *params,
+		ruleSet:   *ruleSet,
+		expiresAt: time.Now().Add(ttl),
+	}
+	entry.ruleSet.cached = false
+
+	cacheKey := genFlagRuleSetCacheKey(params)
+	if element, ok := c.entries[cacheKey]; ok {
+		element.Value = entry
+		c.lru.MoveToFront(element)
+
+		return
+	}
+
+	c.entries[cacheKey] = c.lru.PushFront(entry)
+
+	if c.lru.Len() > c.size {
+		c.remove(c.lru.Back())
+	}
+}
+
+// evict removes the rule sets affected by the change.
+func (c *ruleSetLRU) evict(projectKey string, change *changefeed.Change) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for element := c.lru.Front(); element != nil; {
+		next := element.Next()
+
+		entry, _ := element.Value.(*lruEntry)
+		if entry.params.ProjectKey == projectKey &&
+			(change.EnvironmentName == "" || entry.params.EnvironmentName == change.EnvironmentName) &&
+			(change.FlagName == "" || entry.params.FlagName == change.FlagName) {
+			c.remove(element)
+		}
+
+		element = next
+	}
+}
+
+// evictFlagNames removes the names of the flags of the environments that are
+// affected by the change, if the change can add, remove or rename flags. The
+// names are kept with the parameters of a flag without a name.
+func (c *ruleSetLRU) evictFlagNames(projectKey string, change *changefeed.Change) {
+	if !rulesetcache.AffectsFlagNames(change) {
+		return
+	}
+
+	c.evict(projectKey, &changefeed.Change{EnvironmentName: change.EnvironmentName})
+}
+
+// remove removes the element from the cache. The lock must be held by the
+// caller.
+func (c *ruleSetLRU) remove(element *list.Element) {
+	entry, _ := c.lru.Remove(element).(*lruEntry)
+	delete(c.entries, genFlagRuleSetCacheKey(&entry.params))
+}
+
+// newRuleSetLRU creates a cache of up to the provided number of rule sets.
+func newRuleSetLRU(size int) *ruleSetLRU {
+	return &ruleSetLRU{
+		size:    max(size, 1),
+		entries: make(map[string]*list.Element),
+		lru:     list.New(),
+	}
+}
@@ -0,0 +1,343 @@
+package provider
+
+import (
+	"context"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/waduhek/flagger/internal/changefeed"
+	"github.com/waduhek/flagger/internal/environment"
+)
+
+// MemoryCacheRepository is a CacheRepository that keeps a bounded number of
+// rule sets in the memory of the process until their TTL passes. The cache is
+// not shared between replicas, so it is meant for deployments with a single
+// replica. The last known good rule sets are kept in a separate cache of the
+// same size, so that they are not evicted by the cached rule sets. The names of
+// the flags of the environments are kept in a third cache of the same size.
+type MemoryCacheRepository struct {
+	lru           *ruleSetLRU
+	lastKnownGood *ruleSetLRU
+	flagNames     *ruleSetLRU
+}
+
+func (r *MemoryCacheRepository) GetFlagRuleSet(
+	_ context.Context,
+	params *cacheParameters,
+) (*FlagRuleSet, bool, error) {
+	ruleSet, ok := r.lru.get(params)
+
+	return ruleSet, ok, nil
+}
+
+func (r *MemoryCacheRepository) GetFlagRuleSets(
+	_ context.Context,
+	params []cacheParameters,
+) ([]*FlagRuleSet, error) {
+	ruleSets := make([]*FlagRuleSet, len(params))
+	for i := range params {
+		ruleSets[i], _ = r.lru.get(&params[i])
+	}
+
+	return ruleSets, nil
+}
+
+func (r *MemoryCacheRepository) GetLastKnownGoodFlagRuleSets(
+	_ context.Context,
+	params []cacheParameters,
+) ([]*FlagRuleSet, error) {
+	ruleSets := make([]*FlagRuleSet, len(params))
+	for i := range params {
+		ruleSets[i], _ = r.lastKnownGood.get(&params[i])
+	}
+
+	return ruleSets, nil
+}
+
+func (r *MemoryCacheRepository) CacheFlagRuleSet(
+	_ context.Context,
+	params *cacheParameters,
+	ruleSet *FlagRuleSet,
+) error {
+	r.lru.set(params, ruleSet, getRuleSetTTL(ruleSet))
+
+	if !ruleSet.Stale {
+		r.lastKnownGood.set(params, ruleSet, getLastKnownGoodTTL())
+	}
+
+	return nil
+}
+
+func (r *MemoryCacheRepository) CacheFlagRuleSets(
+	ctx context.Context,
+	params []cacheParameters,
+	ruleSets []*FlagRuleSet,
+) error {
+	for i := range params {
+		if err := r.CacheFlagRuleSet(ctx, &params[i], ruleSets[i]); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (r *MemoryCacheRepository) GetFlagNames(
+	_ context.Context,
+	projectKey string,
+	environmentName string,
+) ([]string, bool, error) {
+	ruleSet, ok := r.flagNames.get(&cacheParameters{
+		ProjectKey:      projectKey,
+		EnvironmentName: environmentName,
+	})
+	if !ok {
+		return nil, false, nil
+	}
+
+	return ruleSet.flagNames, true, nil
+}
+
+func (r *MemoryCacheRepository) CacheFlagNames(
+	_ context.Context,
+	projectKey string,
+	environmentName string,
+	flagNames []string,
+) error {
+	r.flagNames.set(
+		&cacheParameters{ProjectKey: projectKey, EnvironmentName: environmentName},
+		&FlagRuleSet{flagNames: flagNames},
+		getFlagNamesTTL(flagNames),
+	)
+
+	return nil
+}
+
+func (r *MemoryCacheRepository) Invalidate(
+	_ context.Context,
+	projectKey string,
+	change *changefeed.Change,
+) error {
+	r.lru.evict(projectKey, change)
+	r.flagNames.evictFlagNames(projectKey, change)
+
+	return nil
+}
+
+func (r *MemoryCacheRepository) Purge(
+	_ context.Context,
+	projectKey string,
+	change *changefeed.Change,
+) error {
+	r.lru.evict(projectKey, change)
+	r.lastKnownGood.evict(projectKey, change)
+	r.flagNames.evictFlagNames(projectKey, change)
+
+	return nil
+}
+
+// NewMemoryCacheRepository creates a cache repository that keeps up to the
+// provided number of rule sets in memory.
+func NewMemoryCacheRepository(size int) *MemoryCacheRepository {
+	return &MemoryCacheRepository{
+		lru:           newRuleSetLRU(size),
+		lastKnownGood: newRuleSetLRU(size),
+		flagNames:     newRuleSetLRU(size),
+	}
+}
+
+// NoCacheRepository is a CacheRepository that does not cache anything, so that
+// every rule set is fetched from the database.
+type NoCacheRepository struct{}
+
+func (r *NoCacheRepository) GetFlagRuleSet(
+	_ context.Context,
+	_ *cacheParameters,
+) (*FlagRuleSet, bool, error) {
+	return nil, false, nil
+}
+
+func (r *NoCacheRepository) GetFlagRuleSets(
+	_ context.Context,
+	params []cacheParameters,
+) ([]*FlagRuleSet, error) {
+	return make([]*FlagRuleSet, len(params)), nil
+}
+
+func (r *NoCacheRepository) GetLastKnownGoodFlagRuleSets(
+	_ context.Context,
+	params []cacheParameters,
+) ([]*FlagRuleSet, error) {
+	return make([]*FlagRuleSet, len(params)), nil
+}
+
+func (r *NoCacheRepository) CacheFlagRuleSet(
+	_ context.Context,
+	_ *cacheParameters,
+	_ *FlagRuleSet,
+) error {
+	return nil
+}
+
+func (r *NoCacheRepository) CacheFlagRuleSets(
+	_ context.Context,
+	_ []cacheParameters,
+	_ []*FlagRuleSet,
+) error {
+	return nil
+}
+
+func (r *NoCacheRepository) GetFlagNames(
+	_ context.Context,
+	_ string,
+	_ string,
+) ([]string, bool, error) {
+	return nil, false, nil
+}
+
+func (r *NoCacheRepository) CacheFlagNames(
+	_ context.Context,
+	_ string,
+	_ string,
+	_ []string,
+) error {
+	return nil
+}
+
+func (r *NoCacheRepository) Invalidate(
+	_ context.Context,
+	_ string,
+	_ *changefeed.Change,
+) error {
+	return nil
+}
+func (r *NoCacheRepository) Purge(
+	_ context.Context,
+	_ string,
+	_ *changefeed.Change,
+) error {
+	return nil
+}
+
+func NewNoCacheRepository() *NoCacheRepository {
+	return &NoCacheRepository{}
+}
+
+// MemoryUsageRepository is a UsageRepository that keeps the recently used
+// environments in the memory of the process. The environments are lost when
+// the process restarts.
+type MemoryUsageRepository struct {
+	mu     sync.Mutex
+	usages map[[2]string]time.Time
+	// lastUsed is the time that each environment was last used at. Unlike
+	// usages, it is not capped but pruned of the environments that weren't
+	// used within the recent evaluation window of the environments.
+	lastUsed   map[[2]string]time.Time
+	lastPruned time.Time
+}
+
+func (r *MemoryUsageRepository) RecordUsage(
+	_ context.Context,
+	usage *EnvironmentUsage,
+) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	usageKey := [2]string{usage.ProjectKey, usage.EnvironmentName}
+
+	r.usages[usageKey] = usage.UsedAt
+
+	if usage.UsedAt.After(r.lastUsed[usageKey]) {
+		r.lastUsed[usageKey] = usage.UsedAt
+	}
+
+	r.pruneLastUsed(usage.UsedAt)
+
+	// Only the most recently used environments are kept.
+	if len(r.usages) > maxTrackedUsages {
+		var oldestKeys [2]string
+
+		oldestUsedAt := usage.UsedAt
+		for keys, usedAt := range r.usages {
+			if usedAt.Before(oldestUsedAt) {
+				oldestKeys, oldestUsedAt = keys, usedAt
+			}
+		}
+
+		delete(r.usages, oldestKeys)
+	}
+
+	return nil
+}
+
+func (r *MemoryUsageRepository) GetRecentlyUsed(
+	_ context.Context,
+	since time.Time,
+	limit int,
+) ([]EnvironmentUsage, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	usages := make([]EnvironmentUsage, 0, len(r.usages))
+
+	for keys, usedAt := range r.usages {
+		if usedAt.Before(since) {
+			continue
+		}
+
+		usages = append(usages, EnvironmentUsage{
+			ProjectKey:      keys[0],
+			EnvironmentName: keys[1],
+			UsedAt:          usedAt,
+		})
+	}
+
+	slices.SortFunc(usages, func(a, b EnvironmentUsage) int {
+		return b.UsedAt.Compare(a.UsedAt)
+	})
+
+	return usages[:min(limit, len(usages))], nil
+}
+
+func (r *MemoryUsageRepository) GetLastUsed(
+	_ context.Context,
+	projectKey string,
+	environmentName string,
+) (time.Time, bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	usedAt, ok := r.lastUsed[[2]string{projectKey, environmentName}]
+	if !ok || time.Since(usedAt) >= environment.RecentEvaluationWindow {
+		return time.Time{}, false, nil
+	}
+
+	return usedAt, true, nil
+}
+
+// pruneLastUsed removes the environments that weren't used within the recent
+// evaluation window. The environments are pruned at most once per usage record
+// interval.
+func (r *MemoryUsageRepository) pruneLastUsed(now time.Time) {
+	if now.Sub(r.lastPruned) < usageRecordInterval {
+		return
+	}
+
+	r.lastPruned = now
+
+	for usageKey, usedAt := range r.lastUsed {
+		if now.Sub(usedAt) >= environment.RecentEvaluationWindow {
+			delete(r.lastUsed, usageKey)
+		}
+	}
+}
+
+// NewMemoryUsageRepository creates a usage repository that keeps the recently
+// used environments in memory.
+func NewMemoryUsageRepository() *MemoryUsageRepository {
+	return &MemoryUsageRepository{
+		usages:   map[[2]string]time.Time{},
+		lastUsed: map[[2]string]time.Time{},
+	}
+}
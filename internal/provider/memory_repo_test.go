@@ -0,0 +1,67 @@
+package provider_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/waduhek/flagger/internal/changefeed"
+	"github.com/waduhek/flagger/internal/logger"
+	"github.com/waduhek/flagger/internal/provider"
+)
+
+func TestMemoryCacheRepository(t *testing.T) {
+	t.Setenv("FLAGGER_CACHE_TTL", "100ms")
+
+	cacheRepo := provider.NewMemoryCacheRepository(1)
+	repo := newInvalidationRepository(t, cacheRepo)
+	server := provider.NewFlagProviderServer(repo, cacheRepo, nil, nil, &logger.StubLogger{})
+
+	getFlag(t, server, "production", "kill-switch")
+
+	if response := getFlag(t, server, "production", "kill-switch"); !response.GetCached() {
+		t.Fatal("expected the rule set to be cached")
+	}
+
+	toggleFlag(repo, "kill-switch", false)
+
+	err := cacheRepo.Invalidate(context.Background(), invalidationProjectKey, &changefeed.Change{
+		FlagName: "kill-switch",
+	})
+	if err != nil {
+		t.Fatalf("could not invalidate the flag: %v", err)
+	}
+
+	if response := getFlag(t, server, "production", "kill-switch"); response.GetCached() || response.GetStatus() {
+		t.Fatalf("expected the toggle to be visible after the invalidation but got %v", response)
+	}
+
+	// Caching another rule set evicts the least recently used rule set.
+	getFlag(t, server, "production", "new-checkout")
+
+	if response := getFlag(t, server, "production", "kill-switch"); response.GetCached() {
+		t.Fatal("expected the least recently used rule set to be evicted")
+	}
+
+	time.Sleep(150 * time.Millisecond)
+
+	if response := getFlag(t, server, "production", "kill-switch"); response.GetCached() {
+		t.Fatal("expected the rule set to expire after the TTL")
+	}
+}
+
+func TestNoCacheRepository(t *testing.T) {
+	cacheRepo := provider.NewNoCacheRepository()
+	repo := newInvalidationRepository(t, cacheRepo)
+	server := provider.NewFlagProviderServer(repo, cacheRepo, nil, nil, &logger.StubLogger{})
+
+	for range 3 {
+		if response := getFlag(t, server, "production", "kill-switch"); response.GetCached() {
+			t.Fatal("expected the rule set not to be cached")
+		}
+	}
+
+	if queries := repo.queries.Load(); queries != 3 {
+		t.Fatalf("expected every request to query the flag but got %d queries", queries)
+	}
+}
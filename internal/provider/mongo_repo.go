@@ -2,15 +2,21 @@ package provider
 
 import (
 	"context"
+	"time"
 
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/mongo"
 
-	"github.com/waduhek/flagger/internal/project"
+	"github.com/waduhek/flagger/internal/alias"
+	"github.com/waduhek/flagger/internal/flagview"
 )
 
+// MongoDataRepository reads the details of the flags from their views, which
+// are indexed by the project key, the environment name and the flag name. The
+// aliases of the renamed flags and environments are only looked up by the
+// project key, as they are only read when a flag is not found by its name.
 type MongoDataRepository struct {
-	projectColl *mongo.Collection
+	viewColl *mongo.Collection
 }
 
 func (r *MongoDataRepository) GetFlagDetailsByProjectKey(
@@ -19,135 +25,58 @@ func (r *MongoDataRepository) GetFlagDetailsByProjectKey(
 	environmentName string,
 	flagName string,
 ) ([]FlagDetails, error) {
-	pipeline := bson.A{
-		bson.D{
-			{
-				Key:   "$match",
-				Value: bson.D{{Key: "key", Value: projectKey}},
-			},
-		},
-		bson.D{
-			{
-				Key: "$lookup",
-				Value: bson.D{
-					{Key: "from", Value: "environments"},
-					{Key: "localField", Value: "environments"},
-					{Key: "foreignField", Value: "_id"},
-					{Key: "as", Value: "environment"},
-				},
-			},
-		},
-		bson.D{
-			{
-				Key:   "$unwind",
-				Value: bson.D{{Key: "path", Value: "$environment"}},
-			},
-		},
-		bson.D{
-			{
-				Key: "$match",
-				Value: bson.D{
-					{Key: "environment.name", Value: environmentName},
-				},
-			},
-		},
-		bson.D{
-			{
-				Key: "$lookup",
-				Value: bson.D{
-					{Key: "from", Value: "flags"},
-					{Key: "localField", Value: "flags"},
-					{Key: "foreignField", Value: "_id"},
-					{Key: "as", Value: "flag"},
-				},
-			},
-		},
-		bson.D{
-			{
-				Key:   "$unwind",
-				Value: bson.D{{Key: "path", Value: "$flag"}},
-			},
-		},
-		bson.D{
-			{
-				Key:   "$match",
-				Value: bson.D{{Key: "flag.name", Value: flagName}},
-			},
-		},
-		bson.D{
-			{
-				Key: "$lookup",
-				Value: bson.D{
-					{Key: "from", Value: "flag_settings"},
-					{Key: "localField", Value: "flag_settings"},
-					{Key: "foreignField", Value: "_id"},
-					{Key: "as", Value: "flag_setting"},
-				},
-			},
-		},
-		bson.D{
-			{
-				Key:   "$unwind",
-				Value: bson.D{{Key: "path", Value: "$flag_setting"}},
-			},
-		},
-		bson.D{
-			{
-				Key: "$match",
-				Value: bson.D{
-					{
-						Key: "$expr",
-						Value: bson.D{
-							{
-								Key: "$and",
-								Value: bson.A{
-									bson.D{
-										{
-											Key: "$eq",
-											Value: bson.A{
-												"$flag_setting.project_id",
-												"$_id",
-											},
-										},
-									},
-									bson.D{
-										{
-											Key: "$eq",
-											Value: bson.A{
-												"$flag_setting.environment_id",
-												"$environment._id",
-											},
-										},
-									},
-									bson.D{
-										{
-											Key: "$eq",
-											Value: bson.A{
-												"$flag_setting.flag_id",
-												"$flag._id",
-											},
-										},
-									},
-								},
-							},
-						},
-					},
-				},
-			},
-		},
-		bson.D{
-			{
-				Key: "$project",
-				Value: bson.D{
-					{Key: "environments", Value: 0},
-					{Key: "flags", Value: 0},
-					{Key: "flag_settings", Value: 0},
-				},
-			},
-		},
+	return r.findFlagDetails(ctx, bson.D{
+		{Key: "key", Value: projectKey},
+		{Key: "environment.name", Value: environmentName},
+		{Key: "flag.name", Value: flagName},
+	})
+}
+
+func (r *MongoDataRepository) GetRenamedFlagDetails(
+	ctx context.Context,
+	projectKey string,
+	environmentName string,
+	flagName string,
+) ([]FlagDetails, error) {
+	now := time.Now()
+
+	return r.findFlagDetails(ctx, bson.D{
+		{Key: "key", Value: projectKey},
+		{Key: "$and", Value: bson.A{
+			alias.NameFilter("environment.", environmentName, now),
+			alias.NameFilter("flag.", flagName, now),
+		}},
+	})
+}
+
+func (r *MongoDataRepository) GetFlagDetailsByProjectKeyAndNames(
+	ctx context.Context,
+	projectKey string,
+	environmentName string,
+	flagNames []string,
+) ([]FlagDetails, error) {
+	filter := bson.D{
+		{Key: "key", Value: projectKey},
+		{Key: "environment.name", Value: environmentName},
+	}
+
+	if len(flagNames) > 0 {
+		filter = append(filter, bson.E{
+			Key:   "flag.name",
+			Value: bson.D{{Key: "$in", Value: flagNames}},
+		})
 	}
 
-	cursor, err := r.projectColl.Aggregate(ctx, pipeline)
+	return r.findFlagDetails(ctx, filter)
+}
+
+// findFlagDetails finds the views of the flags matching the filter and decodes
+// the flag details.
+func (r *MongoDataRepository) findFlagDetails(
+	ctx context.Context,
+	filter bson.D,
+) ([]FlagDetails, error) {
+	cursor, err := r.viewColl.Find(ctx, filter)
 	if err != nil {
 		return nil, err
 	}
@@ -161,7 +90,7 @@ func (r *MongoDataRepository) GetFlagDetailsByProjectKey(
 }
 
 func NewProviderRepository(db *mongo.Database) *MongoDataRepository {
-	projectColl := db.Collection(project.ProjectCollection)
+	viewColl := db.Collection(flagview.FlagViewCollection)
 
-	return &MongoDataRepository{projectColl: projectColl}
+	return &MongoDataRepository{viewColl: viewColl}
 }
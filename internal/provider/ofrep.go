@@ -0,0 +1,365 @@
+package provider
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"io"
+	"maps"
+	"net/http"
+	"slices"
+	"strings"
+
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/structpb"
+
+	"github.com/waduhek/flagger/proto/providerpb"
+
+	"github.com/waduhek/flagger/internal/logger"
+	"github.com/waduhek/flagger/internal/project"
+	"github.com/waduhek/flagger/internal/targeting"
+)
+
+// environmentHeader is the HTTP header that the environment of the evaluated
+// flags is read from, as OFREP doesn't have environments.
+const environmentHeader = "X-Flagger-Environment"
+
+// maxOFREPRequestSize is the maximum size in bytes of the body of an OFREP
+// request.
+const maxOFREPRequestSize = 1 << 20
+
+// errInvalidTargetingKey is returned when the targeting key of an OFREP
+// evaluation context is not a string.
+var errInvalidTargetingKey = errors.New("the targeting key must be a string")
+
+// ofrepRequest is the body of an OFREP evaluation request.
+type ofrepRequest struct {
+	Context map[string]any `json:"context"`
+}
+
+// ofrepEvaluation is the evaluation of a flag in an OFREP response. Flags that
+// could not be evaluated have an error code instead of a value.
+type ofrepEvaluation struct {
+	Key          string         `json:"key"`
+	Value        any            `json:"value,omitempty"`
+	Reason       string         `json:"reason,omitempty"`
+	Variant      string         `json:"variant,omitempty"`
+	Metadata     map[string]any `json:"metadata,omitempty"`
+	ErrorCode    string         `json:"errorCode,omitempty"`
+	ErrorDetails string         `json:"errorDetails,omitempty"`
+}
+
+// ofrepBulkEvaluation is the response of an OFREP bulk evaluation request.
+type ofrepBulkEvaluation struct {
+	Flags []ofrepEvaluation `json:"flags"`
+}
+
+// ofrepError is the response of an OFREP request that failed.
+type ofrepError struct {
+	ErrorCode    string `json:"errorCode,omitempty"`
+	ErrorDetails string `json:"errorDetails"`
+}
+
+// OFREPHandler serves the OpenFeature Remote Evaluation Protocol over HTTP by
+// evaluating the flags with the flag provider.
+type OFREPHandler struct {
+	server       *FlagProviderServer
+	archivedKeys *project.ArchivedKeys
+	mux          *http.ServeMux
+	logger       logger.Logger
+}
+
+func (h *OFREPHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	h.mux.ServeHTTP(w, r)
+}
+
+// evaluateFlag serves the evaluation of a single flag.
+func (h *OFREPHandler) evaluateFlag(w http.ResponseWriter, r *http.Request) {
+	flagName := r.PathValue("key")
+
+	req, ok := h.parseRequest(w, r)
+	if !ok {
+		return
+	}
+
+	response, err := h.server.GetFlag(req.ctx, &providerpb.GetFlagRequest{
+		FlagName:    flagName,
+		Environment: req.environment,
+		Context:     req.evalCtx,
+	})
+	if err != nil {
+		writeOFREPError(w, http.StatusInternalServerError, "", status.Convert(err).Message())
+		return
+	}
+
+	evaluation := newOFREPEvaluation(flagName, response)
+
+	switch response.GetErrorCode() {
+	case providerpb.ErrorCode_ERROR_CODE_UNSPECIFIED:
+		writeOFREPResponse(w, http.StatusOK, evaluation)
+	case providerpb.ErrorCode_ERROR_CODE_FLAG_NOT_FOUND:
+		writeOFREPResponse(w, http.StatusNotFound, evaluation)
+	default:
+		writeOFREPResponse(w, http.StatusBadRequest, evaluation)
+	}
+}
+
+// evaluateAllFlags serves the evaluation of all the flags of the project. The
+// response has an ETag so that clients can skip unchanged evaluations.
+func (h *OFREPHandler) evaluateAllFlags(w http.ResponseWriter, r *http.Request) {
+	req, ok := h.parseRequest(w, r)
+	if !ok {
+		return
+	}
+
+	response, err := h.server.GetAllFlags(req.ctx, &providerpb.GetAllFlagsRequest{
+		Environment: req.environment,
+		Context:     req.evalCtx,
+	})
+	if err != nil {
+		writeOFREPError(w, http.StatusInternalServerError, "", status.Convert(err).Message())
+		return
+	}
+
+	bulkEvaluation := ofrepBulkEvaluation{
+		Flags: make([]ofrepEvaluation, 0, len(response.GetFlags())),
+	}
+
+	for flagName, flagResponse := range response.GetFlags() {
+		bulkEvaluation.Flags = append(
+			bulkEvaluation.Flags,
+			newOFREPEvaluation(flagName, flagResponse),
+		)
+	}
+
+	// The flags are sorted so that the same evaluations have the same ETag.
+	slices.SortFunc(bulkEvaluation.Flags, func(a, b ofrepEvaluation) int {
+		return strings.Compare(a.Key, b.Key)
+	})
+
+	body, err := json.Marshal(bulkEvaluation)
+	if err != nil {
+		h.logger.Error("could not encode bulk evaluation: %v", err)
+		writeOFREPError(w, http.StatusInternalServerError, "", "could not encode evaluation")
+
+		return
+	}
+
+	etag, err := newBulkEvaluationETag(&bulkEvaluation)
+	if err != nil {
+		h.logger.Error("could not encode bulk evaluation: %v", err)
+		writeOFREPError(w, http.StatusInternalServerError, "", "could not encode evaluation")
+
+		return
+	}
+
+	w.Header().Set("ETag", etag)
+
+	if r.Header.Get("If-None-Match") == etag {
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+
+	if _, err = w.Write(body); err != nil {
+		h.logger.Error("could not write bulk evaluation: %v", err)
+	}
+}
+
+// newBulkEvaluationETag creates the ETag of the bulk evaluation. Whether the
+// flags were evaluated from the cache doesn't change the evaluations, so it is
+// left out of the weak ETag.
+func newBulkEvaluationETag(bulkEvaluation *ofrepBulkEvaluation) (string, error) {
+	flags := make([]ofrepEvaluation, 0, len(bulkEvaluation.Flags))
+
+	for _, evaluation := range bulkEvaluation.Flags {
+		evaluation.Metadata = maps.Clone(evaluation.Metadata)
+		delete(evaluation.Metadata, "cached")
+
+		flags = append(flags, evaluation)
+	}
+
+	body, err := json.Marshal(ofrepBulkEvaluation{Flags: flags})
+	if err != nil {
+		return "", err
+	}
+
+	sum := sha256.Sum256(body)
+
+	return `W/"` + hex.EncodeToString(sum[:]) + `"`, nil
+}
+
+// parsedOFREPRequest is an authorised OFREP request.
+type parsedOFREPRequest struct {
+	ctx         context.Context
+	environment string
+	evalCtx     *providerpb.EvaluationContext
+}
+
+// parseRequest authorises the request with the project key and parses the
+// evaluation context from its body. The error response is written if the
+// request could not be parsed.
+func (h *OFREPHandler) parseRequest(
+	w http.ResponseWriter,
+	r *http.Request,
+) (*parsedOFREPRequest, bool) {
+	ctx, err := project.AuthoriseHTTPRequest(r, h.logger)
+	if err != nil {
+		writeOFREPError(w, http.StatusUnauthorized, "", status.Convert(err).Message())
+		return nil, false
+	}
+
+	if err = project.CheckNotArchived(ctx, h.logger, h.archivedKeys); err != nil {
+		writeOFREPError(w, http.StatusForbidden, "", status.Convert(err).Message())
+		return nil, false
+	}
+
+	environment := r.Header.Get(environmentHeader)
+	if environment == "" {
+		writeOFREPError(
+			w,
+			http.StatusBadRequest,
+			"INVALID_CONTEXT",
+			"the "+environmentHeader+" header is required",
+		)
+
+		return nil, false
+	}
+
+	var body ofrepRequest
+
+	decoder := json.NewDecoder(io.LimitReader(r.Body, maxOFREPRequestSize))
+	if err = decoder.Decode(&body); err != nil && !errors.Is(err, io.EOF) {
+		writeOFREPError(w, http.StatusBadRequest, "PARSE_ERROR", "invalid request body")
+		return nil, false
+	}
+
+	evalCtx, err := newOFREPEvaluationContext(body.Context)
+	if err != nil {
+		writeOFREPError(w, http.StatusBadRequest, "INVALID_CONTEXT", err.Error())
+		return nil, false
+	}
+
+	parsed := &parsedOFREPRequest{
+		ctx:         ctx,
+		environment: environment,
+		evalCtx:     evalCtx,
+	}
+
+	return parsed, true
+}
+
+// newOFREPEvaluationContext maps the context of an OFREP request to the
+// evaluation context of a flag provider request.
+func newOFREPEvaluationContext(
+	ofrepCtx map[string]any,
+) (*providerpb.EvaluationContext, error) {
+	evalCtx := &providerpb.EvaluationContext{}
+
+	if targetingKey, ok := ofrepCtx[targeting.TargetingKeyAttribute]; ok {
+		targetingKeyString, isString := targetingKey.(string)
+		if !isString {
+			return nil, errInvalidTargetingKey
+		}
+
+		evalCtx.TargetingKey = targetingKeyString
+		delete(ofrepCtx, targeting.TargetingKeyAttribute)
+	}
+
+	attributes, err := structpb.NewStruct(ofrepCtx)
+	if err != nil {
+		return nil, err
+	}
+
+	evalCtx.Attributes = attributes
+
+	return evalCtx, nil
+}
+
+// newOFREPEvaluation maps the response of a flag to its OFREP evaluation.
+func newOFREPEvaluation(
+	flagName string,
+	response *providerpb.GetFlagResponse,
+) ofrepEvaluation {
+	if response.GetErrorCode() != providerpb.ErrorCode_ERROR_CODE_UNSPECIFIED {
+		return ofrepEvaluation{
+			Key: flagName,
+			ErrorCode: strings.TrimPrefix(
+				response.GetErrorCode().String(),
+				"ERROR_CODE_",
+			),
+			ErrorDetails: response.GetErrorMessage(),
+		}
+	}
+
+	var value any
+
+	switch typedValue := response.GetValue().(type) {
+	case *providerpb.GetFlagResponse_BoolValue:
+		value = typedValue.BoolValue
+	case *providerpb.GetFlagResponse_StringValue:
+		value = typedValue.StringValue
+	case *providerpb.GetFlagResponse_NumberValue:
+		value = typedValue.NumberValue
+	case *providerpb.GetFlagResponse_JsonValue:
+		value = typedValue.JsonValue.AsInterface()
+	}
+
+	return ofrepEvaluation{
+		Key:     flagName,
+		Value:   value,
+		Reason:  strings.TrimPrefix(response.GetReason().String(), "REASON_"),
+		Variant: response.GetVariation(),
+		Metadata: map[string]any{
+			"version": response.GetVersion(),
+			"cached":  response.GetCached(),
+		},
+	}
+}
+
+// writeOFREPResponse writes the JSON encoded response with the status code.
+func writeOFREPResponse(w http.ResponseWriter, statusCode int, response any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(statusCode)
+
+	//nolint:errchkjson // The status code has already been written.
+	_ = json.NewEncoder(w).Encode(response)
+}
+
+// writeOFREPError writes an OFREP error response with the status code.
+func writeOFREPError(
+	w http.ResponseWriter,
+	statusCode int,
+	errorCode string,
+	errorDetails string,
+) {
+	writeOFREPResponse(w, statusCode, ofrepError{
+		ErrorCode:    errorCode,
+		ErrorDetails: errorDetails,
+	})
+}
+
+// NewOFREPHandler creates the HTTP handler of the OFREP evaluation endpoints
+// that evaluates the flags with the flag provider. The keys of the archived
+// projects are rejected.
+func NewOFREPHandler(
+	server *FlagProviderServer,
+	archivedKeys *project.ArchivedKeys,
+	logger logger.Logger,
+) *OFREPHandler {
+	handler := &OFREPHandler{
+		server:       server,
+		archivedKeys: archivedKeys,
+		mux:          http.NewServeMux(),
+		logger:       logger,
+	}
+
+	handler.mux.HandleFunc("POST /ofrep/v1/evaluate/flags/{key}", handler.evaluateFlag)
+	handler.mux.HandleFunc("POST /ofrep/v1/evaluate/flags", handler.evaluateAllFlags)
+
+	return handler
+}
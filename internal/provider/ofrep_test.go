@@ -0,0 +1,311 @@
+package provider_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/redis/go-redis/v9"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"github.com/waduhek/flagger/internal/flag"
+	"github.com/waduhek/flagger/internal/logger"
+	"github.com/waduhek/flagger/internal/project"
+	"github.com/waduhek/flagger/internal/provider"
+)
+
+const testProjectKey = "ofrep-test-project"
+
+// fakeProviderRepository serves the details of the flags from memory and
+// counts the queries that it receives. Queries wait for the gate to be closed
+// if one is set and fail while the repository is unavailable. The aliases map
+// the previous names of the renamed flags to their current names.
+type fakeProviderRepository struct {
+	flagDetails   []provider.FlagDetails
+	aliases       map[string]string
+	queries       atomic.Int64
+	gate          chan struct{}
+	isUnavailable atomic.Bool
+}
+
+func (r *fakeProviderRepository) GetFlagDetailsByProjectKey(
+	ctx context.Context,
+	projectKey string,
+	environmentName string,
+	flagName string,
+) ([]provider.FlagDetails, error) {
+	return r.GetFlagDetailsByProjectKeyAndNames(
+		ctx,
+		projectKey,
+		environmentName,
+		[]string{flagName},
+	)
+}
+
+func (r *fakeProviderRepository) GetRenamedFlagDetails(
+	_ context.Context,
+	projectKey string,
+	environmentName string,
+	flagName string,
+) ([]provider.FlagDetails, error) {
+	results := []provider.FlagDetails{}
+
+	for _, details := range r.flagDetails {
+		if details.Key == projectKey && details.Environment.Name == environmentName &&
+			details.Flag.Name == r.aliases[flagName] {
+			results = append(results, details)
+		}
+	}
+
+	return results, nil
+}
+
+func (r *fakeProviderRepository) GetFlagDetailsByProjectKeyAndNames(
+	_ context.Context,
+	projectKey string,
+	environmentName string,
+	flagNames []string,
+) ([]provider.FlagDetails, error) {
+	r.queries.Add(1)
+
+	if r.gate != nil {
+		<-r.gate
+	}
+
+	if r.isUnavailable.Load() {
+		return nil, errors.New("database is unavailable")
+	}
+
+	results := []provider.FlagDetails{}
+
+	for _, details := range r.flagDetails {
+		if details.Key != projectKey || details.Environment.Name != environmentName {
+			continue
+		}
+
+		if len(flagNames) > 0 && !slices.Contains(flagNames, details.Flag.Name) {
+			continue
+		}
+
+		results = append(results, details)
+	}
+
+	return results, nil
+}
+
+func newFlagDetails(name string, isActive bool) provider.FlagDetails {
+	return provider.FlagDetails{
+		ID:          primitive.NewObjectID(),
+		Key:         testProjectKey,
+		Environment: provider.EnvironmentDetails{ID: primitive.NewObjectID(), Name: "production"},
+		Flag: provider.FlagDefinition{
+			ID:   primitive.NewObjectID(),
+			Name: name,
+			Type: flag.TypeBoolean,
+			Variations: []flag.Variation{
+				{Key: flag.OnVariationKey, Value: "true"},
+				{Key: flag.OffVariationKey, Value: "false"},
+			},
+			DefaultVariation: flag.OnVariationKey,
+			OffVariation:     flag.OffVariationKey,
+		},
+		FlagSetting: provider.FlagSettingDetails{
+			ID:       primitive.NewObjectID(),
+			IsActive: isActive,
+			Version:  1,
+		},
+	}
+}
+
+// newTestServer creates a provider server for the fake flags that caches them
+// in the provided cache.
+func newTestServer(t *testing.T, cacheRepo provider.CacheRepository) *provider.FlagProviderServer {
+	t.Helper()
+
+	repo := &fakeProviderRepository{
+		flagDetails: []provider.FlagDetails{
+			newFlagDetails("new-checkout", true),
+			newFlagDetails("dark-mode", false),
+		},
+	}
+
+	return provider.NewFlagProviderServer(repo, cacheRepo, nil, nil, &logger.StubLogger{})
+}
+
+// newRedisCacheRepository creates a cache repository with the Redis server at
+// the address.
+func newRedisCacheRepository(t *testing.T, addr string) *provider.RedisCacheRepository {
+	t.Helper()
+
+	t.Setenv("FLAGGER_CACHE_TTL", "1s")
+
+	rdb := redis.NewClient(&redis.Options{Addr: addr, MaxRetries: -1})
+	t.Cleanup(func() {
+		rdb.Close()
+	})
+
+	return provider.NewProviderCacheRepository(rdb)
+}
+
+func newTestHandler(t *testing.T) http.Handler {
+	t.Helper()
+
+	server := newTestServer(t, newRedisCacheRepository(t, "localhost:6379"))
+
+	return provider.NewOFREPHandler(server, nil, &logger.StubLogger{})
+}
+
+func newOFREPRequest(path string, body string) *http.Request {
+	req := httptest.NewRequest(http.MethodPost, path, strings.NewReader(body))
+	req.Header.Set("Authorization", "Bearer "+testProjectKey)
+	req.Header.Set("X-Flagger-Environment", "production")
+
+	return req
+}
+
+func TestOFREP_EvaluateFlag(t *testing.T) {
+	handler := newTestHandler(t)
+
+	recorder := httptest.NewRecorder()
+	handler.ServeHTTP(recorder, newOFREPRequest(
+		"/ofrep/v1/evaluate/flags/new-checkout",
+		`{"context": {"targetingKey": "user-123", "country": "IN"}}`,
+	))
+
+	if recorder.Code != http.StatusOK {
+		t.Fatalf("expected status %d but got %d: %s", http.StatusOK, recorder.Code, recorder.Body)
+	}
+
+	var evaluation map[string]any
+	if err := json.NewDecoder(recorder.Body).Decode(&evaluation); err != nil {
+		t.Fatalf("could not decode response: %v", err)
+	}
+
+	if evaluation["key"] != "new-checkout" || evaluation["value"] != true ||
+		evaluation["variant"] != "on" || evaluation["reason"] != "STATIC" {
+		t.Fatalf("unexpected evaluation %v", evaluation)
+	}
+}
+
+func TestOFREP_EvaluateFlag_Errors(t *testing.T) {
+	handler := newTestHandler(t)
+
+	testCases := []struct {
+		Name           string
+		Request        *http.Request
+		ExpectedStatus int
+		ExpectedCode   string
+	}{
+		{
+			Name:           "flag_not_found",
+			Request:        newOFREPRequest("/ofrep/v1/evaluate/flags/missing", `{}`),
+			ExpectedStatus: http.StatusNotFound,
+			ExpectedCode:   "FLAG_NOT_FOUND",
+		},
+		{
+			Name: "invalid_context",
+			Request: newOFREPRequest(
+				"/ofrep/v1/evaluate/flags/new-checkout",
+				`{"context": {"targetingKey": 123}}`,
+			),
+			ExpectedStatus: http.StatusBadRequest,
+			ExpectedCode:   "INVALID_CONTEXT",
+		},
+		{
+			Name:           "invalid_body",
+			Request:        newOFREPRequest("/ofrep/v1/evaluate/flags/new-checkout", `{`),
+			ExpectedStatus: http.StatusBadRequest,
+			ExpectedCode:   "PARSE_ERROR",
+		},
+		{
+			Name: "missing_project_key",
+			Request: httptest.NewRequest(
+				http.MethodPost,
+				"/ofrep/v1/evaluate/flags/new-checkout",
+				strings.NewReader(`{}`),
+			),
+			ExpectedStatus: http.StatusUnauthorized,
+			ExpectedCode:   "",
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.Name, func(t *testing.T) {
+			recorder := httptest.NewRecorder()
+			handler.ServeHTTP(recorder, testCase.Request)
+
+			if recorder.Code != testCase.ExpectedStatus {
+				t.Fatalf("expected status %d but got %d", testCase.ExpectedStatus, recorder.Code)
+			}
+
+			var response map[string]any
+			if err := json.NewDecoder(recorder.Body).Decode(&response); err != nil {
+				t.Fatalf("could not decode response: %v", err)
+			}
+
+			if errorCode, _ := response["errorCode"].(string); errorCode != testCase.ExpectedCode {
+				t.Fatalf("expected error code %q but got %q", testCase.ExpectedCode, errorCode)
+			}
+		})
+	}
+}
+
+func TestOFREP_EvaluateFlag_ArchivedProject(t *testing.T) {
+	server := newTestServer(t, newRedisCacheRepository(t, "localhost:6379"))
+
+	archivedKeys := project.NewArchivedKeys(nil, &logger.StubLogger{})
+	archivedKeys.Add(testProjectKey)
+
+	handler := provider.NewOFREPHandler(server, archivedKeys, &logger.StubLogger{})
+
+	recorder := httptest.NewRecorder()
+	handler.ServeHTTP(recorder, newOFREPRequest("/ofrep/v1/evaluate/flags/new-checkout", `{}`))
+
+	if recorder.Code != http.StatusForbidden {
+		t.Fatalf("expected status %d but got %d: %s", http.StatusForbidden, recorder.Code, recorder.Body)
+	}
+}
+
+func TestOFREP_EvaluateAllFlags_ETag(t *testing.T) {
+	handler := newTestHandler(t)
+
+	recorder := httptest.NewRecorder()
+	handler.ServeHTTP(recorder, newOFREPRequest("/ofrep/v1/evaluate/flags", `{}`))
+
+	if recorder.Code != http.StatusOK {
+		t.Fatalf("expected status %d but got %d: %s", http.StatusOK, recorder.Code, recorder.Body)
+	}
+
+	var bulkEvaluation struct {
+		Flags []map[string]any `json:"flags"`
+	}
+	if err := json.NewDecoder(recorder.Body).Decode(&bulkEvaluation); err != nil {
+		t.Fatalf("could not decode response: %v", err)
+	}
+
+	if len(bulkEvaluation.Flags) != 2 || bulkEvaluation.Flags[0]["key"] != "dark-mode" ||
+		bulkEvaluation.Flags[0]["reason"] != "DISABLED" {
+		t.Fatalf("unexpected bulk evaluation %v", bulkEvaluation)
+	}
+
+	etag := recorder.Header().Get("ETag")
+	if etag == "" {
+		t.Fatal("expected an ETag in the bulk evaluation response")
+	}
+
+	req := newOFREPRequest("/ofrep/v1/evaluate/flags", `{}`)
+	req.Header.Set("If-None-Match", etag)
+
+	recorder = httptest.NewRecorder()
+	handler.ServeHTTP(recorder, req)
+
+	if recorder.Code != http.StatusNotModified {
+		t.Fatalf("expected status %d but got %d", http.StatusNotModified, recorder.Code)
+	}
+}
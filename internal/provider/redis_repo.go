@@ -2,70 +2,469 @@ package provider
 
 import (
 	"context"
-	"fmt"
+	"encoding/json"
+	"errors"
 	"os"
+	"strconv"
 	"time"
 
 	"github.com/redis/go-redis/v9"
+
+	"github.com/waduhek/flagger/internal/changefeed"
+	"github.com/waduhek/flagger/internal/environment"
+	"github.com/waduhek/flagger/internal/rulesetcache"
 )
 
+// RedisCacheRepository is a CacheRepository that stores the rule sets in a
+// standalone, Sentinel monitored or clustered Redis deployment.
 type RedisCacheRepository struct {
-	rdb *redis.Client
+	rdb redis.UniversalClient
 }
 
-func (r *RedisCacheRepository) IsFlagStatusCached(
+func (r *RedisCacheRepository) GetFlagRuleSet(
 	ctx context.Context,
 	params *cacheParameters,
-) (bool, error) {
-	cacheKey := genFlagStatusCacheKey(params)
+) (*FlagRuleSet, bool, error) {
+	cacheKey := genFlagRuleSetCacheKey(params)
+
+	cachedRuleSet, err := r.rdb.Get(ctx, cacheKey).Bytes()
+	if errors.Is(err, redis.Nil) {
+		return nil, false, nil
+	}
 
-	result, err := r.rdb.Exists(ctx, cacheKey).Result()
 	if err != nil {
-		return false, err
+		return nil, false, err
+	}
+
+	var ruleSet FlagRuleSet
+	if err = json.Unmarshal(cachedRuleSet, &ruleSet); err != nil {
+		return nil, false, err
 	}
 
-	return result >= 1, nil
+	return &ruleSet, true, nil
 }
 
-func (r *RedisCacheRepository) GetFlagStatus(
+func (r *RedisCacheRepository) GetFlagRuleSets(
 	ctx context.Context,
-	params *cacheParameters,
-) (bool, error) {
-	cacheKey := genFlagStatusCacheKey(params)
+	params []cacheParameters,
+) ([]*FlagRuleSet, error) {
+	cacheKeys := make([]string, 0, len(params))
+	for i := range params {
+		cacheKeys = append(cacheKeys, genFlagRuleSetCacheKey(&params[i]))
+	}
+
+	return r.getRuleSets(ctx, cacheKeys)
+}
+
+func (r *RedisCacheRepository) GetLastKnownGoodFlagRuleSets(
+	ctx context.Context,
+	params []cacheParameters,
+) ([]*FlagRuleSet, error) {
+	cacheKeys := make([]string, 0, len(params))
+	for i := range params {
+		cacheKeys = append(cacheKeys, genLastKnownGoodCacheKey(&params[i]))
+	}
 
-	return r.rdb.Get(ctx, cacheKey).Bool()
+	return r.getRuleSets(ctx, cacheKeys)
 }
 
-func (r *RedisCacheRepository) CacheFlagStatus(
+// getRuleSets gets the rule sets stored at the keys in a single round trip,
+// with a nil rule set for every key that does not exist.
+func (r *RedisCacheRepository) getRuleSets(
+	ctx context.Context,
+	cacheKeys []string,
+) ([]*FlagRuleSet, error) {
+	if len(cacheKeys) == 0 {
+		return []*FlagRuleSet{}, nil
+	}
+
+	cachedRuleSets, err := r.rdb.MGet(ctx, cacheKeys...).Result()
+	if err != nil {
+		return nil, err
+	}
+
+	ruleSets := make([]*FlagRuleSet, 0, len(cachedRuleSets))
+	for _, cachedRuleSet := range cachedRuleSets {
+		encodedRuleSet, ok := cachedRuleSet.(string)
+		if !ok {
+			ruleSets = append(ruleSets, nil)
+			continue
+		}
+
+		var ruleSet FlagRuleSet
+		if err = json.Unmarshal([]byte(encodedRuleSet), &ruleSet); err != nil {
+			return nil, err
+		}
+
+		ruleSets = append(ruleSets, &ruleSet)
+	}
+
+	return ruleSets, nil
+}
+
+func (r *RedisCacheRepository) CacheFlagRuleSet(
 	ctx context.Context,
 	params *cacheParameters,
-	status bool,
+	ruleSet *FlagRuleSet,
 ) error {
-	// The TTL in seconds of the keys stored in the Redis cache.
-	cacheTTL, _ := time.ParseDuration(os.Getenv("FLAGGER_CACHE_TTL"))
+	return r.CacheFlagRuleSets(ctx, []cacheParameters{*params}, []*FlagRuleSet{ruleSet})
+}
+
+func (r *RedisCacheRepository) CacheFlagRuleSets(
+	ctx context.Context,
+	params []cacheParameters,
+	ruleSets []*FlagRuleSet,
+) error {
+	pipe := r.rdb.Pipeline()
+
+	for i := range params {
+		encodedRuleSet, err := json.Marshal(ruleSets[i])
+		if err != nil {
+			return err
+		}
 
-	cacheKey := genFlagStatusCacheKey(params)
+		pipe.SetEx(
+			ctx,
+			genFlagRuleSetCacheKey(&params[i]),
+			encodedRuleSet,
+			getRuleSetTTL(ruleSets[i]),
+		)
+
+		if !ruleSets[i].Stale {
+			pipe.SetEx(
+				ctx,
+				genLastKnownGoodCacheKey(&params[i]),
+				encodedRuleSet,
+				getLastKnownGoodTTL(),
+			)
+		}
+	}
+
+	_, err := pipe.Exec(ctx)
+
+	return err
+}
+
+func (r *RedisCacheRepository) GetFlagNames(
+	ctx context.Context,
+	projectKey string,
+	environmentName string,
+) ([]string, bool, error) {
+	cachedFlagNames, err := r.rdb.Get(ctx, rulesetcache.FlagNamesKey(projectKey, environmentName)).Bytes()
+	if errors.Is(err, redis.Nil) {
+		return nil, false, nil
+	}
+
+	if err != nil {
+		return nil, false, err
+	}
+
+	var flagNames []string
+	if err = json.Unmarshal(cachedFlagNames, &flagNames); err != nil {
+		return nil, false, err
+	}
+
+	return flagNames, true, nil
+}
+
+func (r *RedisCacheRepository) CacheFlagNames(
+	ctx context.Context,
+	projectKey string,
+	environmentName string,
+	flagNames []string,
+) error {
+	encodedFlagNames, err := json.Marshal(flagNames)
+	if err != nil {
+		return err
+	}
 
 	return r.rdb.SetEx(
 		ctx,
-		cacheKey,
-		status,
-		cacheTTL,
+		rulesetcache.FlagNamesKey(projectKey, environmentName),
+		encodedFlagNames,
+		getFlagNamesTTL(flagNames),
 	).Err()
 }
 
-// getFlagStatusCacheKey generates the cache key used for caching the flag
-// status.
-func genFlagStatusCacheKey(params *cacheParameters) string {
-	return fmt.Sprintf(
-		"status:%v:%v:%v",
+func (r *RedisCacheRepository) Invalidate(
+	ctx context.Context,
+	projectKey string,
+	change *changefeed.Change,
+) error {
+	if !rulesetcache.AffectsFlagNames(change) {
+		return r.rdb.Del(ctx, rulesetcache.Key(
+			projectKey,
+			change.EnvironmentName,
+			change.FlagName,
+		)).Err()
+	}
+
+	if err := r.deleteMatching(ctx, rulesetcache.Pattern(projectKey, change)); err != nil {
+		return err
+	}
+
+	if change.EnvironmentName != "" {
+		return r.rdb.Del(ctx, rulesetcache.FlagNamesKey(projectKey, change.EnvironmentName)).Err()
+	}
+
+	return r.deleteMatching(ctx, rulesetcache.FlagNamesPattern(projectKey, change))
+}
+
+func (r *RedisCacheRepository) Purge(
+	ctx context.Context,
+	projectKey string,
+	change *changefeed.Change,
+) error {
+	if err := r.Invalidate(ctx, projectKey, change); err != nil {
+		return err
+	}
+
+	if change.EnvironmentName != "" && change.FlagName != "" {
+		return r.rdb.Del(ctx, rulesetcache.LastKnownGoodKey(
+			projectKey,
+			change.EnvironmentName,
+			change.FlagName,
+		)).Err()
+	}
+
+	return r.deleteMatching(ctx, rulesetcache.LastKnownGoodPattern(projectKey, change))
+}
+
+// deleteMatching deletes the keys matching the pattern.
+func (r *RedisCacheRepository) deleteMatching(ctx context.Context, pattern string) error {
+	// The keys of a cluster are spread over its masters, each of which has to
+	// be scanned separately.
+	if cluster, ok := r.rdb.(*redis.ClusterClient); ok {
+		return cluster.ForEachMaster(ctx, func(ctx context.Context, node *redis.Client) error {
+			return deleteMatchingKeys(ctx, node, pattern)
+		})
+	}
+
+	return deleteMatchingKeys(ctx, r.rdb, pattern)
+}
+
+// deleteMatchingKeys scans the node for the keys matching the pattern and
+// deletes them in batches. The keys of the rule sets of all the environments
+// or all the flags are only known to Redis. As the keys of a batch can belong
+// to different slots of a cluster, they are deleted one by one in a pipeline.
+func deleteMatchingKeys(ctx context.Context, rdb redis.Cmdable, pattern string) error {
+	const scanCount = 100
+
+	iter := rdb.Scan(ctx, 0, pattern, scanCount).Iterator()
+
+	cacheKeys := make([]string, 0, scanCount)
+	for iter.Next(ctx) {
+		cacheKeys = append(cacheKeys, iter.Val())
+
+		if len(cacheKeys) == scanCount {
+			if err := deleteKeys(ctx, rdb, cacheKeys); err != nil {
+				return err
+			}
+
+			cacheKeys = cacheKeys[:0]
+		}
+	}
+
+	if err := iter.Err(); err != nil {
+		return err
+	}
+
+	return deleteKeys(ctx, rdb, cacheKeys)
+}
+
+// deleteKeys deletes the keys one by one in a pipeline.
+func deleteKeys(ctx context.Context, rdb redis.Cmdable, keys []string) error {
+	if len(keys) == 0 {
+		return nil
+	}
+
+	_, err := rdb.Pipelined(ctx, func(pipe redis.Pipeliner) error {
+		for _, key := range keys {
+			pipe.Del(ctx, key)
+		}
+
+		return nil
+	})
+
+	return err
+}
+
+// usageKey is the key of the sorted set of the recently used environments,
+// which are scored by the Unix time in milliseconds that they were last used
+// at.
+const usageKey = "environment-usage"
+
+// maxTrackedUsages is the number of the most recently used environments that
+// are kept.
+const maxTrackedUsages = 10000
+
+// lastUsedKeyPrefix is the prefix of the keys of the time that the flags of each
+// environment were last evaluated at, in Unix milliseconds. The keys expire
+// after the recent evaluation window of the environments instead of being
+// capped like the recently used environments, so that an environment in use is
+// never reported as unused.
+const lastUsedKeyPrefix = "environment-last-used:"
+
+// RedisUsageRepository is a UsageRepository that keeps the recently used
+// environments in Redis, so that they are shared by all the replicas and are
+// kept across restarts.
+type RedisUsageRepository struct {
+	rdb redis.UniversalClient
+}
+
+func (r *RedisUsageRepository) RecordUsage(
+	ctx context.Context,
+	usage *EnvironmentUsage,
+) error {
+	member, err := json.Marshal([]string{usage.ProjectKey, usage.EnvironmentName})
+	if err != nil {
+		return err
+	}
+
+	_, err = r.rdb.Pipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.ZAdd(ctx, usageKey, redis.Z{
+			Score:  float64(usage.UsedAt.UnixMilli()),
+			Member: member,
+		})
+		// Only the most recently used environments are kept.
+		pipe.ZRemRangeByRank(ctx, usageKey, 0, -maxTrackedUsages-1)
+
+		if ttl := time.Until(usage.UsedAt.Add(environment.RecentEvaluationWindow)); ttl > 0 {
+			pipe.Set(ctx, lastUsedKeyPrefix+string(member), usage.UsedAt.UnixMilli(), ttl)
+		}
+
+		return nil
+	})
+
+	return err
+}
+
+func (r *RedisUsageRepository) GetRecentlyUsed(
+	ctx context.Context,
+	since time.Time,
+	limit int,
+) ([]EnvironmentUsage, error) {
+	members, err := r.rdb.ZRevRangeByScoreWithScores(ctx, usageKey, &redis.ZRangeBy{
+		Min:   strconv.FormatInt(since.UnixMilli(), 10),
+		Max:   "+inf",
+		Count: int64(limit),
+	}).Result()
+	if err != nil {
+		return nil, err
+	}
+
+	usages := make([]EnvironmentUsage, 0, len(members))
+
+	for _, member := range members {
+		encodedMember, _ := member.Member.(string)
+
+		var keys []string
+		if err = json.Unmarshal([]byte(encodedMember), &keys); err != nil || len(keys) != 2 {
+			continue
+		}
+
+		usages = append(usages, EnvironmentUsage{
+			ProjectKey:      keys[0],
+			EnvironmentName: keys[1],
+			UsedAt:          time.UnixMilli(int64(member.Score)),
+		})
+	}
+
+	return usages, nil
+}
+
+func (r *RedisUsageRepository) GetLastUsed(
+	ctx context.Context,
+	projectKey string,
+	environmentName string,
+) (time.Time, bool, error) {
+	member, err := json.Marshal([]string{projectKey, environmentName})
+	if err != nil {
+		return time.Time{}, false, err
+	}
+
+	usedAt, err := r.rdb.Get(ctx, lastUsedKeyPrefix+string(member)).Int64()
+	if errors.Is(err, redis.Nil) {
+		return time.Time{}, false, nil
+	}
+
+	if err != nil {
+		return time.Time{}, false, err
+	}
+
+	return time.UnixMilli(usedAt), true, nil
+}
+
+func NewRedisUsageRepository(rdb redis.UniversalClient) *RedisUsageRepository {
+	return &RedisUsageRepository{rdb: rdb}
+}
+
+// getCacheTTL gets the TTL of the keys stored in the Redis cache.
+func getCacheTTL() time.Duration {
+	cacheTTL, _ := time.ParseDuration(os.Getenv("FLAGGER_CACHE_TTL"))
+
+	return cacheTTL
+}
+
+// getRuleSetTTL gets the TTL of the cached rule set. Flags that were not found
+// are cached for at most the negative cache TTL so that newly created flags
+// are not hidden for long if an invalidation is missed.
+func getRuleSetTTL(ruleSet *FlagRuleSet) time.Duration {
+	cacheTTL := getCacheTTL()
+
+	if !ruleSet.NotFound {
+		return cacheTTL
+	}
+
+	negativeCacheTTL, err := time.ParseDuration(os.Getenv("FLAGGER_NEGATIVE_CACHE_TTL"))
+	if err != nil {
+		return cacheTTL
+	}
+
+	return min(cacheTTL, negativeCacheTTL)
+}
+
+// getFlagNamesTTL gets the TTL of the cached names of the flags of an
+// environment. The names of an environment without flags are cached for at
+// most the negative cache TTL, like a flag that was not found.
+func getFlagNamesTTL(flagNames []string) time.Duration {
+	return getRuleSetTTL(&FlagRuleSet{NotFound: len(flagNames) == 0})
+}
+
+// getLastKnownGoodTTL gets the TTL of the last known good rule sets, which is
+// never shorter than the TTL of the cache.
+func getLastKnownGoodTTL() time.Duration {
+	const defaultLastKnownGoodTTL = 7 * 24 * time.Hour
+
+	lastKnownGoodTTL, err := time.ParseDuration(os.Getenv("FLAGGER_LAST_KNOWN_GOOD_TTL"))
+	if err != nil {
+		lastKnownGoodTTL = defaultLastKnownGoodTTL
+	}
+
+	return max(lastKnownGoodTTL, getCacheTTL())
+}
+
+// genFlagRuleSetCacheKey generates the cache key used for caching the flag
+// rule set.
+func genFlagRuleSetCacheKey(params *cacheParameters) string {
+	return rulesetcache.Key(
+		params.ProjectKey,
+		params.EnvironmentName,
+		params.FlagName,
+	)
+}
+
+// genLastKnownGoodCacheKey generates the key that the last known good rule set
+// of the flag is kept with.
+func genLastKnownGoodCacheKey(params *cacheParameters) string {
+	return rulesetcache.LastKnownGoodKey(
 		params.ProjectKey,
 		params.EnvironmentName,
 		params.FlagName,
 	)
 }
 
-func NewProviderCacheRepository(rdb *redis.Client) *RedisCacheRepository {
+func NewProviderCacheRepository(rdb redis.UniversalClient) *RedisCacheRepository {
 	return &RedisCacheRepository{
 		rdb: rdb,
 	}
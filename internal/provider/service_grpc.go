@@ -3,13 +3,20 @@ package provider
 import (
 	"context"
 	"errors"
+	"maps"
+	"slices"
+	"strings"
+	"time"
 
-	"github.com/redis/go-redis/v9"
+	"golang.org/x/sync/singleflight"
+	"google.golang.org/grpc/status"
 
 	"github.com/waduhek/flagger/proto/providerpb"
 
+	"github.com/waduhek/flagger/internal/changefeed"
 	"github.com/waduhek/flagger/internal/logger"
 	"github.com/waduhek/flagger/internal/project"
+	"github.com/waduhek/flagger/internal/targeting"
 )
 
 // FlagProviderServer is an implementation of the FlagProvider service.
@@ -17,9 +24,18 @@ type FlagProviderServer struct {
 	providerpb.UnimplementedFlagProviderServer
 	providerDataRepo  DataRepository
 	providerCacheRepo CacheRepository
+	changeFeedRepo    changefeed.Repository
+	usageRepo         UsageRepository
 	logger            logger.Logger
+	fetchGroup        singleflight.Group
+	warmer            cacheWarmer
+	refresher         staleRefresher
 }
 
+// fetchTimeout is the longest duration that a fetch of rule sets from the
+// database that is shared by concurrent requests can take.
+const fetchTimeout = 10 * time.Second
+
 func (s *FlagProviderServer) GetFlag(
 	ctx context.Context,
 	req *providerpb.GetFlagRequest,
@@ -30,133 +46,568 @@ func (s *FlagProviderServer) GetFlag(
 		return nil, project.ErrProjectKeyNotFound
 	}
 
-	// Check if the flag status has been cached previously and return it.
-	isFlagStatusCached, isCachedErr :=
-		s.checkIfFlagStatusIsCached(ctx, projectKey, req)
-	if isCachedErr != nil {
-		return nil, isCachedErr
+	cacheParams := cacheParameters{
+		ProjectKey:      projectKey,
+		EnvironmentName: req.GetEnvironment(),
+		FlagName:        req.GetFlagName(),
+	}
+
+	environmentName := req.GetEnvironment()
+
+	ruleSet, err := s.getFlagRuleSet(ctx, &cacheParams)
+	if err == nil && ruleSet.Renamed != nil {
+		environmentName = ruleSet.Renamed.EnvironmentName
+		ruleSet, err = s.getRenamedFlagRuleSet(ctx, &cacheParams, ruleSet.Renamed)
+	}
+
+	if errors.Is(err, ErrFlagNotFound) {
+		return newErrorResponse(nil, err), nil
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	s.recordUsage(projectKey, environmentName)
+
+	// The rule set is evaluated for every request as the served variation
+	// depends on the evaluation context of the caller.
+	return s.evaluateFlagResponse(
+		req.GetFlagName(),
+		ruleSet,
+		newEvaluationContext(req.GetContext()),
+	), nil
+}
+
+func (s *FlagProviderServer) GetFlags(
+	ctx context.Context,
+	req *providerpb.GetFlagsRequest,
+) (*providerpb.GetFlagsResponse, error) {
+	projectKey, ok := project.KeyFromContext(ctx)
+	if !ok {
+		s.logger.Error("could not find project key in request")
+		return nil, project.ErrProjectKeyNotFound
+	}
+
+	ruleSets, err := s.getFlagRuleSets(
+		ctx,
+		projectKey,
+		req.GetEnvironment(),
+		req.GetFlagNames(),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(ruleSets) > 0 {
+		s.recordUsage(projectKey, req.GetEnvironment())
+	}
+
+	response := s.newGetFlagsResponse(
+		ruleSets,
+		newEvaluationContext(req.GetContext()),
+	)
+
+	// Flags that were requested but not found are responded to with an error
+	// code so that the callers can tell them apart from disabled flags.
+	for _, flagName := range req.GetFlagNames() {
+		if _, isFound := response.GetFlags()[flagName]; !isFound {
+			response.Flags[flagName] = newErrorResponse(nil, ErrFlagNotFound)
+		}
+	}
+
+	return response, nil
+}
+
+func (s *FlagProviderServer) GetAllFlags(
+	ctx context.Context,
+	req *providerpb.GetAllFlagsRequest,
+) (*providerpb.GetFlagsResponse, error) {
+	projectKey, ok := project.KeyFromContext(ctx)
+	if !ok {
+		s.logger.Error("could not find project key in request")
+		return nil, project.ErrProjectKeyNotFound
+	}
+
+	ruleSets, err := s.getAllFlagRuleSets(ctx, projectKey, req.GetEnvironment())
+	if err != nil {
+		return nil, err
+	}
+
+	if len(ruleSets) > 0 {
+		s.recordUsage(projectKey, req.GetEnvironment())
 	}
 
-	if isFlagStatusCached {
-		s.logger.Info("found cached value for flag status")
+	return s.newGetFlagsResponse(
+		ruleSets,
+		newEvaluationContext(req.GetContext()),
+	), nil
+}
+
+// newGetFlagsResponse evaluates the rule sets of the flags for the evaluation
+// context.
+func (s *FlagProviderServer) newGetFlagsResponse(
+	ruleSets map[string]*FlagRuleSet,
+	evalCtx *targeting.Context,
+) *providerpb.GetFlagsResponse {
+	flags := make(map[string]*providerpb.GetFlagResponse, len(ruleSets))
+
+	for flagName, ruleSet := range ruleSets {
+		flags[flagName] = s.evaluateFlagResponse(flagName, ruleSet, evalCtx)
+	}
+
+	return &providerpb.GetFlagsResponse{Flags: flags}
+}
+
+// evaluateFlagResponse evaluates the rule set of the flag for the evaluation
+// context. A flag that could not be evaluated is responded to with the code of
+// the error instead of failing the request.
+func (s *FlagProviderServer) evaluateFlagResponse(
+	flagName string,
+	ruleSet *FlagRuleSet,
+	evalCtx *targeting.Context,
+) *providerpb.GetFlagResponse {
+	flagStatus, err := evaluateFlag(ruleSet, evalCtx)
+	if err != nil {
+		s.logger.Error("could not evaluate flag %q: %v", flagName, err)
+		return newErrorResponse(ruleSet, err)
+	}
+
+	response, err := newGetFlagResponse(flagStatus)
+	if err != nil {
+		s.logger.Error("could not create response for flag %q: %v", flagName, err)
+		return newErrorResponse(ruleSet, err)
+	}
 
-		cachedStatus, cachedStatusErr := s.getCachedFlagStatus(ctx, projectKey, req)
-		if cachedStatusErr != nil {
-			return nil, cachedStatusErr
+	return response
+}
+
+// getAllFlagRuleSets gets the rule sets of all the flags of the environment
+// from the cache through the cached names of the flags. The names are only
+// known to the database, so the rule sets of all the flags are fetched along
+// with their names when the names have not been cached.
+func (s *FlagProviderServer) getAllFlagRuleSets(
+	ctx context.Context,
+	projectKey string,
+	environmentName string,
+) (map[string]*FlagRuleSet, error) {
+	flagNames, isCached, err := s.providerCacheRepo.GetFlagNames(ctx, projectKey, environmentName)
+	if err != nil {
+		s.logger.Warn("could not get cached flag names: %v. ignoring error", err)
+	}
+
+	if !isCached {
+		return s.fetchFlagRuleSets(ctx, projectKey, environmentName, nil)
+	}
+
+	return s.getFlagRuleSets(ctx, projectKey, environmentName, flagNames)
+}
+
+// getFlagRuleSets gets the rule sets of the flags from the cache with a single
+// round trip. The rule sets of the flags that have not been cached are fetched
+// with a single query and cached for the next time. Flags that are requested by
+// a previous name of the flag or its environment get the rule sets of their
+// current names. Flags that do not exist are left out of the rule sets.
+func (s *FlagProviderServer) getFlagRuleSets(
+	ctx context.Context,
+	projectKey string,
+	environmentName string,
+	flagNames []string,
+) (map[string]*FlagRuleSet, error) {
+	flagNames = slices.Compact(slices.Sorted(slices.Values(flagNames)))
+
+	allCacheParams := make([]cacheParameters, 0, len(flagNames))
+	for _, flagName := range flagNames {
+		allCacheParams = append(allCacheParams, cacheParameters{
+			ProjectKey:      projectKey,
+			EnvironmentName: environmentName,
+			FlagName:        flagName,
+		})
+	}
+
+	// The cache is only an accelerator, so all the rule sets are fetched from
+	// the database when it fails.
+	cachedRuleSets, err := s.providerCacheRepo.GetFlagRuleSets(ctx, allCacheParams)
+	if err != nil {
+		s.logger.Warn("could not get cached flag rule sets: %v. ignoring error", err)
+		cachedRuleSets = make([]*FlagRuleSet, len(allCacheParams))
+	}
+
+	ruleSets := make(map[string]*FlagRuleSet, len(flagNames))
+	uncachedFlagNames := make([]string, 0, len(flagNames))
+
+	for i, cachedRuleSet := range cachedRuleSets {
+		if cachedRuleSet == nil {
+			uncachedFlagNames = append(uncachedFlagNames, flagNames[i])
+			continue
 		}
 
-		response := &providerpb.GetFlagResponse{
-			Status: cachedStatus,
+		if cachedRuleSet.NotFound && cachedRuleSet.Renamed == nil {
+			continue
 		}
 
-		return response, nil
+		cachedRuleSet.cached = true
+		ruleSets[flagNames[i]] = cachedRuleSet
 	}
 
-	environmentName := req.GetEnvironment()
-	flagName := req.GetFlagName()
+	if len(uncachedFlagNames) > 0 {
+		fetchedRuleSets, err := s.fetchFlagRuleSets(
+			ctx,
+			projectKey,
+			environmentName,
+			uncachedFlagNames,
+		)
+		if err != nil {
+			return nil, err
+		}
 
-	flagDetails, err := s.providerDataRepo.GetFlagDetailsByProjectKey(
+		maps.Copy(ruleSets, fetchedRuleSets)
+	}
+
+	for flagName, ruleSet := range ruleSets {
+		if ruleSet.Renamed == nil {
+			continue
+		}
+
+		cacheParams := cacheParameters{
+			ProjectKey:      projectKey,
+			EnvironmentName: environmentName,
+			FlagName:        flagName,
+		}
+
+		renamedRuleSet, err := s.getRenamedFlagRuleSet(ctx, &cacheParams, ruleSet.Renamed)
+		if errors.Is(err, ErrFlagNotFound) {
+			delete(ruleSets, flagName)
+			continue
+		}
+
+		if err != nil {
+			return nil, err
+		}
+
+		ruleSets[flagName] = renamedRuleSet
+	}
+
+	return ruleSets, nil
+}
+
+// fetchFlagRuleSets fetches the rule sets of the flags with a single query and
+// caches them for the next time. The rule sets of all the flags of the project
+// are fetched when no names are provided. Flags that are only found by a
+// previous name get rule sets with their current names. Concurrent fetches of the same flags
+// are served by a single query. The last known good rule sets of the flags are
+// served stale if the query fails.
+func (s *FlagProviderServer) fetchFlagRuleSets(
+	ctx context.Context,
+	projectKey string,
+	environmentName string,
+	flagNames []string,
+) (map[string]*FlagRuleSet, error) {
+	fetchKey := strings.Join(
+		append([]string{"rulesets", projectKey, environmentName}, flagNames...),
+		"\x00",
+	)
+
+	return coalesce(ctx, &s.fetchGroup, fetchKey, func(ctx context.Context) (map[string]*FlagRuleSet, error) {
+		ruleSets, err := s.queryFlagRuleSets(ctx, projectKey, environmentName, flagNames)
+		if errors.Is(err, ErrFetchFlagDetails) {
+			return s.getStaleFlagRuleSets(ctx, projectKey, environmentName, flagNames)
+		}
+
+		return ruleSets, err
+	})
+}
+
+// queryFlagRuleSets queries the rule sets of the flags and caches them. The
+// flags that were requested but not found are cached as not found, along with
+// their current names if they were found by an alias. The rule sets of the
+// flags that were found by an alias are returned with their current names. The
+// names of all the flags are cached as well when no names are provided.
+func (s *FlagProviderServer) queryFlagRuleSets(
+	ctx context.Context,
+	projectKey string,
+	environmentName string,
+	flagNames []string,
+) (map[string]*FlagRuleSet, error) {
+	flagDetails, err := s.providerDataRepo.GetFlagDetailsByProjectKeyAndNames(
 		ctx,
 		projectKey,
 		environmentName,
-		flagName,
+		flagNames,
 	)
 	if err != nil {
-		s.logger.Error("error while fetching details of the flag: %v", err)
+		s.logger.Error("error while fetching details of the flags: %v", err)
 		return nil, ErrFetchFlagDetails
 	}
 
-	if len(flagDetails) != 1 {
-		s.logger.Error("found %d responses of flag details", len(flagDetails))
-		return nil, ErrIncorrectFlagDetailCount
+	ruleSets := make(map[string]*FlagRuleSet, len(flagDetails))
+	allCacheParams := make([]cacheParameters, 0, len(flagDetails))
+	ruleSetsToCache := make([]*FlagRuleSet, 0, len(flagDetails))
+
+	for i := range flagDetails {
+		ruleSet := newFlagRuleSet(&flagDetails[i])
+
+		ruleSets[ruleSet.Flag.Name] = ruleSet
+		allCacheParams = append(allCacheParams, cacheParameters{
+			ProjectKey:      projectKey,
+			EnvironmentName: environmentName,
+			FlagName:        ruleSet.Flag.Name,
+		})
+		ruleSetsToCache = append(ruleSetsToCache, ruleSet)
 	}
 
-	flagDetail := flagDetails[0]
-	status := flagDetail.FlagSetting.IsActive
+	for _, flagName := range flagNames {
+		if _, ok := ruleSets[flagName]; ok {
+			continue
+		}
 
-	// Cache the result of this flag status for the next time.
-	cacheParams := cacheParameters{
-		ProjectKey:      projectKey,
-		EnvironmentName: environmentName,
-		FlagName:        flagName,
+		cacheParams := cacheParameters{
+			ProjectKey:      projectKey,
+			EnvironmentName: environmentName,
+			FlagName:        flagName,
+		}
+
+		ruleSet := &FlagRuleSet{NotFound: true}
+		if err = s.queryRenamedFlag(ctx, &cacheParams, ruleSet); err != nil {
+			return nil, err
+		}
+
+		if ruleSet.Renamed != nil {
+			ruleSets[flagName] = ruleSet
+		}
+
+		allCacheParams = append(allCacheParams, cacheParams)
+		ruleSetsToCache = append(ruleSetsToCache, ruleSet)
 	}
 
-	cacheErr := s.providerCacheRepo.CacheFlagStatus(ctx, &cacheParams, status)
+	// Cache the rule sets of these flags for the next time.
+	cacheErr := s.providerCacheRepo.CacheFlagRuleSets(
+		ctx,
+		allCacheParams,
+		ruleSetsToCache,
+	)
 	if cacheErr != nil {
-		s.logger.Warn("could not cache flag status: %v. ignoring error", cacheErr)
+		s.logger.Warn("could not cache flag rule sets: %v. ignoring error", cacheErr)
 	}
 
-	response := &providerpb.GetFlagResponse{
-		Status: status,
+	if len(flagNames) > 0 {
+		return ruleSets, nil
 	}
 
-	return response, nil
+	cacheErr = s.providerCacheRepo.CacheFlagNames(
+		ctx,
+		projectKey,
+		environmentName,
+		slices.Sorted(maps.Keys(ruleSets)),
+	)
+	if cacheErr != nil {
+		s.logger.Warn("could not cache flag names: %v. ignoring error", cacheErr)
+	}
+
+	return ruleSets, nil
 }
 
-// checkIfFlagStatusIsCached checks if the requested flag's status has already
-// been cached.
-func (s *FlagProviderServer) checkIfFlagStatusIsCached(
+// getRenamedFlagRuleSet gets the rule set of a flag that was requested by a
+// previous name of the flag or its environment by its current names. The
+// previous names are deprecated and stop resolving once their aliases expire.
+func (s *FlagProviderServer) getRenamedFlagRuleSet(
 	ctx context.Context,
-	projectKey string,
-	req *providerpb.GetFlagRequest,
-) (bool, error) {
-	environmentName := req.GetEnvironment()
-	flagName := req.GetFlagName()
+	cacheParams *cacheParameters,
+	renamed *RenamedFlag,
+) (*FlagRuleSet, error) {
+	s.logger.Warn(
+		"flag %q of environment %q of project %q was requested by a deprecated name. use flag %q of environment %q",
+		cacheParams.FlagName,
+		cacheParams.EnvironmentName,
+		cacheParams.ProjectKey,
+		renamed.FlagName,
+		renamed.EnvironmentName,
+	)
 
-	cacheParams := cacheParameters{
-		ProjectKey:      projectKey,
-		EnvironmentName: environmentName,
-		FlagName:        flagName,
+	renamedCacheParams := cacheParameters{
+		ProjectKey:      cacheParams.ProjectKey,
+		EnvironmentName: renamed.EnvironmentName,
+		FlagName:        renamed.FlagName,
 	}
 
-	statusExists, err := s.providerCacheRepo.IsFlagStatusCached(ctx, &cacheParams)
+	ruleSet, err := s.getFlagRuleSet(ctx, &renamedCacheParams)
 	if err != nil {
-		s.logger.Error(
-			"error occurred while checking if flag status is cached: %v",
-			err,
-		)
-		return false, ErrStatusCache
+		return nil, err
 	}
 
-	return statusExists, nil
+	// The flag was renamed again since, so the previous name is resolved
+	// again once the cached rule set expires.
+	if ruleSet.Renamed != nil {
+		return nil, ErrFlagNotFound
+	}
+
+	return ruleSet, nil
 }
 
-// getCachedFlagStatus gets the value of the cached flag status.
-func (s *FlagProviderServer) getCachedFlagStatus(
+// getFlagRuleSet gets the rule set of the flag from the cache with a single
+// round trip. If the rule set has not been cached, it is fetched and cached for
+// the next time. The last known good rule set of the flag is served stale if
+// it could not be fetched. A flag that is only found by a previous name of the
+// flag or its environment gets a rule set with the current names.
+func (s *FlagProviderServer) getFlagRuleSet(
 	ctx context.Context,
-	projectKey string,
-	req *providerpb.GetFlagRequest,
-) (bool, error) {
-	environmentName := req.GetEnvironment()
-	flagName := req.GetFlagName()
+	cacheParams *cacheParameters,
+) (*FlagRuleSet, error) {
+	// The cache is only an accelerator, so the rule set is fetched from the
+	// database when it fails.
+	cachedRuleSet, isCached, err := s.providerCacheRepo.GetFlagRuleSet(ctx, cacheParams)
+	if err != nil {
+		s.logger.Warn("could not get cached flag rule set: %v. ignoring error", err)
+	}
 
-	cacheParams := cacheParameters{
-		ProjectKey:      projectKey,
-		EnvironmentName: environmentName,
-		FlagName:        flagName,
+	if isCached {
+		if cachedRuleSet.NotFound && cachedRuleSet.Renamed == nil {
+			return nil, ErrFlagNotFound
+		}
+
+		cachedRuleSet.cached = true
+
+		return cachedRuleSet, nil
 	}
 
-	cachedStatus, err := s.providerCacheRepo.GetFlagStatus(ctx, &cacheParams)
+	// Concurrent misses of the same flag are served by a single query.
+	return coalesce(
+		ctx,
+		&s.fetchGroup,
+		genFlagRuleSetCacheKey(cacheParams),
+		func(ctx context.Context) (*FlagRuleSet, error) {
+			ruleSet, err := s.queryFlagRuleSet(ctx, cacheParams)
+			if errors.Is(err, ErrFetchFlagDetails) {
+				return s.getStaleFlagRuleSet(ctx, cacheParams)
+			}
+
+			return ruleSet, err
+		},
+	)
+}
+
+// queryFlagRuleSet queries the rule set of the flag and caches it. A flag that
+// was not found is cached as not found, along with its current names if it was
+// found by an alias.
+func (s *FlagProviderServer) queryFlagRuleSet(
+	ctx context.Context,
+	cacheParams *cacheParameters,
+) (*FlagRuleSet, error) {
+	flagDetails, err := s.providerDataRepo.GetFlagDetailsByProjectKey(
+		ctx,
+		cacheParams.ProjectKey,
+		cacheParams.EnvironmentName,
+		cacheParams.FlagName,
+	)
 	if err != nil {
-		if errors.Is(err, redis.Nil) {
-			s.logger.Error("Could not find cached flag status")
-			return false, nil
-		}
+		s.logger.Error("error while fetching details of the flag: %v", err)
+		return nil, ErrFetchFlagDetails
+	}
 
-		return false, err
+	if len(flagDetails) > 1 {
+		s.logger.Error("found %d responses of flag details", len(flagDetails))
+		return nil, ErrIncorrectFlagDetailCount
+	}
+
+	ruleSet := &FlagRuleSet{NotFound: true}
+	if len(flagDetails) == 1 {
+		ruleSet = newFlagRuleSet(&flagDetails[0])
+	} else if err = s.queryRenamedFlag(ctx, cacheParams, ruleSet); err != nil {
+		return nil, err
 	}
 
-	return cachedStatus, nil
+	// Cache the rule set of this flag for the next time.
+	cacheErr := s.providerCacheRepo.CacheFlagRuleSet(ctx, cacheParams, ruleSet)
+	if cacheErr != nil {
+		s.logger.Warn("could not cache flag rule set: %v. ignoring error", cacheErr)
+	}
+
+	if ruleSet.Renamed != nil {
+		return ruleSet, nil
+	}
+
+	if ruleSet.NotFound {
+		s.logger.Error("flag %q was not found", cacheParams.FlagName)
+		return nil, ErrFlagNotFound
+	}
+
+	return ruleSet, nil
+}
+
+// queryRenamedFlag queries the current names of the flag that was not found by
+// its name into its rule set, in case its name or the name of its environment
+// is an alias that has not expired. The flag is not renamed if the aliases are
+// ambiguous.
+func (s *FlagProviderServer) queryRenamedFlag(
+	ctx context.Context,
+	cacheParams *cacheParameters,
+	ruleSet *FlagRuleSet,
+) error {
+	flagDetails, err := s.providerDataRepo.GetRenamedFlagDetails(
+		ctx,
+		cacheParams.ProjectKey,
+		cacheParams.EnvironmentName,
+		cacheParams.FlagName,
+	)
+	if err != nil {
+		s.logger.Error("error while fetching details of the renamed flag: %v", err)
+		return ErrFetchFlagDetails
+	}
+
+	if len(flagDetails) > 1 {
+		s.logger.Error("found %d renamed flags for flag %q", len(flagDetails), cacheParams.FlagName)
+	}
+
+	if len(flagDetails) == 1 {
+		ruleSet.Renamed = &RenamedFlag{
+			EnvironmentName: flagDetails[0].Environment.Name,
+			FlagName:        flagDetails[0].Flag.Name,
+		}
+	}
+
+	return nil
+}
+
+// coalesce runs the fetch once for all the concurrent callers with the same
+// key. The fetch is not cancelled with the request of the caller that started
+// it, as the other callers are waiting for it.
+func coalesce[T any](
+	ctx context.Context,
+	group *singleflight.Group,
+	key string,
+	fetch func(ctx context.Context) (T, error),
+) (T, error) {
+	results := group.DoChan(key, func() (any, error) {
+		fetchCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), fetchTimeout)
+		defer cancel()
+
+		return fetch(fetchCtx)
+	})
+
+	select {
+	case <-ctx.Done():
+		var zero T
+		return zero, status.FromContextError(ctx.Err()).Err()
+	case result := <-results:
+		value, _ := result.Val.(T)
+		return value, result.Err
+	}
 }
 
 func NewFlagProviderServer(
 	providerDataRepo DataRepository,
 	providerCacheRepo CacheRepository,
+	changeFeedRepo changefeed.Repository,
+	usageRepo UsageRepository,
 	logger logger.Logger,
 ) *FlagProviderServer {
 	return &FlagProviderServer{
 		providerDataRepo:  providerDataRepo,
 		providerCacheRepo: providerCacheRepo,
+		changeFeedRepo:    changeFeedRepo,
+		usageRepo:         usageRepo,
 		logger:            logger,
+		warmer:            cacheWarmer{requests: make(chan warmRequest, warmQueueSize)},
+		refresher:         staleRefresher{requests: make(chan staleRefresh, staleRefreshQueueSize)},
 	}
 }
@@ -0,0 +1,477 @@
+package provider_test
+
+import (
+	"context"
+	"slices"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/waduhek/flagger/proto/providerpb"
+
+	"github.com/waduhek/flagger/internal/changefeed"
+	"github.com/waduhek/flagger/internal/flag"
+	"github.com/waduhek/flagger/internal/logger"
+	"github.com/waduhek/flagger/internal/provider"
+	"github.com/waduhek/flagger/internal/targeting"
+)
+
+func TestFlagProviderServer_GetFlag_NegativeCaching(t *testing.T) {
+	cacheRepo := newRedisCacheRepository(t, "localhost:6379")
+	t.Setenv("FLAGGER_CACHE_TTL", "1m")
+
+	repo := newInvalidationRepository(t, cacheRepo)
+	server := provider.NewFlagProviderServer(repo, cacheRepo, nil, nil, &logger.StubLogger{})
+
+	getUnknownFlag := func() {
+		t.Helper()
+
+		response, err := server.GetFlag(authorisedContext(t), &providerpb.GetFlagRequest{
+			Environment: "production",
+			FlagName:    "unknown",
+		})
+		if err != nil {
+			t.Fatalf("could not get flag: %v", err)
+		}
+
+		if response.GetErrorCode() != providerpb.ErrorCode_ERROR_CODE_FLAG_NOT_FOUND {
+			t.Fatalf("expected the flag not to be found but got %v", response)
+		}
+	}
+
+	for range 3 {
+		getUnknownFlag()
+	}
+
+	response, err := server.GetFlags(authorisedContext(t), &providerpb.GetFlagsRequest{
+		Environment: "production",
+		FlagNames:   []string{"unknown"},
+	})
+	if err != nil {
+		t.Fatalf("could not get flags: %v", err)
+	}
+
+	if response.GetFlags()["unknown"].GetErrorCode() != providerpb.ErrorCode_ERROR_CODE_FLAG_NOT_FOUND {
+		t.Fatalf("expected the flag not to be found but got %v", response)
+	}
+
+	if queries := repo.queries.Load(); queries != 1 {
+		t.Fatalf("expected the unknown flag to be queried once but it was queried %d times", queries)
+	}
+
+	// Creating the flag invalidates it in all the environments.
+	err = cacheRepo.Invalidate(context.Background(), invalidationProjectKey, &changefeed.Change{
+		FlagName: "unknown",
+	})
+	if err != nil {
+		t.Fatalf("could not invalidate the flag: %v", err)
+	}
+
+	getUnknownFlag()
+
+	if queries := repo.queries.Load(); queries != 2 {
+		t.Fatalf("expected the invalidated flag to be queried again but it was queried %d times", queries)
+	}
+}
+
+func TestFlagProviderServer_GetFlag_ArchivedFlag(t *testing.T) {
+	cacheRepo := provider.NewMemoryCacheRepository(100)
+	repo := newInvalidationRepository(t, cacheRepo)
+	server := provider.NewFlagProviderServer(repo, cacheRepo, nil, nil, &logger.StubLogger{})
+
+	for i := range repo.flagDetails {
+		switch repo.flagDetails[i].Flag.Name {
+		case "kill-switch":
+			repo.flagDetails[i].Flag.IsArchived = true
+		case "new-checkout":
+			repo.flagDetails[i].Flag.IsArchived = true
+			repo.flagDetails[i].Flag.ArchivedVariation = flag.OnVariationKey
+			repo.flagDetails[i].FlagSetting.IsActive = false
+		}
+	}
+
+	// An archived flag serves its off variation by default, even while it is
+	// active in the environment.
+	response := getFlag(t, server, "production", "kill-switch")
+	if response.GetStatus() || response.GetBoolValue() || response.GetReason() != providerpb.Reason_REASON_DISABLED {
+		t.Fatalf("expected the archived flag to serve its off variation but got %v", response)
+	}
+
+	response = getFlag(t, server, "production", "new-checkout")
+	if !response.GetBoolValue() || response.GetVariation() != flag.OnVariationKey {
+		t.Fatalf("expected the archived flag to serve its archived variation but got %v", response)
+	}
+}
+
+func TestFlagProviderServer_GetFlag_RenamedFlag(t *testing.T) {
+	t.Setenv("FLAGGER_CACHE_TTL", "1m")
+
+	cacheRepo := provider.NewMemoryCacheRepository(100)
+	repo := newInvalidationRepository(t, cacheRepo)
+	repo.aliases = map[string]string{"checkout-v2": "new-checkout"}
+	server := provider.NewFlagProviderServer(repo, cacheRepo, nil, nil, &logger.StubLogger{})
+
+	// The flag is served by its previous name until the alias expires, and
+	// the previous name is cached along with the current name.
+	for range 2 {
+		response := getFlag(t, server, "production", "checkout-v2")
+		if !response.GetStatus() {
+			t.Fatalf("expected the renamed flag to be served by its previous name but got %v", response)
+		}
+	}
+
+	if queries := repo.queries.Load(); queries != 2 {
+		t.Fatalf("expected the previous and the current name to be queried once but got %d queries", queries)
+	}
+
+	response, err := server.GetFlag(authorisedContext(t), &providerpb.GetFlagRequest{
+		Environment: "production",
+		FlagName:    "checkout-v1",
+	})
+	if err != nil {
+		t.Fatalf("could not get flag: %v", err)
+	}
+
+	if response.GetErrorCode() != providerpb.ErrorCode_ERROR_CODE_FLAG_NOT_FOUND {
+		t.Fatalf("expected an unknown previous name not to be found but got %v", response)
+	}
+}
+
+func TestFlagProviderServer_GetFlags_RenamedFlag(t *testing.T) {
+	t.Setenv("FLAGGER_CACHE_TTL", "1m")
+
+	cacheRepo := provider.NewMemoryCacheRepository(100)
+	repo := newInvalidationRepository(t, cacheRepo)
+	repo.aliases = map[string]string{"checkout-v2": "new-checkout"}
+	server := provider.NewFlagProviderServer(repo, cacheRepo, nil, nil, &logger.StubLogger{})
+
+	response, err := server.GetFlags(authorisedContext(t), &providerpb.GetFlagsRequest{
+		Environment: "production",
+		FlagNames:   []string{"checkout-v2", "unknown"},
+	})
+	if err != nil {
+		t.Fatalf("could not get flags: %v", err)
+	}
+
+	if !response.GetFlags()["checkout-v2"].GetStatus() {
+		t.Fatalf("expected the renamed flag to be served by its previous name but got %v", response)
+	}
+
+	if response.GetFlags()["unknown"].GetErrorCode() != providerpb.ErrorCode_ERROR_CODE_FLAG_NOT_FOUND {
+		t.Fatalf("expected the unknown flag not to be found but got %v", response)
+	}
+
+	// The previous name is cached along with the current name, so that it is
+	// served from the cache to the requests of a single flag as well.
+	if !getFlag(t, server, "production", "checkout-v2").GetStatus() {
+		t.Fatal("expected the renamed flag to be served by its previous name")
+	}
+
+	if queries := repo.queries.Load(); queries != 2 {
+		t.Fatalf("expected the previous and the current name to be queried once but got %d queries", queries)
+	}
+}
+
+func TestFlagProviderServer_GetFlag_RenamedFlagKeepsRollout(t *testing.T) {
+	cacheRepo := provider.NewMemoryCacheRepository(100)
+	repo := newInvalidationRepository(t, cacheRepo)
+	server := provider.NewFlagProviderServer(repo, cacheRepo, nil, nil, &logger.StubLogger{})
+
+	for i := range repo.flagDetails {
+		if repo.flagDetails[i].Flag.Name == "new-checkout" {
+			repo.flagDetails[i].Flag.Salt = "salt"
+			repo.flagDetails[i].FlagSetting.Rollout = []targeting.WeightedVariation{
+				{Variation: flag.OnVariationKey, Weight: targeting.TotalWeight / 2},
+				{Variation: flag.OffVariationKey, Weight: targeting.TotalWeight / 2},
+			}
+		}
+	}
+
+	rollVariations := func(flagName string) []string {
+		t.Helper()
+
+		variations := make([]string, 0, 100)
+		for i := range 100 {
+			response, err := server.GetFlag(authorisedContext(t), &providerpb.GetFlagRequest{
+				Environment: "production",
+				FlagName:    flagName,
+				Context:     &providerpb.EvaluationContext{TargetingKey: "user-" + strconv.Itoa(i)},
+			})
+			if err != nil {
+				t.Fatalf("could not get flag: %v", err)
+			}
+
+			variations = append(variations, response.GetVariation())
+		}
+
+		return variations
+	}
+
+	before := rollVariations("new-checkout")
+	if !slices.Contains(before, flag.OnVariationKey) || !slices.Contains(before, flag.OffVariationKey) {
+		t.Fatalf("expected the rollout to serve both variations but got %v", before)
+	}
+
+	for i := range repo.flagDetails {
+		if repo.flagDetails[i].Flag.Name == "new-checkout" {
+			repo.flagDetails[i].Flag.Name = "checkout-v2"
+		}
+	}
+
+	err := cacheRepo.Purge(context.Background(), invalidationProjectKey, &changefeed.Change{})
+	if err != nil {
+		t.Fatalf("could not purge the rule sets of the project: %v", err)
+	}
+
+	// Renaming the flag doesn't move the targeting keys between variations.
+	after := rollVariations("checkout-v2")
+	if !slices.Equal(before, after) {
+		t.Fatalf("expected the renamed flag to serve the same variations but got %v and %v", before, after)
+	}
+}
+
+func TestFlagProviderServer_GetAllFlags_ServesFromCache(t *testing.T) {
+	testCases := []struct {
+		Name         string
+		NewCacheRepo func(t *testing.T) provider.CacheRepository
+	}{
+		{
+			Name: "memory",
+			NewCacheRepo: func(*testing.T) provider.CacheRepository {
+				return provider.NewMemoryCacheRepository(100)
+			},
+		},
+		{
+			Name: "redis",
+			NewCacheRepo: func(t *testing.T) provider.CacheRepository {
+				return newRedisCacheRepository(t, "localhost:6379")
+			},
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.Name, func(t *testing.T) {
+			cacheRepo := testCase.NewCacheRepo(t)
+			t.Setenv("FLAGGER_CACHE_TTL", "1m")
+
+			repo := newInvalidationRepository(t, cacheRepo)
+			server := provider.NewFlagProviderServer(repo, cacheRepo, nil, nil, &logger.StubLogger{})
+
+			getAllFlags := func() map[string]*providerpb.GetFlagResponse {
+				t.Helper()
+
+				response, err := server.GetAllFlags(authorisedContext(t), &providerpb.GetAllFlagsRequest{
+					Environment: "production",
+				})
+				if err != nil {
+					t.Fatalf("could not get all flags: %v", err)
+				}
+
+				return response.GetFlags()
+			}
+
+			expectQueries := func(expected int64) {
+				t.Helper()
+
+				if queries := repo.queries.Load(); queries != expected {
+					t.Fatalf("expected %d queries but got %d", expected, queries)
+				}
+			}
+
+			getAllFlags()
+
+			// The names of the flags are cached along with their rule sets.
+			if flags := getAllFlags(); len(flags) != 2 || !flags["kill-switch"].GetCached() {
+				t.Fatalf("expected all the flags to be served from the cache but got %v", flags)
+			}
+
+			expectQueries(1)
+
+			// A change to a flag in the environment only refetches the flag.
+			toggleFlag(repo, "kill-switch", false)
+
+			err := cacheRepo.Invalidate(context.Background(), invalidationProjectKey, &changefeed.Change{
+				EnvironmentName: "production",
+				FlagName:        "kill-switch",
+			})
+			if err != nil {
+				t.Fatalf("could not invalidate the flag: %v", err)
+			}
+
+			if flags := getAllFlags(); flags["kill-switch"].GetStatus() || !flags["new-checkout"].GetCached() {
+				t.Fatalf("expected only the changed flag to be refetched but got %v", flags)
+			}
+
+			expectQueries(2)
+
+			// A new flag is added to the names of the flags of all the
+			// environments.
+			details := newFlagDetails("dark-mode", true)
+			details.Key = invalidationProjectKey
+			repo.flagDetails = append(repo.flagDetails, details)
+
+			err = cacheRepo.Invalidate(context.Background(), invalidationProjectKey, &changefeed.Change{
+				FlagName: "dark-mode",
+			})
+			if err != nil {
+				t.Fatalf("could not invalidate the flag: %v", err)
+			}
+
+			if flags := getAllFlags(); len(flags) != 3 {
+				t.Fatalf("expected the new flag to be served but got %v", flags)
+			}
+
+			expectQueries(3)
+		})
+	}
+}
+
+func TestFlagProviderServer_GetFlag_CoalescesConcurrentMisses(t *testing.T) {
+	cacheRepo := newRedisCacheRepository(t, "localhost:6379")
+	t.Setenv("FLAGGER_CACHE_TTL", "1m")
+
+	repo := newInvalidationRepository(t, cacheRepo)
+	repo.gate = make(chan struct{})
+
+	server := provider.NewFlagProviderServer(repo, cacheRepo, nil, nil, &logger.StubLogger{})
+
+	const concurrentRequests = 20
+
+	var wg sync.WaitGroup
+
+	responses := make(chan *providerpb.GetFlagResponse, concurrentRequests)
+
+	for range concurrentRequests {
+		wg.Go(func() {
+			response, err := server.GetFlag(authorisedContext(t), &providerpb.GetFlagRequest{
+				Environment: "production",
+				FlagName:    "kill-switch",
+			})
+			if err != nil {
+				t.Errorf("could not get flag: %v", err)
+				return
+			}
+
+			responses <- response
+		})
+	}
+
+	// The requests are given time to miss the cache before the query that
+	// they all wait for is answered.
+	time.Sleep(100 * time.Millisecond)
+	close(repo.gate)
+
+	wg.Wait()
+	close(responses)
+
+	for response := range responses {
+		if !response.GetStatus() {
+			t.Errorf("unexpected response %v", response)
+		}
+	}
+
+	if queries := repo.queries.Load(); queries != 1 {
+		t.Fatalf("expected the concurrent misses to be served by 1 query but got %d", queries)
+	}
+}
+
+func TestFlagProviderServer_GetFlag_ServesStaleWhileDatabaseIsUnavailable(t *testing.T) {
+	cacheRepo := newRedisCacheRepository(t, "localhost:6379")
+	t.Setenv("FLAGGER_CACHE_TTL", "1s")
+
+	repo := newInvalidationRepository(t, cacheRepo)
+	server := provider.NewFlagProviderServer(repo, cacheRepo, nil, nil, &logger.StubLogger{})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+
+	server.StartStaleRefreshing(ctx, 1)
+
+	getFlag(t, server, "production", "kill-switch")
+
+	repo.isUnavailable.Store(true)
+
+	// The cached rule set expires while the database is unavailable.
+	time.Sleep(1200 * time.Millisecond)
+
+	response := getFlag(t, server, "production", "kill-switch")
+	if response.GetReason() != providerpb.Reason_REASON_STALE || !response.GetStatus() {
+		t.Fatalf("expected the last known good value to be served stale but got %v", response)
+	}
+
+	_, err := server.GetFlag(authorisedContext(t), &providerpb.GetFlagRequest{
+		Environment: "production",
+		FlagName:    "never-evaluated",
+	})
+	if err == nil {
+		t.Fatal("expected a flag without a last known good value to fail")
+	}
+
+	// The flag is refreshed in the background once the database recovers.
+	toggleFlag(repo, "kill-switch", false)
+	repo.isUnavailable.Store(false)
+
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		response = getFlag(t, server, "production", "kill-switch")
+		if response.GetReason() != providerpb.Reason_REASON_STALE {
+			break
+		}
+
+		if time.Now().After(deadline) {
+			t.Fatal("expected the stale flag to be refreshed after the database recovered")
+		}
+
+		time.Sleep(50 * time.Millisecond)
+	}
+
+	if response.GetStatus() {
+		t.Fatalf("expected the refreshed flag to be disabled but got %v", response)
+	}
+}
+
+func TestFlagProviderServer_GetFlag_RefreshesStaleFlagsUntilCancelled(t *testing.T) {
+	cacheRepo := newRedisCacheRepository(t, "localhost:6379")
+	t.Setenv("FLAGGER_CACHE_TTL", "1s")
+
+	repo := newInvalidationRepository(t, cacheRepo)
+	server := provider.NewFlagProviderServer(repo, cacheRepo, nil, nil, &logger.StubLogger{})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+
+	server.StartStaleRefreshing(ctx, 2)
+
+	getFlag(t, server, "production", "kill-switch")
+	getFlag(t, server, "production", "new-checkout")
+
+	repo.isUnavailable.Store(true)
+
+	// The cached rule sets expire while the database is unavailable.
+	time.Sleep(1200 * time.Millisecond)
+
+	getFlag(t, server, "production", "kill-switch")
+	getFlag(t, server, "production", "new-checkout")
+	getFlag(t, server, "production", "kill-switch")
+
+	queries := repo.queries.Load()
+
+	// Each flag is refreshed once after the shortest interval, however many
+	// times it was served stale.
+	time.Sleep(time.Second)
+
+	if refreshes := repo.queries.Load() - queries; refreshes != 2 {
+		t.Fatalf("expected each stale flag to be refreshed once but got %d refreshes", refreshes)
+	}
+
+	cancel()
+
+	queries = repo.queries.Load()
+
+	time.Sleep(1500 * time.Millisecond)
+
+	if refreshes := repo.queries.Load() - queries; refreshes != 0 {
+		t.Fatalf("expected the refreshes to stop once cancelled but got %d refreshes", refreshes)
+	}
+}
@@ -0,0 +1,235 @@
+package provider
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// staleRefreshMinInterval and staleRefreshMaxInterval bound the interval at
+// which the flags that are served stale are refetched until the database
+// recovers. The interval doubles after every failed refetch.
+const (
+	staleRefreshMinInterval = 500 * time.Millisecond
+	staleRefreshMaxInterval = 30 * time.Second
+)
+
+// staleRefreshQueueSize is the number of refreshes of stale flags that can
+// wait for a worker. Refreshes are dropped while the queue is full.
+const staleRefreshQueueSize = 1024
+
+// staleRefreshKey identifies a flag that is being refreshed.
+type staleRefreshKey struct {
+	projectKey      string
+	environmentName string
+	flagName        string
+}
+
+// staleRefresh is a refresh of a flag that was served stale.
+type staleRefresh struct {
+	key staleRefreshKey
+	// interval is the duration after which the flag is refetched.
+	interval time.Duration
+}
+
+// staleRefresher holds the state of the refreshing of the stale flags.
+type staleRefresher struct {
+	isStarted atomic.Bool
+	requests  chan staleRefresh
+	// pending are the keys of the flags whose refreshes are scheduled.
+	pending sync.Map
+}
+
+// getStaleFlagRuleSet gets the last known good rule set of the flag when it
+// could not be fetched from the database.
+func (s *FlagProviderServer) getStaleFlagRuleSet(
+	ctx context.Context,
+	cacheParams *cacheParameters,
+) (*FlagRuleSet, error) {
+	ruleSets, err := s.getStaleFlagRuleSets(
+		ctx,
+		cacheParams.ProjectKey,
+		cacheParams.EnvironmentName,
+		[]string{cacheParams.FlagName},
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	ruleSet, ok := ruleSets[cacheParams.FlagName]
+	if !ok {
+		return nil, ErrFlagNotFound
+	}
+
+	return ruleSet, nil
+}
+
+// getStaleFlagRuleSets gets the last known good rule sets of the flags when
+// they could not be fetched from the database. The rule sets are cached as
+// stale, so that the database is not queried for every request while it
+// fails, and are refreshed in the background once it recovers. The rule sets
+// of all the flags of the project cannot be served stale as their names are
+// only known to the database. Fails with ErrFetchFlagDetails unless the last
+// known good rule sets of all the flags are known.
+func (s *FlagProviderServer) getStaleFlagRuleSets(
+	ctx context.Context,
+	projectKey string,
+	environmentName string,
+	flagNames []string,
+) (map[string]*FlagRuleSet, error) {
+	if len(flagNames) == 0 {
+		return nil, ErrFetchFlagDetails
+	}
+
+	allCacheParams := make([]cacheParameters, 0, len(flagNames))
+	for _, flagName := range flagNames {
+		allCacheParams = append(allCacheParams, cacheParameters{
+			ProjectKey:      projectKey,
+			EnvironmentName: environmentName,
+			FlagName:        flagName,
+		})
+	}
+
+	lastKnownGoodRuleSets, err := s.providerCacheRepo.GetLastKnownGoodFlagRuleSets(ctx, allCacheParams)
+	if err != nil {
+		s.logger.Error("could not get last known good flag rule sets: %v", err)
+		return nil, ErrFetchFlagDetails
+	}
+
+	ruleSets := make(map[string]*FlagRuleSet, len(flagNames))
+
+	for i, ruleSet := range lastKnownGoodRuleSets {
+		if ruleSet == nil {
+			s.logger.Error("last known good rule set of flag %q is not known", flagNames[i])
+			return nil, ErrFetchFlagDetails
+		}
+
+		ruleSet.Stale = true
+
+		if !ruleSet.NotFound || ruleSet.Renamed != nil {
+			ruleSets[flagNames[i]] = ruleSet
+		}
+	}
+
+	s.logger.Warn(
+		"serving last known good rule sets of %d flags of project %q in environment %q",
+		len(flagNames),
+		projectKey,
+		environmentName,
+	)
+
+	cacheErr := s.providerCacheRepo.CacheFlagRuleSets(ctx, allCacheParams, lastKnownGoodRuleSets)
+	if cacheErr != nil {
+		s.logger.Warn("could not cache stale flag rule sets: %v. ignoring error", cacheErr)
+	}
+
+	s.refreshStale(projectKey, environmentName, flagNames)
+
+	return ruleSets, nil
+}
+
+// StartStaleRefreshing starts refetching the rule sets of the flags that are
+// served stale in the background with the number of workers and returns
+// immediately. The flags are refetched until the database recovers or the
+// context is cancelled. Flags are not refetched unless this was started, in
+// which case their stale rule sets are served until they expire.
+func (s *FlagProviderServer) StartStaleRefreshing(ctx context.Context, workers int) {
+	if !s.refresher.isStarted.CompareAndSwap(false, true) {
+		return
+	}
+
+	for range max(workers, 1) {
+		go s.serveStaleRefreshes(ctx)
+	}
+}
+
+// refreshStale schedules the refetch of the rule sets of the flags in the
+// background, which replaces their stale rule sets in the cache. Only one
+// refresh of a flag is scheduled at a time.
+func (s *FlagProviderServer) refreshStale(
+	projectKey string,
+	environmentName string,
+	flagNames []string,
+) {
+	if !s.refresher.isStarted.Load() {
+		return
+	}
+
+	for _, flagName := range flagNames {
+		request := staleRefresh{
+			key:      staleRefreshKey{projectKey: projectKey, environmentName: environmentName, flagName: flagName},
+			interval: staleRefreshMinInterval,
+		}
+
+		if _, isRefreshing := s.refresher.pending.LoadOrStore(request.key, struct{}{}); isRefreshing {
+			continue
+		}
+
+		s.scheduleStaleRefresh(request)
+	}
+}
+
+// scheduleStaleRefresh queues the refresh once its interval has passed. The
+// refresh is dropped if the queue is full, after which the flag is scheduled
+// again the next time that it is served stale.
+func (s *FlagProviderServer) scheduleStaleRefresh(request staleRefresh) {
+	time.AfterFunc(request.interval, func() {
+		select {
+		case s.refresher.requests <- request:
+		default:
+			s.refresher.pending.Delete(request.key)
+			s.logger.Warn(
+				"stale refresh queue is full. dropping refresh of flag %q of project %q in environment %q",
+				request.key.flagName,
+				request.key.projectKey,
+				request.key.environmentName,
+			)
+		}
+	})
+}
+
+// serveStaleRefreshes serves the queued refreshes until the context is
+// cancelled.
+func (s *FlagProviderServer) serveStaleRefreshes(ctx context.Context) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case request := <-s.refresher.requests:
+			s.refreshStaleFlag(ctx, request)
+		}
+	}
+}
+
+// refreshStaleFlag refetches the rule set of the flag. The refresh is
+// scheduled again with a doubled interval if the database is still failing.
+func (s *FlagProviderServer) refreshStaleFlag(ctx context.Context, request staleRefresh) {
+	fetchCtx, cancel := context.WithTimeout(ctx, fetchTimeout)
+	_, err := s.queryFlagRuleSets(
+		fetchCtx,
+		request.key.projectKey,
+		request.key.environmentName,
+		[]string{request.key.flagName},
+	)
+
+	cancel()
+
+	if err != nil && ctx.Err() == nil {
+		request.interval = min(2*request.interval, staleRefreshMaxInterval)
+		s.scheduleStaleRefresh(request)
+
+		return
+	}
+
+	s.refresher.pending.Delete(request.key)
+
+	if err == nil {
+		s.logger.Info(
+			"refreshed stale rule set of flag %q of project %q in environment %q",
+			request.key.flagName,
+			request.key.projectKey,
+			request.key.environmentName,
+		)
+	}
+}
@@ -0,0 +1,229 @@
+package provider
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/waduhek/flagger/internal/changefeed"
+)
+
+// usageRecordInterval is the shortest interval at which the usage of an
+// environment is recorded by a replica.
+const usageRecordInterval = 1 * time.Minute
+
+// warmQueueSize is the number of warm requests that can wait for a worker.
+// Requests are dropped while the queue is full.
+const warmQueueSize = 1024
+
+// CacheWarmingOptions bound the warming of the cache.
+type CacheWarmingOptions struct {
+	// Workers is the number of warm requests that are served at the same time.
+	Workers int
+	// RecentWindow is how recently an environment must have been used for
+	// its flags to be warmed at startup and for new flags to be warmed in it.
+	RecentWindow time.Duration
+	// MaxEnvironments is the largest number of the most recently used
+	// environments that are warmed at startup and that new flags are warmed
+	// in.
+	MaxEnvironments int
+}
+
+// warmRequest is a request to warm the rule sets of the flags of a project
+// that are affected by a change.
+type warmRequest struct {
+	projectKey string
+	change     changefeed.Change
+}
+
+// cacheWarmer holds the state of the warming of the cache.
+type cacheWarmer struct {
+	isStarted atomic.Bool
+	options   CacheWarmingOptions
+	requests  chan warmRequest
+	// lastRecorded is the last time that the usage of each environment was
+	// recorded by this replica. The entries are pruned once they are older
+	// than usageRecordInterval.
+	lastRecorded sync.Map
+	// lastPruned is the Unix time in nanoseconds at which lastRecorded was
+	// last pruned.
+	lastPruned atomic.Int64
+}
+
+// StartCacheWarming starts warming the cache in the background with the
+// options and returns immediately. The flags of the recently used
+// environments are warmed first, after which the flags of new environments
+// and new flags are warmed as they are created.
+func (s *FlagProviderServer) StartCacheWarming(ctx context.Context, options CacheWarmingOptions) {
+	if !s.warmer.isStarted.CompareAndSwap(false, true) {
+		return
+	}
+
+	s.warmer.options = options
+
+	for range max(options.Workers, 1) {
+		go s.serveWarmRequests(ctx)
+	}
+
+	go s.warmRecentlyUsed(ctx)
+}
+
+// Warm queues a request to warm the rule sets of the flags of the project that
+// are affected by the change. The request is dropped if the cache is not being
+// warmed or if the queue is full.
+func (s *FlagProviderServer) Warm(projectKey string, change *changefeed.Change) {
+	if !s.warmer.isStarted.Load() {
+		return
+	}
+
+	select {
+	case s.warmer.requests <- warmRequest{projectKey: projectKey, change: *change}:
+	default:
+		s.logger.Warn("warm queue is full. dropping warm request of project %q", projectKey)
+	}
+}
+
+// warmRecentlyUsed queues requests to warm all the flags of the most recently
+// used environments.
+func (s *FlagProviderServer) warmRecentlyUsed(ctx context.Context) {
+	usages, err := s.getRecentlyUsed(ctx)
+	if err != nil {
+		s.logger.Warn("could not get the recently used environments to warm: %v", err)
+		return
+	}
+
+	s.logger.Info("warming the cache with the flags of %d environments", len(usages))
+
+	for _, usage := range usages {
+		select {
+		case <-ctx.Done():
+			return
+		case s.warmer.requests <- warmRequest{
+			projectKey: usage.ProjectKey,
+			change:     changefeed.Change{EnvironmentName: usage.EnvironmentName},
+		}:
+		}
+	}
+}
+
+// serveWarmRequests serves the queued warm requests until the context is
+// cancelled.
+func (s *FlagProviderServer) serveWarmRequests(ctx context.Context) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case request := <-s.warmer.requests:
+			s.warm(ctx, &request)
+		}
+	}
+}
+
+// warm fetches the rule sets of the flags affected by the change, which caches
+// them. A new flag is warmed in the recently used environments of its project.
+func (s *FlagProviderServer) warm(ctx context.Context, request *warmRequest) {
+	var flagNames []string
+	if request.change.FlagName != "" {
+		flagNames = []string{request.change.FlagName}
+	}
+
+	environmentNames := []string{request.change.EnvironmentName}
+
+	if request.change.EnvironmentName == "" {
+		usages, err := s.getRecentlyUsed(ctx)
+		if err != nil {
+			s.logger.Warn("could not get the recently used environments to warm: %v", err)
+			return
+		}
+
+		environmentNames = environmentNames[:0]
+
+		for _, usage := range usages {
+			if usage.ProjectKey == request.projectKey {
+				environmentNames = append(environmentNames, usage.EnvironmentName)
+			}
+		}
+	}
+
+	for _, environmentName := range environmentNames {
+		_, err := s.fetchFlagRuleSets(ctx, request.projectKey, environmentName, flagNames)
+		if err != nil {
+			s.logger.Warn(
+				"could not warm flags of project %q in environment %q: %v",
+				request.projectKey,
+				environmentName,
+				err,
+			)
+		}
+	}
+}
+
+// getRecentlyUsed gets the most recently used environments within the recent
+// window.
+func (s *FlagProviderServer) getRecentlyUsed(ctx context.Context) ([]EnvironmentUsage, error) {
+	if s.usageRepo == nil || s.warmer.options.MaxEnvironments <= 0 {
+		return nil, nil
+	}
+
+	return s.usageRepo.GetRecentlyUsed(
+		ctx,
+		time.Now().Add(-s.warmer.options.RecentWindow),
+		s.warmer.options.MaxEnvironments,
+	)
+}
+
+// recordUsage records that the flags of the project were evaluated in the
+// environment in the background. The usage of an environment is recorded at
+// most once per interval by a replica. The usage must only be recorded once the
+// flags of the environment were found, so that unknown environments aren't
+// recorded.
+func (s *FlagProviderServer) recordUsage(projectKey string, environmentName string) {
+	if s.usageRepo == nil {
+		return
+	}
+
+	usage := &EnvironmentUsage{
+		ProjectKey:      projectKey,
+		EnvironmentName: environmentName,
+		UsedAt:          time.Now(),
+	}
+
+	usageKey := [2]string{projectKey, environmentName}
+	if lastRecorded, ok := s.warmer.lastRecorded.Load(usageKey); ok {
+		if recordedAt, _ := lastRecorded.(time.Time); usage.UsedAt.Sub(recordedAt) < usageRecordInterval {
+			return
+		}
+	}
+
+	s.warmer.lastRecorded.Store(usageKey, usage.UsedAt)
+	s.warmer.pruneLastRecorded(usage.UsedAt)
+
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), fetchTimeout)
+		defer cancel()
+
+		if err := s.usageRepo.RecordUsage(ctx, usage); err != nil {
+			s.logger.Warn("could not record usage of environment %q: %v", environmentName, err)
+		}
+	}()
+}
+
+// pruneLastRecorded removes the environments whose usage was recorded more
+// than usageRecordInterval ago, as their usage is recorded again anyway. The
+// environments are pruned at most once per interval.
+func (w *cacheWarmer) pruneLastRecorded(now time.Time) {
+	lastPruned := w.lastPruned.Load()
+	if now.UnixNano()-lastPruned < int64(usageRecordInterval) ||
+		!w.lastPruned.CompareAndSwap(lastPruned, now.UnixNano()) {
+		return
+	}
+
+	w.lastRecorded.Range(func(usageKey, lastRecorded any) bool {
+		if recordedAt, _ := lastRecorded.(time.Time); now.Sub(recordedAt) >= usageRecordInterval {
+			w.lastRecorded.Delete(usageKey)
+		}
+
+		return true
+	})
+}
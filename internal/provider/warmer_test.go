@@ -0,0 +1,312 @@
+package provider_test
+
+import (
+	"context"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+
+	"github.com/waduhek/flagger/proto/providerpb"
+
+	"github.com/waduhek/flagger/internal/changefeed"
+	"github.com/waduhek/flagger/internal/environment"
+	"github.com/waduhek/flagger/internal/logger"
+	"github.com/waduhek/flagger/internal/provider"
+)
+
+// newWarmingTest creates a provider server with an in-memory cache that warms
+// the cache with the flags of the environments used within the last hour. The
+// project has a "dark-mode" flag in addition to the flags of the invalidation
+// tests.
+func newWarmingTest(
+	t *testing.T,
+	usages ...provider.EnvironmentUsage,
+) (*provider.FlagProviderServer, *fakeProviderRepository, *provider.MemoryUsageRepository) {
+	t.Helper()
+
+	t.Setenv("FLAGGER_CACHE_TTL", "1m")
+
+	cacheRepo := provider.NewMemoryCacheRepository(100)
+	repo := newInvalidationRepository(t, cacheRepo)
+
+	for _, environmentName := range []string{"production", "staging"} {
+		details := newFlagDetails("dark-mode", true)
+		details.Key = invalidationProjectKey
+		details.Environment.Name = environmentName
+
+		repo.flagDetails = append(repo.flagDetails, details)
+	}
+
+	usageRepo := provider.NewMemoryUsageRepository()
+	for i := range usages {
+		if err := usageRepo.RecordUsage(context.Background(), &usages[i]); err != nil {
+			t.Fatalf("could not record usage: %v", err)
+		}
+	}
+
+	server := provider.NewFlagProviderServer(repo, cacheRepo, nil, usageRepo, &logger.StubLogger{})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+
+	server.StartCacheWarming(ctx, provider.CacheWarmingOptions{
+		Workers:         2,
+		RecentWindow:    time.Hour,
+		MaxEnvironments: 10,
+	})
+
+	return server, repo, usageRepo
+}
+
+// waitForQueries waits until the repository has received the number of
+// queries and the fetched rule sets have been cached.
+func waitForQueries(t *testing.T, repo *fakeProviderRepository, queries int64) {
+	t.Helper()
+
+	deadline := time.Now().Add(5 * time.Second)
+	for repo.queries.Load() < queries {
+		if time.Now().After(deadline) {
+			t.Fatalf("expected %d queries but got %d", queries, repo.queries.Load())
+		}
+
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	time.Sleep(50 * time.Millisecond)
+}
+
+func TestCacheWarming_WarmsRecentlyUsedEnvironmentsAtStartup(t *testing.T) {
+	server, repo, _ := newWarmingTest(t,
+		provider.EnvironmentUsage{
+			ProjectKey:      invalidationProjectKey,
+			EnvironmentName: "production",
+			UsedAt:          time.Now(),
+		},
+		provider.EnvironmentUsage{
+			ProjectKey:      invalidationProjectKey,
+			EnvironmentName: "staging",
+			UsedAt:          time.Now().Add(-2 * time.Hour),
+		},
+	)
+
+	waitForQueries(t, repo, 1)
+
+	if response := getFlag(t, server, "production", "kill-switch"); !response.GetCached() {
+		t.Fatal("expected the flags of the recently used environment to be warmed")
+	}
+
+	if response := getFlag(t, server, "staging", "kill-switch"); response.GetCached() {
+		t.Fatal("expected the flags of an environment used outside the window not to be warmed")
+	}
+}
+
+func TestCacheWarming_WarmsNewFlagsInRecentlyUsedEnvironments(t *testing.T) {
+	server, repo, usageRepo := newWarmingTest(t)
+
+	// Evaluating a flag records the usage of the environment in the
+	// background.
+	getFlag(t, server, "staging", "kill-switch")
+
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		usages, err := usageRepo.GetRecentlyUsed(context.Background(), time.Now().Add(-time.Hour), 10)
+		if err != nil {
+			t.Fatalf("could not get the recently used environments: %v", err)
+		}
+
+		if len(usages) == 1 && usages[0].EnvironmentName == "staging" {
+			break
+		}
+
+		if time.Now().After(deadline) {
+			t.Fatalf("expected the usage of staging to be recorded but got %v", usages)
+		}
+
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	server.Warm(invalidationProjectKey, &changefeed.Change{FlagName: "dark-mode"})
+
+	waitForQueries(t, repo, 2)
+
+	if response := getFlag(t, server, "staging", "dark-mode"); !response.GetCached() {
+		t.Fatal("expected the new flag to be warmed in the recently used environment")
+	}
+
+	if response := getFlag(t, server, "production", "dark-mode"); response.GetCached() {
+		t.Fatal("expected the new flag not to be warmed in an environment that was not used")
+	}
+}
+
+func TestCacheWarming_DoesNotRecordUsageOfUnknownEnvironments(t *testing.T) {
+	server, _, usageRepo := newWarmingTest(t)
+
+	response, err := server.GetFlag(authorisedContext(t), &providerpb.GetFlagRequest{
+		Environment: "unknown",
+		FlagName:    "kill-switch",
+	})
+	if err != nil {
+		t.Fatalf("could not get flag: %v", err)
+	}
+
+	if response.GetErrorCode() != providerpb.ErrorCode_ERROR_CODE_FLAG_NOT_FOUND {
+		t.Fatalf("expected the flag not to be found but got %v", response)
+	}
+
+	_, err = server.GetAllFlags(authorisedContext(t), &providerpb.GetAllFlagsRequest{Environment: "unknown"})
+	if err != nil {
+		t.Fatalf("could not get all flags: %v", err)
+	}
+
+	// The usage is recorded in the background, so the usage of a known
+	// environment is waited for to tell that the unknown one wasn't recorded.
+	getFlag(t, server, "production", "kill-switch")
+
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		usages, err := usageRepo.GetRecentlyUsed(context.Background(), time.Now().Add(-time.Hour), 10)
+		if err != nil {
+			t.Fatalf("could not get the recently used environments: %v", err)
+		}
+
+		if len(usages) > 0 {
+			if len(usages) != 1 || usages[0].EnvironmentName != "production" {
+				t.Fatalf("expected only the usage of production to be recorded but got %v", usages)
+			}
+
+			break
+		}
+
+		if time.Now().After(deadline) {
+			t.Fatal("expected the usage of production to be recorded")
+		}
+
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func TestUsageRepository_GetLastUsed(t *testing.T) {
+	// maxTrackedUsages is the number of the most recently used environments
+	// that are kept.
+	const maxTrackedUsages = 10000
+
+	rdb := redis.NewClient(&redis.Options{Addr: "localhost:6379", MaxRetries: -1})
+	t.Cleanup(func() {
+		// The recently used environments of the test must not be warmed by
+		// the other tests.
+		rdb.Del(context.Background(), "environment-usage")
+		rdb.Close()
+	})
+
+	memoryUsageRepo := provider.NewMemoryUsageRepository()
+
+	// The environments are recorded as used now until more environments are
+	// used than the recently used environments that are kept. The sorted set
+	// of Redis is filled directly, which is much faster than recording each
+	// usage.
+	testCases := map[string]struct {
+		usageRepo provider.UsageRepository
+		fill      func(ctx context.Context) error
+	}{
+		"memory": {
+			usageRepo: memoryUsageRepo,
+			fill: func(ctx context.Context) error {
+				for i := range maxTrackedUsages {
+					err := memoryUsageRepo.RecordUsage(ctx, &provider.EnvironmentUsage{
+						ProjectKey:      "usage-test-project",
+						EnvironmentName: strconv.Itoa(i),
+						UsedAt:          time.Now(),
+					})
+					if err != nil {
+						return err
+					}
+				}
+
+				return nil
+			},
+		},
+		"redis": {
+			usageRepo: provider.NewRedisUsageRepository(rdb),
+			fill: func(ctx context.Context) error {
+				members := make([]redis.Z, 0, maxTrackedUsages)
+				for i := range maxTrackedUsages {
+					members = append(members, redis.Z{
+						Score:  float64(time.Now().UnixMilli()),
+						Member: `["usage-test-project","` + strconv.Itoa(i) + `"]`,
+					})
+				}
+
+				return rdb.ZAdd(ctx, "environment-usage", members...).Err()
+			},
+		},
+	}
+
+	for name, testCase := range testCases {
+		usageRepo := testCase.usageRepo
+
+		t.Run(name, func(t *testing.T) {
+			ctx := context.Background()
+			usedAt := time.UnixMilli(time.Now().UnixMilli())
+
+			err := usageRepo.RecordUsage(ctx, &provider.EnvironmentUsage{
+				ProjectKey:      invalidationProjectKey,
+				EnvironmentName: "production",
+				UsedAt:          usedAt,
+			})
+			if err != nil {
+				t.Fatalf("could not record usage: %v", err)
+			}
+
+			lastUsedAt, isUsed, err := usageRepo.GetLastUsed(ctx, invalidationProjectKey, "production")
+			if err != nil || !isUsed || !lastUsedAt.Equal(usedAt) {
+				t.Fatalf("expected the environment to be last used at %v but got %v, %v, %v", usedAt, lastUsedAt, isUsed, err)
+			}
+
+			_, isUsed, err = usageRepo.GetLastUsed(ctx, invalidationProjectKey, "never-used")
+			if err != nil || isUsed {
+				t.Fatalf("expected an unused environment not to be found but got %v, %v", isUsed, err)
+			}
+
+			err = usageRepo.RecordUsage(ctx, &provider.EnvironmentUsage{
+				ProjectKey:      invalidationProjectKey,
+				EnvironmentName: "staging",
+				UsedAt:          time.Now().Add(-environment.RecentEvaluationWindow),
+			})
+			if err != nil {
+				t.Fatalf("could not record usage: %v", err)
+			}
+
+			_, isUsed, err = usageRepo.GetLastUsed(ctx, invalidationProjectKey, "staging")
+			if err != nil || isUsed {
+				t.Fatalf("expected an environment used before the window not to be found but got %v, %v", isUsed, err)
+			}
+
+			// More environments are used than the recently used environments
+			// that are kept, which doesn't drop the last usage of production.
+			if err = testCase.fill(ctx); err != nil {
+				t.Fatalf("could not record usages: %v", err)
+			}
+
+			err = usageRepo.RecordUsage(ctx, &provider.EnvironmentUsage{
+				ProjectKey:      invalidationProjectKey,
+				EnvironmentName: "development",
+				UsedAt:          time.Now(),
+			})
+			if err != nil {
+				t.Fatalf("could not record usage: %v", err)
+			}
+
+			recentlyUsed, err := usageRepo.GetRecentlyUsed(ctx, usedAt.Add(-time.Hour), 2*maxTrackedUsages)
+			if err != nil || len(recentlyUsed) != maxTrackedUsages {
+				t.Fatalf("expected %d recently used environments but got %d, %v", maxTrackedUsages, len(recentlyUsed), err)
+			}
+
+			lastUsedAt, isUsed, err = usageRepo.GetLastUsed(ctx, invalidationProjectKey, "production")
+			if err != nil || !isUsed || !lastUsedAt.Equal(usedAt) {
+				t.Fatalf("expected production to be last used at %v but got %v, %v, %v", usedAt, lastUsedAt, isUsed, err)
+			}
+		})
+	}
+}
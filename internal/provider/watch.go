@@ -0,0 +1,232 @@
+package provider
+
+import (
+	"context"
+	"slices"
+	"time"
+
+	"github.com/waduhek/flagger/proto/providerpb"
+
+	"github.com/waduhek/flagger/internal/project"
+	"github.com/waduhek/flagger/internal/targeting"
+)
+
+// watchPollInterval is the interval at which the feed of changes is read for
+// the watched flags.
+const watchPollInterval = 1 * time.Second
+
+// watchHeartbeatInterval is the interval at which heartbeats are sent on the
+// stream of watched flags.
+const watchHeartbeatInterval = 15 * time.Second
+
+// flagWatch is a stream of the flags of a project in an environment that are
+// evaluated for an evaluation context.
+type flagWatch struct {
+	stream          providerpb.FlagProvider_WatchFlagsServer
+	projectKey      string
+	environmentName string
+	evalCtx         *targeting.Context
+	version         string
+	// isFound is whether flags of the watched environment have been found,
+	// after which the environment counts as being used.
+	isFound bool
+}
+
+func (s *FlagProviderServer) WatchFlags(
+	req *providerpb.WatchFlagsRequest,
+	stream providerpb.FlagProvider_WatchFlagsServer,
+) error {
+	ctx := stream.Context()
+
+	projectKey, ok := project.KeyFromContext(ctx)
+	if !ok {
+		s.logger.Error("could not find project key in request")
+		return project.ErrProjectKeyNotFound
+	}
+
+	watch := &flagWatch{
+		stream:          stream,
+		projectKey:      projectKey,
+		environmentName: req.GetEnvironment(),
+		evalCtx:         newEvaluationContext(req.GetContext()),
+		version:         req.GetVersion(),
+	}
+
+	// A reconnecting client only needs the changes after its version if they
+	// are still in the feed, otherwise it starts over with a snapshot.
+	isRetained, err := s.changeFeedRepo.IsRetained(ctx, projectKey, watch.version)
+	if err != nil {
+		s.logger.Error("could not check retention of version %q: %v", watch.version, err)
+		return ErrWatchChanges
+	}
+
+	if !isRetained {
+		latestVersion, latestErr := s.changeFeedRepo.LatestVersion(ctx, projectKey)
+		if latestErr != nil {
+			s.logger.Error("could not get latest version of changes: %v", latestErr)
+			return ErrWatchChanges
+		}
+
+		if err = s.sendSnapshot(ctx, watch, latestVersion); err != nil {
+			return err
+		}
+	}
+
+	pollTicker := time.NewTicker(watchPollInterval)
+	defer pollTicker.Stop()
+
+	heartbeatTicker := time.NewTicker(watchHeartbeatInterval)
+	defer heartbeatTicker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-heartbeatTicker.C:
+			// The environment is in use for as long as it is watched, which
+			// keeps it warm and stops it from being deleted without a
+			// confirmation.
+			if watch.isFound {
+				s.recordUsage(projectKey, watch.environmentName)
+			}
+
+			err = stream.Send(&providerpb.WatchFlagsResponse{
+				Type:    providerpb.WatchEventType_WATCH_EVENT_TYPE_HEARTBEAT,
+				Version: watch.version,
+			})
+			if err != nil {
+				s.logger.Error("could not send heartbeat: %v", err)
+				return err
+			}
+		case <-pollTicker.C:
+			if err = s.sendChanges(ctx, watch); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// sendChanges reads the changes after the version of the watch and sends the
+// status of the changed flags of the watched environment.
+func (s *FlagProviderServer) sendChanges(
+	ctx context.Context,
+	watch *flagWatch,
+) error {
+	changes, err := s.changeFeedRepo.ReadAfter(ctx, watch.projectKey, watch.version)
+	if err != nil {
+		s.logger.Error("could not read changes after version %q: %v", watch.version, err)
+		return ErrWatchChanges
+	}
+
+	if len(changes) == 0 {
+		return nil
+	}
+
+	latestVersion := changes[len(changes)-1].Version
+
+	changedFlagNames := []string{}
+
+	for _, change := range changes {
+		if change.EnvironmentName != "" &&
+			change.EnvironmentName != watch.environmentName {
+			continue
+		}
+
+		// A change to all the flags of the environment is sent as a snapshot.
+		if change.FlagName == "" {
+			return s.sendSnapshot(ctx, watch, latestVersion)
+		}
+
+		changedFlagNames = append(changedFlagNames, change.FlagName)
+	}
+
+	// None of the changes were to the watched environment, so only the version
+	// is moved ahead to be sent with the next heartbeat.
+	if len(changedFlagNames) == 0 {
+		watch.version = latestVersion
+		return nil
+	}
+
+	changedFlagNames = slices.Compact(slices.Sorted(slices.Values(changedFlagNames)))
+
+	ruleSets, err := s.fetchFlagRuleSets(
+		ctx,
+		watch.projectKey,
+		watch.environmentName,
+		changedFlagNames,
+	)
+	if err != nil {
+		return err
+	}
+
+	// A flag that is only found by its previous name was renamed, so it is
+	// removed under the name and sent with its current name by its own change.
+	removedFlagNames := []string{}
+	for _, flagName := range changedFlagNames {
+		if ruleSet, ok := ruleSets[flagName]; !ok || ruleSet.Renamed != nil {
+			delete(ruleSets, flagName)
+			removedFlagNames = append(removedFlagNames, flagName)
+		}
+	}
+
+	s.markFound(watch, ruleSets)
+
+	flags := s.newGetFlagsResponse(ruleSets, watch.evalCtx)
+
+	err = watch.stream.Send(&providerpb.WatchFlagsResponse{
+		Type:         providerpb.WatchEventType_WATCH_EVENT_TYPE_UPDATE,
+		Version:      latestVersion,
+		Flags:        flags.GetFlags(),
+		RemovedFlags: removedFlagNames,
+	})
+	if err != nil {
+		s.logger.Error("could not send flag update: %v", err)
+		return err
+	}
+
+	watch.version = latestVersion
+
+	return nil
+}
+
+// sendSnapshot sends the status of all the flags of the watched environment
+// as of the provided version.
+func (s *FlagProviderServer) sendSnapshot(
+	ctx context.Context,
+	watch *flagWatch,
+	version string,
+) error {
+	ruleSets, err := s.getAllFlagRuleSets(ctx, watch.projectKey, watch.environmentName)
+	if err != nil {
+		return err
+	}
+
+	s.markFound(watch, ruleSets)
+
+	flags := s.newGetFlagsResponse(ruleSets, watch.evalCtx)
+
+	err = watch.stream.Send(&providerpb.WatchFlagsResponse{
+		Type:    providerpb.WatchEventType_WATCH_EVENT_TYPE_SNAPSHOT,
+		Version: version,
+		Flags:   flags.GetFlags(),
+	})
+	if err != nil {
+		s.logger.Error("could not send flag snapshot: %v", err)
+		return err
+	}
+
+	watch.version = version
+
+	return nil
+}
+
+// markFound records the usage of the watched environment the first time that
+// its flags are found.
+func (s *FlagProviderServer) markFound(watch *flagWatch, ruleSets map[string]*FlagRuleSet) {
+	if watch.isFound || len(ruleSets) == 0 {
+		return
+	}
+
+	watch.isFound = true
+	s.recordUsage(watch.projectKey, watch.environmentName)
+}
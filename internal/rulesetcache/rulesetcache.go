@@ -0,0 +1,128 @@
+// Package rulesetcache defines the keys of the cached rule sets of the flags,
+// the invalidation of the cached rule sets when the flags change and the
+// warming of the cache with the rule sets of new flags.
+package rulesetcache
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/waduhek/flagger/internal/changefeed"
+)
+
+// Invalidator removes the cached rule sets of the flags that were changed so
+// that the next evaluation of the flags reads their current settings.
+type Invalidator interface {
+	// Invalidate removes the cached rule sets of the flags of the project that
+	// are affected by the change. A change without an environment name
+	// affects the flag in all the environments and a change without a flag
+	// name affects all the flags of the environment.
+	Invalidate(ctx context.Context, projectKey string, change *changefeed.Change) error
+
+	// Purge removes the cached rule sets of the flags of the project that are
+	// affected by the change along with their last known good rule sets, so
+	// that flags that were deleted are not served even while the database
+	// fails.
+	Purge(ctx context.Context, projectKey string, change *changefeed.Change) error
+}
+
+// Warmer preloads the rule sets of the flags into the cache ahead of their
+// evaluation, so that the first evaluations of new flags and environments are
+// not cache misses.
+type Warmer interface {
+	// Warm preloads the rule sets of the flags of the project that are
+	// affected by the change in the background. A change without an
+	// environment name affects the flag in all the environments and a change
+	// without a flag name affects all the flags of the environment. Warming is
+	// best effort and never blocks the caller.
+	Warm(projectKey string, change *changefeed.Change)
+}
+
+// Key generates the key that the rule set of the flag in the environment is
+// cached with. The project key and the environment name are the hash tag of
+// the key so that the rule sets of an environment are stored in the same slot
+// of a Redis Cluster and can be read together.
+func Key(projectKey string, environmentName string, flagName string) string {
+	return fmt.Sprintf("ruleset:{%v:%v}:%v", projectKey, environmentName, flagName)
+}
+
+// LastKnownGoodKey generates the key that the last known good rule set of the
+// flag in the environment is kept with. It shares the hash tag of the key of
+// the cached rule set, but is not matched by the patterns of the
+// invalidations, so that it outlives the cached rule set.
+func LastKnownGoodKey(projectKey string, environmentName string, flagName string) string {
+	return fmt.Sprintf("ruleset-lkg:{%v:%v}:%v", projectKey, environmentName, flagName)
+}
+
+// FlagNamesKey generates the key that the names of all the flags of the
+// environment are cached with. It shares the hash tag of the keys of the
+// cached rule sets of the environment.
+func FlagNamesKey(projectKey string, environmentName string) string {
+	return fmt.Sprintf("ruleset-names:{%v:%v}", projectKey, environmentName)
+}
+
+// AffectsFlagNames reports whether the change can add, remove or rename flags
+// of the environments, which is the case for all the changes except the
+// changes to a flag in a single environment.
+func AffectsFlagNames(change *changefeed.Change) bool {
+	return change.EnvironmentName == "" || change.FlagName == ""
+}
+
+// FlagNamesPattern generates a glob pattern that matches the keys of the
+// cached names of the flags of the environments affected by the change.
+func FlagNamesPattern(projectKey string, change *changefeed.Change) string {
+	environmentPattern := "*"
+	if change.EnvironmentName != "" {
+		environmentPattern = escapeGlob(change.EnvironmentName)
+	}
+
+	return FlagNamesKey(escapeGlob(projectKey), environmentPattern)
+}
+
+// Pattern generates a glob pattern that matches the keys of the cached rule
+// sets affected by the change.
+func Pattern(projectKey string, change *changefeed.Change) string {
+	return pattern(Key, projectKey, change)
+}
+
+// LastKnownGoodPattern generates a glob pattern that matches the keys of the
+// last known good rule sets affected by the change.
+func LastKnownGoodPattern(projectKey string, change *changefeed.Change) string {
+	return pattern(LastKnownGoodKey, projectKey, change)
+}
+
+// pattern generates a glob pattern that matches the keys generated by the key
+// function for the rule sets affected by the change.
+func pattern(
+	key func(projectKey string, environmentName string, flagName string) string,
+	projectKey string,
+	change *changefeed.Change,
+) string {
+	environmentPattern := "*"
+	if change.EnvironmentName != "" {
+		environmentPattern = escapeGlob(change.EnvironmentName)
+	}
+
+	flagPattern := "*"
+	if change.FlagName != "" {
+		flagPattern = escapeGlob(change.FlagName)
+	}
+
+	return key(escapeGlob(projectKey), environmentPattern, flagPattern)
+}
+
+// escapeGlob escapes the special characters of a glob pattern in the value.
+func escapeGlob(value string) string {
+	var builder strings.Builder
+
+	for _, char := range value {
+		if strings.ContainsRune(`*?[]\`, char) {
+			builder.WriteRune('\\')
+		}
+
+		builder.WriteRune(char)
+	}
+
+	return builder.String()
+}
@@ -0,0 +1,98 @@
+package rulesetcache_test
+
+import (
+	"testing"
+
+	"github.com/waduhek/flagger/internal/changefeed"
+	"github.com/waduhek/flagger/internal/rulesetcache"
+)
+
+func TestPattern(t *testing.T) {
+	testCases := []struct {
+		Name            string
+		ProjectKey      string
+		Change          *changefeed.Change
+		ExpectedPattern string
+	}{
+		{
+			Name:            "flag_in_environment",
+			ProjectKey:      "key",
+			Change:          &changefeed.Change{EnvironmentName: "production", FlagName: "flag"},
+			ExpectedPattern: "ruleset:{key:production}:flag",
+		},
+		{
+			Name:            "flag_in_all_environments",
+			ProjectKey:      "key",
+			Change:          &changefeed.Change{FlagName: "flag"},
+			ExpectedPattern: "ruleset:{key:*}:flag",
+		},
+		{
+			Name:            "all_flags_in_environment",
+			ProjectKey:      "key",
+			Change:          &changefeed.Change{EnvironmentName: "production"},
+			ExpectedPattern: "ruleset:{key:production}:*",
+		},
+		{
+			Name:            "special_characters",
+			ProjectKey:      "k*y",
+			Change:          &changefeed.Change{EnvironmentName: "prod?", FlagName: `[f]\`},
+			ExpectedPattern: `ruleset:{k\*y:prod\?}:\[f\]\\`,
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.Name, func(t *testing.T) {
+			pattern := rulesetcache.Pattern(testCase.ProjectKey, testCase.Change)
+			if pattern != testCase.ExpectedPattern {
+				t.Errorf("expected pattern %q but got %q", testCase.ExpectedPattern, pattern)
+			}
+		})
+	}
+}
+
+func TestLastKnownGoodPattern(t *testing.T) {
+	pattern := rulesetcache.LastKnownGoodPattern("k*y", &changefeed.Change{FlagName: "flag"})
+	if pattern != `ruleset-lkg:{k\*y:*}:flag` {
+		t.Errorf("unexpected pattern %q", pattern)
+	}
+}
+
+func TestFlagNamesPattern(t *testing.T) {
+	testCases := []struct {
+		Name             string
+		Change           *changefeed.Change
+		ExpectedPattern  string
+		ExpectedAffected bool
+	}{
+		{
+			Name:             "flag_in_environment",
+			Change:           &changefeed.Change{EnvironmentName: "production", FlagName: "flag"},
+			ExpectedPattern:  `ruleset-names:{k\*y:production}`,
+			ExpectedAffected: false,
+		},
+		{
+			Name:             "flag_in_all_environments",
+			Change:           &changefeed.Change{FlagName: "flag"},
+			ExpectedPattern:  `ruleset-names:{k\*y:*}`,
+			ExpectedAffected: true,
+		},
+		{
+			Name:             "all_flags_in_environment",
+			Change:           &changefeed.Change{EnvironmentName: "prod?"},
+			ExpectedPattern:  `ruleset-names:{k\*y:prod\?}`,
+			ExpectedAffected: true,
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.Name, func(t *testing.T) {
+			if pattern := rulesetcache.FlagNamesPattern("k*y", testCase.Change); pattern != testCase.ExpectedPattern {
+				t.Errorf("expected pattern %q but got %q", testCase.ExpectedPattern, pattern)
+			}
+
+			if isAffected := rulesetcache.AffectsFlagNames(testCase.Change); isAffected != testCase.ExpectedAffected {
+				t.Errorf("expected the change to affect the flag names to be %v", testCase.ExpectedAffected)
+			}
+		})
+	}
+}
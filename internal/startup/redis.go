@@ -2,7 +2,12 @@ package startup
 
 import (
 	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
 	"os"
+	"strings"
 	"time"
 
 	"github.com/redis/go-redis/v9"
@@ -10,22 +15,87 @@ import (
 	"github.com/waduhek/flagger/internal/logger"
 )
 
-func ConnectRedis(logger logger.Logger) (*redis.Client, error) {
-	connectionString := os.Getenv("FLAGGER_REDIS_URI")
+// Modes of deployment of Redis that can be connected to.
+const (
+	redisModeStandalone = "standalone"
+	redisModeSentinel   = "sentinel"
+	redisModeCluster    = "cluster"
+)
+
+// ErrUnknownRedisMode is returned when the configured mode of Redis is not one
+// of the supported modes.
+var ErrUnknownRedisMode = errors.New("unknown redis mode")
+
+// ErrRedisAddrsRequired is returned when the seed addresses of Sentinel or
+// Cluster are not configured.
+var ErrRedisAddrsRequired = errors.New("redis addresses are required for sentinel and cluster modes")
+
+// ErrRedisMasterNameRequired is returned when the name of the master monitored
+// by Sentinel is not configured.
+var ErrRedisMasterNameRequired = errors.New("redis master name is required for sentinel mode")
+
+// ErrInvalidRedisCA is returned when the CA certificates for verifying the TLS
+// certificates of Redis could not be parsed.
+var ErrInvalidRedisCA = errors.New("could not parse redis ca certificates")
+
+// ConnectRedis connects to the Redis deployment configured by the environment
+// and returns the client. A single node is connected to with the connection
+// string in FLAGGER_REDIS_URI unless FLAGGER_REDIS_MODE selects Sentinel or
+// Cluster, which are connected to with the seed addresses in
+// FLAGGER_REDIS_ADDRS.
+func ConnectRedis(logger logger.Logger) (redis.UniversalClient, error) {
+	mode := os.Getenv("FLAGGER_REDIS_MODE")
+
+	tlsConfig, tlsErr := getRedisTLSConfig()
+	if tlsErr != nil {
+		logger.Error("error while configuring redis tls: %v", tlsErr)
+		return nil, tlsErr
+	}
+
+	if mode == "" || mode == redisModeStandalone {
+		connectionString := os.Getenv("FLAGGER_REDIS_URI")
+
+		return connectRedisWithConnectionString(logger, connectionString, tlsConfig)
+	}
+
+	opts, optsErr := getRedisUniversalOptions(mode, tlsConfig)
+	if optsErr != nil {
+		logger.Error("error while configuring redis %v: %v", mode, optsErr)
+		return nil, optsErr
+	}
+
+	client := redis.NewUniversalClient(opts)
+
+	pingErr := pingRedis(client)
+	if pingErr != nil {
+		logger.Error("error while pinging redis: %v", pingErr)
+		return nil, pingErr
+	}
 
-	return connectRedisWithConnectionString(logger, connectionString)
+	return client, nil
 }
 
 func connectRedisWithConnectionString(
 	logger logger.Logger,
 	connString string,
-) (*redis.Client, error) {
+	tlsConfig *tls.Config,
+) (redis.UniversalClient, error) {
 	opt, parseErr := redis.ParseURL(connString)
 	if parseErr != nil {
 		logger.Error("error while parsing redis connection string: %v", parseErr)
 		return nil, parseErr
 	}
 
+	// The credentials and TLS in the connection string take precedence.
+	if opt.Username == "" && opt.Password == "" {
+		opt.Username = os.Getenv("FLAGGER_REDIS_USERNAME")
+		opt.Password = os.Getenv("FLAGGER_REDIS_PASSWORD")
+	}
+
+	if opt.TLSConfig == nil {
+		opt.TLSConfig = tlsConfig
+	}
+
 	client := redis.NewClient(opt)
 
 	pingErr := pingRedis(client)
@@ -37,7 +107,79 @@ func connectRedisWithConnectionString(
 	return client, nil
 }
 
-func pingRedis(client *redis.Client) error {
+// getRedisUniversalOptions gets the options of a client of Sentinel or Cluster
+// from the environment.
+func getRedisUniversalOptions(
+	mode string,
+	tlsConfig *tls.Config,
+) (*redis.UniversalOptions, error) {
+	addrs := []string{}
+	for addr := range strings.SplitSeq(os.Getenv("FLAGGER_REDIS_ADDRS"), ",") {
+		if addr = strings.TrimSpace(addr); addr != "" {
+			addrs = append(addrs, addr)
+		}
+	}
+
+	if len(addrs) == 0 {
+		return nil, ErrRedisAddrsRequired
+	}
+
+	opts := &redis.UniversalOptions{
+		Addrs:     addrs,
+		Username:  os.Getenv("FLAGGER_REDIS_USERNAME"),
+		Password:  os.Getenv("FLAGGER_REDIS_PASSWORD"),
+		TLSConfig: tlsConfig,
+	}
+
+	switch mode {
+	case redisModeSentinel:
+		opts.MasterName = os.Getenv("FLAGGER_REDIS_MASTER_NAME")
+		if opts.MasterName == "" {
+			return nil, ErrRedisMasterNameRequired
+		}
+
+		opts.SentinelUsername = os.Getenv("FLAGGER_REDIS_SENTINEL_USERNAME")
+		opts.SentinelPassword = os.Getenv("FLAGGER_REDIS_SENTINEL_PASSWORD")
+	case redisModeCluster:
+		opts.IsClusterMode = true
+	default:
+		return nil, fmt.Errorf("%w: %q", ErrUnknownRedisMode, mode)
+	}
+
+	return opts, nil
+}
+
+// getRedisTLSConfig gets the TLS configuration of the connections to Redis from
+// the environment. Returns a nil configuration if TLS is not enabled.
+func getRedisTLSConfig() (*tls.Config, error) {
+	if os.Getenv("FLAGGER_REDIS_TLS") != "true" {
+		return nil, nil //nolint:nilnil // TLS is disabled by a nil configuration.
+	}
+
+	tlsConfig := &tls.Config{
+		MinVersion: tls.VersionTLS12,
+		ServerName: os.Getenv("FLAGGER_REDIS_TLS_SERVER_NAME"),
+	}
+
+	caFilePath := os.Getenv("FLAGGER_REDIS_TLS_CA_FILE_PATH")
+	if caFilePath == "" {
+		return tlsConfig, nil
+	}
+
+	caCertificates, readErr := os.ReadFile(caFilePath)
+	if readErr != nil {
+		return nil, readErr
+	}
+
+	tlsConfig.RootCAs = x509.NewCertPool()
+	if !tlsConfig.RootCAs.AppendCertsFromPEM(caCertificates) {
+		return nil, ErrInvalidRedisCA
+	}
+
+	return tlsConfig, nil
+}
+
+func pingRedis(client redis.UniversalClient) error {
 	ctx := context.Background()
 	ctx, cancel := context.WithTimeout(ctx, 1*time.Second)
 	defer cancel()
@@ -1,6 +1,9 @@
 package startup_test
 
 import (
+	"errors"
+	"os"
+	"path/filepath"
 	"testing"
 
 	"github.com/waduhek/flagger/internal/logger"
@@ -26,3 +29,67 @@ func TestUnsuccessfulRedisConnection(t *testing.T) {
 		t.Error("expected error when connecting to redis")
 	}
 }
+
+func TestRedisModeConfiguration(t *testing.T) {
+	testCases := []struct {
+		Name          string
+		Mode          string
+		Addrs         string
+		MasterName    string
+		ExpectedError error
+	}{
+		{
+			Name:          "unknown mode",
+			Mode:          "replicated",
+			Addrs:         "localhost:6379",
+			ExpectedError: startup.ErrUnknownRedisMode,
+		},
+		{
+			Name:          "sentinel without addresses",
+			Mode:          "sentinel",
+			MasterName:    "mymaster",
+			ExpectedError: startup.ErrRedisAddrsRequired,
+		},
+		{
+			Name:          "sentinel without master name",
+			Mode:          "sentinel",
+			Addrs:         "localhost:26379, localhost:26380",
+			ExpectedError: startup.ErrRedisMasterNameRequired,
+		},
+		{
+			Name:          "cluster without addresses",
+			Mode:          "cluster",
+			Addrs:         " , ",
+			ExpectedError: startup.ErrRedisAddrsRequired,
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.Name, func(t *testing.T) {
+			t.Setenv("FLAGGER_REDIS_MODE", testCase.Mode)
+			t.Setenv("FLAGGER_REDIS_ADDRS", testCase.Addrs)
+			t.Setenv("FLAGGER_REDIS_MASTER_NAME", testCase.MasterName)
+
+			_, err := startup.ConnectRedis(&logger.StubLogger{})
+			if !errors.Is(err, testCase.ExpectedError) {
+				t.Errorf("expected error %v but got %v", testCase.ExpectedError, err)
+			}
+		})
+	}
+}
+
+func TestRedisTLSConfiguration(t *testing.T) {
+	caFilePath := filepath.Join(t.TempDir(), "ca.pem")
+	if err := os.WriteFile(caFilePath, []byte("not a certificate"), 0o600); err != nil {
+		t.Fatalf("could not write ca file: %v", err)
+	}
+
+	t.Setenv("FLAGGER_REDIS_URI", "redis://localhost:6379/0")
+	t.Setenv("FLAGGER_REDIS_TLS", "true")
+	t.Setenv("FLAGGER_REDIS_TLS_CA_FILE_PATH", caFilePath)
+
+	_, err := startup.ConnectRedis(&logger.StubLogger{})
+	if !errors.Is(err, startup.ErrInvalidRedisCA) {
+		t.Errorf("expected error %v but got %v", startup.ErrInvalidRedisCA, err)
+	}
+}
@@ -0,0 +1,34 @@
+package targeting
+
+import (
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// ErrUnknownVariation is a GRPC error that is returned when a rule serves a
+// variation that does not exist on the flag.
+var ErrUnknownVariation = status.Error(
+	codes.InvalidArgument,
+	"a rule serves a variation that does not exist on the flag",
+)
+
+// ErrUnknownOperator is a GRPC error that is returned when a condition uses an
+// operator that is not supported.
+var ErrUnknownOperator = status.Error(
+	codes.InvalidArgument,
+	"a condition uses an unsupported operator",
+)
+
+// ErrInvalidCondition is a GRPC error that is returned when the attribute or
+// the values of a condition are invalid for its operator.
+var ErrInvalidCondition = status.Error(
+	codes.InvalidArgument,
+	"a condition has an invalid attribute or values",
+)
+
+// ErrInvalidRollout is a GRPC error that is returned when the weights of a
+// percentage rollout don't add up to the total weight.
+var ErrInvalidRollout = status.Error(
+	codes.InvalidArgument,
+	"the weights of the rollout must add up to 100000",
+)
@@ -0,0 +1,297 @@
+package targeting
+
+import (
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// compare performs the comparison of the operator between the value of an
+// attribute and the values of a condition.
+func compare(operator Operator, value any, values []string) bool {
+	switch operator {
+	case OperatorEquals:
+		return compareStrings(value, values, func(a, b string) bool { return a == b })
+	case OperatorNotEquals:
+		return !compareStrings(value, values, func(a, b string) bool { return a == b })
+	case OperatorIn:
+		return isIn(value, values)
+	case OperatorNotIn:
+		return !isIn(value, values)
+	case OperatorContains:
+		return contains(value, values)
+	case OperatorStartsWith:
+		return compareStrings(value, values, strings.HasPrefix)
+	case OperatorEndsWith:
+		return compareStrings(value, values, strings.HasSuffix)
+	case OperatorMatches:
+		return matchesPattern(value, values)
+	case OperatorLessThan,
+		OperatorLessThanOrEqual,
+		OperatorGreaterThan,
+		OperatorGreaterThanOrEqual:
+		return compareNumbers(operator, value, values)
+	case OperatorSemverEqual, OperatorSemverLessThan, OperatorSemverGreaterThan:
+		return compareSemvers(operator, value, values)
+	case OperatorBefore, OperatorAfter:
+		return compareDates(operator, value, values)
+	default:
+		return false
+	}
+}
+
+// validateCondition checks that the condition has an attribute, uses a known
+// operator and has values that can be parsed for the operator.
+func validateCondition(condition *Condition) error {
+	if condition.Attribute == "" || len(condition.Values) == 0 {
+		return ErrInvalidCondition
+	}
+
+	switch condition.Operator {
+	case OperatorIn, OperatorNotIn, OperatorContains:
+		return nil
+	case OperatorEquals,
+		OperatorNotEquals,
+		OperatorStartsWith,
+		OperatorEndsWith:
+		return requireSingleValue(condition.Values, func(string) bool {
+			return true
+		})
+	case OperatorMatches:
+		return requireSingleValue(condition.Values, func(value string) bool {
+			_, err := regexp.Compile(value)
+			return err == nil
+		})
+	case OperatorLessThan,
+		OperatorLessThanOrEqual,
+		OperatorGreaterThan,
+		OperatorGreaterThanOrEqual:
+		return requireSingleValue(condition.Values, func(value string) bool {
+			_, err := strconv.ParseFloat(value, 64)
+			return err == nil
+		})
+	case OperatorSemverEqual, OperatorSemverLessThan, OperatorSemverGreaterThan:
+		return requireSingleValue(condition.Values, func(value string) bool {
+			_, ok := parseSemver(value)
+			return ok
+		})
+	case OperatorBefore, OperatorAfter:
+		return requireSingleValue(condition.Values, func(value string) bool {
+			_, err := time.Parse(time.RFC3339, value)
+			return err == nil
+		})
+	default:
+		return ErrUnknownOperator
+	}
+}
+
+// requireSingleValue checks that exactly one value is provided and that it is
+// valid.
+func requireSingleValue(values []string, isValid func(string) bool) error {
+	if len(values) != 1 || !isValid(values[0]) {
+		return ErrInvalidCondition
+	}
+
+	return nil
+}
+
+// attributeString converts the value of a scalar attribute to a string.
+func attributeString(value any) (string, bool) {
+	switch typedValue := value.(type) {
+	case string:
+		return typedValue, true
+	case bool:
+		return strconv.FormatBool(typedValue), true
+	case float64:
+		return strconv.FormatFloat(typedValue, 'f', -1, 64), true
+	case int:
+		return strconv.Itoa(typedValue), true
+	case int64:
+		return strconv.FormatInt(typedValue, 10), true
+	default:
+		return "", false
+	}
+}
+
+// attributeNumber converts the value of an attribute to a number.
+func attributeNumber(value any) (float64, bool) {
+	switch typedValue := value.(type) {
+	case float64:
+		return typedValue, true
+	case int:
+		return float64(typedValue), true
+	case int64:
+		return float64(typedValue), true
+	case string:
+		number, err := strconv.ParseFloat(typedValue, 64)
+		return number, err == nil
+	default:
+		return 0, false
+	}
+}
+
+// attributeTime converts the value of an attribute to a time. Strings are
+// parsed as RFC 3339 timestamps and numbers as seconds since the Unix epoch.
+func attributeTime(value any) (time.Time, bool) {
+	if typedValue, ok := value.(string); ok {
+		parsed, err := time.Parse(time.RFC3339, typedValue)
+		return parsed, err == nil
+	}
+
+	seconds, ok := attributeNumber(value)
+	if !ok {
+		return time.Time{}, false
+	}
+
+	return time.Unix(int64(seconds), 0), true
+}
+
+// compareStrings compares a scalar attribute with the first value of the
+// condition.
+func compareStrings(
+	value any,
+	values []string,
+	comparator func(attribute, value string) bool,
+) bool {
+	attribute, ok := attributeString(value)
+	if !ok || len(values) == 0 {
+		return false
+	}
+
+	return comparator(attribute, values[0])
+}
+
+// isIn checks whether a scalar attribute is one of the values of the
+// condition.
+func isIn(value any, values []string) bool {
+	attribute, ok := attributeString(value)
+	if !ok {
+		return false
+	}
+
+	return slices.Contains(values, attribute)
+}
+
+// contains checks whether a string attribute contains any of the values of the
+// condition as a substring, or whether a list attribute contains any of the
+// values of the condition as an element.
+func contains(value any, values []string) bool {
+	if list, ok := value.([]any); ok {
+		for _, element := range list {
+			if isIn(element, values) {
+				return true
+			}
+		}
+
+		return false
+	}
+
+	attribute, ok := value.(string)
+	if !ok {
+		return false
+	}
+
+	for _, conditionValue := range values {
+		if strings.Contains(attribute, conditionValue) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// matchesPattern checks whether a scalar attribute matches the regular
+// expression in the condition.
+func matchesPattern(value any, values []string) bool {
+	return compareStrings(value, values, func(attribute, pattern string) bool {
+		compiled, ok := compiledPatterns.compile(pattern)
+		return ok && compiled.MatchString(attribute)
+	})
+}
+
+// compareNumbers compares a numeric attribute with the first value of the
+// condition.
+func compareNumbers(operator Operator, value any, values []string) bool {
+	attribute, ok := attributeNumber(value)
+	if !ok || len(values) == 0 {
+		return false
+	}
+
+	conditionValue, err := strconv.ParseFloat(values[0], 64)
+	if err != nil {
+		return false
+	}
+
+	//nolint:exhaustive // Only the numeric operators are compared here.
+	switch operator {
+	case OperatorLessThan:
+		return attribute < conditionValue
+	case OperatorLessThanOrEqual:
+		return attribute <= conditionValue
+	case OperatorGreaterThan:
+		return attribute > conditionValue
+	case OperatorGreaterThanOrEqual:
+		return attribute >= conditionValue
+	default:
+		return false
+	}
+}
+
+// compareSemvers compares a semantic version attribute with the first value of
+// the condition.
+func compareSemvers(operator Operator, value any, values []string) bool {
+	attribute, ok := value.(string)
+	if !ok || len(values) == 0 {
+		return false
+	}
+
+	attributeVersion, ok := parseSemver(attribute)
+	if !ok {
+		return false
+	}
+
+	conditionVersion, ok := parseSemver(values[0])
+	if !ok {
+		return false
+	}
+
+	result := attributeVersion.compare(conditionVersion)
+
+	//nolint:exhaustive // Only the semantic version operators are compared here.
+	switch operator {
+	case OperatorSemverEqual:
+		return result == 0
+	case OperatorSemverLessThan:
+		return result < 0
+	case OperatorSemverGreaterThan:
+		return result > 0
+	default:
+		return false
+	}
+}
+
+// compareDates compares a date attribute with the first value of the
+// condition.
+func compareDates(operator Operator, value any, values []string) bool {
+	attribute, ok := attributeTime(value)
+	if !ok || len(values) == 0 {
+		return false
+	}
+
+	conditionValue, err := time.Parse(time.RFC3339, values[0])
+	if err != nil {
+		return false
+	}
+
+	//nolint:exhaustive // Only the date operators are compared here.
+	switch operator {
+	case OperatorBefore:
+		return attribute.Before(conditionValue)
+	case OperatorAfter:
+		return attribute.After(conditionValue)
+	default:
+		return false
+	}
+}
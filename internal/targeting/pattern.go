@@ -0,0 +1,70 @@
+package targeting
+
+import (
+	"container/list"
+	"regexp"
+	"sync"
+)
+
+// patternCacheSize is the largest number of compiled regular expressions that
+// are kept in memory.
+const patternCacheSize = 1024
+
+// compiledPatterns are the compiled regular expressions of the conditions that
+// were evaluated, so that they are not compiled on every evaluation.
+//
+//nolint:gochecknoglobals // The compiled patterns are shared by all evaluations.
+var compiledPatterns = &patternCache{
+	size:    patternCacheSize,
+	entries: make(map[string]*list.Element),
+	lru:     list.New(),
+}
+
+// patternEntry is a compiled regular expression. The regular expression is nil
+// if the pattern is invalid.
+type patternEntry struct {
+	pattern string
+	regexp  *regexp.Regexp
+}
+
+// patternCache keeps a bounded number of compiled regular expressions in
+// memory, removing the least recently used one when it is full.
+type patternCache struct {
+	size int
+
+	mu      sync.Mutex
+	entries map[string]*list.Element
+	lru     *list.List
+}
+
+// compile gets the compiled regular expression of the pattern, compiling it
+// if it is not cached. Returns false if the pattern is invalid.
+func (c *patternCache) compile(pattern string) (*regexp.Regexp, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if element, ok := c.entries[pattern]; ok {
+		c.lru.MoveToFront(element)
+
+		entry, _ := element.Value.(*patternEntry)
+
+		return entry.regexp, entry.regexp != nil
+	}
+
+	compiled, err := regexp.Compile(pattern)
+	if err != nil {
+		compiled = nil
+	}
+
+	c.entries[pattern] = c.lru.PushFront(&patternEntry{pattern: pattern, regexp: compiled})
+
+	if c.lru.Len() > c.size {
+		oldest := c.lru.Back()
+		c.lru.Remove(oldest)
+
+		entry, _ := oldest.Value.(*patternEntry)
+		delete(c.entries, entry.pattern)
+	}
+
+	return compiled, compiled != nil
+}
@@ -0,0 +1,72 @@
+package targeting
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+)
+
+// TotalWeight is the sum of the weights of all the variations in a rollout.
+// A weight of 1000 serves the variation to 1% of the evaluation contexts.
+const TotalWeight uint32 = 100000
+
+// WeightedVariation is a variation of a flag that is served to a share of the
+// evaluation contexts in a percentage rollout.
+type WeightedVariation struct {
+	Variation string `bson:"variation" json:"variation"`
+	Weight    uint32 `bson:"weight"    json:"weight"`
+}
+
+// Bucket deterministically assigns a targeting key to a bucket in the range
+// [0, TotalWeight) by hashing it along with the salt of the flag. The same
+// targeting key is always assigned to the same bucket of a flag, even after the
+// flag is renamed.
+func Bucket(salt string, targetingKey string) uint32 {
+	sum := sha256.Sum256([]byte(salt + "." + targetingKey))
+
+	//nolint:gosec // The remainder is always less than TotalWeight.
+	return uint32(binary.BigEndian.Uint64(sum[:8]) % uint64(TotalWeight))
+}
+
+// SelectVariation selects the variation of the rollout that the bucket falls
+// in. Returns false if the rollout does not cover the bucket.
+func SelectVariation(rollout []WeightedVariation, bucket uint32) (string, bool) {
+	var cumulativeWeight uint32
+
+	for _, weightedVariation := range rollout {
+		cumulativeWeight += weightedVariation.Weight
+		if bucket < cumulativeWeight {
+			return weightedVariation.Variation, true
+		}
+	}
+
+	return "", false
+}
+
+// ValidateRollout checks that every variation of the rollout is one of the
+// provided variations and that the weights add up to TotalWeight. An empty
+// rollout is valid and disables the percentage rollout.
+func ValidateRollout(rollout []WeightedVariation, variations []string) error {
+	if len(rollout) == 0 {
+		return nil
+	}
+
+	knownVariations := make(map[string]struct{}, len(variations))
+	for _, variation := range variations {
+		knownVariations[variation] = struct{}{}
+	}
+
+	var totalWeight uint64
+	for _, weightedVariation := range rollout {
+		if _, ok := knownVariations[weightedVariation.Variation]; !ok {
+			return ErrUnknownVariation
+		}
+
+		totalWeight += uint64(weightedVariation.Weight)
+	}
+
+	if totalWeight != uint64(TotalWeight) {
+		return ErrInvalidRollout
+	}
+
+	return nil
+}
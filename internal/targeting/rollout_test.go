@@ -0,0 +1,116 @@
+package targeting_test
+
+import (
+	"errors"
+	"strconv"
+	"testing"
+
+	"github.com/waduhek/flagger/internal/targeting"
+)
+
+func TestBucket_Deterministic(t *testing.T) {
+	first := targeting.Bucket("salt", "user-123")
+	second := targeting.Bucket("salt", "user-123")
+
+	if first != second {
+		t.Fatalf("expected the same bucket but got %d and %d", first, second)
+	}
+
+	if first >= targeting.TotalWeight {
+		t.Fatalf("expected bucket to be less than %d but got %d", targeting.TotalWeight, first)
+	}
+}
+
+func TestBucket_Distribution(t *testing.T) {
+	const keys = 20000
+
+	rollout := []targeting.WeightedVariation{
+		{Variation: "on", Weight: 5000},
+		{Variation: "off", Weight: 95000},
+	}
+
+	onCount := 0
+	for i := range keys {
+		bucket := targeting.Bucket("salt", "user-"+strconv.Itoa(i))
+
+		variation, ok := targeting.SelectVariation(rollout, bucket)
+		if !ok {
+			t.Fatalf("expected bucket %d to be covered by the rollout", bucket)
+		}
+
+		if variation == "on" {
+			onCount++
+		}
+	}
+
+	// 5% of the keys are expected to be in the rollout, with a margin of 1%.
+	if onCount < keys*4/100 || onCount > keys*6/100 {
+		t.Fatalf("expected about 5%% of %d keys to be on but got %d", keys, onCount)
+	}
+}
+
+func TestSelectVariation_Boundaries(t *testing.T) {
+	rollout := []targeting.WeightedVariation{
+		{Variation: "a", Weight: 25000},
+		{Variation: "b", Weight: 75000},
+	}
+
+	if variation, _ := targeting.SelectVariation(rollout, 24999); variation != "a" {
+		t.Fatalf("expected bucket 24999 to select a but got %q", variation)
+	}
+
+	if variation, _ := targeting.SelectVariation(rollout, 25000); variation != "b" {
+		t.Fatalf("expected bucket 25000 to select b but got %q", variation)
+	}
+}
+
+func TestValidateRollout(t *testing.T) {
+	t.Parallel()
+
+	variations := []string{"on", "off"}
+
+	testCases := []struct {
+		Name     string
+		Rollout  []targeting.WeightedVariation
+		Expected error
+	}{
+		{
+			Name:     "empty",
+			Rollout:  nil,
+			Expected: nil,
+		},
+		{
+			Name: "valid",
+			Rollout: []targeting.WeightedVariation{
+				{Variation: "on", Weight: 25000},
+				{Variation: "off", Weight: 75000},
+			},
+			Expected: nil,
+		},
+		{
+			Name: "incomplete_weights",
+			Rollout: []targeting.WeightedVariation{
+				{Variation: "on", Weight: 25000},
+			},
+			Expected: targeting.ErrInvalidRollout,
+		},
+		{
+			Name: "unknown_variation",
+			Rollout: []targeting.WeightedVariation{
+				{Variation: "maybe", Weight: 100000},
+			},
+			Expected: targeting.ErrUnknownVariation,
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.Name, func(t *testing.T) {
+			t.Parallel()
+
+			err := targeting.ValidateRollout(testCase.Rollout, variations)
+			if !errors.Is(err, testCase.Expected) {
+				t.Fatalf("expected error %v but got %v", testCase.Expected, err)
+			}
+		})
+	}
+}
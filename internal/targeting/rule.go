@@ -0,0 +1,128 @@
+package targeting
+
+// TargetingKeyAttribute is the name of the attribute that refers to the
+// targeting key of the evaluation context.
+const TargetingKeyAttribute = "targetingKey"
+
+// Operator is the comparison performed by a condition between an attribute of
+// the evaluation context and the values of the condition.
+type Operator string
+
+const (
+	OperatorEquals             Operator = "equals"
+	OperatorNotEquals          Operator = "not_equals"
+	OperatorIn                 Operator = "in"
+	OperatorNotIn              Operator = "not_in"
+	OperatorContains           Operator = "contains"
+	OperatorStartsWith         Operator = "starts_with"
+	OperatorEndsWith           Operator = "ends_with"
+	OperatorMatches            Operator = "matches"
+	OperatorLessThan           Operator = "less_than"
+	OperatorLessThanOrEqual    Operator = "less_than_or_equal"
+	OperatorGreaterThan        Operator = "greater_than"
+	OperatorGreaterThanOrEqual Operator = "greater_than_or_equal"
+	OperatorSemverEqual        Operator = "semver_equal"
+	OperatorSemverLessThan     Operator = "semver_less_than"
+	OperatorSemverGreaterThan  Operator = "semver_greater_than"
+	OperatorBefore             Operator = "before"
+	OperatorAfter              Operator = "after"
+)
+
+// Condition compares an attribute of the evaluation context with the values of
+// the condition using the operator.
+type Condition struct {
+	Attribute string   `bson:"attribute" json:"attribute"`
+	Operator  Operator `bson:"operator"  json:"operator"`
+	Values    []string `bson:"values"    json:"values"`
+}
+
+// Rule serves a variation of a flag when all of its conditions match the
+// evaluation context. A rule without any conditions matches every context.
+type Rule struct {
+	Conditions []Condition `bson:"conditions" json:"conditions"`
+	Variation  string      `bson:"variation"  json:"variation"`
+}
+
+// Context is the evaluation context sent by a caller that the rules of a flag
+// are evaluated against.
+type Context struct {
+	TargetingKey string
+	Attributes   map[string]any
+}
+
+// attribute finds the value of an attribute in the evaluation context.
+func (c *Context) attribute(name string) (any, bool) {
+	if c == nil {
+		return nil, false
+	}
+
+	if name == TargetingKeyAttribute {
+		return c.TargetingKey, c.TargetingKey != ""
+	}
+
+	value, ok := c.Attributes[name]
+	if !ok || value == nil {
+		return nil, false
+	}
+
+	return value, true
+}
+
+// Evaluate finds the first rule that matches the evaluation context and
+// returns its index. Returns false if none of the rules match.
+func Evaluate(rules []Rule, evalCtx *Context) (int, bool) {
+	for i := range rules {
+		if rules[i].matches(evalCtx) {
+			return i, true
+		}
+	}
+
+	return 0, false
+}
+
+// matches checks whether all the conditions of the rule match the evaluation
+// context.
+func (r *Rule) matches(evalCtx *Context) bool {
+	for i := range r.Conditions {
+		if !r.Conditions[i].matches(evalCtx) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// matches checks whether the condition matches the evaluation context. A
+// condition on an attribute that is missing from the context never matches.
+func (c *Condition) matches(evalCtx *Context) bool {
+	value, ok := evalCtx.attribute(c.Attribute)
+	if !ok {
+		return false
+	}
+
+	return compare(c.Operator, value, c.Values)
+}
+
+// Validate checks that the rules use known operators, that the values of the
+// conditions can be parsed for their operators and that every rule serves one
+// of the provided variations.
+func Validate(rules []Rule, variations []string) error {
+	knownVariations := make(map[string]struct{}, len(variations))
+	for _, variation := range variations {
+		knownVariations[variation] = struct{}{}
+	}
+
+	for _, rule := range rules {
+		if _, ok := knownVariations[rule.Variation]; !ok {
+			return ErrUnknownVariation
+		}
+
+		for _, condition := range rule.Conditions {
+			if err := validateCondition(&condition); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
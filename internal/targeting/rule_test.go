@@ -0,0 +1,275 @@
+package targeting_test
+
+import (
+	"errors"
+	"strconv"
+	"testing"
+
+	"github.com/waduhek/flagger/internal/targeting"
+)
+
+var evalCtx = &targeting.Context{
+	TargetingKey: "user-123",
+	Attributes: map[string]any{
+		"email":      "jane@example.com",
+		"country":    "IN",
+		"age":        float64(27),
+		"appVersion": "2.4.1",
+		"signedUpAt": "2024-03-01T10:00:00Z",
+		"groups":     []any{"beta", "staff"},
+		"isPremium":  true,
+	},
+}
+
+func TestEvaluate_Operators(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		Name      string
+		Condition targeting.Condition
+		Expected  bool
+	}{
+		{
+			Name:      "equals",
+			Condition: targeting.Condition{Attribute: "country", Operator: targeting.OperatorEquals, Values: []string{"IN"}},
+			Expected:  true,
+		},
+		{
+			Name:      "equals_boolean",
+			Condition: targeting.Condition{Attribute: "isPremium", Operator: targeting.OperatorEquals, Values: []string{"true"}},
+			Expected:  true,
+		},
+		{
+			Name:      "not_equals",
+			Condition: targeting.Condition{Attribute: "country", Operator: targeting.OperatorNotEquals, Values: []string{"IN"}},
+			Expected:  false,
+		},
+		{
+			Name: "targeting_key",
+			Condition: targeting.Condition{
+				Attribute: targeting.TargetingKeyAttribute,
+				Operator:  targeting.OperatorIn,
+				Values:    []string{"user-1", "user-123"},
+			},
+			Expected: true,
+		},
+		{
+			Name:      "not_in",
+			Condition: targeting.Condition{Attribute: "country", Operator: targeting.OperatorNotIn, Values: []string{"US", "GB"}},
+			Expected:  true,
+		},
+		{
+			Name:      "contains_string",
+			Condition: targeting.Condition{Attribute: "email", Operator: targeting.OperatorContains, Values: []string{"@example"}},
+			Expected:  true,
+		},
+		{
+			Name:      "contains_list",
+			Condition: targeting.Condition{Attribute: "groups", Operator: targeting.OperatorContains, Values: []string{"beta"}},
+			Expected:  true,
+		},
+		{
+			Name:      "ends_with",
+			Condition: targeting.Condition{Attribute: "email", Operator: targeting.OperatorEndsWith, Values: []string{".org"}},
+			Expected:  false,
+		},
+		{
+			Name:      "matches",
+			Condition: targeting.Condition{Attribute: "email", Operator: targeting.OperatorMatches, Values: []string{`^[a-z]+@example\.com$`}},
+			Expected:  true,
+		},
+		{
+			Name:      "greater_than_or_equal",
+			Condition: targeting.Condition{Attribute: "age", Operator: targeting.OperatorGreaterThanOrEqual, Values: []string{"18"}},
+			Expected:  true,
+		},
+		{
+			Name:      "less_than",
+			Condition: targeting.Condition{Attribute: "age", Operator: targeting.OperatorLessThan, Values: []string{"18"}},
+			Expected:  false,
+		},
+		{
+			Name:      "semver_greater_than",
+			Condition: targeting.Condition{Attribute: "appVersion", Operator: targeting.OperatorSemverGreaterThan, Values: []string{"2.4.1-beta.2"}},
+			Expected:  true,
+		},
+		{
+			Name:      "semver_less_than",
+			Condition: targeting.Condition{Attribute: "appVersion", Operator: targeting.OperatorSemverLessThan, Values: []string{"v2.10.0"}},
+			Expected:  true,
+		},
+		{
+			Name:      "before",
+			Condition: targeting.Condition{Attribute: "signedUpAt", Operator: targeting.OperatorBefore, Values: []string{"2024-01-01T00:00:00Z"}},
+			Expected:  false,
+		},
+		{
+			Name:      "after",
+			Condition: targeting.Condition{Attribute: "signedUpAt", Operator: targeting.OperatorAfter, Values: []string{"2024-01-01T00:00:00Z"}},
+			Expected:  true,
+		},
+		{
+			Name:      "missing_attribute",
+			Condition: targeting.Condition{Attribute: "plan", Operator: targeting.OperatorNotEquals, Values: []string{"free"}},
+			Expected:  false,
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.Name, func(t *testing.T) {
+			t.Parallel()
+
+			rules := []targeting.Rule{
+				{Conditions: []targeting.Condition{testCase.Condition}, Variation: "on"},
+			}
+
+			_, matched := targeting.Evaluate(rules, evalCtx)
+			if matched != testCase.Expected {
+				t.Fatalf("expected match to be %v but got %v", testCase.Expected, matched)
+			}
+		})
+	}
+}
+
+func TestEvaluate_FirstMatchingRule(t *testing.T) {
+	rules := []targeting.Rule{
+		{
+			Conditions: []targeting.Condition{
+				{Attribute: "country", Operator: targeting.OperatorEquals, Values: []string{"IN"}},
+				{Attribute: "isPremium", Operator: targeting.OperatorEquals, Values: []string{"false"}},
+			},
+			Variation: "first",
+		},
+		{
+			Conditions: []targeting.Condition{
+				{Attribute: "country", Operator: targeting.OperatorEquals, Values: []string{"IN"}},
+			},
+			Variation: "second",
+		},
+		{Variation: "catch_all"},
+	}
+
+	index, matched := targeting.Evaluate(rules, evalCtx)
+	if !matched {
+		t.Fatal("expected a rule to match")
+	}
+
+	if rules[index].Variation != "second" {
+		t.Fatalf("expected the second rule to match but got %q", rules[index].Variation)
+	}
+}
+
+func TestEvaluate_MatchesManyPatterns(t *testing.T) {
+	// More patterns are evaluated than the compiled patterns that are kept in
+	// memory, and every pattern is evaluated twice.
+	for range 2 {
+		for i := range 2000 {
+			rules := []targeting.Rule{
+				{
+					Conditions: []targeting.Condition{
+						{
+							Attribute: "email",
+							Operator:  targeting.OperatorMatches,
+							Values:    []string{`^jane@example\.com$|^user-` + strconv.Itoa(i) + `$`},
+						},
+					},
+					Variation: "on",
+				},
+				{
+					Conditions: []targeting.Condition{
+						{
+							Attribute: "email",
+							Operator:  targeting.OperatorMatches,
+							Values:    []string{`^user-` + strconv.Itoa(i) + `@example\.com$`},
+						},
+					},
+					Variation: "off",
+				},
+			}
+
+			index, matched := targeting.Evaluate(rules, evalCtx)
+			if !matched || rules[index].Variation != "on" {
+				t.Fatalf("expected the first rule to match pattern %d", i)
+			}
+		}
+	}
+}
+
+func TestValidate(t *testing.T) {
+	t.Parallel()
+
+	variations := []string{"on", "off"}
+
+	testCases := []struct {
+		Name     string
+		Rule     targeting.Rule
+		Expected error
+	}{
+		{
+			Name: "valid",
+			Rule: targeting.Rule{
+				Conditions: []targeting.Condition{
+					{Attribute: "appVersion", Operator: targeting.OperatorSemverEqual, Values: []string{"1.0.0"}},
+				},
+				Variation: "on",
+			},
+			Expected: nil,
+		},
+		{
+			Name:     "unknown_variation",
+			Rule:     targeting.Rule{Variation: "maybe"},
+			Expected: targeting.ErrUnknownVariation,
+		},
+		{
+			Name: "unknown_operator",
+			Rule: targeting.Rule{
+				Conditions: []targeting.Condition{
+					{Attribute: "country", Operator: targeting.Operator("like"), Values: []string{"IN"}},
+				},
+				Variation: "on",
+			},
+			Expected: targeting.ErrUnknownOperator,
+		},
+		{
+			Name: "invalid_regex",
+			Rule: targeting.Rule{
+				Conditions: []targeting.Condition{
+					{Attribute: "email", Operator: targeting.OperatorMatches, Values: []string{"("}},
+				},
+				Variation: "on",
+			},
+			Expected: targeting.ErrInvalidCondition,
+		},
+		{
+			Name: "invalid_date",
+			Rule: targeting.Rule{
+				Conditions: []targeting.Condition{
+					{Attribute: "signedUpAt", Operator: targeting.OperatorBefore, Values: []string{"yesterday"}},
+				},
+				Variation: "on",
+			},
+			Expected: targeting.ErrInvalidCondition,
+		},
+		{
+			Name: "no_values",
+			Rule: targeting.Rule{
+				Conditions: []targeting.Condition{
+					{Attribute: "country", Operator: targeting.OperatorIn},
+				},
+				Variation: "on",
+			},
+			Expected: targeting.ErrInvalidCondition,
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.Name, func(t *testing.T) {
+			t.Parallel()
+
+			err := targeting.Validate([]targeting.Rule{testCase.Rule}, variations)
+			if !errors.Is(err, testCase.Expected) {
+				t.Fatalf("expected error %v but got %v", testCase.Expected, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,108 @@
+package targeting
+
+import (
+	"cmp"
+	"strconv"
+	"strings"
+)
+
+// semver is a parsed semantic version. Build metadata is ignored as it does
+// not affect the precedence of a version.
+type semver struct {
+	Major      uint64
+	Minor      uint64
+	Patch      uint64
+	PreRelease []string
+}
+
+// semverParts is the number of dot separated parts in the core of a semantic
+// version.
+const semverParts = 3
+
+// parseSemver parses a semantic version with an optional "v" prefix.
+func parseSemver(version string) (semver, bool) {
+	version = strings.TrimPrefix(version, "v")
+
+	version, _, _ = strings.Cut(version, "+")
+	core, preRelease, hasPreRelease := strings.Cut(version, "-")
+
+	parts := strings.Split(core, ".")
+	if len(parts) != semverParts {
+		return semver{}, false
+	}
+
+	numbers := make([]uint64, 0, semverParts)
+	for _, part := range parts {
+		number, err := strconv.ParseUint(part, 10, 64)
+		if err != nil {
+			return semver{}, false
+		}
+
+		numbers = append(numbers, number)
+	}
+
+	parsed := semver{Major: numbers[0], Minor: numbers[1], Patch: numbers[2]}
+
+	if hasPreRelease {
+		if preRelease == "" {
+			return semver{}, false
+		}
+
+		parsed.PreRelease = strings.Split(preRelease, ".")
+	}
+
+	return parsed, true
+}
+
+// compare returns -1, 0 or +1 depending on whether v has a lower, equal or
+// higher precedence than other.
+func (v semver) compare(other semver) int {
+	if result := cmp.Compare(v.Major, other.Major); result != 0 {
+		return result
+	}
+
+	if result := cmp.Compare(v.Minor, other.Minor); result != 0 {
+		return result
+	}
+
+	if result := cmp.Compare(v.Patch, other.Patch); result != 0 {
+		return result
+	}
+
+	// A version without a pre-release has a higher precedence than one with a
+	// pre-release.
+	switch {
+	case len(v.PreRelease) == 0 && len(other.PreRelease) == 0:
+		return 0
+	case len(v.PreRelease) == 0:
+		return 1
+	case len(other.PreRelease) == 0:
+		return -1
+	}
+
+	for i := 0; i < len(v.PreRelease) && i < len(other.PreRelease); i++ {
+		if result := comparePreRelease(v.PreRelease[i], other.PreRelease[i]); result != 0 {
+			return result
+		}
+	}
+
+	return cmp.Compare(len(v.PreRelease), len(other.PreRelease))
+}
+
+// comparePreRelease compares two pre-release identifiers. Numeric identifiers
+// are compared numerically and have a lower precedence than alphanumeric ones.
+func comparePreRelease(a, b string) int {
+	aNumber, aErr := strconv.ParseUint(a, 10, 64)
+	bNumber, bErr := strconv.ParseUint(b, 10, 64)
+
+	switch {
+	case aErr == nil && bErr == nil:
+		return cmp.Compare(aNumber, bNumber)
+	case aErr == nil:
+		return -1
+	case bErr == nil:
+		return 1
+	default:
+		return strings.Compare(a, b)
+	}
+}
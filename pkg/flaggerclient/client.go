@@ -0,0 +1,519 @@
+// Package flaggerclient implements a client of the FlagProvider GRPC service of
+// flagger that keeps the flags of an environment in an in-process cache. The
+// cache is refreshed in the background and keeps serving the last known values
+// of the flags while the service is unreachable.
+package flaggerclient
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"sync"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/structpb"
+
+	"github.com/waduhek/flagger/proto/providerpb"
+)
+
+// projectTokenMetadataKey is the metadata key that the project key is sent in.
+//
+//nolint:gosec // This isn't a secret but a header key.
+const projectTokenMetadataKey = "x-flagger-token"
+
+const (
+	// defaultPollInterval is the interval at which the flags are fetched in
+	// the polling mode when no interval is provided.
+	defaultPollInterval = 30 * time.Second
+	// defaultRetryInterval is the interval after which a failed refresh is
+	// first retried when no interval is provided.
+	defaultRetryInterval = 1 * time.Second
+	// maxRetryInterval is the longest interval that failed refreshes are
+	// backed off to.
+	maxRetryInterval = 30 * time.Second
+)
+
+// ErrStreamClosed is returned as the error of the last refresh when the server
+// closes the stream of watched flags.
+var ErrStreamClosed = errors.New("flaggerclient: watch stream closed by server")
+
+// Mode is the way that the cached flags are refreshed in the background.
+type Mode int
+
+const (
+	// ModeStream refreshes the flags with the changes that are streamed by
+	// the WatchFlags RPC.
+	ModeStream Mode = iota
+	// ModePoll refreshes the flags by fetching all of them with the
+	// GetAllFlags RPC at the poll interval.
+	ModePoll
+)
+
+// Options configure the refreshing of the flags and the context that they are
+// evaluated against.
+type Options struct {
+	// Mode is the way that the flags are refreshed. Defaults to ModeStream.
+	Mode Mode
+	// PollInterval is the interval at which the flags are fetched in the
+	// polling mode. Defaults to 30 seconds.
+	PollInterval time.Duration
+	// RetryInterval is the interval after which a failed refresh is first
+	// retried. Consecutive failures double the interval up to 30 seconds.
+	// Defaults to 1 second.
+	RetryInterval time.Duration
+	// TargetingKey is the targeting key of the context that the flags are
+	// evaluated against.
+	TargetingKey string
+	// Attributes are the attributes of the context that the flags are
+	// evaluated against.
+	Attributes map[string]any
+}
+
+// FlagChange describes a change to the value of a cached flag.
+type FlagChange struct {
+	// Name is the name of the flag that changed.
+	Name string
+	// Previous is the previous status of the flag. It is nil if the flag was
+	// added.
+	Previous *providerpb.GetFlagResponse
+	// Current is the current status of the flag. It is nil if the flag was
+	// removed.
+	Current *providerpb.GetFlagResponse
+}
+
+// ChangeHook is called with every change to the cached flags.
+type ChangeHook func(change FlagChange)
+
+// Client keeps the flags of a project in an environment in an in-process
+// cache that is refreshed in the background.
+type Client struct {
+	client      providerpb.FlagProviderClient
+	projectKey  string
+	environment string
+	evalCtx     *providerpb.EvaluationContext
+	options     Options
+
+	mu          sync.RWMutex
+	flags       map[string]*providerpb.GetFlagResponse
+	lastRefresh time.Time
+	lastErr     error
+	hooks       []ChangeHook
+
+	ready     chan struct{}
+	readyOnce sync.Once
+	cancel    context.CancelFunc
+	done      chan struct{}
+}
+
+// NewClient creates a client for the flags of the project with the provided
+// key in the environment and starts refreshing them in the background over the
+// GRPC connection. The client must be closed once it is no longer used.
+func NewClient(
+	conn grpc.ClientConnInterface,
+	projectKey string,
+	environment string,
+	options Options,
+) (*Client, error) {
+	attributes, err := structpb.NewStruct(options.Attributes)
+	if err != nil {
+		return nil, fmt.Errorf("flaggerclient: unsupported attributes: %w", err)
+	}
+
+	if options.PollInterval <= 0 {
+		options.PollInterval = defaultPollInterval
+	}
+
+	if options.RetryInterval <= 0 {
+		options.RetryInterval = defaultRetryInterval
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+
+	c := &Client{
+		client:      providerpb.NewFlagProviderClient(conn),
+		projectKey:  projectKey,
+		environment: environment,
+		evalCtx: &providerpb.EvaluationContext{
+			TargetingKey: options.TargetingKey,
+			Attributes:   attributes,
+		},
+		options: options,
+		flags:   map[string]*providerpb.GetFlagResponse{},
+		ready:   make(chan struct{}),
+		cancel:  cancel,
+		done:    make(chan struct{}),
+	}
+
+	go c.run(ctx)
+
+	return c, nil
+}
+
+// WaitForReady blocks until the flags have been fetched for the first time or
+// the context is done.
+func (c *Client) WaitForReady(ctx context.Context) error {
+	select {
+	case <-c.ready:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Close stops refreshing the flags. The last known values of the flags are
+// still served after the client is closed.
+func (c *Client) Close() {
+	c.cancel()
+	<-c.done
+}
+
+// OnChange registers a hook that is called with every change to the cached
+// flags. Hooks are called from the background refresh in the order that they
+// were registered.
+func (c *Client) OnChange(hook ChangeHook) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.hooks = append(c.hooks, hook)
+}
+
+// LastRefresh returns the time at which the flags were last refreshed and the
+// error of the latest refresh if it failed.
+func (c *Client) LastRefresh() (time.Time, error) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	return c.lastRefresh, c.lastErr
+}
+
+// Flag returns the cached status of the flag.
+func (c *Client) Flag(name string) (*providerpb.GetFlagResponse, bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	flag, ok := c.flags[name]
+	if !ok {
+		return nil, false
+	}
+
+	return proto.CloneOf(flag), true
+}
+
+// Bool returns the value of the boolean flag or the default value if the flag
+// is not cached or is not a boolean flag.
+func (c *Client) Bool(name string, defaultValue bool) bool {
+	value, ok := c.lookup(name).GetValue().(*providerpb.GetFlagResponse_BoolValue)
+	if !ok {
+		return defaultValue
+	}
+
+	return value.BoolValue
+}
+
+// String returns the value of the string flag or the default value if the
+// flag is not cached or is not a string flag.
+func (c *Client) String(name string, defaultValue string) string {
+	value, ok := c.lookup(name).GetValue().(*providerpb.GetFlagResponse_StringValue)
+	if !ok {
+		return defaultValue
+	}
+
+	return value.StringValue
+}
+
+// Float returns the value of the number flag or the default value if the flag
+// is not cached or is not a number flag.
+func (c *Client) Float(name string, defaultValue float64) float64 {
+	value, ok := c.lookup(name).GetValue().(*providerpb.GetFlagResponse_NumberValue)
+	if !ok {
+		return defaultValue
+	}
+
+	return value.NumberValue
+}
+
+// Int returns the value of the number flag or the default value if the flag
+// is not cached or its value is not an integer.
+func (c *Client) Int(name string, defaultValue int64) int64 {
+	// math.MaxInt64 is rounded up to 2^63 as a floating point number, which
+	// is out of the range of int64.
+	value, ok := c.lookup(name).GetValue().(*providerpb.GetFlagResponse_NumberValue)
+	if !ok || value.NumberValue != math.Trunc(value.NumberValue) ||
+		value.NumberValue < math.MinInt64 || value.NumberValue >= math.MaxInt64 {
+		return defaultValue
+	}
+
+	return int64(value.NumberValue)
+}
+
+// JSON returns the value of the JSON flag or the default value if the flag is
+// not cached or is not a JSON flag.
+func (c *Client) JSON(name string, defaultValue any) any {
+	value, ok := c.lookup(name).GetValue().(*providerpb.GetFlagResponse_JsonValue)
+	if !ok {
+		return defaultValue
+	}
+
+	return value.JsonValue.AsInterface()
+}
+
+// lookup returns the cached status of the flag if it was evaluated without an
+// error.
+func (c *Client) lookup(name string) *providerpb.GetFlagResponse {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	flag, ok := c.flags[name]
+	if !ok || flag.GetErrorCode() != providerpb.ErrorCode_ERROR_CODE_UNSPECIFIED {
+		return nil
+	}
+
+	return flag
+}
+
+// run refreshes the flags in the background until the context is cancelled.
+func (c *Client) run(ctx context.Context) {
+	defer close(c.done)
+
+	//nolint:exhaustive // Every other mode streams the changes.
+	switch c.options.Mode {
+	case ModePoll:
+		c.poll(ctx)
+	default:
+		c.watch(ctx)
+	}
+}
+
+// poll fetches all the flags at the poll interval. Failed fetches are retried
+// with a backoff that is at most the poll interval.
+func (c *Client) poll(ctx context.Context) {
+	retryInterval := c.options.RetryInterval
+
+	for {
+		interval := c.options.PollInterval
+
+		response, err := c.client.GetAllFlags(c.outgoingContext(ctx), &providerpb.GetAllFlagsRequest{
+			Environment: c.environment,
+			Context:     c.evalCtx,
+		})
+		if err == nil {
+			c.replaceFlags(response.GetFlags())
+			retryInterval = c.options.RetryInterval
+		} else {
+			if ctx.Err() != nil {
+				return
+			}
+
+			c.setError(err)
+
+			interval = min(retryInterval, c.options.PollInterval)
+			retryInterval = min(retryInterval*2, maxRetryInterval)
+		}
+
+		if !sleep(ctx, interval) {
+			return
+		}
+	}
+}
+
+// watch applies the changes streamed by the server to the flags. The stream
+// is reconnected with a backoff when it fails, resuming after the version of
+// the last event that was received.
+func (c *Client) watch(ctx context.Context) {
+	retryInterval := c.options.RetryInterval
+	version := ""
+
+	for {
+		received, err := c.watchStream(ctx, &version)
+		if ctx.Err() != nil {
+			return
+		}
+
+		c.setError(err)
+
+		if received {
+			retryInterval = c.options.RetryInterval
+		}
+
+		if !sleep(ctx, retryInterval) {
+			return
+		}
+
+		retryInterval = min(retryInterval*2, maxRetryInterval)
+	}
+}
+
+// watchStream applies the events of a single stream of watched flags until it
+// fails and reports whether any event was received.
+func (c *Client) watchStream(ctx context.Context, version *string) (bool, error) {
+	stream, err := c.client.WatchFlags(c.outgoingContext(ctx), &providerpb.WatchFlagsRequest{
+		Environment: c.environment,
+		Context:     c.evalCtx,
+		Version:     *version,
+	})
+	if err != nil {
+		return false, err
+	}
+
+	received := false
+
+	for {
+		event, recvErr := stream.Recv()
+		if recvErr != nil {
+			if errors.Is(recvErr, io.EOF) {
+				recvErr = ErrStreamClosed
+			}
+
+			return received, recvErr
+		}
+
+		received = true
+
+		switch event.GetType() {
+		case providerpb.WatchEventType_WATCH_EVENT_TYPE_SNAPSHOT:
+			c.replaceFlags(event.GetFlags())
+		case providerpb.WatchEventType_WATCH_EVENT_TYPE_UPDATE:
+			c.updateFlags(event.GetFlags(), event.GetRemovedFlags())
+		case providerpb.WatchEventType_WATCH_EVENT_TYPE_HEARTBEAT:
+			c.setError(nil)
+		}
+
+		*version = event.GetVersion()
+	}
+}
+
+// outgoingContext adds the project key to the metadata of the requests made
+// with the context.
+func (c *Client) outgoingContext(ctx context.Context) context.Context {
+	return metadata.AppendToOutgoingContext(ctx, projectTokenMetadataKey, c.projectKey)
+}
+
+// replaceFlags replaces all the cached flags.
+func (c *Client) replaceFlags(flags map[string]*providerpb.GetFlagResponse) {
+	c.mu.Lock()
+
+	removedFlagNames := []string{}
+	for name := range c.flags {
+		if _, ok := flags[name]; !ok {
+			removedFlagNames = append(removedFlagNames, name)
+		}
+	}
+
+	changes := c.applyFlags(flags, removedFlagNames)
+	hooks := c.hooks
+
+	c.mu.Unlock()
+
+	c.markReady()
+	notify(hooks, changes)
+}
+
+// updateFlags updates the changed flags and removes the removed flags from
+// the cache.
+func (c *Client) updateFlags(
+	flags map[string]*providerpb.GetFlagResponse,
+	removedFlagNames []string,
+) {
+	c.mu.Lock()
+
+	changes := c.applyFlags(flags, removedFlagNames)
+	hooks := c.hooks
+
+	c.mu.Unlock()
+
+	notify(hooks, changes)
+}
+
+// applyFlags stores the flags and removes the removed flags from the cache,
+// returning the changes to their values. The lock must be held by the caller.
+func (c *Client) applyFlags(
+	flags map[string]*providerpb.GetFlagResponse,
+	removedFlagNames []string,
+) []FlagChange {
+	changes := []FlagChange{}
+
+	for _, name := range removedFlagNames {
+		previous, ok := c.flags[name]
+		if !ok {
+			continue
+		}
+
+		delete(c.flags, name)
+		changes = append(changes, FlagChange{Name: name, Previous: previous})
+	}
+
+	for name, flag := range flags {
+		// Whether the server read the flag from its cache is not a part of
+		// the value of the flag.
+		flag.Cached = false
+
+		previous, ok := c.flags[name]
+		c.flags[name] = flag
+
+		if !ok || !isSameValue(previous, flag) {
+			changes = append(changes, FlagChange{Name: name, Previous: previous, Current: flag})
+		}
+	}
+
+	c.lastRefresh = time.Now()
+	c.lastErr = nil
+
+	return changes
+}
+
+// setError records the error of the latest refresh.
+func (c *Client) setError(err error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if err == nil {
+		c.lastRefresh = time.Now()
+	}
+
+	c.lastErr = err
+}
+
+// markReady unblocks the callers waiting for the flags to be fetched.
+func (c *Client) markReady() {
+	c.readyOnce.Do(func() {
+		close(c.ready)
+	})
+}
+
+// isSameValue checks whether the two statuses of a flag serve the same value.
+func isSameValue(a *providerpb.GetFlagResponse, b *providerpb.GetFlagResponse) bool {
+	return a.GetVariation() == b.GetVariation() &&
+		a.GetErrorCode() == b.GetErrorCode() &&
+		proto.Equal(
+			&providerpb.GetFlagResponse{Value: a.GetValue()},
+			&providerpb.GetFlagResponse{Value: b.GetValue()},
+		)
+}
+
+// notify calls the hooks with each of the changes.
+func notify(hooks []ChangeHook, changes []FlagChange) {
+	for _, change := range changes {
+		for _, hook := range hooks {
+			hook(change)
+		}
+	}
+}
+
+// sleep waits for the duration and reports whether the context is still
+// active.
+func sleep(ctx context.Context, duration time.Duration) bool {
+	timer := time.NewTimer(duration)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return false
+	case <-timer.C:
+		return true
+	}
+}
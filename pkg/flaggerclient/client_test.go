@@ -0,0 +1,426 @@
+package flaggerclient_test
+
+import (
+	"context"
+	"math"
+	"net"
+	"sync"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+	"google.golang.org/protobuf/types/known/structpb"
+
+	"github.com/waduhek/flagger/proto/providerpb"
+
+	"github.com/waduhek/flagger/pkg/flaggerclient"
+)
+
+const (
+	testProjectKey  = "test-project-key"
+	testEnvironment = "production"
+	testTimeout     = 5 * time.Second
+)
+
+// fakeFlagProviderServer serves the flags from memory. Fetching the flags
+// fails while the server is unavailable and watched flags are sent from the
+// events channel.
+type fakeFlagProviderServer struct {
+	providerpb.UnimplementedFlagProviderServer
+
+	mu            sync.Mutex
+	flags         map[string]*providerpb.GetFlagResponse
+	isUnavailable bool
+	lastRequest   *providerpb.GetAllFlagsRequest
+	lastToken     string
+
+	events chan *providerpb.WatchFlagsResponse
+}
+
+func (s *fakeFlagProviderServer) GetAllFlags(
+	ctx context.Context,
+	req *providerpb.GetAllFlagsRequest,
+) (*providerpb.GetFlagsResponse, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.lastRequest = req
+
+	md, _ := metadata.FromIncomingContext(ctx)
+	if tokens := md.Get("x-flagger-token"); len(tokens) == 1 {
+		s.lastToken = tokens[0]
+	}
+
+	if s.isUnavailable {
+		return nil, status.Error(codes.Unavailable, "service unavailable")
+	}
+
+	flags := map[string]*providerpb.GetFlagResponse{}
+	for name, flag := range s.flags {
+		flags[name] = flag
+	}
+
+	return &providerpb.GetFlagsResponse{Flags: flags}, nil
+}
+
+func (s *fakeFlagProviderServer) WatchFlags(
+	_ *providerpb.WatchFlagsRequest,
+	stream providerpb.FlagProvider_WatchFlagsServer,
+) error {
+	for {
+		select {
+		case <-stream.Context().Done():
+			return nil
+		case event := <-s.events:
+			if err := stream.Send(event); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+func (s *fakeFlagProviderServer) setFlag(name string, flag *providerpb.GetFlagResponse) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.flags[name] = flag
+}
+
+func (s *fakeFlagProviderServer) setUnavailable(isUnavailable bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.isUnavailable = isUnavailable
+}
+
+func newFakeServer(t *testing.T) *fakeFlagProviderServer {
+	t.Helper()
+
+	jsonValue, err := structpb.NewValue(map[string]any{"theme": "dark"})
+	if err != nil {
+		t.Fatalf("could not create json value: %v", err)
+	}
+
+	return &fakeFlagProviderServer{
+		flags: map[string]*providerpb.GetFlagResponse{
+			"new-checkout": boolFlag(true),
+			"banner-text": {
+				Status:    true,
+				Variation: "welcome",
+				Value:     &providerpb.GetFlagResponse_StringValue{StringValue: "Welcome"},
+			},
+			"max-items": {
+				Status:    true,
+				Variation: "ten",
+				Value:     &providerpb.GetFlagResponse_NumberValue{NumberValue: 10},
+			},
+			"ratio": {
+				Status:    true,
+				Variation: "half",
+				Value:     &providerpb.GetFlagResponse_NumberValue{NumberValue: 0.5},
+			},
+			"overflow": {
+				Status:    true,
+				Variation: "huge",
+				Value:     &providerpb.GetFlagResponse_NumberValue{NumberValue: math.Exp2(63)},
+			},
+			"min-int": {
+				Status:    true,
+				Variation: "smallest",
+				Value:     &providerpb.GetFlagResponse_NumberValue{NumberValue: -math.Exp2(63)},
+			},
+			"theme": {
+				Status:    true,
+				Variation: "dark",
+				Value:     &providerpb.GetFlagResponse_JsonValue{JsonValue: jsonValue},
+			},
+			"broken": {
+				Reason:    providerpb.Reason_REASON_ERROR,
+				ErrorCode: providerpb.ErrorCode_ERROR_CODE_PARSE_ERROR,
+			},
+		},
+		events: make(chan *providerpb.WatchFlagsResponse),
+	}
+}
+
+func boolFlag(value bool) *providerpb.GetFlagResponse {
+	variation := "off"
+	if value {
+		variation = "on"
+	}
+
+	return &providerpb.GetFlagResponse{
+		Status:    value,
+		Variation: variation,
+		Value:     &providerpb.GetFlagResponse_BoolValue{BoolValue: value},
+	}
+}
+
+// newTestClient starts an in-process server with the fake service and creates
+// a client connected to it that is ready to serve flags.
+func newTestClient(
+	t *testing.T,
+	server *fakeFlagProviderServer,
+	options flaggerclient.Options,
+) *flaggerclient.Client {
+	t.Helper()
+
+	const bufferSize = 1024 * 1024
+
+	lis := bufconn.Listen(bufferSize)
+
+	grpcServer := grpc.NewServer()
+	providerpb.RegisterFlagProviderServer(grpcServer, server)
+
+	go func() {
+		_ = grpcServer.Serve(lis)
+	}()
+
+	conn, err := grpc.NewClient(
+		"passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return lis.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	if err != nil {
+		t.Fatalf("could not connect to in-process server: %v", err)
+	}
+
+	client, err := flaggerclient.NewClient(conn, testProjectKey, testEnvironment, options)
+	if err != nil {
+		t.Fatalf("could not create client: %v", err)
+	}
+
+	t.Cleanup(func() {
+		client.Close()
+		conn.Close()
+		grpcServer.Stop()
+	})
+
+	return client
+}
+
+func waitForReady(t *testing.T, client *flaggerclient.Client) {
+	t.Helper()
+
+	ctx, cancel := context.WithTimeout(context.Background(), testTimeout)
+	defer cancel()
+
+	if err := client.WaitForReady(ctx); err != nil {
+		t.Fatalf("client did not become ready: %v", err)
+	}
+}
+
+func TestClient_Values(t *testing.T) {
+	server := newFakeServer(t)
+	client := newTestClient(t, server, flaggerclient.Options{
+		Mode:         flaggerclient.ModePoll,
+		TargetingKey: "user-123",
+		Attributes:   map[string]any{"country": "IN"},
+	})
+
+	waitForReady(t, client)
+
+	if server.lastToken != testProjectKey {
+		t.Errorf("expected project key %q but got %q", testProjectKey, server.lastToken)
+	}
+
+	if server.lastRequest.GetEnvironment() != testEnvironment ||
+		server.lastRequest.GetContext().GetTargetingKey() != "user-123" ||
+		server.lastRequest.GetContext().GetAttributes().GetFields()["country"].GetStringValue() != "IN" {
+		t.Errorf("unexpected request %v", server.lastRequest)
+	}
+
+	if !client.Bool("new-checkout", false) {
+		t.Error("expected new-checkout to be true")
+	}
+
+	if value := client.String("banner-text", "Hello"); value != "Welcome" {
+		t.Errorf("expected banner-text to be %q but got %q", "Welcome", value)
+	}
+
+	if value := client.Int("max-items", 5); value != 10 {
+		t.Errorf("expected max-items to be 10 but got %d", value)
+	}
+
+	if value := client.Int("min-int", 5); value != math.MinInt64 {
+		t.Errorf("expected min-int to be %d but got %d", int64(math.MinInt64), value)
+	}
+
+	if value := client.Float("ratio", 1); value != 0.5 {
+		t.Errorf("expected ratio to be 0.5 but got %v", value)
+	}
+
+	theme, ok := client.JSON("theme", nil).(map[string]any)
+	if !ok || theme["theme"] != "dark" {
+		t.Errorf("unexpected theme %v", theme)
+	}
+}
+
+func TestClient_Defaults(t *testing.T) {
+	client := newTestClient(t, newFakeServer(t), flaggerclient.Options{
+		Mode: flaggerclient.ModePoll,
+	})
+
+	waitForReady(t, client)
+
+	if !client.Bool("missing", true) {
+		t.Error("expected default value for a missing flag")
+	}
+
+	if !client.Bool("broken", true) {
+		t.Error("expected default value for a flag that failed to evaluate")
+	}
+
+	if value := client.String("new-checkout", "default"); value != "default" {
+		t.Errorf("expected default value for a type mismatch but got %q", value)
+	}
+
+	if value := client.Int("ratio", 7); value != 7 {
+		t.Errorf("expected default value for a fractional number but got %d", value)
+	}
+
+	if value := client.Int("overflow", 7); value != 7 {
+		t.Errorf("expected default value for a number out of the range of int64 but got %d", value)
+	}
+}
+
+func TestClient_ServesLastKnownValuesDuringOutage(t *testing.T) {
+	server := newFakeServer(t)
+	client := newTestClient(t, server, flaggerclient.Options{
+		Mode:          flaggerclient.ModePoll,
+		PollInterval:  10 * time.Millisecond,
+		RetryInterval: 10 * time.Millisecond,
+	})
+
+	waitForReady(t, client)
+
+	server.setUnavailable(true)
+
+	deadline := time.Now().Add(testTimeout)
+	for {
+		if _, err := client.LastRefresh(); status.Code(err) == codes.Unavailable {
+			break
+		}
+
+		if time.Now().After(deadline) {
+			t.Fatal("expected the refresh to fail while the server is unavailable")
+		}
+
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	if !client.Bool("new-checkout", false) {
+		t.Error("expected the last known value to be served during the outage")
+	}
+}
+
+func TestClient_PollChangeHooks(t *testing.T) {
+	server := newFakeServer(t)
+	client := newTestClient(t, server, flaggerclient.Options{
+		Mode:         flaggerclient.ModePoll,
+		PollInterval: 10 * time.Millisecond,
+	})
+
+	changes := make(chan flaggerclient.FlagChange, 10)
+	client.OnChange(func(change flaggerclient.FlagChange) {
+		if change.Name == "new-checkout" {
+			changes <- change
+		}
+	})
+
+	waitForReady(t, client)
+
+	added := receiveChange(t, changes)
+	if added.Previous != nil || added.Current.GetVariation() != "on" {
+		t.Fatalf("unexpected change when the flag was fetched %v", added)
+	}
+
+	server.setFlag("new-checkout", boolFlag(false))
+
+	updated := receiveChange(t, changes)
+	if updated.Previous.GetVariation() != "on" || updated.Current.GetVariation() != "off" {
+		t.Fatalf("unexpected change when the flag was updated %v", updated)
+	}
+
+	if client.Bool("new-checkout", true) {
+		t.Error("expected new-checkout to be false after the update")
+	}
+}
+
+func TestClient_WatchChangeHooks(t *testing.T) {
+	server := newFakeServer(t)
+	client := newTestClient(t, server, flaggerclient.Options{
+		Mode: flaggerclient.ModeStream,
+	})
+
+	changes := make(chan flaggerclient.FlagChange, 10)
+	client.OnChange(func(change flaggerclient.FlagChange) {
+		changes <- change
+	})
+
+	server.events <- &providerpb.WatchFlagsResponse{
+		Type:    providerpb.WatchEventType_WATCH_EVENT_TYPE_SNAPSHOT,
+		Version: "1-0",
+		Flags: map[string]*providerpb.GetFlagResponse{
+			"new-checkout": boolFlag(true),
+			"dark-mode":    boolFlag(true),
+		},
+	}
+
+	waitForReady(t, client)
+
+	receiveChange(t, changes)
+	receiveChange(t, changes)
+
+	server.events <- &providerpb.WatchFlagsResponse{
+		Type:         providerpb.WatchEventType_WATCH_EVENT_TYPE_UPDATE,
+		Version:      "2-0",
+		Flags:        map[string]*providerpb.GetFlagResponse{"new-checkout": boolFlag(false)},
+		RemovedFlags: []string{"dark-mode"},
+	}
+
+	received := map[string]flaggerclient.FlagChange{}
+	for range 2 {
+		change := receiveChange(t, changes)
+		received[change.Name] = change
+	}
+
+	if received["new-checkout"].Current.GetVariation() != "off" {
+		t.Errorf("unexpected change of new-checkout %v", received["new-checkout"])
+	}
+
+	if removed := received["dark-mode"]; removed.Previous == nil || removed.Current != nil {
+		t.Errorf("unexpected change of dark-mode %v", removed)
+	}
+
+	if client.Bool("new-checkout", true) {
+		t.Error("expected new-checkout to be false after the update")
+	}
+
+	if _, ok := client.Flag("dark-mode"); ok {
+		t.Error("expected dark-mode to be removed")
+	}
+}
+
+func receiveChange(
+	t *testing.T,
+	changes <-chan flaggerclient.FlagChange,
+) flaggerclient.FlagChange {
+	t.Helper()
+
+	select {
+	case change := <-changes:
+		return change
+	case <-time.After(testTimeout):
+		t.Fatal("timed out waiting for a change")
+	}
+
+	return flaggerclient.FlagChange{}
+}
@@ -0,0 +1,376 @@
+// Package ofprovider implements an OpenFeature provider that evaluates flags
+// with the FlagProvider GRPC service of flagger.
+package ofprovider
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/open-feature/go-sdk/openfeature"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/structpb"
+
+	"github.com/waduhek/flagger/proto/providerpb"
+)
+
+// ProviderName is the name of the provider reported in its metadata.
+const ProviderName = "flagger"
+
+// projectTokenMetadataKey is the metadata key that the project key is sent in.
+//
+//nolint:gosec // This isn't a secret but a header key.
+const projectTokenMetadataKey = "x-flagger-token"
+
+// Metadata keys of the flag metadata of the resolved flags.
+const (
+	// VersionMetadataKey is the key of the version of the setting of the flag
+	// that was evaluated.
+	VersionMetadataKey = "version"
+	// CachedMetadataKey is the key of whether the setting of the flag was read
+	// from the cache of the server.
+	CachedMetadataKey = "cached"
+)
+
+// StaleReason is the reason of a flag that was resolved with the last known
+// good setting of the flag as the server could not read its current setting.
+const StaleReason openfeature.Reason = "STALE"
+
+// Provider is an OpenFeature provider that evaluates the flags of a project in
+// an environment with the FlagProvider GRPC service.
+type Provider struct {
+	client      providerpb.FlagProviderClient
+	projectKey  string
+	environment string
+}
+
+var _ openfeature.FeatureProvider = (*Provider)(nil)
+
+// NewProvider creates a provider that evaluates the flags of the project with
+// the provided key in the environment over the GRPC connection.
+func NewProvider(
+	conn grpc.ClientConnInterface,
+	projectKey string,
+	environment string,
+) *Provider {
+	return &Provider{
+		client:      providerpb.NewFlagProviderClient(conn),
+		projectKey:  projectKey,
+		environment: environment,
+	}
+}
+
+func (p *Provider) Metadata() openfeature.Metadata {
+	return openfeature.Metadata{Name: ProviderName}
+}
+
+func (p *Provider) Hooks() []openfeature.Hook {
+	return []openfeature.Hook{}
+}
+
+func (p *Provider) BooleanEvaluation(
+	ctx context.Context,
+	flag string,
+	defaultValue bool,
+	flatCtx openfeature.FlattenedContext,
+) openfeature.BoolResolutionDetail {
+	response, detail := p.evaluate(ctx, flag, flatCtx)
+	if detail.Error() != nil {
+		return openfeature.BoolResolutionDetail{
+			Value:                    defaultValue,
+			ProviderResolutionDetail: detail,
+		}
+	}
+
+	value, ok := response.GetValue().(*providerpb.GetFlagResponse_BoolValue)
+	if !ok {
+		return openfeature.BoolResolutionDetail{
+			Value:                    defaultValue,
+			ProviderResolutionDetail: typeMismatch(flag, "boolean"),
+		}
+	}
+
+	return openfeature.BoolResolutionDetail{
+		Value:                    value.BoolValue,
+		ProviderResolutionDetail: detail,
+	}
+}
+
+func (p *Provider) StringEvaluation(
+	ctx context.Context,
+	flag string,
+	defaultValue string,
+	flatCtx openfeature.FlattenedContext,
+) openfeature.StringResolutionDetail {
+	response, detail := p.evaluate(ctx, flag, flatCtx)
+	if detail.Error() != nil {
+		return openfeature.StringResolutionDetail{
+			Value:                    defaultValue,
+			ProviderResolutionDetail: detail,
+		}
+	}
+
+	value, ok := response.GetValue().(*providerpb.GetFlagResponse_StringValue)
+	if !ok {
+		return openfeature.StringResolutionDetail{
+			Value:                    defaultValue,
+			ProviderResolutionDetail: typeMismatch(flag, "string"),
+		}
+	}
+
+	return openfeature.StringResolutionDetail{
+		Value:                    value.StringValue,
+		ProviderResolutionDetail: detail,
+	}
+}
+
+func (p *Provider) FloatEvaluation(
+	ctx context.Context,
+	flag string,
+	defaultValue float64,
+	flatCtx openfeature.FlattenedContext,
+) openfeature.FloatResolutionDetail {
+	response, detail := p.evaluate(ctx, flag, flatCtx)
+	if detail.Error() != nil {
+		return openfeature.FloatResolutionDetail{
+			Value:                    defaultValue,
+			ProviderResolutionDetail: detail,
+		}
+	}
+
+	value, ok := response.GetValue().(*providerpb.GetFlagResponse_NumberValue)
+	if !ok {
+		return openfeature.FloatResolutionDetail{
+			Value:                    defaultValue,
+			ProviderResolutionDetail: typeMismatch(flag, "number"),
+		}
+	}
+
+	return openfeature.FloatResolutionDetail{
+		Value:                    value.NumberValue,
+		ProviderResolutionDetail: detail,
+	}
+}
+
+func (p *Provider) IntEvaluation(
+	ctx context.Context,
+	flag string,
+	defaultValue int64,
+	flatCtx openfeature.FlattenedContext,
+) openfeature.IntResolutionDetail {
+	response, detail := p.evaluate(ctx, flag, flatCtx)
+	if detail.Error() != nil {
+		return openfeature.IntResolutionDetail{
+			Value:                    defaultValue,
+			ProviderResolutionDetail: detail,
+		}
+	}
+
+	// Number flags are served as floating point numbers, so only the ones
+	// without a fractional part that are in the range of int64 can be
+	// resolved as integers. math.MaxInt64 is rounded up to 2^63 as a floating
+	// point number, which is out of the range.
+	value, ok := response.GetValue().(*providerpb.GetFlagResponse_NumberValue)
+	if !ok || value.NumberValue != math.Trunc(value.NumberValue) ||
+		value.NumberValue < math.MinInt64 || value.NumberValue >= math.MaxInt64 {
+		return openfeature.IntResolutionDetail{
+			Value:                    defaultValue,
+			ProviderResolutionDetail: typeMismatch(flag, "integer"),
+		}
+	}
+
+	return openfeature.IntResolutionDetail{
+		Value:                    int64(value.NumberValue),
+		ProviderResolutionDetail: detail,
+	}
+}
+
+func (p *Provider) ObjectEvaluation(
+	ctx context.Context,
+	flag string,
+	defaultValue any,
+	flatCtx openfeature.FlattenedContext,
+) openfeature.InterfaceResolutionDetail {
+	response, detail := p.evaluate(ctx, flag, flatCtx)
+	if detail.Error() != nil {
+		return openfeature.InterfaceResolutionDetail{
+			Value:                    defaultValue,
+			ProviderResolutionDetail: detail,
+		}
+	}
+
+	value, ok := response.GetValue().(*providerpb.GetFlagResponse_JsonValue)
+	if !ok {
+		return openfeature.InterfaceResolutionDetail{
+			Value:                    defaultValue,
+			ProviderResolutionDetail: typeMismatch(flag, "object"),
+		}
+	}
+
+	return openfeature.InterfaceResolutionDetail{
+		Value:                    value.JsonValue.AsInterface(),
+		ProviderResolutionDetail: detail,
+	}
+}
+
+// evaluate evaluates the flag with the GRPC service. The resolution detail
+// contains a resolution error if the flag could not be evaluated.
+func (p *Provider) evaluate(
+	ctx context.Context,
+	flag string,
+	flatCtx openfeature.FlattenedContext,
+) (*providerpb.GetFlagResponse, openfeature.ProviderResolutionDetail) {
+	evalCtx, err := newEvaluationContext(flatCtx)
+	if err != nil {
+		return nil, openfeature.ProviderResolutionDetail{
+			ResolutionError: openfeature.NewInvalidContextResolutionError(err.Error()),
+			Reason:          openfeature.ErrorReason,
+		}
+	}
+
+	ctx = metadata.AppendToOutgoingContext(ctx, projectTokenMetadataKey, p.projectKey)
+
+	response, err := p.client.GetFlag(ctx, &providerpb.GetFlagRequest{
+		FlagName:    flag,
+		Environment: p.environment,
+		Context:     evalCtx,
+	})
+	if err != nil {
+		return nil, openfeature.ProviderResolutionDetail{
+			ResolutionError: resolutionErrorFromStatus(err),
+			Reason:          openfeature.ErrorReason,
+		}
+	}
+
+	detail := openfeature.ProviderResolutionDetail{
+		Reason:  reasonFromProto(response.GetReason()),
+		Variant: response.GetVariation(),
+		FlagMetadata: openfeature.FlagMetadata{
+			VersionMetadataKey: response.GetVersion(),
+			CachedMetadataKey:  response.GetCached(),
+		},
+	}
+
+	if response.GetErrorCode() != providerpb.ErrorCode_ERROR_CODE_UNSPECIFIED {
+		detail.ResolutionError = resolutionErrorFromProto(
+			response.GetErrorCode(),
+			response.GetErrorMessage(),
+		)
+		detail.Reason = openfeature.ErrorReason
+	}
+
+	return response, detail
+}
+
+// newEvaluationContext maps an OpenFeature evaluation context to the
+// evaluation context of a request. Time attributes are sent as RFC 3339
+// timestamps.
+func newEvaluationContext(
+	flatCtx openfeature.FlattenedContext,
+) (*providerpb.EvaluationContext, error) {
+	evalCtx := &providerpb.EvaluationContext{
+		Attributes: &structpb.Struct{Fields: map[string]*structpb.Value{}},
+	}
+
+	for key, value := range flatCtx {
+		if key == openfeature.TargetingKey {
+			targetingKey, ok := value.(string)
+			if !ok {
+				return nil, fmt.Errorf("targeting key must be a string, got %T", value)
+			}
+
+			evalCtx.TargetingKey = targetingKey
+
+			continue
+		}
+
+		if timeValue, ok := value.(time.Time); ok {
+			value = timeValue.Format(time.RFC3339)
+		}
+
+		attribute, err := structpb.NewValue(value)
+		if err != nil {
+			return nil, fmt.Errorf("unsupported value for attribute %q: %w", key, err)
+		}
+
+		evalCtx.Attributes.Fields[key] = attribute
+	}
+
+	return evalCtx, nil
+}
+
+// reasonFromProto maps the reason of a response to an OpenFeature reason.
+func reasonFromProto(reason providerpb.Reason) openfeature.Reason {
+	switch reason {
+	case providerpb.Reason_REASON_STATIC:
+		return openfeature.StaticReason
+	case providerpb.Reason_REASON_DEFAULT:
+		return openfeature.DefaultReason
+	case providerpb.Reason_REASON_TARGETING_MATCH:
+		return openfeature.TargetingMatchReason
+	case providerpb.Reason_REASON_SPLIT:
+		return openfeature.SplitReason
+	case providerpb.Reason_REASON_DISABLED:
+		return openfeature.DisabledReason
+	case providerpb.Reason_REASON_ERROR:
+		return openfeature.ErrorReason
+	case providerpb.Reason_REASON_STALE:
+		return StaleReason
+	case providerpb.Reason_REASON_UNKNOWN:
+		return openfeature.UnknownReason
+	default:
+		return openfeature.UnknownReason
+	}
+}
+
+// resolutionErrorFromProto maps the error code of a response to an OpenFeature
+// resolution error.
+func resolutionErrorFromProto(
+	code providerpb.ErrorCode,
+	message string,
+) openfeature.ResolutionError {
+	switch code {
+	case providerpb.ErrorCode_ERROR_CODE_FLAG_NOT_FOUND:
+		return openfeature.NewFlagNotFoundResolutionError(message)
+	case providerpb.ErrorCode_ERROR_CODE_PARSE_ERROR:
+		return openfeature.NewParseErrorResolutionError(message)
+	case providerpb.ErrorCode_ERROR_CODE_TYPE_MISMATCH:
+		return openfeature.NewTypeMismatchResolutionError(message)
+	case providerpb.ErrorCode_ERROR_CODE_GENERAL,
+		providerpb.ErrorCode_ERROR_CODE_UNSPECIFIED:
+		return openfeature.NewGeneralResolutionError(message)
+	default:
+		return openfeature.NewGeneralResolutionError(message)
+	}
+}
+
+// resolutionErrorFromStatus maps the GRPC error of a failed request to an
+// OpenFeature resolution error.
+func resolutionErrorFromStatus(err error) openfeature.ResolutionError {
+	grpcStatus := status.Convert(err)
+
+	//nolint:exhaustive // All the other codes are general errors.
+	switch grpcStatus.Code() {
+	case codes.NotFound:
+		return openfeature.NewFlagNotFoundResolutionError(grpcStatus.Message())
+	case codes.InvalidArgument:
+		return openfeature.NewInvalidContextResolutionError(grpcStatus.Message())
+	default:
+		return openfeature.NewGeneralResolutionError(grpcStatus.Message(), err)
+	}
+}
+
+// typeMismatch creates the resolution detail of a flag whose value does not
+// have the requested type.
+func typeMismatch(flag string, requestedType string) openfeature.ProviderResolutionDetail {
+	return openfeature.ProviderResolutionDetail{
+		ResolutionError: openfeature.NewTypeMismatchResolutionError(
+			fmt.Sprintf("flag %q is not a %s flag", flag, requestedType),
+		),
+		Reason: openfeature.ErrorReason,
+	}
+}
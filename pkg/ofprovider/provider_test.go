@@ -0,0 +1,418 @@
+package ofprovider_test
+
+import (
+	"context"
+	"math"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/open-feature/go-sdk/openfeature"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+	"google.golang.org/protobuf/types/known/structpb"
+
+	"github.com/waduhek/flagger/proto/providerpb"
+
+	"github.com/waduhek/flagger/pkg/ofprovider"
+)
+
+const (
+	testProjectKey  = "test-project-key"
+	testEnvironment = "production"
+)
+
+// unavailableFlagName is the name of the flag that the fake server fails to
+// evaluate with a GRPC error.
+const unavailableFlagName = "unavailable"
+
+// fakeFlagProviderServer serves the responses of the flags by their names and
+// records the last request that it received.
+type fakeFlagProviderServer struct {
+	providerpb.UnimplementedFlagProviderServer
+	responses   map[string]*providerpb.GetFlagResponse
+	lastRequest *providerpb.GetFlagRequest
+	lastToken   string
+}
+
+func (s *fakeFlagProviderServer) GetFlag(
+	ctx context.Context,
+	req *providerpb.GetFlagRequest,
+) (*providerpb.GetFlagResponse, error) {
+	s.lastRequest = req
+
+	md, _ := metadata.FromIncomingContext(ctx)
+	if tokens := md.Get("x-flagger-token"); len(tokens) == 1 {
+		s.lastToken = tokens[0]
+	}
+
+	if req.GetFlagName() == unavailableFlagName {
+		return nil, status.Error(codes.Unavailable, "service unavailable")
+	}
+
+	response, ok := s.responses[req.GetFlagName()]
+	if !ok {
+		return &providerpb.GetFlagResponse{
+			Reason:       providerpb.Reason_REASON_ERROR,
+			ErrorCode:    providerpb.ErrorCode_ERROR_CODE_FLAG_NOT_FOUND,
+			ErrorMessage: "flag not found",
+		}, nil
+	}
+
+	return response, nil
+}
+
+// newTestProvider starts an in-process server with the fake service and
+// creates a provider connected to it.
+func newTestProvider(
+	t *testing.T,
+	server *fakeFlagProviderServer,
+) *ofprovider.Provider {
+	t.Helper()
+
+	const bufferSize = 1024 * 1024
+
+	lis := bufconn.Listen(bufferSize)
+
+	grpcServer := grpc.NewServer()
+	providerpb.RegisterFlagProviderServer(grpcServer, server)
+
+	go func() {
+		_ = grpcServer.Serve(lis)
+	}()
+
+	conn, err := grpc.NewClient(
+		"passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return lis.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	if err != nil {
+		t.Fatalf("could not connect to in-process server: %v", err)
+	}
+
+	t.Cleanup(func() {
+		conn.Close()
+		grpcServer.Stop()
+	})
+
+	return ofprovider.NewProvider(conn, testProjectKey, testEnvironment)
+}
+
+func newFakeServer(t *testing.T) *fakeFlagProviderServer {
+	t.Helper()
+
+	jsonValue, err := structpb.NewValue(map[string]any{"theme": "dark"})
+	if err != nil {
+		t.Fatalf("could not create json value: %v", err)
+	}
+
+	return &fakeFlagProviderServer{
+		responses: map[string]*providerpb.GetFlagResponse{
+			"new-checkout": {
+				Status:    true,
+				Variation: "on",
+				Value:     &providerpb.GetFlagResponse_BoolValue{BoolValue: true},
+				Reason:    providerpb.Reason_REASON_TARGETING_MATCH,
+				Version:   4,
+				Cached:    true,
+			},
+			"banner-text": {
+				Status:    true,
+				Variation: "welcome",
+				Value:     &providerpb.GetFlagResponse_StringValue{StringValue: "Welcome"},
+				Reason:    providerpb.Reason_REASON_STATIC,
+			},
+			"max-items": {
+				Status:    true,
+				Variation: "ten",
+				Value:     &providerpb.GetFlagResponse_NumberValue{NumberValue: 10},
+				Reason:    providerpb.Reason_REASON_SPLIT,
+			},
+			"ratio": {
+				Status:    true,
+				Variation: "half",
+				Value:     &providerpb.GetFlagResponse_NumberValue{NumberValue: 0.5},
+				Reason:    providerpb.Reason_REASON_DEFAULT,
+			},
+			"settings": {
+				Status:    false,
+				Variation: "dark",
+				Value:     &providerpb.GetFlagResponse_JsonValue{JsonValue: jsonValue},
+				Reason:    providerpb.Reason_REASON_DISABLED,
+			},
+			"huge": {
+				Status:    true,
+				Variation: "huge",
+				Value:     &providerpb.GetFlagResponse_NumberValue{NumberValue: 1e300},
+			},
+			"overflow": {
+				Status:    true,
+				Variation: "overflow",
+				Value:     &providerpb.GetFlagResponse_NumberValue{NumberValue: math.Exp2(63)},
+			},
+			"kill-switch": {
+				Status:    true,
+				Variation: "on",
+				Value:     &providerpb.GetFlagResponse_BoolValue{BoolValue: true},
+				Reason:    providerpb.Reason_REASON_STALE,
+			},
+		},
+	}
+}
+
+func TestBooleanEvaluation(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 1*time.Second)
+	defer cancel()
+
+	server := newFakeServer(t)
+	provider := newTestProvider(t, server)
+
+	signedUpAt := time.Date(2024, 3, 1, 10, 0, 0, 0, time.UTC)
+
+	detail := provider.BooleanEvaluation(
+		ctx,
+		"new-checkout",
+		false,
+		openfeature.FlattenedContext{
+			openfeature.TargetingKey: "user-123",
+			"country":                "IN",
+			"age":                    27,
+			"signedUpAt":             signedUpAt,
+		},
+	)
+
+	if detail.Error() != nil {
+		t.Fatalf("did not expect resolution error: %v", detail.Error())
+	}
+
+	if !detail.Value || detail.Variant != "on" {
+		t.Fatalf("expected variant on with value true but got %q %v", detail.Variant, detail.Value)
+	}
+
+	if detail.Reason != openfeature.TargetingMatchReason {
+		t.Fatalf("expected reason %q but got %q", openfeature.TargetingMatchReason, detail.Reason)
+	}
+
+	if detail.FlagMetadata[ofprovider.VersionMetadataKey] != int64(4) ||
+		detail.FlagMetadata[ofprovider.CachedMetadataKey] != true {
+		t.Fatalf("expected version and cached flag metadata but got %v", detail.FlagMetadata)
+	}
+
+	if server.lastToken != testProjectKey {
+		t.Fatalf("expected project key %q to be sent but got %q", testProjectKey, server.lastToken)
+	}
+
+	request := server.lastRequest
+	if request.GetEnvironment() != testEnvironment ||
+		request.GetContext().GetTargetingKey() != "user-123" {
+		t.Fatalf("unexpected request %v", request)
+	}
+
+	attributes := request.GetContext().GetAttributes().AsMap()
+	if attributes["country"] != "IN" || attributes["age"] != float64(27) ||
+		attributes["signedUpAt"] != signedUpAt.Format(time.RFC3339) {
+		t.Fatalf("unexpected attributes %v", attributes)
+	}
+
+	if _, ok := attributes[openfeature.TargetingKey]; ok {
+		t.Fatal("did not expect the targeting key to be sent as an attribute")
+	}
+}
+
+func TestTypedEvaluations(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 1*time.Second)
+	defer cancel()
+
+	provider := newTestProvider(t, newFakeServer(t))
+
+	stringDetail := provider.StringEvaluation(ctx, "banner-text", "", nil)
+	if stringDetail.Value != "Welcome" || stringDetail.Reason != openfeature.StaticReason {
+		t.Fatalf("unexpected string resolution %+v", stringDetail)
+	}
+
+	floatDetail := provider.FloatEvaluation(ctx, "ratio", 0, nil)
+	if floatDetail.Value != 0.5 || floatDetail.Reason != openfeature.DefaultReason {
+		t.Fatalf("unexpected float resolution %+v", floatDetail)
+	}
+
+	intDetail := provider.IntEvaluation(ctx, "max-items", 0, nil)
+	if intDetail.Value != 10 || intDetail.Reason != openfeature.SplitReason {
+		t.Fatalf("unexpected int resolution %+v", intDetail)
+	}
+
+	objectDetail := provider.ObjectEvaluation(ctx, "settings", nil, nil)
+	if objectDetail.Reason != openfeature.DisabledReason {
+		t.Fatalf("expected reason %q but got %q", openfeature.DisabledReason, objectDetail.Reason)
+	}
+
+	object, ok := objectDetail.Value.(map[string]any)
+	if !ok || object["theme"] != "dark" {
+		t.Fatalf("unexpected object resolution %+v", objectDetail)
+	}
+}
+
+func TestEvaluationReasons(t *testing.T) {
+	provider := newTestProvider(t, newFakeServer(t))
+
+	evaluateBoolean := func(ctx context.Context, flagName string) openfeature.ProviderResolutionDetail {
+		return provider.BooleanEvaluation(ctx, flagName, false, nil).ProviderResolutionDetail
+	}
+	evaluateString := func(ctx context.Context, flagName string) openfeature.ProviderResolutionDetail {
+		return provider.StringEvaluation(ctx, flagName, "", nil).ProviderResolutionDetail
+	}
+	evaluateFloat := func(ctx context.Context, flagName string) openfeature.ProviderResolutionDetail {
+		return provider.FloatEvaluation(ctx, flagName, 0, nil).ProviderResolutionDetail
+	}
+	evaluateObject := func(ctx context.Context, flagName string) openfeature.ProviderResolutionDetail {
+		return provider.ObjectEvaluation(ctx, flagName, nil, nil).ProviderResolutionDetail
+	}
+
+	testCases := []struct {
+		FlagName       string
+		Evaluate       func(ctx context.Context, flagName string) openfeature.ProviderResolutionDetail
+		ExpectedReason openfeature.Reason
+	}{
+		{FlagName: "new-checkout", Evaluate: evaluateBoolean, ExpectedReason: openfeature.TargetingMatchReason},
+		{FlagName: "banner-text", Evaluate: evaluateString, ExpectedReason: openfeature.StaticReason},
+		{FlagName: "max-items", Evaluate: evaluateFloat, ExpectedReason: openfeature.SplitReason},
+		{FlagName: "ratio", Evaluate: evaluateFloat, ExpectedReason: openfeature.DefaultReason},
+		{FlagName: "settings", Evaluate: evaluateObject, ExpectedReason: openfeature.DisabledReason},
+		{FlagName: "kill-switch", Evaluate: evaluateBoolean, ExpectedReason: ofprovider.StaleReason},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.FlagName, func(t *testing.T) {
+			ctx, cancel := context.WithTimeout(context.Background(), 1*time.Second)
+			defer cancel()
+
+			detail := testCase.Evaluate(ctx, testCase.FlagName)
+			if detail.Error() != nil || detail.Reason != testCase.ExpectedReason {
+				t.Fatalf("expected reason %q but got %+v", testCase.ExpectedReason, detail)
+			}
+		})
+	}
+}
+
+func TestEvaluationErrors(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		Name     string
+		Evaluate func(ctx context.Context, provider *ofprovider.Provider) openfeature.ProviderResolutionDetail
+		Expected openfeature.ErrorCode
+	}{
+		{
+			Name: "flag_not_found",
+			Evaluate: func(ctx context.Context, provider *ofprovider.Provider) openfeature.ProviderResolutionDetail {
+				detail := provider.BooleanEvaluation(ctx, "missing", true, nil)
+				if !detail.Value {
+					t.Error("expected the default value to be returned")
+				}
+
+				return detail.ProviderResolutionDetail
+			},
+			Expected: openfeature.FlagNotFoundCode,
+		},
+		{
+			Name: "type_mismatch",
+			Evaluate: func(ctx context.Context, provider *ofprovider.Provider) openfeature.ProviderResolutionDetail {
+				return provider.StringEvaluation(ctx, "new-checkout", "", nil).ProviderResolutionDetail
+			},
+			Expected: openfeature.TypeMismatchCode,
+		},
+		{
+			Name: "fractional_integer",
+			Evaluate: func(ctx context.Context, provider *ofprovider.Provider) openfeature.ProviderResolutionDetail {
+				return provider.IntEvaluation(ctx, "ratio", 0, nil).ProviderResolutionDetail
+			},
+			Expected: openfeature.TypeMismatchCode,
+		},
+		{
+			Name: "integer_out_of_range",
+			Evaluate: func(ctx context.Context, provider *ofprovider.Provider) openfeature.ProviderResolutionDetail {
+				return provider.IntEvaluation(ctx, "huge", 0, nil).ProviderResolutionDetail
+			},
+			Expected: openfeature.TypeMismatchCode,
+		},
+		{
+			Name: "integer_of_2_pow_63",
+			Evaluate: func(ctx context.Context, provider *ofprovider.Provider) openfeature.ProviderResolutionDetail {
+				return provider.IntEvaluation(ctx, "overflow", 0, nil).ProviderResolutionDetail
+			},
+			Expected: openfeature.TypeMismatchCode,
+		},
+		{
+			Name: "server_error",
+			Evaluate: func(ctx context.Context, provider *ofprovider.Provider) openfeature.ProviderResolutionDetail {
+				return provider.BooleanEvaluation(ctx, unavailableFlagName, false, nil).ProviderResolutionDetail
+			},
+			Expected: openfeature.GeneralCode,
+		},
+		{
+			Name: "invalid_context",
+			Evaluate: func(ctx context.Context, provider *ofprovider.Provider) openfeature.ProviderResolutionDetail {
+				return provider.BooleanEvaluation(
+					ctx,
+					"new-checkout",
+					false,
+					openfeature.FlattenedContext{openfeature.TargetingKey: 123},
+				).ProviderResolutionDetail
+			},
+			Expected: openfeature.InvalidContextCode,
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.Name, func(t *testing.T) {
+			t.Parallel()
+
+			ctx, cancel := context.WithTimeout(context.Background(), 1*time.Second)
+			defer cancel()
+
+			provider := newTestProvider(t, newFakeServer(t))
+
+			detail := testCase.Evaluate(ctx, provider)
+			if detail.Reason != openfeature.ErrorReason {
+				t.Fatalf("expected reason %q but got %q", openfeature.ErrorReason, detail.Reason)
+			}
+
+			if code := detail.ResolutionDetail().ErrorCode; code != testCase.Expected {
+				t.Fatalf("expected error code %q but got %q", testCase.Expected, code)
+			}
+		})
+	}
+}
+
+func TestOpenFeatureClient(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 1*time.Second)
+	defer cancel()
+
+	provider := newTestProvider(t, newFakeServer(t))
+
+	if err := openfeature.SetNamedProviderAndWait(t.Name(), provider); err != nil {
+		t.Fatalf("could not set provider: %v", err)
+	}
+
+	client := openfeature.NewClient(t.Name())
+
+	value, err := client.BooleanValue(
+		ctx,
+		"new-checkout",
+		false,
+		openfeature.NewEvaluationContext("user-123", nil),
+	)
+	if err != nil || !value {
+		t.Fatalf("expected true without error but got %v, %v", value, err)
+	}
+
+	_, err = client.BooleanValue(ctx, "missing", false, openfeature.EvaluationContext{})
+	if err == nil {
+		t.Fatal("expected an error for a missing flag")
+	}
+}
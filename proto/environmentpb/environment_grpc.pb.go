@@ -20,6 +20,9 @@ const _ = grpc.SupportPackageIsVersion7
 
 const (
 	Environment_CreateEnvironment_FullMethodName = "/environmentpb.Environment/CreateEnvironment"
+	Environment_ListEnvironments_FullMethodName  = "/environmentpb.Environment/ListEnvironments"
+	Environment_DeleteEnvironment_FullMethodName = "/environmentpb.Environment/DeleteEnvironment"
+	Environment_RenameEnvironment_FullMethodName = "/environmentpb.Environment/RenameEnvironment"
 )
 
 // EnvironmentClient is the client API for Environment service.
@@ -28,6 +31,16 @@ const (
 type EnvironmentClient interface {
 	// CreateEnvironment creates a new environment for a project.
 	CreateEnvironment(ctx context.Context, in *CreateEnvironmentRequest, opts ...grpc.CallOption) (*CreateEnvironmentResponse, error)
+	// ListEnvironments returns a page of the environments of a project.
+	ListEnvironments(ctx context.Context, in *ListEnvironmentsRequest, opts ...grpc.CallOption) (*ListEnvironmentsResponse, error)
+	// DeleteEnvironment permanently deletes an environment of a project along
+	// with the settings of all the flags in the environment.
+	DeleteEnvironment(ctx context.Context, in *DeleteEnvironmentRequest, opts ...grpc.CallOption) (*DeleteEnvironmentResponse, error)
+	// RenameEnvironment changes the name and the display name of an
+	// environment. The flags are still served in the environment by its
+	// previous name until the alias period has passed, so that the clients can
+	// move to the new name.
+	RenameEnvironment(ctx context.Context, in *RenameEnvironmentRequest, opts ...grpc.CallOption) (*RenameEnvironmentResponse, error)
 }
 
 type environmentClient struct {
@@ -47,12 +60,49 @@ func (c *environmentClient) CreateEnvironment(ctx context.Context, in *CreateEnv
 	return out, nil
 }
 
+func (c *environmentClient) ListEnvironments(ctx context.Context, in *ListEnvironmentsRequest, opts ...grpc.CallOption) (*ListEnvironmentsResponse, error) {
+	out := new(ListEnvironmentsResponse)
+	err := c.cc.Invoke(ctx, Environment_ListEnvironments_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *environmentClient) DeleteEnvironment(ctx context.Context, in *DeleteEnvironmentRequest, opts ...grpc.CallOption) (*DeleteEnvironmentResponse, error) {
+	out := new(DeleteEnvironmentResponse)
+	err := c.cc.Invoke(ctx, Environment_DeleteEnvironment_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *environmentClient) RenameEnvironment(ctx context.Context, in *RenameEnvironmentRequest, opts ...grpc.CallOption) (*RenameEnvironmentResponse, error) {
+	out := new(RenameEnvironmentResponse)
+	err := c.cc.Invoke(ctx, Environment_RenameEnvironment_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // EnvironmentServer is the server API for Environment service.
 // All implementations must embed UnimplementedEnvironmentServer
 // for forward compatibility
 type EnvironmentServer interface {
 	// CreateEnvironment creates a new environment for a project.
 	CreateEnvironment(context.Context, *CreateEnvironmentRequest) (*CreateEnvironmentResponse, error)
+	// ListEnvironments returns a page of the environments of a project.
+	ListEnvironments(context.Context, *ListEnvironmentsRequest) (*ListEnvironmentsResponse, error)
+	// DeleteEnvironment permanently deletes an environment of a project along
+	// with the settings of all the flags in the environment.
+	DeleteEnvironment(context.Context, *DeleteEnvironmentRequest) (*DeleteEnvironmentResponse, error)
+	// RenameEnvironment changes the name and the display name of an
+	// environment. The flags are still served in the environment by its
+	// previous name until the alias period has passed, so that the clients can
+	// move to the new name.
+	RenameEnvironment(context.Context, *RenameEnvironmentRequest) (*RenameEnvironmentResponse, error)
 	mustEmbedUnimplementedEnvironmentServer()
 }
 
@@ -63,6 +113,15 @@ type UnimplementedEnvironmentServer struct {
 func (UnimplementedEnvironmentServer) CreateEnvironment(context.Context, *CreateEnvironmentRequest) (*CreateEnvironmentResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method CreateEnvironment not implemented")
 }
+func (UnimplementedEnvironmentServer) ListEnvironments(context.Context, *ListEnvironmentsRequest) (*ListEnvironmentsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListEnvironments not implemented")
+}
+func (UnimplementedEnvironmentServer) DeleteEnvironment(context.Context, *DeleteEnvironmentRequest) (*DeleteEnvironmentResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteEnvironment not implemented")
+}
+func (UnimplementedEnvironmentServer) RenameEnvironment(context.Context, *RenameEnvironmentRequest) (*RenameEnvironmentResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RenameEnvironment not implemented")
+}
 func (UnimplementedEnvironmentServer) mustEmbedUnimplementedEnvironmentServer() {}
 
 // UnsafeEnvironmentServer may be embedded to opt out of forward compatibility for this service.
@@ -94,6 +153,60 @@ func _Environment_CreateEnvironment_Handler(srv interface{}, ctx context.Context
 	return interceptor(ctx, in, info, handler)
 }
 
+func _Environment_ListEnvironments_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListEnvironmentsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(EnvironmentServer).ListEnvironments(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Environment_ListEnvironments_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(EnvironmentServer).ListEnvironments(ctx, req.(*ListEnvironmentsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Environment_DeleteEnvironment_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeleteEnvironmentRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(EnvironmentServer).DeleteEnvironment(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Environment_DeleteEnvironment_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(EnvironmentServer).DeleteEnvironment(ctx, req.(*DeleteEnvironmentRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Environment_RenameEnvironment_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RenameEnvironmentRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(EnvironmentServer).RenameEnvironment(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Environment_RenameEnvironment_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(EnvironmentServer).RenameEnvironment(ctx, req.(*RenameEnvironmentRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // Environment_ServiceDesc is the grpc.ServiceDesc for Environment service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -105,6 +218,18 @@ var Environment_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "CreateEnvironment",
 			Handler:    _Environment_CreateEnvironment_Handler,
 		},
+		{
+			MethodName: "ListEnvironments",
+			Handler:    _Environment_ListEnvironments_Handler,
+		},
+		{
+			MethodName: "DeleteEnvironment",
+			Handler:    _Environment_DeleteEnvironment_Handler,
+		},
+		{
+			MethodName: "RenameEnvironment",
+			Handler:    _Environment_RenameEnvironment_Handler,
+		},
 	},
 	Streams:  []grpc.StreamDesc{},
 	Metadata: "proto/environmentpb/environment.proto",
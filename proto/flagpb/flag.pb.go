@@ -9,6 +9,8 @@ package flagpb
 import (
 	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
 	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	structpb "google.golang.org/protobuf/types/known/structpb"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
 	reflect "reflect"
 	sync "sync"
 )
@@ -20,20 +22,355 @@ const (
 	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
 )
 
-// CreateFlagRequest is the request body for creating a new flag.
-type CreateFlagRequest struct {
+// FlagType is the type of the value that a flag serves.
+type FlagType int32
+
+const (
+	// The flag serves a boolean value.
+	FlagType_FLAG_TYPE_BOOLEAN FlagType = 0
+	// The flag serves a string value.
+	FlagType_FLAG_TYPE_STRING FlagType = 1
+	// The flag serves a numeric value.
+	FlagType_FLAG_TYPE_NUMBER FlagType = 2
+	// The flag serves an arbitrary JSON value.
+	FlagType_FLAG_TYPE_JSON FlagType = 3
+)
+
+// Enum value maps for FlagType.
+var (
+	FlagType_name = map[int32]string{
+		0: "FLAG_TYPE_BOOLEAN",
+		1: "FLAG_TYPE_STRING",
+		2: "FLAG_TYPE_NUMBER",
+		3: "FLAG_TYPE_JSON",
+	}
+	FlagType_value = map[string]int32{
+		"FLAG_TYPE_BOOLEAN": 0,
+		"FLAG_TYPE_STRING":  1,
+		"FLAG_TYPE_NUMBER":  2,
+		"FLAG_TYPE_JSON":    3,
+	}
+)
+
+func (x FlagType) Enum() *FlagType {
+	p := new(FlagType)
+	*p = x
+	return p
+}
+
+func (x FlagType) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (FlagType) Descriptor() protoreflect.EnumDescriptor {
+	return file_proto_flagpb_flag_proto_enumTypes[0].Descriptor()
+}
+
+func (FlagType) Type() protoreflect.EnumType {
+	return &file_proto_flagpb_flag_proto_enumTypes[0]
+}
+
+func (x FlagType) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use FlagType.Descriptor instead.
+func (FlagType) EnumDescriptor() ([]byte, []int) {
+	return file_proto_flagpb_flag_proto_rawDescGZIP(), []int{0}
+}
+
+// FlagKind is the purpose of a flag.
+type FlagKind int32
+
+const (
+	// Defaults to a release flag.
+	FlagKind_FLAG_KIND_UNSPECIFIED FlagKind = 0
+	// The flag releases a feature gradually and is removed once the feature
+	// is fully released.
+	FlagKind_FLAG_KIND_RELEASE FlagKind = 1
+	// The flag runs an experiment between its variations.
+	FlagKind_FLAG_KIND_EXPERIMENT FlagKind = 2
+	// The flag controls the operation of the system, such as a kill switch.
+	FlagKind_FLAG_KIND_OPS FlagKind = 3
+	// The flag grants access to a feature to some of the users.
+	FlagKind_FLAG_KIND_PERMISSION FlagKind = 4
+)
+
+// Enum value maps for FlagKind.
+var (
+	FlagKind_name = map[int32]string{
+		0: "FLAG_KIND_UNSPECIFIED",
+		1: "FLAG_KIND_RELEASE",
+		2: "FLAG_KIND_EXPERIMENT",
+		3: "FLAG_KIND_OPS",
+		4: "FLAG_KIND_PERMISSION",
+	}
+	FlagKind_value = map[string]int32{
+		"FLAG_KIND_UNSPECIFIED": 0,
+		"FLAG_KIND_RELEASE":     1,
+		"FLAG_KIND_EXPERIMENT":  2,
+		"FLAG_KIND_OPS":         3,
+		"FLAG_KIND_PERMISSION":  4,
+	}
+)
+
+func (x FlagKind) Enum() *FlagKind {
+	p := new(FlagKind)
+	*p = x
+	return p
+}
+
+func (x FlagKind) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (FlagKind) Descriptor() protoreflect.EnumDescriptor {
+	return file_proto_flagpb_flag_proto_enumTypes[1].Descriptor()
+}
+
+func (FlagKind) Type() protoreflect.EnumType {
+	return &file_proto_flagpb_flag_proto_enumTypes[1]
+}
+
+func (x FlagKind) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use FlagKind.Descriptor instead.
+func (FlagKind) EnumDescriptor() ([]byte, []int) {
+	return file_proto_flagpb_flag_proto_rawDescGZIP(), []int{1}
+}
+
+// OwnerType is the type of the owner of a flag.
+type OwnerType int32
+
+const (
+	OwnerType_OWNER_TYPE_UNSPECIFIED OwnerType = 0
+	// The flag is owned by a user of Flagger.
+	OwnerType_OWNER_TYPE_USER OwnerType = 1
+	// The flag is owned by a team.
+	OwnerType_OWNER_TYPE_TEAM OwnerType = 2
+)
+
+// Enum value maps for OwnerType.
+var (
+	OwnerType_name = map[int32]string{
+		0: "OWNER_TYPE_UNSPECIFIED",
+		1: "OWNER_TYPE_USER",
+		2: "OWNER_TYPE_TEAM",
+	}
+	OwnerType_value = map[string]int32{
+		"OWNER_TYPE_UNSPECIFIED": 0,
+		"OWNER_TYPE_USER":        1,
+		"OWNER_TYPE_TEAM":        2,
+	}
+)
+
+func (x OwnerType) Enum() *OwnerType {
+	p := new(OwnerType)
+	*p = x
+	return p
+}
+
+func (x OwnerType) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (OwnerType) Descriptor() protoreflect.EnumDescriptor {
+	return file_proto_flagpb_flag_proto_enumTypes[2].Descriptor()
+}
+
+func (OwnerType) Type() protoreflect.EnumType {
+	return &file_proto_flagpb_flag_proto_enumTypes[2]
+}
+
+func (x OwnerType) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use OwnerType.Descriptor instead.
+func (OwnerType) EnumDescriptor() ([]byte, []int) {
+	return file_proto_flagpb_flag_proto_rawDescGZIP(), []int{2}
+}
+
+// RuleOperator is the comparison performed by a condition between an attribute
+// of the evaluation context and the values of the condition.
+type RuleOperator int32
+
+const (
+	RuleOperator_RULE_OPERATOR_UNSPECIFIED RuleOperator = 0
+	// The attribute is equal to the value.
+	RuleOperator_RULE_OPERATOR_EQUALS RuleOperator = 1
+	// The attribute is not equal to the value.
+	RuleOperator_RULE_OPERATOR_NOT_EQUALS RuleOperator = 2
+	// The attribute is one of the values.
+	RuleOperator_RULE_OPERATOR_IN RuleOperator = 3
+	// The attribute is none of the values.
+	RuleOperator_RULE_OPERATOR_NOT_IN RuleOperator = 4
+	// The string attribute contains any of the values, or the list attribute
+	// has any of the values as an element.
+	RuleOperator_RULE_OPERATOR_CONTAINS RuleOperator = 5
+	// The attribute starts with the value.
+	RuleOperator_RULE_OPERATOR_STARTS_WITH RuleOperator = 6
+	// The attribute ends with the value.
+	RuleOperator_RULE_OPERATOR_ENDS_WITH RuleOperator = 7
+	// The attribute matches the regular expression in the value.
+	RuleOperator_RULE_OPERATOR_MATCHES RuleOperator = 8
+	// The numeric attribute is less than the value.
+	RuleOperator_RULE_OPERATOR_LESS_THAN RuleOperator = 9
+	// The numeric attribute is less than or equal to the value.
+	RuleOperator_RULE_OPERATOR_LESS_THAN_OR_EQUAL RuleOperator = 10
+	// The numeric attribute is greater than the value.
+	RuleOperator_RULE_OPERATOR_GREATER_THAN RuleOperator = 11
+	// The numeric attribute is greater than or equal to the value.
+	RuleOperator_RULE_OPERATOR_GREATER_THAN_OR_EQUAL RuleOperator = 12
+	// The semantic version attribute is equal to the value.
+	RuleOperator_RULE_OPERATOR_SEMVER_EQUAL RuleOperator = 13
+	// The semantic version attribute is lower than the value.
+	RuleOperator_RULE_OPERATOR_SEMVER_LESS_THAN RuleOperator = 14
+	// The semantic version attribute is higher than the value.
+	RuleOperator_RULE_OPERATOR_SEMVER_GREATER_THAN RuleOperator = 15
+	// The date attribute is before the RFC 3339 timestamp in the value.
+	RuleOperator_RULE_OPERATOR_BEFORE RuleOperator = 16
+	// The date attribute is after the RFC 3339 timestamp in the value.
+	RuleOperator_RULE_OPERATOR_AFTER RuleOperator = 17
+)
+
+// Enum value maps for RuleOperator.
+var (
+	RuleOperator_name = map[int32]string{
+		0:  "RULE_OPERATOR_UNSPECIFIED",
+		1:  "RULE_OPERATOR_EQUALS",
+		2:  "RULE_OPERATOR_NOT_EQUALS",
+		3:  "RULE_OPERATOR_IN",
+		4:  "RULE_OPERATOR_NOT_IN",
+		5:  "RULE_OPERATOR_CONTAINS",
+		6:  "RULE_OPERATOR_STARTS_WITH",
+		7:  "RULE_OPERATOR_ENDS_WITH",
+		8:  "RULE_OPERATOR_MATCHES",
+		9:  "RULE_OPERATOR_LESS_THAN",
+		10: "RULE_OPERATOR_LESS_THAN_OR_EQUAL",
+		11: "RULE_OPERATOR_GREATER_THAN",
+		12: "RULE_OPERATOR_GREATER_THAN_OR_EQUAL",
+		13: "RULE_OPERATOR_SEMVER_EQUAL",
+		14: "RULE_OPERATOR_SEMVER_LESS_THAN",
+		15: "RULE_OPERATOR_SEMVER_GREATER_THAN",
+		16: "RULE_OPERATOR_BEFORE",
+		17: "RULE_OPERATOR_AFTER",
+	}
+	RuleOperator_value = map[string]int32{
+		"RULE_OPERATOR_UNSPECIFIED":           0,
+		"RULE_OPERATOR_EQUALS":                1,
+		"RULE_OPERATOR_NOT_EQUALS":            2,
+		"RULE_OPERATOR_IN":                    3,
+		"RULE_OPERATOR_NOT_IN":                4,
+		"RULE_OPERATOR_CONTAINS":              5,
+		"RULE_OPERATOR_STARTS_WITH":           6,
+		"RULE_OPERATOR_ENDS_WITH":             7,
+		"RULE_OPERATOR_MATCHES":               8,
+		"RULE_OPERATOR_LESS_THAN":             9,
+		"RULE_OPERATOR_LESS_THAN_OR_EQUAL":    10,
+		"RULE_OPERATOR_GREATER_THAN":          11,
+		"RULE_OPERATOR_GREATER_THAN_OR_EQUAL": 12,
+		"RULE_OPERATOR_SEMVER_EQUAL":          13,
+		"RULE_OPERATOR_SEMVER_LESS_THAN":      14,
+		"RULE_OPERATOR_SEMVER_GREATER_THAN":   15,
+		"RULE_OPERATOR_BEFORE":                16,
+		"RULE_OPERATOR_AFTER":                 17,
+	}
+)
+
+func (x RuleOperator) Enum() *RuleOperator {
+	p := new(RuleOperator)
+	*p = x
+	return p
+}
+
+func (x RuleOperator) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (RuleOperator) Descriptor() protoreflect.EnumDescriptor {
+	return file_proto_flagpb_flag_proto_enumTypes[3].Descriptor()
+}
+
+func (RuleOperator) Type() protoreflect.EnumType {
+	return &file_proto_flagpb_flag_proto_enumTypes[3]
+}
+
+func (x RuleOperator) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use RuleOperator.Descriptor instead.
+func (RuleOperator) EnumDescriptor() ([]byte, []int) {
+	return file_proto_flagpb_flag_proto_rawDescGZIP(), []int{3}
+}
+
+// SortField is the field that a listing is sorted by. Listings with the same
+// value of the field are sorted by the time of creation.
+type SortField int32
+
+const (
+	// Sort by the name.
+	SortField_SORT_FIELD_NAME SortField = 0
+	// Sort by the time of creation.
+	SortField_SORT_FIELD_CREATED_AT SortField = 1
+)
+
+// Enum value maps for SortField.
+var (
+	SortField_name = map[int32]string{
+		0: "SORT_FIELD_NAME",
+		1: "SORT_FIELD_CREATED_AT",
+	}
+	SortField_value = map[string]int32{
+		"SORT_FIELD_NAME":       0,
+		"SORT_FIELD_CREATED_AT": 1,
+	}
+)
+
+func (x SortField) Enum() *SortField {
+	p := new(SortField)
+	*p = x
+	return p
+}
+
+func (x SortField) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (SortField) Descriptor() protoreflect.EnumDescriptor {
+	return file_proto_flagpb_flag_proto_enumTypes[4].Descriptor()
+}
+
+func (SortField) Type() protoreflect.EnumType {
+	return &file_proto_flagpb_flag_proto_enumTypes[4]
+}
+
+func (x SortField) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use SortField.Descriptor instead.
+func (SortField) EnumDescriptor() ([]byte, []int) {
+	return file_proto_flagpb_flag_proto_rawDescGZIP(), []int{4}
+}
+
+// FlagOwner is the user or the team that is responsible for a flag.
+type FlagOwner struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	// A unique name in the project for the flag.
-	FlagName string `protobuf:"bytes,1,opt,name=flag_name,json=flagName,proto3" json:"flag_name,omitempty"`
-	// The name of the project under which the flag is to be created.
-	ProjectName string `protobuf:"bytes,2,opt,name=project_name,json=projectName,proto3" json:"project_name,omitempty"`
+	// The type of the owner.
+	Type OwnerType `protobuf:"varint,1,opt,name=type,proto3,enum=flagpb.OwnerType" json:"type,omitempty"`
+	// The username of the user or the name of the team.
+	Name string `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
 }
 
-func (x *CreateFlagRequest) Reset() {
-	*x = CreateFlagRequest{}
+func (x *FlagOwner) Reset() {
+	*x = FlagOwner{}
 	if protoimpl.UnsafeEnabled {
 		mi := &file_proto_flagpb_flag_proto_msgTypes[0]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -41,13 +378,13 @@ func (x *CreateFlagRequest) Reset() {
 	}
 }
 
-func (x *CreateFlagRequest) String() string {
+func (x *FlagOwner) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*CreateFlagRequest) ProtoMessage() {}
+func (*FlagOwner) ProtoMessage() {}
 
-func (x *CreateFlagRequest) ProtoReflect() protoreflect.Message {
+func (x *FlagOwner) ProtoReflect() protoreflect.Message {
 	mi := &file_proto_flagpb_flag_proto_msgTypes[0]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -59,34 +396,40 @@ func (x *CreateFlagRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use CreateFlagRequest.ProtoReflect.Descriptor instead.
-func (*CreateFlagRequest) Descriptor() ([]byte, []int) {
+// Deprecated: Use FlagOwner.ProtoReflect.Descriptor instead.
+func (*FlagOwner) Descriptor() ([]byte, []int) {
 	return file_proto_flagpb_flag_proto_rawDescGZIP(), []int{0}
 }
 
-func (x *CreateFlagRequest) GetFlagName() string {
+func (x *FlagOwner) GetType() OwnerType {
 	if x != nil {
-		return x.FlagName
+		return x.Type
 	}
-	return ""
+	return OwnerType_OWNER_TYPE_UNSPECIFIED
 }
 
-func (x *CreateFlagRequest) GetProjectName() string {
+func (x *FlagOwner) GetName() string {
 	if x != nil {
-		return x.ProjectName
+		return x.Name
 	}
 	return ""
 }
 
-// CreateFlagResponse is the response for creating a new flag.
-type CreateFlagResponse struct {
+// Variation is a named value that a flag can serve.
+type Variation struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
+
+	// A unique key in the flag for the variation.
+	Key string `protobuf:"bytes,1,opt,name=key,proto3" json:"key,omitempty"`
+	// The value of the variation. The kind of the value must match the type of
+	// the flag.
+	Value *structpb.Value `protobuf:"bytes,2,opt,name=value,proto3" json:"value,omitempty"`
 }
 
-func (x *CreateFlagResponse) Reset() {
-	*x = CreateFlagResponse{}
+func (x *Variation) Reset() {
+	*x = Variation{}
 	if protoimpl.UnsafeEnabled {
 		mi := &file_proto_flagpb_flag_proto_msgTypes[1]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -94,13 +437,13 @@ func (x *CreateFlagResponse) Reset() {
 	}
 }
 
-func (x *CreateFlagResponse) String() string {
+func (x *Variation) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*CreateFlagResponse) ProtoMessage() {}
+func (*Variation) ProtoMessage() {}
 
-func (x *CreateFlagResponse) ProtoReflect() protoreflect.Message {
+func (x *Variation) ProtoReflect() protoreflect.Message {
 	mi := &file_proto_flagpb_flag_proto_msgTypes[1]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -112,29 +455,43 @@ func (x *CreateFlagResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use CreateFlagResponse.ProtoReflect.Descriptor instead.
-func (*CreateFlagResponse) Descriptor() ([]byte, []int) {
+// Deprecated: Use Variation.ProtoReflect.Descriptor instead.
+func (*Variation) Descriptor() ([]byte, []int) {
 	return file_proto_flagpb_flag_proto_rawDescGZIP(), []int{1}
 }
 
-// UpdateFlagStatusRequest is the request body to update the status of a flag.
-type UpdateFlagStatusRequest struct {
+func (x *Variation) GetKey() string {
+	if x != nil {
+		return x.Key
+	}
+	return ""
+}
+
+func (x *Variation) GetValue() *structpb.Value {
+	if x != nil {
+		return x.Value
+	}
+	return nil
+}
+
+// Condition compares an attribute of the evaluation context with the values
+// of the condition.
+type Condition struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	// The name of the project where the flag is created.
-	ProjectName string `protobuf:"bytes,1,opt,name=project_name,json=projectName,proto3" json:"project_name,omitempty"`
-	// The name of the environment in which the flag is to be updated.
-	EnvironmentName string `protobuf:"bytes,2,opt,name=environment_name,json=environmentName,proto3" json:"environment_name,omitempty"`
-	// The name of the flag to be updated.
-	FlagName string `protobuf:"bytes,3,opt,name=flag_name,json=flagName,proto3" json:"flag_name,omitempty"`
-	// The update to be made to the flag.
-	IsActive bool `protobuf:"varint,4,opt,name=is_active,json=isActive,proto3" json:"is_active,omitempty"`
+	// The name of the attribute. "targetingKey" refers to the targeting key of
+	// the evaluation context.
+	Attribute string `protobuf:"bytes,1,opt,name=attribute,proto3" json:"attribute,omitempty"`
+	// The comparison to perform.
+	Operator RuleOperator `protobuf:"varint,2,opt,name=operator,proto3,enum=flagpb.RuleOperator" json:"operator,omitempty"`
+	// The values to compare the attribute with.
+	Values []string `protobuf:"bytes,3,rep,name=values,proto3" json:"values,omitempty"`
 }
 
-func (x *UpdateFlagStatusRequest) Reset() {
-	*x = UpdateFlagStatusRequest{}
+func (x *Condition) Reset() {
+	*x = Condition{}
 	if protoimpl.UnsafeEnabled {
 		mi := &file_proto_flagpb_flag_proto_msgTypes[2]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -142,13 +499,13 @@ func (x *UpdateFlagStatusRequest) Reset() {
 	}
 }
 
-func (x *UpdateFlagStatusRequest) String() string {
+func (x *Condition) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*UpdateFlagStatusRequest) ProtoMessage() {}
+func (*Condition) ProtoMessage() {}
 
-func (x *UpdateFlagStatusRequest) ProtoReflect() protoreflect.Message {
+func (x *Condition) ProtoReflect() protoreflect.Message {
 	mi := &file_proto_flagpb_flag_proto_msgTypes[2]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -160,48 +517,47 @@ func (x *UpdateFlagStatusRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use UpdateFlagStatusRequest.ProtoReflect.Descriptor instead.
-func (*UpdateFlagStatusRequest) Descriptor() ([]byte, []int) {
+// Deprecated: Use Condition.ProtoReflect.Descriptor instead.
+func (*Condition) Descriptor() ([]byte, []int) {
 	return file_proto_flagpb_flag_proto_rawDescGZIP(), []int{2}
 }
 
-func (x *UpdateFlagStatusRequest) GetProjectName() string {
-	if x != nil {
-		return x.ProjectName
-	}
-	return ""
-}
-
-func (x *UpdateFlagStatusRequest) GetEnvironmentName() string {
+func (x *Condition) GetAttribute() string {
 	if x != nil {
-		return x.EnvironmentName
+		return x.Attribute
 	}
 	return ""
 }
 
-func (x *UpdateFlagStatusRequest) GetFlagName() string {
+func (x *Condition) GetOperator() RuleOperator {
 	if x != nil {
-		return x.FlagName
+		return x.Operator
 	}
-	return ""
+	return RuleOperator_RULE_OPERATOR_UNSPECIFIED
 }
 
-func (x *UpdateFlagStatusRequest) GetIsActive() bool {
+func (x *Condition) GetValues() []string {
 	if x != nil {
-		return x.IsActive
+		return x.Values
 	}
-	return false
+	return nil
 }
 
-// UpdateFlagStatusResponse is the response for updating a flag.
-type UpdateFlagStatusResponse struct {
+// Rule serves a variation when all of its conditions match the evaluation
+// context. A rule without any conditions matches every evaluation context.
+type Rule struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
+
+	// The conditions that must all match.
+	Conditions []*Condition `protobuf:"bytes,1,rep,name=conditions,proto3" json:"conditions,omitempty"`
+	// The key of the variation served when the rule matches.
+	Variation string `protobuf:"bytes,2,opt,name=variation,proto3" json:"variation,omitempty"`
 }
 
-func (x *UpdateFlagStatusResponse) Reset() {
-	*x = UpdateFlagStatusResponse{}
+func (x *Rule) Reset() {
+	*x = Rule{}
 	if protoimpl.UnsafeEnabled {
 		mi := &file_proto_flagpb_flag_proto_msgTypes[3]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -209,13 +565,13 @@ func (x *UpdateFlagStatusResponse) Reset() {
 	}
 }
 
-func (x *UpdateFlagStatusResponse) String() string {
+func (x *Rule) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*UpdateFlagStatusResponse) ProtoMessage() {}
+func (*Rule) ProtoMessage() {}
 
-func (x *UpdateFlagStatusResponse) ProtoReflect() protoreflect.Message {
+func (x *Rule) ProtoReflect() protoreflect.Message {
 	mi := &file_proto_flagpb_flag_proto_msgTypes[3]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -227,116 +583,2543 @@ func (x *UpdateFlagStatusResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use UpdateFlagStatusResponse.ProtoReflect.Descriptor instead.
-func (*UpdateFlagStatusResponse) Descriptor() ([]byte, []int) {
+// Deprecated: Use Rule.ProtoReflect.Descriptor instead.
+func (*Rule) Descriptor() ([]byte, []int) {
 	return file_proto_flagpb_flag_proto_rawDescGZIP(), []int{3}
 }
 
-var File_proto_flagpb_flag_proto protoreflect.FileDescriptor
+func (x *Rule) GetConditions() []*Condition {
+	if x != nil {
+		return x.Conditions
+	}
+	return nil
+}
 
-var file_proto_flagpb_flag_proto_rawDesc = []byte{
-	0x0a, 0x17, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2f, 0x66, 0x6c, 0x61, 0x67, 0x70, 0x62, 0x2f, 0x66,
-	0x6c, 0x61, 0x67, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x12, 0x06, 0x66, 0x6c, 0x61, 0x67, 0x70,
-	0x62, 0x22, 0x53, 0x0a, 0x11, 0x43, 0x72, 0x65, 0x61, 0x74, 0x65, 0x46, 0x6c, 0x61, 0x67, 0x52,
-	0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x1b, 0x0a, 0x09, 0x66, 0x6c, 0x61, 0x67, 0x5f, 0x6e,
-	0x61, 0x6d, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x66, 0x6c, 0x61, 0x67, 0x4e,
-	0x61, 0x6d, 0x65, 0x12, 0x21, 0x0a, 0x0c, 0x70, 0x72, 0x6f, 0x6a, 0x65, 0x63, 0x74, 0x5f, 0x6e,
-	0x61, 0x6d, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0b, 0x70, 0x72, 0x6f, 0x6a, 0x65,
-	0x63, 0x74, 0x4e, 0x61, 0x6d, 0x65, 0x22, 0x14, 0x0a, 0x12, 0x43, 0x72, 0x65, 0x61, 0x74, 0x65,
-	0x46, 0x6c, 0x61, 0x67, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0xa1, 0x01, 0x0a,
-	0x17, 0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x46, 0x6c, 0x61, 0x67, 0x53, 0x74, 0x61, 0x74, 0x75,
-	0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x21, 0x0a, 0x0c, 0x70, 0x72, 0x6f, 0x6a,
-	0x65, 0x63, 0x74, 0x5f, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0b,
-	0x70, 0x72, 0x6f, 0x6a, 0x65, 0x63, 0x74, 0x4e, 0x61, 0x6d, 0x65, 0x12, 0x29, 0x0a, 0x10, 0x65,
-	0x6e, 0x76, 0x69, 0x72, 0x6f, 0x6e, 0x6d, 0x65, 0x6e, 0x74, 0x5f, 0x6e, 0x61, 0x6d, 0x65, 0x18,
-	0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0f, 0x65, 0x6e, 0x76, 0x69, 0x72, 0x6f, 0x6e, 0x6d, 0x65,
-	0x6e, 0x74, 0x4e, 0x61, 0x6d, 0x65, 0x12, 0x1b, 0x0a, 0x09, 0x66, 0x6c, 0x61, 0x67, 0x5f, 0x6e,
-	0x61, 0x6d, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x66, 0x6c, 0x61, 0x67, 0x4e,
-	0x61, 0x6d, 0x65, 0x12, 0x1b, 0x0a, 0x09, 0x69, 0x73, 0x5f, 0x61, 0x63, 0x74, 0x69, 0x76, 0x65,
-	0x18, 0x04, 0x20, 0x01, 0x28, 0x08, 0x52, 0x08, 0x69, 0x73, 0x41, 0x63, 0x74, 0x69, 0x76, 0x65,
-	0x22, 0x1a, 0x0a, 0x18, 0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x46, 0x6c, 0x61, 0x67, 0x53, 0x74,
-	0x61, 0x74, 0x75, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x32, 0xa2, 0x01, 0x0a,
-	0x04, 0x46, 0x6c, 0x61, 0x67, 0x12, 0x43, 0x0a, 0x0a, 0x43, 0x72, 0x65, 0x61, 0x74, 0x65, 0x46,
-	0x6c, 0x61, 0x67, 0x12, 0x19, 0x2e, 0x66, 0x6c, 0x61, 0x67, 0x70, 0x62, 0x2e, 0x43, 0x72, 0x65,
-	0x61, 0x74, 0x65, 0x46, 0x6c, 0x61, 0x67, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1a,
-	0x2e, 0x66, 0x6c, 0x61, 0x67, 0x70, 0x62, 0x2e, 0x43, 0x72, 0x65, 0x61, 0x74, 0x65, 0x46, 0x6c,
-	0x61, 0x67, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x55, 0x0a, 0x10, 0x55, 0x70,
-	0x64, 0x61, 0x74, 0x65, 0x46, 0x6c, 0x61, 0x67, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x12, 0x1f,
-	0x2e, 0x66, 0x6c, 0x61, 0x67, 0x70, 0x62, 0x2e, 0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x46, 0x6c,
-	0x61, 0x67, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a,
-	0x20, 0x2e, 0x66, 0x6c, 0x61, 0x67, 0x70, 0x62, 0x2e, 0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x46,
-	0x6c, 0x61, 0x67, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73,
-	0x65, 0x42, 0x29, 0x5a, 0x27, 0x67, 0x69, 0x74, 0x68, 0x75, 0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2f,
-	0x77, 0x61, 0x64, 0x75, 0x68, 0x65, 0x6b, 0x2f, 0x66, 0x6c, 0x61, 0x67, 0x67, 0x65, 0x72, 0x2f,
-	0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2f, 0x66, 0x6c, 0x61, 0x67, 0x70, 0x62, 0x62, 0x06, 0x70, 0x72,
-	0x6f, 0x74, 0x6f, 0x33,
+func (x *Rule) GetVariation() string {
+	if x != nil {
+		return x.Variation
+	}
+	return ""
 }
 
-var (
-	file_proto_flagpb_flag_proto_rawDescOnce sync.Once
-	file_proto_flagpb_flag_proto_rawDescData = file_proto_flagpb_flag_proto_rawDesc
-)
+// WeightedVariation is a variation that is served to a share of the
+// evaluation contexts in a percentage rollout.
+type WeightedVariation struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
 
-func file_proto_flagpb_flag_proto_rawDescGZIP() []byte {
-	file_proto_flagpb_flag_proto_rawDescOnce.Do(func() {
-		file_proto_flagpb_flag_proto_rawDescData = protoimpl.X.CompressGZIP(file_proto_flagpb_flag_proto_rawDescData)
-	})
-	return file_proto_flagpb_flag_proto_rawDescData
+	// The key of the variation.
+	Variation string `protobuf:"bytes,1,opt,name=variation,proto3" json:"variation,omitempty"`
+	// The share of the evaluation contexts that are served the variation, out
+	// of 100000. A weight of 1000 serves the variation to 1% of the contexts.
+	Weight uint32 `protobuf:"varint,2,opt,name=weight,proto3" json:"weight,omitempty"`
 }
 
-var file_proto_flagpb_flag_proto_msgTypes = make([]protoimpl.MessageInfo, 4)
-var file_proto_flagpb_flag_proto_goTypes = []interface{}{
-	(*CreateFlagRequest)(nil),        // 0: flagpb.CreateFlagRequest
-	(*CreateFlagResponse)(nil),       // 1: flagpb.CreateFlagResponse
-	(*UpdateFlagStatusRequest)(nil),  // 2: flagpb.UpdateFlagStatusRequest
-	(*UpdateFlagStatusResponse)(nil), // 3: flagpb.UpdateFlagStatusResponse
+func (x *WeightedVariation) Reset() {
+	*x = WeightedVariation{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_proto_flagpb_flag_proto_msgTypes[4]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
 }
-var file_proto_flagpb_flag_proto_depIdxs = []int32{
-	0, // 0: flagpb.Flag.CreateFlag:input_type -> flagpb.CreateFlagRequest
-	2, // 1: flagpb.Flag.UpdateFlagStatus:input_type -> flagpb.UpdateFlagStatusRequest
-	1, // 2: flagpb.Flag.CreateFlag:output_type -> flagpb.CreateFlagResponse
-	3, // 3: flagpb.Flag.UpdateFlagStatus:output_type -> flagpb.UpdateFlagStatusResponse
-	2, // [2:4] is the sub-list for method output_type
-	0, // [0:2] is the sub-list for method input_type
-	0, // [0:0] is the sub-list for extension type_name
-	0, // [0:0] is the sub-list for extension extendee
-	0, // [0:0] is the sub-list for field type_name
+
+func (x *WeightedVariation) String() string {
+	return protoimpl.X.MessageStringOf(x)
 }
 
-func init() { file_proto_flagpb_flag_proto_init() }
-func file_proto_flagpb_flag_proto_init() {
-	if File_proto_flagpb_flag_proto != nil {
-		return
-	}
-	if !protoimpl.UnsafeEnabled {
-		file_proto_flagpb_flag_proto_msgTypes[0].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*CreateFlagRequest); i {
-			case 0:
-				return &v.state
-			case 1:
-				return &v.sizeCache
-			case 2:
-				return &v.unknownFields
-			default:
-				return nil
-			}
-		}
-		file_proto_flagpb_flag_proto_msgTypes[1].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*CreateFlagResponse); i {
-			case 0:
-				return &v.state
-			case 1:
-				return &v.sizeCache
-			case 2:
-				return &v.unknownFields
-			default:
-				return nil
-			}
+func (*WeightedVariation) ProtoMessage() {}
+
+func (x *WeightedVariation) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_flagpb_flag_proto_msgTypes[4]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
 		}
-		file_proto_flagpb_flag_proto_msgTypes[2].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*UpdateFlagStatusRequest); i {
-			case 0:
-				return &v.state
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WeightedVariation.ProtoReflect.Descriptor instead.
+func (*WeightedVariation) Descriptor() ([]byte, []int) {
+	return file_proto_flagpb_flag_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *WeightedVariation) GetVariation() string {
+	if x != nil {
+		return x.Variation
+	}
+	return ""
+}
+
+func (x *WeightedVariation) GetWeight() uint32 {
+	if x != nil {
+		return x.Weight
+	}
+	return 0
+}
+
+// Rollout is the percentage rollout of the variations served by a flag while
+// it is active. An evaluation context is assigned to a bucket by hashing the
+// salt of the flag with its targeting key. The name of the flag is not hashed,
+// so that renaming the flag keeps the assignments, which stay distinct per flag
+// as every flag has its own salt.
+type Rollout struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// The weighted variations of the rollout. The weights must add up to
+	// 100000. An empty rollout serves the same variation to every evaluation
+	// context.
+	Variations []*WeightedVariation `protobuf:"bytes,1,rep,name=variations,proto3" json:"variations,omitempty"`
+}
+
+func (x *Rollout) Reset() {
+	*x = Rollout{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_proto_flagpb_flag_proto_msgTypes[5]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Rollout) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Rollout) ProtoMessage() {}
+
+func (x *Rollout) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_flagpb_flag_proto_msgTypes[5]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Rollout.ProtoReflect.Descriptor instead.
+func (*Rollout) Descriptor() ([]byte, []int) {
+	return file_proto_flagpb_flag_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *Rollout) GetVariations() []*WeightedVariation {
+	if x != nil {
+		return x.Variations
+	}
+	return nil
+}
+
+// FlagDetails are the details of a flag.
+type FlagDetails struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// The name of the flag, which is the key that the clients request the flag
+	// with.
+	Name string `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	// The type of the value served by the flag.
+	FlagType FlagType `protobuf:"varint,2,opt,name=flag_type,json=flagType,proto3,enum=flagpb.FlagType" json:"flag_type,omitempty"`
+	// The variations that the flag can serve.
+	Variations []*Variation `protobuf:"bytes,3,rep,name=variations,proto3" json:"variations,omitempty"`
+	// The key of the variation served while the flag is active, unless an
+	// environment serves another variation.
+	DefaultVariation string `protobuf:"bytes,4,opt,name=default_variation,json=defaultVariation,proto3" json:"default_variation,omitempty"`
+	// The key of the variation served while the flag is inactive.
+	OffVariation string `protobuf:"bytes,5,opt,name=off_variation,json=offVariation,proto3" json:"off_variation,omitempty"`
+	// The time at which the flag was created.
+	CreatedAt *timestamppb.Timestamp `protobuf:"bytes,6,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	// Whether the flag is archived.
+	IsArchived bool `protobuf:"varint,7,opt,name=is_archived,json=isArchived,proto3" json:"is_archived,omitempty"`
+	// The key of the variation served in every environment while the flag is
+	// archived.
+	ArchivedVariation string `protobuf:"bytes,8,opt,name=archived_variation,json=archivedVariation,proto3" json:"archived_variation,omitempty"`
+	// The time at which the flag was archived. Unset if the flag is not
+	// archived.
+	ArchivedAt *timestamppb.Timestamp `protobuf:"bytes,9,opt,name=archived_at,json=archivedAt,proto3" json:"archived_at,omitempty"`
+	// The name of the flag that is shown to the users. Defaults to the name of
+	// the flag.
+	DisplayName string `protobuf:"bytes,10,opt,name=display_name,json=displayName,proto3" json:"display_name,omitempty"`
+	// What the flag does.
+	Description string `protobuf:"bytes,11,opt,name=description,proto3" json:"description,omitempty"`
+	// The user or the team that is responsible for the flag. Unset if the flag
+	// has no owner.
+	Owner *FlagOwner `protobuf:"bytes,12,opt,name=owner,proto3" json:"owner,omitempty"`
+	// The free-form tags of the flag, sorted.
+	Tags []string `protobuf:"bytes,13,rep,name=tags,proto3" json:"tags,omitempty"`
+	// The purpose of the flag.
+	Kind FlagKind `protobuf:"varint,14,opt,name=kind,proto3,enum=flagpb.FlagKind" json:"kind,omitempty"`
+}
+
+func (x *FlagDetails) Reset() {
+	*x = FlagDetails{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_proto_flagpb_flag_proto_msgTypes[6]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *FlagDetails) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*FlagDetails) ProtoMessage() {}
+
+func (x *FlagDetails) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_flagpb_flag_proto_msgTypes[6]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use FlagDetails.ProtoReflect.Descriptor instead.
+func (*FlagDetails) Descriptor() ([]byte, []int) {
+	return file_proto_flagpb_flag_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *FlagDetails) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *FlagDetails) GetFlagType() FlagType {
+	if x != nil {
+		return x.FlagType
+	}
+	return FlagType_FLAG_TYPE_BOOLEAN
+}
+
+func (x *FlagDetails) GetVariations() []*Variation {
+	if x != nil {
+		return x.Variations
+	}
+	return nil
+}
+
+func (x *FlagDetails) GetDefaultVariation() string {
+	if x != nil {
+		return x.DefaultVariation
+	}
+	return ""
+}
+
+func (x *FlagDetails) GetOffVariation() string {
+	if x != nil {
+		return x.OffVariation
+	}
+	return ""
+}
+
+func (x *FlagDetails) GetCreatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return nil
+}
+
+func (x *FlagDetails) GetIsArchived() bool {
+	if x != nil {
+		return x.IsArchived
+	}
+	return false
+}
+
+func (x *FlagDetails) GetArchivedVariation() string {
+	if x != nil {
+		return x.ArchivedVariation
+	}
+	return ""
+}
+
+func (x *FlagDetails) GetArchivedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.ArchivedAt
+	}
+	return nil
+}
+
+func (x *FlagDetails) GetDisplayName() string {
+	if x != nil {
+		return x.DisplayName
+	}
+	return ""
+}
+
+func (x *FlagDetails) GetDescription() string {
+	if x != nil {
+		return x.Description
+	}
+	return ""
+}
+
+func (x *FlagDetails) GetOwner() *FlagOwner {
+	if x != nil {
+		return x.Owner
+	}
+	return nil
+}
+
+func (x *FlagDetails) GetTags() []string {
+	if x != nil {
+		return x.Tags
+	}
+	return nil
+}
+
+func (x *FlagDetails) GetKind() FlagKind {
+	if x != nil {
+		return x.Kind
+	}
+	return FlagKind_FLAG_KIND_UNSPECIFIED
+}
+
+// FlagEnvironmentStatus is the setting of a flag in an environment.
+type FlagEnvironmentStatus struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// The name of the environment.
+	EnvironmentName string `protobuf:"bytes,1,opt,name=environment_name,json=environmentName,proto3" json:"environment_name,omitempty"`
+	// Whether the flag is active in the environment.
+	IsActive bool `protobuf:"varint,2,opt,name=is_active,json=isActive,proto3" json:"is_active,omitempty"`
+	// The key of the variation served while the flag is active.
+	Variation string `protobuf:"bytes,3,opt,name=variation,proto3" json:"variation,omitempty"`
+	// The targeting rules of the flag in the order they are evaluated.
+	Rules []*Rule `protobuf:"bytes,4,rep,name=rules,proto3" json:"rules,omitempty"`
+	// The percentage rollout of the variations served while the flag is
+	// active.
+	Rollout []*WeightedVariation `protobuf:"bytes,5,rep,name=rollout,proto3" json:"rollout,omitempty"`
+	// The version of the setting, which is incremented by every update.
+	Version int64 `protobuf:"varint,6,opt,name=version,proto3" json:"version,omitempty"`
+	// The time at which the setting was last updated.
+	UpdatedAt *timestamppb.Timestamp `protobuf:"bytes,7,opt,name=updated_at,json=updatedAt,proto3" json:"updated_at,omitempty"`
+}
+
+func (x *FlagEnvironmentStatus) Reset() {
+	*x = FlagEnvironmentStatus{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_proto_flagpb_flag_proto_msgTypes[7]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *FlagEnvironmentStatus) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*FlagEnvironmentStatus) ProtoMessage() {}
+
+func (x *FlagEnvironmentStatus) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_flagpb_flag_proto_msgTypes[7]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use FlagEnvironmentStatus.ProtoReflect.Descriptor instead.
+func (*FlagEnvironmentStatus) Descriptor() ([]byte, []int) {
+	return file_proto_flagpb_flag_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *FlagEnvironmentStatus) GetEnvironmentName() string {
+	if x != nil {
+		return x.EnvironmentName
+	}
+	return ""
+}
+
+func (x *FlagEnvironmentStatus) GetIsActive() bool {
+	if x != nil {
+		return x.IsActive
+	}
+	return false
+}
+
+func (x *FlagEnvironmentStatus) GetVariation() string {
+	if x != nil {
+		return x.Variation
+	}
+	return ""
+}
+
+func (x *FlagEnvironmentStatus) GetRules() []*Rule {
+	if x != nil {
+		return x.Rules
+	}
+	return nil
+}
+
+func (x *FlagEnvironmentStatus) GetRollout() []*WeightedVariation {
+	if x != nil {
+		return x.Rollout
+	}
+	return nil
+}
+
+func (x *FlagEnvironmentStatus) GetVersion() int64 {
+	if x != nil {
+		return x.Version
+	}
+	return 0
+}
+
+func (x *FlagEnvironmentStatus) GetUpdatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.UpdatedAt
+	}
+	return nil
+}
+
+// CreateFlagRequest is the request body for creating a new flag.
+type CreateFlagRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// A unique name in the project for the flag.
+	FlagName string `protobuf:"bytes,1,opt,name=flag_name,json=flagName,proto3" json:"flag_name,omitempty"`
+	// The name of the project under which the flag is to be created.
+	ProjectName string `protobuf:"bytes,2,opt,name=project_name,json=projectName,proto3" json:"project_name,omitempty"`
+	// The type of the value served by the flag. Defaults to a boolean flag.
+	FlagType FlagType `protobuf:"varint,3,opt,name=flag_type,json=flagType,proto3,enum=flagpb.FlagType" json:"flag_type,omitempty"`
+	// The variations that the flag can serve. Boolean flags that don't provide
+	// any variations get an "on" (true) and an "off" (false) variation.
+	Variations []*Variation `protobuf:"bytes,4,rep,name=variations,proto3" json:"variations,omitempty"`
+	// The key of the variation served in every environment while the flag is
+	// active. Defaults to the first variation.
+	DefaultVariation string `protobuf:"bytes,5,opt,name=default_variation,json=defaultVariation,proto3" json:"default_variation,omitempty"`
+	// The key of the variation served while the flag is inactive. Defaults to
+	// the last variation.
+	OffVariation string `protobuf:"bytes,6,opt,name=off_variation,json=offVariation,proto3" json:"off_variation,omitempty"`
+	// The name of the flag that is shown to the users. Defaults to the name of
+	// the flag.
+	DisplayName string `protobuf:"bytes,7,opt,name=display_name,json=displayName,proto3" json:"display_name,omitempty"`
+	// What the flag does. At most 1000 characters long.
+	Description string `protobuf:"bytes,8,opt,name=description,proto3" json:"description,omitempty"`
+	// The user or the team that is responsible for the flag. A user must exist.
+	Owner *FlagOwner `protobuf:"bytes,9,opt,name=owner,proto3" json:"owner,omitempty"`
+	// The free-form tags of the flag. At most 20 tags of at most 50 characters
+	// each. Duplicate tags are ignored.
+	Tags []string `protobuf:"bytes,10,rep,name=tags,proto3" json:"tags,omitempty"`
+	// The purpose of the flag. Defaults to a release flag.
+	Kind FlagKind `protobuf:"varint,11,opt,name=kind,proto3,enum=flagpb.FlagKind" json:"kind,omitempty"`
+}
+
+func (x *CreateFlagRequest) Reset() {
+	*x = CreateFlagRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_proto_flagpb_flag_proto_msgTypes[8]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *CreateFlagRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateFlagRequest) ProtoMessage() {}
+
+func (x *CreateFlagRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_flagpb_flag_proto_msgTypes[8]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateFlagRequest.ProtoReflect.Descriptor instead.
+func (*CreateFlagRequest) Descriptor() ([]byte, []int) {
+	return file_proto_flagpb_flag_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *CreateFlagRequest) GetFlagName() string {
+	if x != nil {
+		return x.FlagName
+	}
+	return ""
+}
+
+func (x *CreateFlagRequest) GetProjectName() string {
+	if x != nil {
+		return x.ProjectName
+	}
+	return ""
+}
+
+func (x *CreateFlagRequest) GetFlagType() FlagType {
+	if x != nil {
+		return x.FlagType
+	}
+	return FlagType_FLAG_TYPE_BOOLEAN
+}
+
+func (x *CreateFlagRequest) GetVariations() []*Variation {
+	if x != nil {
+		return x.Variations
+	}
+	return nil
+}
+
+func (x *CreateFlagRequest) GetDefaultVariation() string {
+	if x != nil {
+		return x.DefaultVariation
+	}
+	return ""
+}
+
+func (x *CreateFlagRequest) GetOffVariation() string {
+	if x != nil {
+		return x.OffVariation
+	}
+	return ""
+}
+
+func (x *CreateFlagRequest) GetDisplayName() string {
+	if x != nil {
+		return x.DisplayName
+	}
+	return ""
+}
+
+func (x *CreateFlagRequest) GetDescription() string {
+	if x != nil {
+		return x.Description
+	}
+	return ""
+}
+
+func (x *CreateFlagRequest) GetOwner() *FlagOwner {
+	if x != nil {
+		return x.Owner
+	}
+	return nil
+}
+
+func (x *CreateFlagRequest) GetTags() []string {
+	if x != nil {
+		return x.Tags
+	}
+	return nil
+}
+
+func (x *CreateFlagRequest) GetKind() FlagKind {
+	if x != nil {
+		return x.Kind
+	}
+	return FlagKind_FLAG_KIND_UNSPECIFIED
+}
+
+// CreateFlagResponse is the response for creating a new flag.
+type CreateFlagResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+}
+
+func (x *CreateFlagResponse) Reset() {
+	*x = CreateFlagResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_proto_flagpb_flag_proto_msgTypes[9]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *CreateFlagResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateFlagResponse) ProtoMessage() {}
+
+func (x *CreateFlagResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_flagpb_flag_proto_msgTypes[9]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateFlagResponse.ProtoReflect.Descriptor instead.
+func (*CreateFlagResponse) Descriptor() ([]byte, []int) {
+	return file_proto_flagpb_flag_proto_rawDescGZIP(), []int{9}
+}
+
+// UpdateFlagStatusRequest is the request body to update the status of a flag.
+type UpdateFlagStatusRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// The name of the project where the flag is created.
+	ProjectName string `protobuf:"bytes,1,opt,name=project_name,json=projectName,proto3" json:"project_name,omitempty"`
+	// The name of the environment in which the flag is to be updated.
+	EnvironmentName string `protobuf:"bytes,2,opt,name=environment_name,json=environmentName,proto3" json:"environment_name,omitempty"`
+	// The name of the flag to be updated.
+	FlagName string `protobuf:"bytes,3,opt,name=flag_name,json=flagName,proto3" json:"flag_name,omitempty"`
+	// The update to be made to the flag.
+	IsActive bool `protobuf:"varint,4,opt,name=is_active,json=isActive,proto3" json:"is_active,omitempty"`
+	// The percentage rollout of the variations served while the flag is
+	// active. The rollout of the flag is left unchanged when it is not set.
+	Rollout *Rollout `protobuf:"bytes,6,opt,name=rollout,proto3" json:"rollout,omitempty"`
+}
+
+func (x *UpdateFlagStatusRequest) Reset() {
+	*x = UpdateFlagStatusRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_proto_flagpb_flag_proto_msgTypes[10]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *UpdateFlagStatusRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UpdateFlagStatusRequest) ProtoMessage() {}
+
+func (x *UpdateFlagStatusRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_flagpb_flag_proto_msgTypes[10]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UpdateFlagStatusRequest.ProtoReflect.Descriptor instead.
+func (*UpdateFlagStatusRequest) Descriptor() ([]byte, []int) {
+	return file_proto_flagpb_flag_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *UpdateFlagStatusRequest) GetProjectName() string {
+	if x != nil {
+		return x.ProjectName
+	}
+	return ""
+}
+
+func (x *UpdateFlagStatusRequest) GetEnvironmentName() string {
+	if x != nil {
+		return x.EnvironmentName
+	}
+	return ""
+}
+
+func (x *UpdateFlagStatusRequest) GetFlagName() string {
+	if x != nil {
+		return x.FlagName
+	}
+	return ""
+}
+
+func (x *UpdateFlagStatusRequest) GetIsActive() bool {
+	if x != nil {
+		return x.IsActive
+	}
+	return false
+}
+
+func (x *UpdateFlagStatusRequest) GetRollout() *Rollout {
+	if x != nil {
+		return x.Rollout
+	}
+	return nil
+}
+
+// UpdateFlagStatusResponse is the response for updating a flag.
+type UpdateFlagStatusResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+}
+
+func (x *UpdateFlagStatusResponse) Reset() {
+	*x = UpdateFlagStatusResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_proto_flagpb_flag_proto_msgTypes[11]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *UpdateFlagStatusResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UpdateFlagStatusResponse) ProtoMessage() {}
+
+func (x *UpdateFlagStatusResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_flagpb_flag_proto_msgTypes[11]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UpdateFlagStatusResponse.ProtoReflect.Descriptor instead.
+func (*UpdateFlagStatusResponse) Descriptor() ([]byte, []int) {
+	return file_proto_flagpb_flag_proto_rawDescGZIP(), []int{11}
+}
+
+// UpdateFlagVariationRequest is the request body to update the variation
+// served by a flag in an environment.
+type UpdateFlagVariationRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// The name of the project where the flag is created.
+	ProjectName string `protobuf:"bytes,1,opt,name=project_name,json=projectName,proto3" json:"project_name,omitempty"`
+	// The name of the environment in which the flag is to be updated.
+	EnvironmentName string `protobuf:"bytes,2,opt,name=environment_name,json=environmentName,proto3" json:"environment_name,omitempty"`
+	// The name of the flag to be updated.
+	FlagName string `protobuf:"bytes,3,opt,name=flag_name,json=flagName,proto3" json:"flag_name,omitempty"`
+	// The key of the variation to serve while the flag is active.
+	Variation string `protobuf:"bytes,4,opt,name=variation,proto3" json:"variation,omitempty"`
+}
+
+func (x *UpdateFlagVariationRequest) Reset() {
+	*x = UpdateFlagVariationRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_proto_flagpb_flag_proto_msgTypes[12]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *UpdateFlagVariationRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UpdateFlagVariationRequest) ProtoMessage() {}
+
+func (x *UpdateFlagVariationRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_flagpb_flag_proto_msgTypes[12]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UpdateFlagVariationRequest.ProtoReflect.Descriptor instead.
+func (*UpdateFlagVariationRequest) Descriptor() ([]byte, []int) {
+	return file_proto_flagpb_flag_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *UpdateFlagVariationRequest) GetProjectName() string {
+	if x != nil {
+		return x.ProjectName
+	}
+	return ""
+}
+
+func (x *UpdateFlagVariationRequest) GetEnvironmentName() string {
+	if x != nil {
+		return x.EnvironmentName
+	}
+	return ""
+}
+
+func (x *UpdateFlagVariationRequest) GetFlagName() string {
+	if x != nil {
+		return x.FlagName
+	}
+	return ""
+}
+
+func (x *UpdateFlagVariationRequest) GetVariation() string {
+	if x != nil {
+		return x.Variation
+	}
+	return ""
+}
+
+// UpdateFlagVariationResponse is the response for updating the variation of a
+// flag.
+type UpdateFlagVariationResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+}
+
+func (x *UpdateFlagVariationResponse) Reset() {
+	*x = UpdateFlagVariationResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_proto_flagpb_flag_proto_msgTypes[13]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *UpdateFlagVariationResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UpdateFlagVariationResponse) ProtoMessage() {}
+
+func (x *UpdateFlagVariationResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_flagpb_flag_proto_msgTypes[13]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UpdateFlagVariationResponse.ProtoReflect.Descriptor instead.
+func (*UpdateFlagVariationResponse) Descriptor() ([]byte, []int) {
+	return file_proto_flagpb_flag_proto_rawDescGZIP(), []int{13}
+}
+
+// GetFlagRulesRequest is the request body to get the targeting rules of a
+// flag.
+type GetFlagRulesRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// The name of the project where the flag is created.
+	ProjectName string `protobuf:"bytes,1,opt,name=project_name,json=projectName,proto3" json:"project_name,omitempty"`
+	// The name of the environment to get the rules from.
+	EnvironmentName string `protobuf:"bytes,2,opt,name=environment_name,json=environmentName,proto3" json:"environment_name,omitempty"`
+	// The name of the flag.
+	FlagName string `protobuf:"bytes,3,opt,name=flag_name,json=flagName,proto3" json:"flag_name,omitempty"`
+}
+
+func (x *GetFlagRulesRequest) Reset() {
+	*x = GetFlagRulesRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_proto_flagpb_flag_proto_msgTypes[14]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *GetFlagRulesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetFlagRulesRequest) ProtoMessage() {}
+
+func (x *GetFlagRulesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_flagpb_flag_proto_msgTypes[14]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetFlagRulesRequest.ProtoReflect.Descriptor instead.
+func (*GetFlagRulesRequest) Descriptor() ([]byte, []int) {
+	return file_proto_flagpb_flag_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *GetFlagRulesRequest) GetProjectName() string {
+	if x != nil {
+		return x.ProjectName
+	}
+	return ""
+}
+
+func (x *GetFlagRulesRequest) GetEnvironmentName() string {
+	if x != nil {
+		return x.EnvironmentName
+	}
+	return ""
+}
+
+func (x *GetFlagRulesRequest) GetFlagName() string {
+	if x != nil {
+		return x.FlagName
+	}
+	return ""
+}
+
+// GetFlagRulesResponse is the response of getting the targeting rules of a
+// flag.
+type GetFlagRulesResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// The rules of the flag in the order they are evaluated.
+	Rules []*Rule `protobuf:"bytes,1,rep,name=rules,proto3" json:"rules,omitempty"`
+}
+
+func (x *GetFlagRulesResponse) Reset() {
+	*x = GetFlagRulesResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_proto_flagpb_flag_proto_msgTypes[15]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *GetFlagRulesResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetFlagRulesResponse) ProtoMessage() {}
+
+func (x *GetFlagRulesResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_flagpb_flag_proto_msgTypes[15]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetFlagRulesResponse.ProtoReflect.Descriptor instead.
+func (*GetFlagRulesResponse) Descriptor() ([]byte, []int) {
+	return file_proto_flagpb_flag_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *GetFlagRulesResponse) GetRules() []*Rule {
+	if x != nil {
+		return x.Rules
+	}
+	return nil
+}
+
+// UpdateFlagRulesRequest is the request body to replace the targeting rules of
+// a flag.
+type UpdateFlagRulesRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// The name of the project where the flag is created.
+	ProjectName string `protobuf:"bytes,1,opt,name=project_name,json=projectName,proto3" json:"project_name,omitempty"`
+	// The name of the environment in which the rules are to be updated.
+	EnvironmentName string `protobuf:"bytes,2,opt,name=environment_name,json=environmentName,proto3" json:"environment_name,omitempty"`
+	// The name of the flag to be updated.
+	FlagName string `protobuf:"bytes,3,opt,name=flag_name,json=flagName,proto3" json:"flag_name,omitempty"`
+	// The rules of the flag in the order they are to be evaluated. The first
+	// matching rule decides the served variation.
+	Rules []*Rule `protobuf:"bytes,4,rep,name=rules,proto3" json:"rules,omitempty"`
+}
+
+func (x *UpdateFlagRulesRequest) Reset() {
+	*x = UpdateFlagRulesRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_proto_flagpb_flag_proto_msgTypes[16]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *UpdateFlagRulesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UpdateFlagRulesRequest) ProtoMessage() {}
+
+func (x *UpdateFlagRulesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_flagpb_flag_proto_msgTypes[16]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UpdateFlagRulesRequest.ProtoReflect.Descriptor instead.
+func (*UpdateFlagRulesRequest) Descriptor() ([]byte, []int) {
+	return file_proto_flagpb_flag_proto_rawDescGZIP(), []int{16}
+}
+
+func (x *UpdateFlagRulesRequest) GetProjectName() string {
+	if x != nil {
+		return x.ProjectName
+	}
+	return ""
+}
+
+func (x *UpdateFlagRulesRequest) GetEnvironmentName() string {
+	if x != nil {
+		return x.EnvironmentName
+	}
+	return ""
+}
+
+func (x *UpdateFlagRulesRequest) GetFlagName() string {
+	if x != nil {
+		return x.FlagName
+	}
+	return ""
+}
+
+func (x *UpdateFlagRulesRequest) GetRules() []*Rule {
+	if x != nil {
+		return x.Rules
+	}
+	return nil
+}
+
+// UpdateFlagRulesResponse is the response for updating the targeting rules of
+// a flag.
+type UpdateFlagRulesResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+}
+
+func (x *UpdateFlagRulesResponse) Reset() {
+	*x = UpdateFlagRulesResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_proto_flagpb_flag_proto_msgTypes[17]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *UpdateFlagRulesResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UpdateFlagRulesResponse) ProtoMessage() {}
+
+func (x *UpdateFlagRulesResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_flagpb_flag_proto_msgTypes[17]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UpdateFlagRulesResponse.ProtoReflect.Descriptor instead.
+func (*UpdateFlagRulesResponse) Descriptor() ([]byte, []int) {
+	return file_proto_flagpb_flag_proto_rawDescGZIP(), []int{17}
+}
+
+// ListFlagsRequest is the request body to list the flags of a project.
+type ListFlagsRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// The name of the project.
+	ProjectName string `protobuf:"bytes,1,opt,name=project_name,json=projectName,proto3" json:"project_name,omitempty"`
+	// The largest number of flags to return. Defaults to 50 and is capped
+	// at 500.
+	PageSize int32 `protobuf:"varint,2,opt,name=page_size,json=pageSize,proto3" json:"page_size,omitempty"`
+	// The token of the page to return from the response of the previous page.
+	// The first page is returned without a token. The sorting and the filter
+	// must not change between pages.
+	PageToken string `protobuf:"bytes,3,opt,name=page_token,json=pageToken,proto3" json:"page_token,omitempty"`
+	// The field that the flags are sorted by.
+	SortBy SortField `protobuf:"varint,4,opt,name=sort_by,json=sortBy,proto3,enum=flagpb.SortField" json:"sort_by,omitempty"`
+	// Whether the flags are sorted in descending order.
+	Descending bool `protobuf:"varint,5,opt,name=descending,proto3" json:"descending,omitempty"`
+	// Only returns the flags whose name contains the filter, ignoring the
+	// case.
+	NameFilter string `protobuf:"bytes,6,opt,name=name_filter,json=nameFilter,proto3" json:"name_filter,omitempty"`
+	// Only returns the flags of the kind, unless unspecified.
+	Kind FlagKind `protobuf:"varint,7,opt,name=kind,proto3,enum=flagpb.FlagKind" json:"kind,omitempty"`
+	// Only returns the flags owned by the user or the team with the name.
+	Owner string `protobuf:"bytes,8,opt,name=owner,proto3" json:"owner,omitempty"`
+	// Only returns the flags that have all the tags.
+	Tags []string `protobuf:"bytes,9,rep,name=tags,proto3" json:"tags,omitempty"`
+	// Only returns the flags whose name, display name or description contain
+	// any of the words of the search, ignoring the case.
+	Search string `protobuf:"bytes,10,opt,name=search,proto3" json:"search,omitempty"`
+}
+
+func (x *ListFlagsRequest) Reset() {
+	*x = ListFlagsRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_proto_flagpb_flag_proto_msgTypes[18]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ListFlagsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListFlagsRequest) ProtoMessage() {}
+
+func (x *ListFlagsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_flagpb_flag_proto_msgTypes[18]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListFlagsRequest.ProtoReflect.Descriptor instead.
+func (*ListFlagsRequest) Descriptor() ([]byte, []int) {
+	return file_proto_flagpb_flag_proto_rawDescGZIP(), []int{18}
+}
+
+func (x *ListFlagsRequest) GetProjectName() string {
+	if x != nil {
+		return x.ProjectName
+	}
+	return ""
+}
+
+func (x *ListFlagsRequest) GetPageSize() int32 {
+	if x != nil {
+		return x.PageSize
+	}
+	return 0
+}
+
+func (x *ListFlagsRequest) GetPageToken() string {
+	if x != nil {
+		return x.PageToken
+	}
+	return ""
+}
+
+func (x *ListFlagsRequest) GetSortBy() SortField {
+	if x != nil {
+		return x.SortBy
+	}
+	return SortField_SORT_FIELD_NAME
+}
+
+func (x *ListFlagsRequest) GetDescending() bool {
+	if x != nil {
+		return x.Descending
+	}
+	return false
+}
+
+func (x *ListFlagsRequest) GetNameFilter() string {
+	if x != nil {
+		return x.NameFilter
+	}
+	return ""
+}
+
+func (x *ListFlagsRequest) GetKind() FlagKind {
+	if x != nil {
+		return x.Kind
+	}
+	return FlagKind_FLAG_KIND_UNSPECIFIED
+}
+
+func (x *ListFlagsRequest) GetOwner() string {
+	if x != nil {
+		return x.Owner
+	}
+	return ""
+}
+
+func (x *ListFlagsRequest) GetTags() []string {
+	if x != nil {
+		return x.Tags
+	}
+	return nil
+}
+
+func (x *ListFlagsRequest) GetSearch() string {
+	if x != nil {
+		return x.Search
+	}
+	return ""
+}
+
+// ListFlagsResponse is the response of listing the flags of a project.
+type ListFlagsResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// The flags of the page.
+	Flags []*FlagDetails `protobuf:"bytes,1,rep,name=flags,proto3" json:"flags,omitempty"`
+	// The token of the next page. Empty if this is the last page.
+	NextPageToken string `protobuf:"bytes,2,opt,name=next_page_token,json=nextPageToken,proto3" json:"next_page_token,omitempty"`
+}
+
+func (x *ListFlagsResponse) Reset() {
+	*x = ListFlagsResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_proto_flagpb_flag_proto_msgTypes[19]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ListFlagsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListFlagsResponse) ProtoMessage() {}
+
+func (x *ListFlagsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_flagpb_flag_proto_msgTypes[19]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListFlagsResponse.ProtoReflect.Descriptor instead.
+func (*ListFlagsResponse) Descriptor() ([]byte, []int) {
+	return file_proto_flagpb_flag_proto_rawDescGZIP(), []int{19}
+}
+
+func (x *ListFlagsResponse) GetFlags() []*FlagDetails {
+	if x != nil {
+		return x.Flags
+	}
+	return nil
+}
+
+func (x *ListFlagsResponse) GetNextPageToken() string {
+	if x != nil {
+		return x.NextPageToken
+	}
+	return ""
+}
+
+// GetFlagRequest is the request body to get a flag.
+type GetFlagRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// The name of the project where the flag is created.
+	ProjectName string `protobuf:"bytes,1,opt,name=project_name,json=projectName,proto3" json:"project_name,omitempty"`
+	// The name of the flag.
+	FlagName string `protobuf:"bytes,2,opt,name=flag_name,json=flagName,proto3" json:"flag_name,omitempty"`
+}
+
+func (x *GetFlagRequest) Reset() {
+	*x = GetFlagRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_proto_flagpb_flag_proto_msgTypes[20]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *GetFlagRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetFlagRequest) ProtoMessage() {}
+
+func (x *GetFlagRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_flagpb_flag_proto_msgTypes[20]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetFlagRequest.ProtoReflect.Descriptor instead.
+func (*GetFlagRequest) Descriptor() ([]byte, []int) {
+	return file_proto_flagpb_flag_proto_rawDescGZIP(), []int{20}
+}
+
+func (x *GetFlagRequest) GetProjectName() string {
+	if x != nil {
+		return x.ProjectName
+	}
+	return ""
+}
+
+func (x *GetFlagRequest) GetFlagName() string {
+	if x != nil {
+		return x.FlagName
+	}
+	return ""
+}
+
+// GetFlagResponse is the response of getting a flag.
+type GetFlagResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// The details of the flag.
+	Flag *FlagDetails `protobuf:"bytes,1,opt,name=flag,proto3" json:"flag,omitempty"`
+	// The settings of the flag in every environment of the project, sorted by
+	// the name of the environment.
+	Environments []*FlagEnvironmentStatus `protobuf:"bytes,2,rep,name=environments,proto3" json:"environments,omitempty"`
+}
+
+func (x *GetFlagResponse) Reset() {
+	*x = GetFlagResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_proto_flagpb_flag_proto_msgTypes[21]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *GetFlagResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetFlagResponse) ProtoMessage() {}
+
+func (x *GetFlagResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_flagpb_flag_proto_msgTypes[21]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetFlagResponse.ProtoReflect.Descriptor instead.
+func (*GetFlagResponse) Descriptor() ([]byte, []int) {
+	return file_proto_flagpb_flag_proto_rawDescGZIP(), []int{21}
+}
+
+func (x *GetFlagResponse) GetFlag() *FlagDetails {
+	if x != nil {
+		return x.Flag
+	}
+	return nil
+}
+
+func (x *GetFlagResponse) GetEnvironments() []*FlagEnvironmentStatus {
+	if x != nil {
+		return x.Environments
+	}
+	return nil
+}
+
+// ArchiveFlagRequest is the request body to archive a flag.
+type ArchiveFlagRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// The name of the project where the flag is created.
+	ProjectName string `protobuf:"bytes,1,opt,name=project_name,json=projectName,proto3" json:"project_name,omitempty"`
+	// The name of the flag to archive.
+	FlagName string `protobuf:"bytes,2,opt,name=flag_name,json=flagName,proto3" json:"flag_name,omitempty"`
+	// The key of the variation to serve in every environment while the flag is
+	// archived. Defaults to the off variation of the flag.
+	Variation string `protobuf:"bytes,3,opt,name=variation,proto3" json:"variation,omitempty"`
+}
+
+func (x *ArchiveFlagRequest) Reset() {
+	*x = ArchiveFlagRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_proto_flagpb_flag_proto_msgTypes[22]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ArchiveFlagRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ArchiveFlagRequest) ProtoMessage() {}
+
+func (x *ArchiveFlagRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_flagpb_flag_proto_msgTypes[22]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ArchiveFlagRequest.ProtoReflect.Descriptor instead.
+func (*ArchiveFlagRequest) Descriptor() ([]byte, []int) {
+	return file_proto_flagpb_flag_proto_rawDescGZIP(), []int{22}
+}
+
+func (x *ArchiveFlagRequest) GetProjectName() string {
+	if x != nil {
+		return x.ProjectName
+	}
+	return ""
+}
+
+func (x *ArchiveFlagRequest) GetFlagName() string {
+	if x != nil {
+		return x.FlagName
+	}
+	return ""
+}
+
+func (x *ArchiveFlagRequest) GetVariation() string {
+	if x != nil {
+		return x.Variation
+	}
+	return ""
+}
+
+// ArchiveFlagResponse is the response for archiving a flag.
+type ArchiveFlagResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+}
+
+func (x *ArchiveFlagResponse) Reset() {
+	*x = ArchiveFlagResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_proto_flagpb_flag_proto_msgTypes[23]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ArchiveFlagResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ArchiveFlagResponse) ProtoMessage() {}
+
+func (x *ArchiveFlagResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_flagpb_flag_proto_msgTypes[23]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ArchiveFlagResponse.ProtoReflect.Descriptor instead.
+func (*ArchiveFlagResponse) Descriptor() ([]byte, []int) {
+	return file_proto_flagpb_flag_proto_rawDescGZIP(), []int{23}
+}
+
+// RestoreFlagRequest is the request body to restore an archived flag.
+type RestoreFlagRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// The name of the project where the flag is created.
+	ProjectName string `protobuf:"bytes,1,opt,name=project_name,json=projectName,proto3" json:"project_name,omitempty"`
+	// The name of the flag to restore.
+	FlagName string `protobuf:"bytes,2,opt,name=flag_name,json=flagName,proto3" json:"flag_name,omitempty"`
+}
+
+func (x *RestoreFlagRequest) Reset() {
+	*x = RestoreFlagRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_proto_flagpb_flag_proto_msgTypes[24]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *RestoreFlagRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RestoreFlagRequest) ProtoMessage() {}
+
+func (x *RestoreFlagRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_flagpb_flag_proto_msgTypes[24]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RestoreFlagRequest.ProtoReflect.Descriptor instead.
+func (*RestoreFlagRequest) Descriptor() ([]byte, []int) {
+	return file_proto_flagpb_flag_proto_rawDescGZIP(), []int{24}
+}
+
+func (x *RestoreFlagRequest) GetProjectName() string {
+	if x != nil {
+		return x.ProjectName
+	}
+	return ""
+}
+
+func (x *RestoreFlagRequest) GetFlagName() string {
+	if x != nil {
+		return x.FlagName
+	}
+	return ""
+}
+
+// RestoreFlagResponse is the response for restoring a flag.
+type RestoreFlagResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+}
+
+func (x *RestoreFlagResponse) Reset() {
+	*x = RestoreFlagResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_proto_flagpb_flag_proto_msgTypes[25]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *RestoreFlagResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RestoreFlagResponse) ProtoMessage() {}
+
+func (x *RestoreFlagResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_flagpb_flag_proto_msgTypes[25]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RestoreFlagResponse.ProtoReflect.Descriptor instead.
+func (*RestoreFlagResponse) Descriptor() ([]byte, []int) {
+	return file_proto_flagpb_flag_proto_rawDescGZIP(), []int{25}
+}
+
+// DeleteFlagRequest is the request body to delete a flag.
+type DeleteFlagRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// The name of the project where the flag is created.
+	ProjectName string `protobuf:"bytes,1,opt,name=project_name,json=projectName,proto3" json:"project_name,omitempty"`
+	// The name of the flag to delete.
+	FlagName string `protobuf:"bytes,2,opt,name=flag_name,json=flagName,proto3" json:"flag_name,omitempty"`
+}
+
+func (x *DeleteFlagRequest) Reset() {
+	*x = DeleteFlagRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_proto_flagpb_flag_proto_msgTypes[26]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *DeleteFlagRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteFlagRequest) ProtoMessage() {}
+
+func (x *DeleteFlagRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_flagpb_flag_proto_msgTypes[26]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteFlagRequest.ProtoReflect.Descriptor instead.
+func (*DeleteFlagRequest) Descriptor() ([]byte, []int) {
+	return file_proto_flagpb_flag_proto_rawDescGZIP(), []int{26}
+}
+
+func (x *DeleteFlagRequest) GetProjectName() string {
+	if x != nil {
+		return x.ProjectName
+	}
+	return ""
+}
+
+func (x *DeleteFlagRequest) GetFlagName() string {
+	if x != nil {
+		return x.FlagName
+	}
+	return ""
+}
+
+// DeleteFlagResponse is the response for deleting a flag.
+type DeleteFlagResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+}
+
+func (x *DeleteFlagResponse) Reset() {
+	*x = DeleteFlagResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_proto_flagpb_flag_proto_msgTypes[27]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *DeleteFlagResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteFlagResponse) ProtoMessage() {}
+
+func (x *DeleteFlagResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_flagpb_flag_proto_msgTypes[27]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteFlagResponse.ProtoReflect.Descriptor instead.
+func (*DeleteFlagResponse) Descriptor() ([]byte, []int) {
+	return file_proto_flagpb_flag_proto_rawDescGZIP(), []int{27}
+}
+
+// RenameFlagRequest is the request body to rename a flag. At least one of the
+// new name and the display name is required.
+type RenameFlagRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// The name of the project where the flag is created.
+	ProjectName string `protobuf:"bytes,1,opt,name=project_name,json=projectName,proto3" json:"project_name,omitempty"`
+	// The current name of the flag.
+	FlagName string `protobuf:"bytes,2,opt,name=flag_name,json=flagName,proto3" json:"flag_name,omitempty"`
+	// The new name of the flag. The name is not changed if empty.
+	NewFlagName string `protobuf:"bytes,3,opt,name=new_flag_name,json=newFlagName,proto3" json:"new_flag_name,omitempty"`
+	// The new display name of the flag. The display name is not changed if
+	// empty.
+	DisplayName string `protobuf:"bytes,4,opt,name=display_name,json=displayName,proto3" json:"display_name,omitempty"`
+}
+
+func (x *RenameFlagRequest) Reset() {
+	*x = RenameFlagRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_proto_flagpb_flag_proto_msgTypes[28]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *RenameFlagRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RenameFlagRequest) ProtoMessage() {}
+
+func (x *RenameFlagRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_flagpb_flag_proto_msgTypes[28]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RenameFlagRequest.ProtoReflect.Descriptor instead.
+func (*RenameFlagRequest) Descriptor() ([]byte, []int) {
+	return file_proto_flagpb_flag_proto_rawDescGZIP(), []int{28}
+}
+
+func (x *RenameFlagRequest) GetProjectName() string {
+	if x != nil {
+		return x.ProjectName
+	}
+	return ""
+}
+
+func (x *RenameFlagRequest) GetFlagName() string {
+	if x != nil {
+		return x.FlagName
+	}
+	return ""
+}
+
+func (x *RenameFlagRequest) GetNewFlagName() string {
+	if x != nil {
+		return x.NewFlagName
+	}
+	return ""
+}
+
+func (x *RenameFlagRequest) GetDisplayName() string {
+	if x != nil {
+		return x.DisplayName
+	}
+	return ""
+}
+
+// RenameFlagResponse is the response for renaming a flag.
+type RenameFlagResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// The time until which the previous name of the flag is served. Unset if
+	// the name was not changed.
+	AliasExpiresAt *timestamppb.Timestamp `protobuf:"bytes,1,opt,name=alias_expires_at,json=aliasExpiresAt,proto3" json:"alias_expires_at,omitempty"`
+}
+
+func (x *RenameFlagResponse) Reset() {
+	*x = RenameFlagResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_proto_flagpb_flag_proto_msgTypes[29]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *RenameFlagResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RenameFlagResponse) ProtoMessage() {}
+
+func (x *RenameFlagResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_flagpb_flag_proto_msgTypes[29]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RenameFlagResponse.ProtoReflect.Descriptor instead.
+func (*RenameFlagResponse) Descriptor() ([]byte, []int) {
+	return file_proto_flagpb_flag_proto_rawDescGZIP(), []int{29}
+}
+
+func (x *RenameFlagResponse) GetAliasExpiresAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.AliasExpiresAt
+	}
+	return nil
+}
+
+// UpdateFlagMetadataRequest is the request body to update the metadata of a
+// flag. The metadata that is not provided is removed.
+type UpdateFlagMetadataRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// The name of the project where the flag is created.
+	ProjectName string `protobuf:"bytes,1,opt,name=project_name,json=projectName,proto3" json:"project_name,omitempty"`
+	// The name of the flag to update.
+	FlagName string `protobuf:"bytes,2,opt,name=flag_name,json=flagName,proto3" json:"flag_name,omitempty"`
+	// What the flag does. At most 1000 characters long.
+	Description string `protobuf:"bytes,3,opt,name=description,proto3" json:"description,omitempty"`
+	// The user or the team that is responsible for the flag. A user must exist.
+	Owner *FlagOwner `protobuf:"bytes,4,opt,name=owner,proto3" json:"owner,omitempty"`
+	// The free-form tags of the flag. At most 20 tags of at most 50 characters
+	// each. Duplicate tags are ignored.
+	Tags []string `protobuf:"bytes,5,rep,name=tags,proto3" json:"tags,omitempty"`
+	// The purpose of the flag. Defaults to a release flag.
+	Kind FlagKind `protobuf:"varint,6,opt,name=kind,proto3,enum=flagpb.FlagKind" json:"kind,omitempty"`
+}
+
+func (x *UpdateFlagMetadataRequest) Reset() {
+	*x = UpdateFlagMetadataRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_proto_flagpb_flag_proto_msgTypes[30]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *UpdateFlagMetadataRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UpdateFlagMetadataRequest) ProtoMessage() {}
+
+func (x *UpdateFlagMetadataRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_flagpb_flag_proto_msgTypes[30]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UpdateFlagMetadataRequest.ProtoReflect.Descriptor instead.
+func (*UpdateFlagMetadataRequest) Descriptor() ([]byte, []int) {
+	return file_proto_flagpb_flag_proto_rawDescGZIP(), []int{30}
+}
+
+func (x *UpdateFlagMetadataRequest) GetProjectName() string {
+	if x != nil {
+		return x.ProjectName
+	}
+	return ""
+}
+
+func (x *UpdateFlagMetadataRequest) GetFlagName() string {
+	if x != nil {
+		return x.FlagName
+	}
+	return ""
+}
+
+func (x *UpdateFlagMetadataRequest) GetDescription() string {
+	if x != nil {
+		return x.Description
+	}
+	return ""
+}
+
+func (x *UpdateFlagMetadataRequest) GetOwner() *FlagOwner {
+	if x != nil {
+		return x.Owner
+	}
+	return nil
+}
+
+func (x *UpdateFlagMetadataRequest) GetTags() []string {
+	if x != nil {
+		return x.Tags
+	}
+	return nil
+}
+
+func (x *UpdateFlagMetadataRequest) GetKind() FlagKind {
+	if x != nil {
+		return x.Kind
+	}
+	return FlagKind_FLAG_KIND_UNSPECIFIED
+}
+
+// UpdateFlagMetadataResponse is the response for updating the metadata of a
+// flag.
+type UpdateFlagMetadataResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// The details of the updated flag.
+	Flag *FlagDetails `protobuf:"bytes,1,opt,name=flag,proto3" json:"flag,omitempty"`
+}
+
+func (x *UpdateFlagMetadataResponse) Reset() {
+	*x = UpdateFlagMetadataResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_proto_flagpb_flag_proto_msgTypes[31]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *UpdateFlagMetadataResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UpdateFlagMetadataResponse) ProtoMessage() {}
+
+func (x *UpdateFlagMetadataResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_flagpb_flag_proto_msgTypes[31]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UpdateFlagMetadataResponse.ProtoReflect.Descriptor instead.
+func (*UpdateFlagMetadataResponse) Descriptor() ([]byte, []int) {
+	return file_proto_flagpb_flag_proto_rawDescGZIP(), []int{31}
+}
+
+func (x *UpdateFlagMetadataResponse) GetFlag() *FlagDetails {
+	if x != nil {
+		return x.Flag
+	}
+	return nil
+}
+
+var File_proto_flagpb_flag_proto protoreflect.FileDescriptor
+
+var file_proto_flagpb_flag_proto_rawDesc = []byte{
+	0x0a, 0x17, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2f, 0x66, 0x6c, 0x61, 0x67, 0x70, 0x62, 0x2f, 0x66,
+	0x6c, 0x61, 0x67, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x12, 0x06, 0x66, 0x6c, 0x61, 0x67, 0x70,
+	0x62, 0x1a, 0x1c, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62,
+	0x75, 0x66, 0x2f, 0x73, 0x74, 0x72, 0x75, 0x63, 0x74, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x1a,
+	0x1f, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66,
+	0x2f, 0x74, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f,
+	0x22, 0x46, 0x0a, 0x09, 0x46, 0x6c, 0x61, 0x67, 0x4f, 0x77, 0x6e, 0x65, 0x72, 0x12, 0x25, 0x0a,
+	0x04, 0x74, 0x79, 0x70, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x11, 0x2e, 0x66, 0x6c,
+	0x61, 0x67, 0x70, 0x62, 0x2e, 0x4f, 0x77, 0x6e, 0x65, 0x72, 0x54, 0x79, 0x70, 0x65, 0x52, 0x04,
+	0x74, 0x79, 0x70, 0x65, 0x12, 0x12, 0x0a, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x02, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x22, 0x4b, 0x0a, 0x09, 0x56, 0x61, 0x72, 0x69,
+	0x61, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x10, 0x0a, 0x03, 0x6b, 0x65, 0x79, 0x18, 0x01, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x03, 0x6b, 0x65, 0x79, 0x12, 0x2c, 0x0a, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65,
+	0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x16, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e,
+	0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x56, 0x61, 0x6c, 0x75, 0x65, 0x52, 0x05,
+	0x76, 0x61, 0x6c, 0x75, 0x65, 0x22, 0x73, 0x0a, 0x09, 0x43, 0x6f, 0x6e, 0x64, 0x69, 0x74, 0x69,
+	0x6f, 0x6e, 0x12, 0x1c, 0x0a, 0x09, 0x61, 0x74, 0x74, 0x72, 0x69, 0x62, 0x75, 0x74, 0x65, 0x18,
+	0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x61, 0x74, 0x74, 0x72, 0x69, 0x62, 0x75, 0x74, 0x65,
+	0x12, 0x30, 0x0a, 0x08, 0x6f, 0x70, 0x65, 0x72, 0x61, 0x74, 0x6f, 0x72, 0x18, 0x02, 0x20, 0x01,
+	0x28, 0x0e, 0x32, 0x14, 0x2e, 0x66, 0x6c, 0x61, 0x67, 0x70, 0x62, 0x2e, 0x52, 0x75, 0x6c, 0x65,
+	0x4f, 0x70, 0x65, 0x72, 0x61, 0x74, 0x6f, 0x72, 0x52, 0x08, 0x6f, 0x70, 0x65, 0x72, 0x61, 0x74,
+	0x6f, 0x72, 0x12, 0x16, 0x0a, 0x06, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x73, 0x18, 0x03, 0x20, 0x03,
+	0x28, 0x09, 0x52, 0x06, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x73, 0x22, 0x57, 0x0a, 0x04, 0x52, 0x75,
+	0x6c, 0x65, 0x12, 0x31, 0x0a, 0x0a, 0x63, 0x6f, 0x6e, 0x64, 0x69, 0x74, 0x69, 0x6f, 0x6e, 0x73,
+	0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x11, 0x2e, 0x66, 0x6c, 0x61, 0x67, 0x70, 0x62, 0x2e,
+	0x43, 0x6f, 0x6e, 0x64, 0x69, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x0a, 0x63, 0x6f, 0x6e, 0x64, 0x69,
+	0x74, 0x69, 0x6f, 0x6e, 0x73, 0x12, 0x1c, 0x0a, 0x09, 0x76, 0x61, 0x72, 0x69, 0x61, 0x74, 0x69,
+	0x6f, 0x6e, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x76, 0x61, 0x72, 0x69, 0x61, 0x74,
+	0x69, 0x6f, 0x6e, 0x22, 0x49, 0x0a, 0x11, 0x57, 0x65, 0x69, 0x67, 0x68, 0x74, 0x65, 0x64, 0x56,
+	0x61, 0x72, 0x69, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x1c, 0x0a, 0x09, 0x76, 0x61, 0x72, 0x69,
+	0x61, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x76, 0x61, 0x72,
+	0x69, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x16, 0x0a, 0x06, 0x77, 0x65, 0x69, 0x67, 0x68, 0x74,
+	0x18, 0x02, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x06, 0x77, 0x65, 0x69, 0x67, 0x68, 0x74, 0x22, 0x44,
+	0x0a, 0x07, 0x52, 0x6f, 0x6c, 0x6c, 0x6f, 0x75, 0x74, 0x12, 0x39, 0x0a, 0x0a, 0x76, 0x61, 0x72,
+	0x69, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x19, 0x2e,
+	0x66, 0x6c, 0x61, 0x67, 0x70, 0x62, 0x2e, 0x57, 0x65, 0x69, 0x67, 0x68, 0x74, 0x65, 0x64, 0x56,
+	0x61, 0x72, 0x69, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x0a, 0x76, 0x61, 0x72, 0x69, 0x61, 0x74,
+	0x69, 0x6f, 0x6e, 0x73, 0x22, 0xc5, 0x04, 0x0a, 0x0b, 0x46, 0x6c, 0x61, 0x67, 0x44, 0x65, 0x74,
+	0x61, 0x69, 0x6c, 0x73, 0x12, 0x12, 0x0a, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x01, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x12, 0x2d, 0x0a, 0x09, 0x66, 0x6c, 0x61, 0x67,
+	0x5f, 0x74, 0x79, 0x70, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x10, 0x2e, 0x66, 0x6c,
+	0x61, 0x67, 0x70, 0x62, 0x2e, 0x46, 0x6c, 0x61, 0x67, 0x54, 0x79, 0x70, 0x65, 0x52, 0x08, 0x66,
+	0x6c, 0x61, 0x67, 0x54, 0x79, 0x70, 0x65, 0x12, 0x31, 0x0a, 0x0a, 0x76, 0x61, 0x72, 0x69, 0x61,
+	0x74, 0x69, 0x6f, 0x6e, 0x73, 0x18, 0x03, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x11, 0x2e, 0x66, 0x6c,
+	0x61, 0x67, 0x70, 0x62, 0x2e, 0x56, 0x61, 0x72, 0x69, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x0a,
+	0x76, 0x61, 0x72, 0x69, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x12, 0x2b, 0x0a, 0x11, 0x64, 0x65,
+	0x66, 0x61, 0x75, 0x6c, 0x74, 0x5f, 0x76, 0x61, 0x72, 0x69, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x18,
+	0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x10, 0x64, 0x65, 0x66, 0x61, 0x75, 0x6c, 0x74, 0x56, 0x61,
+	0x72, 0x69, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x23, 0x0a, 0x0d, 0x6f, 0x66, 0x66, 0x5f, 0x76,
+	0x61, 0x72, 0x69, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x05, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0c,
+	0x6f, 0x66, 0x66, 0x56, 0x61, 0x72, 0x69, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x39, 0x0a, 0x0a,
+	0x63, 0x72, 0x65, 0x61, 0x74, 0x65, 0x64, 0x5f, 0x61, 0x74, 0x18, 0x06, 0x20, 0x01, 0x28, 0x0b,
+	0x32, 0x1a, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62,
+	0x75, 0x66, 0x2e, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x52, 0x09, 0x63, 0x72,
+	0x65, 0x61, 0x74, 0x65, 0x64, 0x41, 0x74, 0x12, 0x1f, 0x0a, 0x0b, 0x69, 0x73, 0x5f, 0x61, 0x72,
+	0x63, 0x68, 0x69, 0x76, 0x65, 0x64, 0x18, 0x07, 0x20, 0x01, 0x28, 0x08, 0x52, 0x0a, 0x69, 0x73,
+	0x41, 0x72, 0x63, 0x68, 0x69, 0x76, 0x65, 0x64, 0x12, 0x2d, 0x0a, 0x12, 0x61, 0x72, 0x63, 0x68,
+	0x69, 0x76, 0x65, 0x64, 0x5f, 0x76, 0x61, 0x72, 0x69, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x08,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x11, 0x61, 0x72, 0x63, 0x68, 0x69, 0x76, 0x65, 0x64, 0x56, 0x61,
+	0x72, 0x69, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x3b, 0x0a, 0x0b, 0x61, 0x72, 0x63, 0x68, 0x69,
+	0x76, 0x65, 0x64, 0x5f, 0x61, 0x74, 0x18, 0x09, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x67,
+	0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x54,
+	0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x52, 0x0a, 0x61, 0x72, 0x63, 0x68, 0x69, 0x76,
+	0x65, 0x64, 0x41, 0x74, 0x12, 0x21, 0x0a, 0x0c, 0x64, 0x69, 0x73, 0x70, 0x6c, 0x61, 0x79, 0x5f,
+	0x6e, 0x61, 0x6d, 0x65, 0x18, 0x0a, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0b, 0x64, 0x69, 0x73, 0x70,
+	0x6c, 0x61, 0x79, 0x4e, 0x61, 0x6d, 0x65, 0x12, 0x20, 0x0a, 0x0b, 0x64, 0x65, 0x73, 0x63, 0x72,
+	0x69, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x0b, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0b, 0x64, 0x65,
+	0x73, 0x63, 0x72, 0x69, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x27, 0x0a, 0x05, 0x6f, 0x77, 0x6e,
+	0x65, 0x72, 0x18, 0x0c, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x11, 0x2e, 0x66, 0x6c, 0x61, 0x67, 0x70,
+	0x62, 0x2e, 0x46, 0x6c, 0x61, 0x67, 0x4f, 0x77, 0x6e, 0x65, 0x72, 0x52, 0x05, 0x6f, 0x77, 0x6e,
+	0x65, 0x72, 0x12, 0x12, 0x0a, 0x04, 0x74, 0x61, 0x67, 0x73, 0x18, 0x0d, 0x20, 0x03, 0x28, 0x09,
+	0x52, 0x04, 0x74, 0x61, 0x67, 0x73, 0x12, 0x24, 0x0a, 0x04, 0x6b, 0x69, 0x6e, 0x64, 0x18, 0x0e,
+	0x20, 0x01, 0x28, 0x0e, 0x32, 0x10, 0x2e, 0x66, 0x6c, 0x61, 0x67, 0x70, 0x62, 0x2e, 0x46, 0x6c,
+	0x61, 0x67, 0x4b, 0x69, 0x6e, 0x64, 0x52, 0x04, 0x6b, 0x69, 0x6e, 0x64, 0x22, 0xab, 0x02, 0x0a,
+	0x15, 0x46, 0x6c, 0x61, 0x67, 0x45, 0x6e, 0x76, 0x69, 0x72, 0x6f, 0x6e, 0x6d, 0x65, 0x6e, 0x74,
+	0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x12, 0x29, 0x0a, 0x10, 0x65, 0x6e, 0x76, 0x69, 0x72, 0x6f,
+	0x6e, 0x6d, 0x65, 0x6e, 0x74, 0x5f, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x0f, 0x65, 0x6e, 0x76, 0x69, 0x72, 0x6f, 0x6e, 0x6d, 0x65, 0x6e, 0x74, 0x4e, 0x61, 0x6d,
+	0x65, 0x12, 0x1b, 0x0a, 0x09, 0x69, 0x73, 0x5f, 0x61, 0x63, 0x74, 0x69, 0x76, 0x65, 0x18, 0x02,
+	0x20, 0x01, 0x28, 0x08, 0x52, 0x08, 0x69, 0x73, 0x41, 0x63, 0x74, 0x69, 0x76, 0x65, 0x12, 0x1c,
+	0x0a, 0x09, 0x76, 0x61, 0x72, 0x69, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x03, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x09, 0x76, 0x61, 0x72, 0x69, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x22, 0x0a, 0x05,
+	0x72, 0x75, 0x6c, 0x65, 0x73, 0x18, 0x04, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x0c, 0x2e, 0x66, 0x6c,
+	0x61, 0x67, 0x70, 0x62, 0x2e, 0x52, 0x75, 0x6c, 0x65, 0x52, 0x05, 0x72, 0x75, 0x6c, 0x65, 0x73,
+	0x12, 0x33, 0x0a, 0x07, 0x72, 0x6f, 0x6c, 0x6c, 0x6f, 0x75, 0x74, 0x18, 0x05, 0x20, 0x03, 0x28,
+	0x0b, 0x32, 0x19, 0x2e, 0x66, 0x6c, 0x61, 0x67, 0x70, 0x62, 0x2e, 0x57, 0x65, 0x69, 0x67, 0x68,
+	0x74, 0x65, 0x64, 0x56, 0x61, 0x72, 0x69, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x07, 0x72, 0x6f,
+	0x6c, 0x6c, 0x6f, 0x75, 0x74, 0x12, 0x18, 0x0a, 0x07, 0x76, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e,
+	0x18, 0x06, 0x20, 0x01, 0x28, 0x03, 0x52, 0x07, 0x76, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x12,
+	0x39, 0x0a, 0x0a, 0x75, 0x70, 0x64, 0x61, 0x74, 0x65, 0x64, 0x5f, 0x61, 0x74, 0x18, 0x07, 0x20,
+	0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f,
+	0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x52,
+	0x09, 0x75, 0x70, 0x64, 0x61, 0x74, 0x65, 0x64, 0x41, 0x74, 0x22, 0xaf, 0x03, 0x0a, 0x11, 0x43,
+	0x72, 0x65, 0x61, 0x74, 0x65, 0x46, 0x6c, 0x61, 0x67, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74,
+	0x12, 0x1b, 0x0a, 0x09, 0x66, 0x6c, 0x61, 0x67, 0x5f, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x01, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x08, 0x66, 0x6c, 0x61, 0x67, 0x4e, 0x61, 0x6d, 0x65, 0x12, 0x21, 0x0a,
+	0x0c, 0x70, 0x72, 0x6f, 0x6a, 0x65, 0x63, 0x74, 0x5f, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x02, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x0b, 0x70, 0x72, 0x6f, 0x6a, 0x65, 0x63, 0x74, 0x4e, 0x61, 0x6d, 0x65,
+	0x12, 0x2d, 0x0a, 0x09, 0x66, 0x6c, 0x61, 0x67, 0x5f, 0x74, 0x79, 0x70, 0x65, 0x18, 0x03, 0x20,
+	0x01, 0x28, 0x0e, 0x32, 0x10, 0x2e, 0x66, 0x6c, 0x61, 0x67, 0x70, 0x62, 0x2e, 0x46, 0x6c, 0x61,
+	0x67, 0x54, 0x79, 0x70, 0x65, 0x52, 0x08, 0x66, 0x6c, 0x61, 0x67, 0x54, 0x79, 0x70, 0x65, 0x12,
+	0x31, 0x0a, 0x0a, 0x76, 0x61, 0x72, 0x69, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x18, 0x04, 0x20,
+	0x03, 0x28, 0x0b, 0x32, 0x11, 0x2e, 0x66, 0x6c, 0x61, 0x67, 0x70, 0x62, 0x2e, 0x56, 0x61, 0x72,
+	0x69, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x0a, 0x76, 0x61, 0x72, 0x69, 0x61, 0x74, 0x69, 0x6f,
+	0x6e, 0x73, 0x12, 0x2b, 0x0a, 0x11, 0x64, 0x65, 0x66, 0x61, 0x75, 0x6c, 0x74, 0x5f, 0x76, 0x61,
+	0x72, 0x69, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x05, 0x20, 0x01, 0x28, 0x09, 0x52, 0x10, 0x64,
+	0x65, 0x66, 0x61, 0x75, 0x6c, 0x74, 0x56, 0x61, 0x72, 0x69, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x12,
+	0x23, 0x0a, 0x0d, 0x6f, 0x66, 0x66, 0x5f, 0x76, 0x61, 0x72, 0x69, 0x61, 0x74, 0x69, 0x6f, 0x6e,
+	0x18, 0x06, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0c, 0x6f, 0x66, 0x66, 0x56, 0x61, 0x72, 0x69, 0x61,
+	0x74, 0x69, 0x6f, 0x6e, 0x12, 0x21, 0x0a, 0x0c, 0x64, 0x69, 0x73, 0x70, 0x6c, 0x61, 0x79, 0x5f,
+	0x6e, 0x61, 0x6d, 0x65, 0x18, 0x07, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0b, 0x64, 0x69, 0x73, 0x70,
+	0x6c, 0x61, 0x79, 0x4e, 0x61, 0x6d, 0x65, 0x12, 0x20, 0x0a, 0x0b, 0x64, 0x65, 0x73, 0x63, 0x72,
+	0x69, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x08, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0b, 0x64, 0x65,
+	0x73, 0x63, 0x72, 0x69, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x27, 0x0a, 0x05, 0x6f, 0x77, 0x6e,
+	0x65, 0x72, 0x18, 0x09, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x11, 0x2e, 0x66, 0x6c, 0x61, 0x67, 0x70,
+	0x62, 0x2e, 0x46, 0x6c, 0x61, 0x67, 0x4f, 0x77, 0x6e, 0x65, 0x72, 0x52, 0x05, 0x6f, 0x77, 0x6e,
+	0x65, 0x72, 0x12, 0x12, 0x0a, 0x04, 0x74, 0x61, 0x67, 0x73, 0x18, 0x0a, 0x20, 0x03, 0x28, 0x09,
+	0x52, 0x04, 0x74, 0x61, 0x67, 0x73, 0x12, 0x24, 0x0a, 0x04, 0x6b, 0x69, 0x6e, 0x64, 0x18, 0x0b,
+	0x20, 0x01, 0x28, 0x0e, 0x32, 0x10, 0x2e, 0x66, 0x6c, 0x61, 0x67, 0x70, 0x62, 0x2e, 0x46, 0x6c,
+	0x61, 0x67, 0x4b, 0x69, 0x6e, 0x64, 0x52, 0x04, 0x6b, 0x69, 0x6e, 0x64, 0x22, 0x14, 0x0a, 0x12,
+	0x43, 0x72, 0x65, 0x61, 0x74, 0x65, 0x46, 0x6c, 0x61, 0x67, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e,
+	0x73, 0x65, 0x22, 0xd2, 0x01, 0x0a, 0x17, 0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x46, 0x6c, 0x61,
+	0x67, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x21,
+	0x0a, 0x0c, 0x70, 0x72, 0x6f, 0x6a, 0x65, 0x63, 0x74, 0x5f, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x01,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x0b, 0x70, 0x72, 0x6f, 0x6a, 0x65, 0x63, 0x74, 0x4e, 0x61, 0x6d,
+	0x65, 0x12, 0x29, 0x0a, 0x10, 0x65, 0x6e, 0x76, 0x69, 0x72, 0x6f, 0x6e, 0x6d, 0x65, 0x6e, 0x74,
+	0x5f, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0f, 0x65, 0x6e, 0x76,
+	0x69, 0x72, 0x6f, 0x6e, 0x6d, 0x65, 0x6e, 0x74, 0x4e, 0x61, 0x6d, 0x65, 0x12, 0x1b, 0x0a, 0x09,
+	0x66, 0x6c, 0x61, 0x67, 0x5f, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x08, 0x66, 0x6c, 0x61, 0x67, 0x4e, 0x61, 0x6d, 0x65, 0x12, 0x1b, 0x0a, 0x09, 0x69, 0x73, 0x5f,
+	0x61, 0x63, 0x74, 0x69, 0x76, 0x65, 0x18, 0x04, 0x20, 0x01, 0x28, 0x08, 0x52, 0x08, 0x69, 0x73,
+	0x41, 0x63, 0x74, 0x69, 0x76, 0x65, 0x12, 0x29, 0x0a, 0x07, 0x72, 0x6f, 0x6c, 0x6c, 0x6f, 0x75,
+	0x74, 0x18, 0x06, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0f, 0x2e, 0x66, 0x6c, 0x61, 0x67, 0x70, 0x62,
+	0x2e, 0x52, 0x6f, 0x6c, 0x6c, 0x6f, 0x75, 0x74, 0x52, 0x07, 0x72, 0x6f, 0x6c, 0x6c, 0x6f, 0x75,
+	0x74, 0x4a, 0x04, 0x08, 0x05, 0x10, 0x06, 0x22, 0x1a, 0x0a, 0x18, 0x55, 0x70, 0x64, 0x61, 0x74,
+	0x65, 0x46, 0x6c, 0x61, 0x67, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f,
+	0x6e, 0x73, 0x65, 0x22, 0xa5, 0x01, 0x0a, 0x1a, 0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x46, 0x6c,
+	0x61, 0x67, 0x56, 0x61, 0x72, 0x69, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x65, 0x71, 0x75, 0x65,
+	0x73, 0x74, 0x12, 0x21, 0x0a, 0x0c, 0x70, 0x72, 0x6f, 0x6a, 0x65, 0x63, 0x74, 0x5f, 0x6e, 0x61,
+	0x6d, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0b, 0x70, 0x72, 0x6f, 0x6a, 0x65, 0x63,
+	0x74, 0x4e, 0x61, 0x6d, 0x65, 0x12, 0x29, 0x0a, 0x10, 0x65, 0x6e, 0x76, 0x69, 0x72, 0x6f, 0x6e,
+	0x6d, 0x65, 0x6e, 0x74, 0x5f, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x0f, 0x65, 0x6e, 0x76, 0x69, 0x72, 0x6f, 0x6e, 0x6d, 0x65, 0x6e, 0x74, 0x4e, 0x61, 0x6d, 0x65,
+	0x12, 0x1b, 0x0a, 0x09, 0x66, 0x6c, 0x61, 0x67, 0x5f, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x03, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x08, 0x66, 0x6c, 0x61, 0x67, 0x4e, 0x61, 0x6d, 0x65, 0x12, 0x1c, 0x0a,
+	0x09, 0x76, 0x61, 0x72, 0x69, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x09, 0x76, 0x61, 0x72, 0x69, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x22, 0x1d, 0x0a, 0x1b, 0x55,
+	0x70, 0x64, 0x61, 0x74, 0x65, 0x46, 0x6c, 0x61, 0x67, 0x56, 0x61, 0x72, 0x69, 0x61, 0x74, 0x69,
+	0x6f, 0x6e, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x80, 0x01, 0x0a, 0x13, 0x47,
+	0x65, 0x74, 0x46, 0x6c, 0x61, 0x67, 0x52, 0x75, 0x6c, 0x65, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65,
+	0x73, 0x74, 0x12, 0x21, 0x0a, 0x0c, 0x70, 0x72, 0x6f, 0x6a, 0x65, 0x63, 0x74, 0x5f, 0x6e, 0x61,
+	0x6d, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0b, 0x70, 0x72, 0x6f, 0x6a, 0x65, 0x63,
+	0x74, 0x4e, 0x61, 0x6d, 0x65, 0x12, 0x29, 0x0a, 0x10, 0x65, 0x6e, 0x76, 0x69, 0x72, 0x6f, 0x6e,
+	0x6d, 0x65, 0x6e, 0x74, 0x5f, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x0f, 0x65, 0x6e, 0x76, 0x69, 0x72, 0x6f, 0x6e, 0x6d, 0x65, 0x6e, 0x74, 0x4e, 0x61, 0x6d, 0x65,
+	0x12, 0x1b, 0x0a, 0x09, 0x66, 0x6c, 0x61, 0x67, 0x5f, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x03, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x08, 0x66, 0x6c, 0x61, 0x67, 0x4e, 0x61, 0x6d, 0x65, 0x22, 0x3a, 0x0a,
+	0x14, 0x47, 0x65, 0x74, 0x46, 0x6c, 0x61, 0x67, 0x52, 0x75, 0x6c, 0x65, 0x73, 0x52, 0x65, 0x73,
+	0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x22, 0x0a, 0x05, 0x72, 0x75, 0x6c, 0x65, 0x73, 0x18, 0x01,
+	0x20, 0x03, 0x28, 0x0b, 0x32, 0x0c, 0x2e, 0x66, 0x6c, 0x61, 0x67, 0x70, 0x62, 0x2e, 0x52, 0x75,
+	0x6c, 0x65, 0x52, 0x05, 0x72, 0x75, 0x6c, 0x65, 0x73, 0x22, 0xa7, 0x01, 0x0a, 0x16, 0x55, 0x70,
+	0x64, 0x61, 0x74, 0x65, 0x46, 0x6c, 0x61, 0x67, 0x52, 0x75, 0x6c, 0x65, 0x73, 0x52, 0x65, 0x71,
+	0x75, 0x65, 0x73, 0x74, 0x12, 0x21, 0x0a, 0x0c, 0x70, 0x72, 0x6f, 0x6a, 0x65, 0x63, 0x74, 0x5f,
+	0x6e, 0x61, 0x6d, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0b, 0x70, 0x72, 0x6f, 0x6a,
+	0x65, 0x63, 0x74, 0x4e, 0x61, 0x6d, 0x65, 0x12, 0x29, 0x0a, 0x10, 0x65, 0x6e, 0x76, 0x69, 0x72,
+	0x6f, 0x6e, 0x6d, 0x65, 0x6e, 0x74, 0x5f, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x0f, 0x65, 0x6e, 0x76, 0x69, 0x72, 0x6f, 0x6e, 0x6d, 0x65, 0x6e, 0x74, 0x4e, 0x61,
+	0x6d, 0x65, 0x12, 0x1b, 0x0a, 0x09, 0x66, 0x6c, 0x61, 0x67, 0x5f, 0x6e, 0x61, 0x6d, 0x65, 0x18,
+	0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x66, 0x6c, 0x61, 0x67, 0x4e, 0x61, 0x6d, 0x65, 0x12,
+	0x22, 0x0a, 0x05, 0x72, 0x75, 0x6c, 0x65, 0x73, 0x18, 0x04, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x0c,
+	0x2e, 0x66, 0x6c, 0x61, 0x67, 0x70, 0x62, 0x2e, 0x52, 0x75, 0x6c, 0x65, 0x52, 0x05, 0x72, 0x75,
+	0x6c, 0x65, 0x73, 0x22, 0x19, 0x0a, 0x17, 0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x46, 0x6c, 0x61,
+	0x67, 0x52, 0x75, 0x6c, 0x65, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0xc6,
+	0x02, 0x0a, 0x10, 0x4c, 0x69, 0x73, 0x74, 0x46, 0x6c, 0x61, 0x67, 0x73, 0x52, 0x65, 0x71, 0x75,
+	0x65, 0x73, 0x74, 0x12, 0x21, 0x0a, 0x0c, 0x70, 0x72, 0x6f, 0x6a, 0x65, 0x63, 0x74, 0x5f, 0x6e,
+	0x61, 0x6d, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0b, 0x70, 0x72, 0x6f, 0x6a, 0x65,
+	0x63, 0x74, 0x4e, 0x61, 0x6d, 0x65, 0x12, 0x1b, 0x0a, 0x09, 0x70, 0x61, 0x67, 0x65, 0x5f, 0x73,
+	0x69, 0x7a, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x05, 0x52, 0x08, 0x70, 0x61, 0x67, 0x65, 0x53,
+	0x69, 0x7a, 0x65, 0x12, 0x1d, 0x0a, 0x0a, 0x70, 0x61, 0x67, 0x65, 0x5f, 0x74, 0x6f, 0x6b, 0x65,
+	0x6e, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x70, 0x61, 0x67, 0x65, 0x54, 0x6f, 0x6b,
+	0x65, 0x6e, 0x12, 0x2a, 0x0a, 0x07, 0x73, 0x6f, 0x72, 0x74, 0x5f, 0x62, 0x79, 0x18, 0x04, 0x20,
+	0x01, 0x28, 0x0e, 0x32, 0x11, 0x2e, 0x66, 0x6c, 0x61, 0x67, 0x70, 0x62, 0x2e, 0x53, 0x6f, 0x72,
+	0x74, 0x46, 0x69, 0x65, 0x6c, 0x64, 0x52, 0x06, 0x73, 0x6f, 0x72, 0x74, 0x42, 0x79, 0x12, 0x1e,
+	0x0a, 0x0a, 0x64, 0x65, 0x73, 0x63, 0x65, 0x6e, 0x64, 0x69, 0x6e, 0x67, 0x18, 0x05, 0x20, 0x01,
+	0x28, 0x08, 0x52, 0x0a, 0x64, 0x65, 0x73, 0x63, 0x65, 0x6e, 0x64, 0x69, 0x6e, 0x67, 0x12, 0x1f,
+	0x0a, 0x0b, 0x6e, 0x61, 0x6d, 0x65, 0x5f, 0x66, 0x69, 0x6c, 0x74, 0x65, 0x72, 0x18, 0x06, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x0a, 0x6e, 0x61, 0x6d, 0x65, 0x46, 0x69, 0x6c, 0x74, 0x65, 0x72, 0x12,
+	0x24, 0x0a, 0x04, 0x6b, 0x69, 0x6e, 0x64, 0x18, 0x07, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x10, 0x2e,
+	0x66, 0x6c, 0x61, 0x67, 0x70, 0x62, 0x2e, 0x46, 0x6c, 0x61, 0x67, 0x4b, 0x69, 0x6e, 0x64, 0x52,
+	0x04, 0x6b, 0x69, 0x6e, 0x64, 0x12, 0x14, 0x0a, 0x05, 0x6f, 0x77, 0x6e, 0x65, 0x72, 0x18, 0x08,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x6f, 0x77, 0x6e, 0x65, 0x72, 0x12, 0x12, 0x0a, 0x04, 0x74,
+	0x61, 0x67, 0x73, 0x18, 0x09, 0x20, 0x03, 0x28, 0x09, 0x52, 0x04, 0x74, 0x61, 0x67, 0x73, 0x12,
+	0x16, 0x0a, 0x06, 0x73, 0x65, 0x61, 0x72, 0x63, 0x68, 0x18, 0x0a, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x06, 0x73, 0x65, 0x61, 0x72, 0x63, 0x68, 0x22, 0x66, 0x0a, 0x11, 0x4c, 0x69, 0x73, 0x74, 0x46,
+	0x6c, 0x61, 0x67, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x29, 0x0a, 0x05,
+	0x66, 0x6c, 0x61, 0x67, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x13, 0x2e, 0x66, 0x6c,
+	0x61, 0x67, 0x70, 0x62, 0x2e, 0x46, 0x6c, 0x61, 0x67, 0x44, 0x65, 0x74, 0x61, 0x69, 0x6c, 0x73,
+	0x52, 0x05, 0x66, 0x6c, 0x61, 0x67, 0x73, 0x12, 0x26, 0x0a, 0x0f, 0x6e, 0x65, 0x78, 0x74, 0x5f,
+	0x70, 0x61, 0x67, 0x65, 0x5f, 0x74, 0x6f, 0x6b, 0x65, 0x6e, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x0d, 0x6e, 0x65, 0x78, 0x74, 0x50, 0x61, 0x67, 0x65, 0x54, 0x6f, 0x6b, 0x65, 0x6e, 0x22,
+	0x50, 0x0a, 0x0e, 0x47, 0x65, 0x74, 0x46, 0x6c, 0x61, 0x67, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73,
+	0x74, 0x12, 0x21, 0x0a, 0x0c, 0x70, 0x72, 0x6f, 0x6a, 0x65, 0x63, 0x74, 0x5f, 0x6e, 0x61, 0x6d,
+	0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0b, 0x70, 0x72, 0x6f, 0x6a, 0x65, 0x63, 0x74,
+	0x4e, 0x61, 0x6d, 0x65, 0x12, 0x1b, 0x0a, 0x09, 0x66, 0x6c, 0x61, 0x67, 0x5f, 0x6e, 0x61, 0x6d,
+	0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x66, 0x6c, 0x61, 0x67, 0x4e, 0x61, 0x6d,
+	0x65, 0x22, 0x7d, 0x0a, 0x0f, 0x47, 0x65, 0x74, 0x46, 0x6c, 0x61, 0x67, 0x52, 0x65, 0x73, 0x70,
+	0x6f, 0x6e, 0x73, 0x65, 0x12, 0x27, 0x0a, 0x04, 0x66, 0x6c, 0x61, 0x67, 0x18, 0x01, 0x20, 0x01,
+	0x28, 0x0b, 0x32, 0x13, 0x2e, 0x66, 0x6c, 0x61, 0x67, 0x70, 0x62, 0x2e, 0x46, 0x6c, 0x61, 0x67,
+	0x44, 0x65, 0x74, 0x61, 0x69, 0x6c, 0x73, 0x52, 0x04, 0x66, 0x6c, 0x61, 0x67, 0x12, 0x41, 0x0a,
+	0x0c, 0x65, 0x6e, 0x76, 0x69, 0x72, 0x6f, 0x6e, 0x6d, 0x65, 0x6e, 0x74, 0x73, 0x18, 0x02, 0x20,
+	0x03, 0x28, 0x0b, 0x32, 0x1d, 0x2e, 0x66, 0x6c, 0x61, 0x67, 0x70, 0x62, 0x2e, 0x46, 0x6c, 0x61,
+	0x67, 0x45, 0x6e, 0x76, 0x69, 0x72, 0x6f, 0x6e, 0x6d, 0x65, 0x6e, 0x74, 0x53, 0x74, 0x61, 0x74,
+	0x75, 0x73, 0x52, 0x0c, 0x65, 0x6e, 0x76, 0x69, 0x72, 0x6f, 0x6e, 0x6d, 0x65, 0x6e, 0x74, 0x73,
+	0x22, 0x72, 0x0a, 0x12, 0x41, 0x72, 0x63, 0x68, 0x69, 0x76, 0x65, 0x46, 0x6c, 0x61, 0x67, 0x52,
+	0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x21, 0x0a, 0x0c, 0x70, 0x72, 0x6f, 0x6a, 0x65, 0x63,
+	0x74, 0x5f, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0b, 0x70, 0x72,
+	0x6f, 0x6a, 0x65, 0x63, 0x74, 0x4e, 0x61, 0x6d, 0x65, 0x12, 0x1b, 0x0a, 0x09, 0x66, 0x6c, 0x61,
+	0x67, 0x5f, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x66, 0x6c,
+	0x61, 0x67, 0x4e, 0x61, 0x6d, 0x65, 0x12, 0x1c, 0x0a, 0x09, 0x76, 0x61, 0x72, 0x69, 0x61, 0x74,
+	0x69, 0x6f, 0x6e, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x76, 0x61, 0x72, 0x69, 0x61,
+	0x74, 0x69, 0x6f, 0x6e, 0x22, 0x15, 0x0a, 0x13, 0x41, 0x72, 0x63, 0x68, 0x69, 0x76, 0x65, 0x46,
+	0x6c, 0x61, 0x67, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x54, 0x0a, 0x12, 0x52,
+	0x65, 0x73, 0x74, 0x6f, 0x72, 0x65, 0x46, 0x6c, 0x61, 0x67, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73,
+	0x74, 0x12, 0x21, 0x0a, 0x0c, 0x70, 0x72, 0x6f, 0x6a, 0x65, 0x63, 0x74, 0x5f, 0x6e, 0x61, 0x6d,
+	0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0b, 0x70, 0x72, 0x6f, 0x6a, 0x65, 0x63, 0x74,
+	0x4e, 0x61, 0x6d, 0x65, 0x12, 0x1b, 0x0a, 0x09, 0x66, 0x6c, 0x61, 0x67, 0x5f, 0x6e, 0x61, 0x6d,
+	0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x66, 0x6c, 0x61, 0x67, 0x4e, 0x61, 0x6d,
+	0x65, 0x22, 0x15, 0x0a, 0x13, 0x52, 0x65, 0x73, 0x74, 0x6f, 0x72, 0x65, 0x46, 0x6c, 0x61, 0x67,
+	0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x53, 0x0a, 0x11, 0x44, 0x65, 0x6c, 0x65,
+	0x74, 0x65, 0x46, 0x6c, 0x61, 0x67, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x21, 0x0a,
+	0x0c, 0x70, 0x72, 0x6f, 0x6a, 0x65, 0x63, 0x74, 0x5f, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x01, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x0b, 0x70, 0x72, 0x6f, 0x6a, 0x65, 0x63, 0x74, 0x4e, 0x61, 0x6d, 0x65,
+	0x12, 0x1b, 0x0a, 0x09, 0x66, 0x6c, 0x61, 0x67, 0x5f, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x02, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x08, 0x66, 0x6c, 0x61, 0x67, 0x4e, 0x61, 0x6d, 0x65, 0x22, 0x14, 0x0a,
+	0x12, 0x44, 0x65, 0x6c, 0x65, 0x74, 0x65, 0x46, 0x6c, 0x61, 0x67, 0x52, 0x65, 0x73, 0x70, 0x6f,
+	0x6e, 0x73, 0x65, 0x22, 0x9a, 0x01, 0x0a, 0x11, 0x52, 0x65, 0x6e, 0x61, 0x6d, 0x65, 0x46, 0x6c,
+	0x61, 0x67, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x21, 0x0a, 0x0c, 0x70, 0x72, 0x6f,
+	0x6a, 0x65, 0x63, 0x74, 0x5f, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x0b, 0x70, 0x72, 0x6f, 0x6a, 0x65, 0x63, 0x74, 0x4e, 0x61, 0x6d, 0x65, 0x12, 0x1b, 0x0a, 0x09,
+	0x66, 0x6c, 0x61, 0x67, 0x5f, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x08, 0x66, 0x6c, 0x61, 0x67, 0x4e, 0x61, 0x6d, 0x65, 0x12, 0x22, 0x0a, 0x0d, 0x6e, 0x65, 0x77,
+	0x5f, 0x66, 0x6c, 0x61, 0x67, 0x5f, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x0b, 0x6e, 0x65, 0x77, 0x46, 0x6c, 0x61, 0x67, 0x4e, 0x61, 0x6d, 0x65, 0x12, 0x21, 0x0a,
+	0x0c, 0x64, 0x69, 0x73, 0x70, 0x6c, 0x61, 0x79, 0x5f, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x04, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x0b, 0x64, 0x69, 0x73, 0x70, 0x6c, 0x61, 0x79, 0x4e, 0x61, 0x6d, 0x65,
+	0x22, 0x5a, 0x0a, 0x12, 0x52, 0x65, 0x6e, 0x61, 0x6d, 0x65, 0x46, 0x6c, 0x61, 0x67, 0x52, 0x65,
+	0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x44, 0x0a, 0x10, 0x61, 0x6c, 0x69, 0x61, 0x73, 0x5f,
+	0x65, 0x78, 0x70, 0x69, 0x72, 0x65, 0x73, 0x5f, 0x61, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b,
+	0x32, 0x1a, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62,
+	0x75, 0x66, 0x2e, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x52, 0x0e, 0x61, 0x6c,
+	0x69, 0x61, 0x73, 0x45, 0x78, 0x70, 0x69, 0x72, 0x65, 0x73, 0x41, 0x74, 0x22, 0xe0, 0x01, 0x0a,
+	0x19, 0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x46, 0x6c, 0x61, 0x67, 0x4d, 0x65, 0x74, 0x61, 0x64,
+	0x61, 0x74, 0x61, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x21, 0x0a, 0x0c, 0x70, 0x72,
+	0x6f, 0x6a, 0x65, 0x63, 0x74, 0x5f, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x0b, 0x70, 0x72, 0x6f, 0x6a, 0x65, 0x63, 0x74, 0x4e, 0x61, 0x6d, 0x65, 0x12, 0x1b, 0x0a,
+	0x09, 0x66, 0x6c, 0x61, 0x67, 0x5f, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x08, 0x66, 0x6c, 0x61, 0x67, 0x4e, 0x61, 0x6d, 0x65, 0x12, 0x20, 0x0a, 0x0b, 0x64, 0x65,
+	0x73, 0x63, 0x72, 0x69, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x0b, 0x64, 0x65, 0x73, 0x63, 0x72, 0x69, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x27, 0x0a, 0x05,
+	0x6f, 0x77, 0x6e, 0x65, 0x72, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x11, 0x2e, 0x66, 0x6c,
+	0x61, 0x67, 0x70, 0x62, 0x2e, 0x46, 0x6c, 0x61, 0x67, 0x4f, 0x77, 0x6e, 0x65, 0x72, 0x52, 0x05,
+	0x6f, 0x77, 0x6e, 0x65, 0x72, 0x12, 0x12, 0x0a, 0x04, 0x74, 0x61, 0x67, 0x73, 0x18, 0x05, 0x20,
+	0x03, 0x28, 0x09, 0x52, 0x04, 0x74, 0x61, 0x67, 0x73, 0x12, 0x24, 0x0a, 0x04, 0x6b, 0x69, 0x6e,
+	0x64, 0x18, 0x06, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x10, 0x2e, 0x66, 0x6c, 0x61, 0x67, 0x70, 0x62,
+	0x2e, 0x46, 0x6c, 0x61, 0x67, 0x4b, 0x69, 0x6e, 0x64, 0x52, 0x04, 0x6b, 0x69, 0x6e, 0x64, 0x22,
+	0x45, 0x0a, 0x1a, 0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x46, 0x6c, 0x61, 0x67, 0x4d, 0x65, 0x74,
+	0x61, 0x64, 0x61, 0x74, 0x61, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x27, 0x0a,
+	0x04, 0x66, 0x6c, 0x61, 0x67, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x13, 0x2e, 0x66, 0x6c,
+	0x61, 0x67, 0x70, 0x62, 0x2e, 0x46, 0x6c, 0x61, 0x67, 0x44, 0x65, 0x74, 0x61, 0x69, 0x6c, 0x73,
+	0x52, 0x04, 0x66, 0x6c, 0x61, 0x67, 0x2a, 0x61, 0x0a, 0x08, 0x46, 0x6c, 0x61, 0x67, 0x54, 0x79,
+	0x70, 0x65, 0x12, 0x15, 0x0a, 0x11, 0x46, 0x4c, 0x41, 0x47, 0x5f, 0x54, 0x59, 0x50, 0x45, 0x5f,
+	0x42, 0x4f, 0x4f, 0x4c, 0x45, 0x41, 0x4e, 0x10, 0x00, 0x12, 0x14, 0x0a, 0x10, 0x46, 0x4c, 0x41,
+	0x47, 0x5f, 0x54, 0x59, 0x50, 0x45, 0x5f, 0x53, 0x54, 0x52, 0x49, 0x4e, 0x47, 0x10, 0x01, 0x12,
+	0x14, 0x0a, 0x10, 0x46, 0x4c, 0x41, 0x47, 0x5f, 0x54, 0x59, 0x50, 0x45, 0x5f, 0x4e, 0x55, 0x4d,
+	0x42, 0x45, 0x52, 0x10, 0x02, 0x12, 0x12, 0x0a, 0x0e, 0x46, 0x4c, 0x41, 0x47, 0x5f, 0x54, 0x59,
+	0x50, 0x45, 0x5f, 0x4a, 0x53, 0x4f, 0x4e, 0x10, 0x03, 0x2a, 0x83, 0x01, 0x0a, 0x08, 0x46, 0x6c,
+	0x61, 0x67, 0x4b, 0x69, 0x6e, 0x64, 0x12, 0x19, 0x0a, 0x15, 0x46, 0x4c, 0x41, 0x47, 0x5f, 0x4b,
+	0x49, 0x4e, 0x44, 0x5f, 0x55, 0x4e, 0x53, 0x50, 0x45, 0x43, 0x49, 0x46, 0x49, 0x45, 0x44, 0x10,
+	0x00, 0x12, 0x15, 0x0a, 0x11, 0x46, 0x4c, 0x41, 0x47, 0x5f, 0x4b, 0x49, 0x4e, 0x44, 0x5f, 0x52,
+	0x45, 0x4c, 0x45, 0x41, 0x53, 0x45, 0x10, 0x01, 0x12, 0x18, 0x0a, 0x14, 0x46, 0x4c, 0x41, 0x47,
+	0x5f, 0x4b, 0x49, 0x4e, 0x44, 0x5f, 0x45, 0x58, 0x50, 0x45, 0x52, 0x49, 0x4d, 0x45, 0x4e, 0x54,
+	0x10, 0x02, 0x12, 0x11, 0x0a, 0x0d, 0x46, 0x4c, 0x41, 0x47, 0x5f, 0x4b, 0x49, 0x4e, 0x44, 0x5f,
+	0x4f, 0x50, 0x53, 0x10, 0x03, 0x12, 0x18, 0x0a, 0x14, 0x46, 0x4c, 0x41, 0x47, 0x5f, 0x4b, 0x49,
+	0x4e, 0x44, 0x5f, 0x50, 0x45, 0x52, 0x4d, 0x49, 0x53, 0x53, 0x49, 0x4f, 0x4e, 0x10, 0x04, 0x2a,
+	0x51, 0x0a, 0x09, 0x4f, 0x77, 0x6e, 0x65, 0x72, 0x54, 0x79, 0x70, 0x65, 0x12, 0x1a, 0x0a, 0x16,
+	0x4f, 0x57, 0x4e, 0x45, 0x52, 0x5f, 0x54, 0x59, 0x50, 0x45, 0x5f, 0x55, 0x4e, 0x53, 0x50, 0x45,
+	0x43, 0x49, 0x46, 0x49, 0x45, 0x44, 0x10, 0x00, 0x12, 0x13, 0x0a, 0x0f, 0x4f, 0x57, 0x4e, 0x45,
+	0x52, 0x5f, 0x54, 0x59, 0x50, 0x45, 0x5f, 0x55, 0x53, 0x45, 0x52, 0x10, 0x01, 0x12, 0x13, 0x0a,
+	0x0f, 0x4f, 0x57, 0x4e, 0x45, 0x52, 0x5f, 0x54, 0x59, 0x50, 0x45, 0x5f, 0x54, 0x45, 0x41, 0x4d,
+	0x10, 0x02, 0x2a, 0xb2, 0x04, 0x0a, 0x0c, 0x52, 0x75, 0x6c, 0x65, 0x4f, 0x70, 0x65, 0x72, 0x61,
+	0x74, 0x6f, 0x72, 0x12, 0x1d, 0x0a, 0x19, 0x52, 0x55, 0x4c, 0x45, 0x5f, 0x4f, 0x50, 0x45, 0x52,
+	0x41, 0x54, 0x4f, 0x52, 0x5f, 0x55, 0x4e, 0x53, 0x50, 0x45, 0x43, 0x49, 0x46, 0x49, 0x45, 0x44,
+	0x10, 0x00, 0x12, 0x18, 0x0a, 0x14, 0x52, 0x55, 0x4c, 0x45, 0x5f, 0x4f, 0x50, 0x45, 0x52, 0x41,
+	0x54, 0x4f, 0x52, 0x5f, 0x45, 0x51, 0x55, 0x41, 0x4c, 0x53, 0x10, 0x01, 0x12, 0x1c, 0x0a, 0x18,
+	0x52, 0x55, 0x4c, 0x45, 0x5f, 0x4f, 0x50, 0x45, 0x52, 0x41, 0x54, 0x4f, 0x52, 0x5f, 0x4e, 0x4f,
+	0x54, 0x5f, 0x45, 0x51, 0x55, 0x41, 0x4c, 0x53, 0x10, 0x02, 0x12, 0x14, 0x0a, 0x10, 0x52, 0x55,
+	0x4c, 0x45, 0x5f, 0x4f, 0x50, 0x45, 0x52, 0x41, 0x54, 0x4f, 0x52, 0x5f, 0x49, 0x4e, 0x10, 0x03,
+	0x12, 0x18, 0x0a, 0x14, 0x52, 0x55, 0x4c, 0x45, 0x5f, 0x4f, 0x50, 0x45, 0x52, 0x41, 0x54, 0x4f,
+	0x52, 0x5f, 0x4e, 0x4f, 0x54, 0x5f, 0x49, 0x4e, 0x10, 0x04, 0x12, 0x1a, 0x0a, 0x16, 0x52, 0x55,
+	0x4c, 0x45, 0x5f, 0x4f, 0x50, 0x45, 0x52, 0x41, 0x54, 0x4f, 0x52, 0x5f, 0x43, 0x4f, 0x4e, 0x54,
+	0x41, 0x49, 0x4e, 0x53, 0x10, 0x05, 0x12, 0x1d, 0x0a, 0x19, 0x52, 0x55, 0x4c, 0x45, 0x5f, 0x4f,
+	0x50, 0x45, 0x52, 0x41, 0x54, 0x4f, 0x52, 0x5f, 0x53, 0x54, 0x41, 0x52, 0x54, 0x53, 0x5f, 0x57,
+	0x49, 0x54, 0x48, 0x10, 0x06, 0x12, 0x1b, 0x0a, 0x17, 0x52, 0x55, 0x4c, 0x45, 0x5f, 0x4f, 0x50,
+	0x45, 0x52, 0x41, 0x54, 0x4f, 0x52, 0x5f, 0x45, 0x4e, 0x44, 0x53, 0x5f, 0x57, 0x49, 0x54, 0x48,
+	0x10, 0x07, 0x12, 0x19, 0x0a, 0x15, 0x52, 0x55, 0x4c, 0x45, 0x5f, 0x4f, 0x50, 0x45, 0x52, 0x41,
+	0x54, 0x4f, 0x52, 0x5f, 0x4d, 0x41, 0x54, 0x43, 0x48, 0x45, 0x53, 0x10, 0x08, 0x12, 0x1b, 0x0a,
+	0x17, 0x52, 0x55, 0x4c, 0x45, 0x5f, 0x4f, 0x50, 0x45, 0x52, 0x41, 0x54, 0x4f, 0x52, 0x5f, 0x4c,
+	0x45, 0x53, 0x53, 0x5f, 0x54, 0x48, 0x41, 0x4e, 0x10, 0x09, 0x12, 0x24, 0x0a, 0x20, 0x52, 0x55,
+	0x4c, 0x45, 0x5f, 0x4f, 0x50, 0x45, 0x52, 0x41, 0x54, 0x4f, 0x52, 0x5f, 0x4c, 0x45, 0x53, 0x53,
+	0x5f, 0x54, 0x48, 0x41, 0x4e, 0x5f, 0x4f, 0x52, 0x5f, 0x45, 0x51, 0x55, 0x41, 0x4c, 0x10, 0x0a,
+	0x12, 0x1e, 0x0a, 0x1a, 0x52, 0x55, 0x4c, 0x45, 0x5f, 0x4f, 0x50, 0x45, 0x52, 0x41, 0x54, 0x4f,
+	0x52, 0x5f, 0x47, 0x52, 0x45, 0x41, 0x54, 0x45, 0x52, 0x5f, 0x54, 0x48, 0x41, 0x4e, 0x10, 0x0b,
+	0x12, 0x27, 0x0a, 0x23, 0x52, 0x55, 0x4c, 0x45, 0x5f, 0x4f, 0x50, 0x45, 0x52, 0x41, 0x54, 0x4f,
+	0x52, 0x5f, 0x47, 0x52, 0x45, 0x41, 0x54, 0x45, 0x52, 0x5f, 0x54, 0x48, 0x41, 0x4e, 0x5f, 0x4f,
+	0x52, 0x5f, 0x45, 0x51, 0x55, 0x41, 0x4c, 0x10, 0x0c, 0x12, 0x1e, 0x0a, 0x1a, 0x52, 0x55, 0x4c,
+	0x45, 0x5f, 0x4f, 0x50, 0x45, 0x52, 0x41, 0x54, 0x4f, 0x52, 0x5f, 0x53, 0x45, 0x4d, 0x56, 0x45,
+	0x52, 0x5f, 0x45, 0x51, 0x55, 0x41, 0x4c, 0x10, 0x0d, 0x12, 0x22, 0x0a, 0x1e, 0x52, 0x55, 0x4c,
+	0x45, 0x5f, 0x4f, 0x50, 0x45, 0x52, 0x41, 0x54, 0x4f, 0x52, 0x5f, 0x53, 0x45, 0x4d, 0x56, 0x45,
+	0x52, 0x5f, 0x4c, 0x45, 0x53, 0x53, 0x5f, 0x54, 0x48, 0x41, 0x4e, 0x10, 0x0e, 0x12, 0x25, 0x0a,
+	0x21, 0x52, 0x55, 0x4c, 0x45, 0x5f, 0x4f, 0x50, 0x45, 0x52, 0x41, 0x54, 0x4f, 0x52, 0x5f, 0x53,
+	0x45, 0x4d, 0x56, 0x45, 0x52, 0x5f, 0x47, 0x52, 0x45, 0x41, 0x54, 0x45, 0x52, 0x5f, 0x54, 0x48,
+	0x41, 0x4e, 0x10, 0x0f, 0x12, 0x18, 0x0a, 0x14, 0x52, 0x55, 0x4c, 0x45, 0x5f, 0x4f, 0x50, 0x45,
+	0x52, 0x41, 0x54, 0x4f, 0x52, 0x5f, 0x42, 0x45, 0x46, 0x4f, 0x52, 0x45, 0x10, 0x10, 0x12, 0x17,
+	0x0a, 0x13, 0x52, 0x55, 0x4c, 0x45, 0x5f, 0x4f, 0x50, 0x45, 0x52, 0x41, 0x54, 0x4f, 0x52, 0x5f,
+	0x41, 0x46, 0x54, 0x45, 0x52, 0x10, 0x11, 0x2a, 0x3b, 0x0a, 0x09, 0x53, 0x6f, 0x72, 0x74, 0x46,
+	0x69, 0x65, 0x6c, 0x64, 0x12, 0x13, 0x0a, 0x0f, 0x53, 0x4f, 0x52, 0x54, 0x5f, 0x46, 0x49, 0x45,
+	0x4c, 0x44, 0x5f, 0x4e, 0x41, 0x4d, 0x45, 0x10, 0x00, 0x12, 0x19, 0x0a, 0x15, 0x53, 0x4f, 0x52,
+	0x54, 0x5f, 0x46, 0x49, 0x45, 0x4c, 0x44, 0x5f, 0x43, 0x52, 0x45, 0x41, 0x54, 0x45, 0x44, 0x5f,
+	0x41, 0x54, 0x10, 0x01, 0x32, 0x96, 0x07, 0x0a, 0x04, 0x46, 0x6c, 0x61, 0x67, 0x12, 0x43, 0x0a,
+	0x0a, 0x43, 0x72, 0x65, 0x61, 0x74, 0x65, 0x46, 0x6c, 0x61, 0x67, 0x12, 0x19, 0x2e, 0x66, 0x6c,
+	0x61, 0x67, 0x70, 0x62, 0x2e, 0x43, 0x72, 0x65, 0x61, 0x74, 0x65, 0x46, 0x6c, 0x61, 0x67, 0x52,
+	0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1a, 0x2e, 0x66, 0x6c, 0x61, 0x67, 0x70, 0x62, 0x2e,
+	0x43, 0x72, 0x65, 0x61, 0x74, 0x65, 0x46, 0x6c, 0x61, 0x67, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e,
+	0x73, 0x65, 0x12, 0x55, 0x0a, 0x10, 0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x46, 0x6c, 0x61, 0x67,
+	0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x12, 0x1f, 0x2e, 0x66, 0x6c, 0x61, 0x67, 0x70, 0x62, 0x2e,
+	0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x46, 0x6c, 0x61, 0x67, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73,
+	0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x20, 0x2e, 0x66, 0x6c, 0x61, 0x67, 0x70, 0x62,
+	0x2e, 0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x46, 0x6c, 0x61, 0x67, 0x53, 0x74, 0x61, 0x74, 0x75,
+	0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x5e, 0x0a, 0x13, 0x55, 0x70, 0x64,
+	0x61, 0x74, 0x65, 0x46, 0x6c, 0x61, 0x67, 0x56, 0x61, 0x72, 0x69, 0x61, 0x74, 0x69, 0x6f, 0x6e,
+	0x12, 0x22, 0x2e, 0x66, 0x6c, 0x61, 0x67, 0x70, 0x62, 0x2e, 0x55, 0x70, 0x64, 0x61, 0x74, 0x65,
+	0x46, 0x6c, 0x61, 0x67, 0x56, 0x61, 0x72, 0x69, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x65, 0x71,
+	0x75, 0x65, 0x73, 0x74, 0x1a, 0x23, 0x2e, 0x66, 0x6c, 0x61, 0x67, 0x70, 0x62, 0x2e, 0x55, 0x70,
+	0x64, 0x61, 0x74, 0x65, 0x46, 0x6c, 0x61, 0x67, 0x56, 0x61, 0x72, 0x69, 0x61, 0x74, 0x69, 0x6f,
+	0x6e, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x49, 0x0a, 0x0c, 0x47, 0x65, 0x74,
+	0x46, 0x6c, 0x61, 0x67, 0x52, 0x75, 0x6c, 0x65, 0x73, 0x12, 0x1b, 0x2e, 0x66, 0x6c, 0x61, 0x67,
+	0x70, 0x62, 0x2e, 0x47, 0x65, 0x74, 0x46, 0x6c, 0x61, 0x67, 0x52, 0x75, 0x6c, 0x65, 0x73, 0x52,
+	0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1c, 0x2e, 0x66, 0x6c, 0x61, 0x67, 0x70, 0x62, 0x2e,
+	0x47, 0x65, 0x74, 0x46, 0x6c, 0x61, 0x67, 0x52, 0x75, 0x6c, 0x65, 0x73, 0x52, 0x65, 0x73, 0x70,
+	0x6f, 0x6e, 0x73, 0x65, 0x12, 0x52, 0x0a, 0x0f, 0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x46, 0x6c,
+	0x61, 0x67, 0x52, 0x75, 0x6c, 0x65, 0x73, 0x12, 0x1e, 0x2e, 0x66, 0x6c, 0x61, 0x67, 0x70, 0x62,
+	0x2e, 0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x46, 0x6c, 0x61, 0x67, 0x52, 0x75, 0x6c, 0x65, 0x73,
+	0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1f, 0x2e, 0x66, 0x6c, 0x61, 0x67, 0x70, 0x62,
+	0x2e, 0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x46, 0x6c, 0x61, 0x67, 0x52, 0x75, 0x6c, 0x65, 0x73,
+	0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x40, 0x0a, 0x09, 0x4c, 0x69, 0x73, 0x74,
+	0x46, 0x6c, 0x61, 0x67, 0x73, 0x12, 0x18, 0x2e, 0x66, 0x6c, 0x61, 0x67, 0x70, 0x62, 0x2e, 0x4c,
+	0x69, 0x73, 0x74, 0x46, 0x6c, 0x61, 0x67, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a,
+	0x19, 0x2e, 0x66, 0x6c, 0x61, 0x67, 0x70, 0x62, 0x2e, 0x4c, 0x69, 0x73, 0x74, 0x46, 0x6c, 0x61,
+	0x67, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x3a, 0x0a, 0x07, 0x47, 0x65,
+	0x74, 0x46, 0x6c, 0x61, 0x67, 0x12, 0x16, 0x2e, 0x66, 0x6c, 0x61, 0x67, 0x70, 0x62, 0x2e, 0x47,
+	0x65, 0x74, 0x46, 0x6c, 0x61, 0x67, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x17, 0x2e,
+	0x66, 0x6c, 0x61, 0x67, 0x70, 0x62, 0x2e, 0x47, 0x65, 0x74, 0x46, 0x6c, 0x61, 0x67, 0x52, 0x65,
+	0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x46, 0x0a, 0x0b, 0x41, 0x72, 0x63, 0x68, 0x69, 0x76,
+	0x65, 0x46, 0x6c, 0x61, 0x67, 0x12, 0x1a, 0x2e, 0x66, 0x6c, 0x61, 0x67, 0x70, 0x62, 0x2e, 0x41,
+	0x72, 0x63, 0x68, 0x69, 0x76, 0x65, 0x46, 0x6c, 0x61, 0x67, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73,
+	0x74, 0x1a, 0x1b, 0x2e, 0x66, 0x6c, 0x61, 0x67, 0x70, 0x62, 0x2e, 0x41, 0x72, 0x63, 0x68, 0x69,
+	0x76, 0x65, 0x46, 0x6c, 0x61, 0x67, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x46,
+	0x0a, 0x0b, 0x52, 0x65, 0x73, 0x74, 0x6f, 0x72, 0x65, 0x46, 0x6c, 0x61, 0x67, 0x12, 0x1a, 0x2e,
+	0x66, 0x6c, 0x61, 0x67, 0x70, 0x62, 0x2e, 0x52, 0x65, 0x73, 0x74, 0x6f, 0x72, 0x65, 0x46, 0x6c,
+	0x61, 0x67, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1b, 0x2e, 0x66, 0x6c, 0x61, 0x67,
+	0x70, 0x62, 0x2e, 0x52, 0x65, 0x73, 0x74, 0x6f, 0x72, 0x65, 0x46, 0x6c, 0x61, 0x67, 0x52, 0x65,
+	0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x43, 0x0a, 0x0a, 0x44, 0x65, 0x6c, 0x65, 0x74, 0x65,
+	0x46, 0x6c, 0x61, 0x67, 0x12, 0x19, 0x2e, 0x66, 0x6c, 0x61, 0x67, 0x70, 0x62, 0x2e, 0x44, 0x65,
+	0x6c, 0x65, 0x74, 0x65, 0x46, 0x6c, 0x61, 0x67, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a,
+	0x1a, 0x2e, 0x66, 0x6c, 0x61, 0x67, 0x70, 0x62, 0x2e, 0x44, 0x65, 0x6c, 0x65, 0x74, 0x65, 0x46,
+	0x6c, 0x61, 0x67, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x43, 0x0a, 0x0a, 0x52,
+	0x65, 0x6e, 0x61, 0x6d, 0x65, 0x46, 0x6c, 0x61, 0x67, 0x12, 0x19, 0x2e, 0x66, 0x6c, 0x61, 0x67,
+	0x70, 0x62, 0x2e, 0x52, 0x65, 0x6e, 0x61, 0x6d, 0x65, 0x46, 0x6c, 0x61, 0x67, 0x52, 0x65, 0x71,
+	0x75, 0x65, 0x73, 0x74, 0x1a, 0x1a, 0x2e, 0x66, 0x6c, 0x61, 0x67, 0x70, 0x62, 0x2e, 0x52, 0x65,
+	0x6e, 0x61, 0x6d, 0x65, 0x46, 0x6c, 0x61, 0x67, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65,
+	0x12, 0x5b, 0x0a, 0x12, 0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x46, 0x6c, 0x61, 0x67, 0x4d, 0x65,
+	0x74, 0x61, 0x64, 0x61, 0x74, 0x61, 0x12, 0x21, 0x2e, 0x66, 0x6c, 0x61, 0x67, 0x70, 0x62, 0x2e,
+	0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x46, 0x6c, 0x61, 0x67, 0x4d, 0x65, 0x74, 0x61, 0x64, 0x61,
+	0x74, 0x61, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x22, 0x2e, 0x66, 0x6c, 0x61, 0x67,
+	0x70, 0x62, 0x2e, 0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x46, 0x6c, 0x61, 0x67, 0x4d, 0x65, 0x74,
+	0x61, 0x64, 0x61, 0x74, 0x61, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x42, 0x29, 0x5a,
+	0x27, 0x67, 0x69, 0x74, 0x68, 0x75, 0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2f, 0x77, 0x61, 0x64, 0x75,
+	0x68, 0x65, 0x6b, 0x2f, 0x66, 0x6c, 0x61, 0x67, 0x67, 0x65, 0x72, 0x2f, 0x70, 0x72, 0x6f, 0x74,
+	0x6f, 0x2f, 0x66, 0x6c, 0x61, 0x67, 0x70, 0x62, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+}
+
+var (
+	file_proto_flagpb_flag_proto_rawDescOnce sync.Once
+	file_proto_flagpb_flag_proto_rawDescData = file_proto_flagpb_flag_proto_rawDesc
+)
+
+func file_proto_flagpb_flag_proto_rawDescGZIP() []byte {
+	file_proto_flagpb_flag_proto_rawDescOnce.Do(func() {
+		file_proto_flagpb_flag_proto_rawDescData = protoimpl.X.CompressGZIP(file_proto_flagpb_flag_proto_rawDescData)
+	})
+	return file_proto_flagpb_flag_proto_rawDescData
+}
+
+var file_proto_flagpb_flag_proto_enumTypes = make([]protoimpl.EnumInfo, 5)
+var file_proto_flagpb_flag_proto_msgTypes = make([]protoimpl.MessageInfo, 32)
+var file_proto_flagpb_flag_proto_goTypes = []interface{}{
+	(FlagType)(0),                       // 0: flagpb.FlagType
+	(FlagKind)(0),                       // 1: flagpb.FlagKind
+	(OwnerType)(0),                      // 2: flagpb.OwnerType
+	(RuleOperator)(0),                   // 3: flagpb.RuleOperator
+	(SortField)(0),                      // 4: flagpb.SortField
+	(*FlagOwner)(nil),                   // 5: flagpb.FlagOwner
+	(*Variation)(nil),                   // 6: flagpb.Variation
+	(*Condition)(nil),                   // 7: flagpb.Condition
+	(*Rule)(nil),                        // 8: flagpb.Rule
+	(*WeightedVariation)(nil),           // 9: flagpb.WeightedVariation
+	(*Rollout)(nil),                     // 10: flagpb.Rollout
+	(*FlagDetails)(nil),                 // 11: flagpb.FlagDetails
+	(*FlagEnvironmentStatus)(nil),       // 12: flagpb.FlagEnvironmentStatus
+	(*CreateFlagRequest)(nil),           // 13: flagpb.CreateFlagRequest
+	(*CreateFlagResponse)(nil),          // 14: flagpb.CreateFlagResponse
+	(*UpdateFlagStatusRequest)(nil),     // 15: flagpb.UpdateFlagStatusRequest
+	(*UpdateFlagStatusResponse)(nil),    // 16: flagpb.UpdateFlagStatusResponse
+	(*UpdateFlagVariationRequest)(nil),  // 17: flagpb.UpdateFlagVariationRequest
+	(*UpdateFlagVariationResponse)(nil), // 18: flagpb.UpdateFlagVariationResponse
+	(*GetFlagRulesRequest)(nil),         // 19: flagpb.GetFlagRulesRequest
+	(*GetFlagRulesResponse)(nil),        // 20: flagpb.GetFlagRulesResponse
+	(*UpdateFlagRulesRequest)(nil),      // 21: flagpb.UpdateFlagRulesRequest
+	(*UpdateFlagRulesResponse)(nil),     // 22: flagpb.UpdateFlagRulesResponse
+	(*ListFlagsRequest)(nil),            // 23: flagpb.ListFlagsRequest
+	(*ListFlagsResponse)(nil),           // 24: flagpb.ListFlagsResponse
+	(*GetFlagRequest)(nil),              // 25: flagpb.GetFlagRequest
+	(*GetFlagResponse)(nil),             // 26: flagpb.GetFlagResponse
+	(*ArchiveFlagRequest)(nil),          // 27: flagpb.ArchiveFlagRequest
+	(*ArchiveFlagResponse)(nil),         // 28: flagpb.ArchiveFlagResponse
+	(*RestoreFlagRequest)(nil),          // 29: flagpb.RestoreFlagRequest
+	(*RestoreFlagResponse)(nil),         // 30: flagpb.RestoreFlagResponse
+	(*DeleteFlagRequest)(nil),           // 31: flagpb.DeleteFlagRequest
+	(*DeleteFlagResponse)(nil),          // 32: flagpb.DeleteFlagResponse
+	(*RenameFlagRequest)(nil),           // 33: flagpb.RenameFlagRequest
+	(*RenameFlagResponse)(nil),          // 34: flagpb.RenameFlagResponse
+	(*UpdateFlagMetadataRequest)(nil),   // 35: flagpb.UpdateFlagMetadataRequest
+	(*UpdateFlagMetadataResponse)(nil),  // 36: flagpb.UpdateFlagMetadataResponse
+	(*structpb.Value)(nil),              // 37: google.protobuf.Value
+	(*timestamppb.Timestamp)(nil),       // 38: google.protobuf.Timestamp
+}
+var file_proto_flagpb_flag_proto_depIdxs = []int32{
+	2,  // 0: flagpb.FlagOwner.type:type_name -> flagpb.OwnerType
+	37, // 1: flagpb.Variation.value:type_name -> google.protobuf.Value
+	3,  // 2: flagpb.Condition.operator:type_name -> flagpb.RuleOperator
+	7,  // 3: flagpb.Rule.conditions:type_name -> flagpb.Condition
+	9,  // 4: flagpb.Rollout.variations:type_name -> flagpb.WeightedVariation
+	0,  // 5: flagpb.FlagDetails.flag_type:type_name -> flagpb.FlagType
+	6,  // 6: flagpb.FlagDetails.variations:type_name -> flagpb.Variation
+	38, // 7: flagpb.FlagDetails.created_at:type_name -> google.protobuf.Timestamp
+	38, // 8: flagpb.FlagDetails.archived_at:type_name -> google.protobuf.Timestamp
+	5,  // 9: flagpb.FlagDetails.owner:type_name -> flagpb.FlagOwner
+	1,  // 10: flagpb.FlagDetails.kind:type_name -> flagpb.FlagKind
+	8,  // 11: flagpb.FlagEnvironmentStatus.rules:type_name -> flagpb.Rule
+	9,  // 12: flagpb.FlagEnvironmentStatus.rollout:type_name -> flagpb.WeightedVariation
+	38, // 13: flagpb.FlagEnvironmentStatus.updated_at:type_name -> google.protobuf.Timestamp
+	0,  // 14: flagpb.CreateFlagRequest.flag_type:type_name -> flagpb.FlagType
+	6,  // 15: flagpb.CreateFlagRequest.variations:type_name -> flagpb.Variation
+	5,  // 16: flagpb.CreateFlagRequest.owner:type_name -> flagpb.FlagOwner
+	1,  // 17: flagpb.CreateFlagRequest.kind:type_name -> flagpb.FlagKind
+	10, // 18: flagpb.UpdateFlagStatusRequest.rollout:type_name -> flagpb.Rollout
+	8,  // 19: flagpb.GetFlagRulesResponse.rules:type_name -> flagpb.Rule
+	8,  // 20: flagpb.UpdateFlagRulesRequest.rules:type_name -> flagpb.Rule
+	4,  // 21: flagpb.ListFlagsRequest.sort_by:type_name -> flagpb.SortField
+	1,  // 22: flagpb.ListFlagsRequest.kind:type_name -> flagpb.FlagKind
+	11, // 23: flagpb.ListFlagsResponse.flags:type_name -> flagpb.FlagDetails
+	11, // 24: flagpb.GetFlagResponse.flag:type_name -> flagpb.FlagDetails
+	12, // 25: flagpb.GetFlagResponse.environments:type_name -> flagpb.FlagEnvironmentStatus
+	38, // 26: flagpb.RenameFlagResponse.alias_expires_at:type_name -> google.protobuf.Timestamp
+	5,  // 27: flagpb.UpdateFlagMetadataRequest.owner:type_name -> flagpb.FlagOwner
+	1,  // 28: flagpb.UpdateFlagMetadataRequest.kind:type_name -> flagpb.FlagKind
+	11, // 29: flagpb.UpdateFlagMetadataResponse.flag:type_name -> flagpb.FlagDetails
+	13, // 30: flagpb.Flag.CreateFlag:input_type -> flagpb.CreateFlagRequest
+	15, // 31: flagpb.Flag.UpdateFlagStatus:input_type -> flagpb.UpdateFlagStatusRequest
+	17, // 32: flagpb.Flag.UpdateFlagVariation:input_type -> flagpb.UpdateFlagVariationRequest
+	19, // 33: flagpb.Flag.GetFlagRules:input_type -> flagpb.GetFlagRulesRequest
+	21, // 34: flagpb.Flag.UpdateFlagRules:input_type -> flagpb.UpdateFlagRulesRequest
+	23, // 35: flagpb.Flag.ListFlags:input_type -> flagpb.ListFlagsRequest
+	25, // 36: flagpb.Flag.GetFlag:input_type -> flagpb.GetFlagRequest
+	27, // 37: flagpb.Flag.ArchiveFlag:input_type -> flagpb.ArchiveFlagRequest
+	29, // 38: flagpb.Flag.RestoreFlag:input_type -> flagpb.RestoreFlagRequest
+	31, // 39: flagpb.Flag.DeleteFlag:input_type -> flagpb.DeleteFlagRequest
+	33, // 40: flagpb.Flag.RenameFlag:input_type -> flagpb.RenameFlagRequest
+	35, // 41: flagpb.Flag.UpdateFlagMetadata:input_type -> flagpb.UpdateFlagMetadataRequest
+	14, // 42: flagpb.Flag.CreateFlag:output_type -> flagpb.CreateFlagResponse
+	16, // 43: flagpb.Flag.UpdateFlagStatus:output_type -> flagpb.UpdateFlagStatusResponse
+	18, // 44: flagpb.Flag.UpdateFlagVariation:output_type -> flagpb.UpdateFlagVariationResponse
+	20, // 45: flagpb.Flag.GetFlagRules:output_type -> flagpb.GetFlagRulesResponse
+	22, // 46: flagpb.Flag.UpdateFlagRules:output_type -> flagpb.UpdateFlagRulesResponse
+	24, // 47: flagpb.Flag.ListFlags:output_type -> flagpb.ListFlagsResponse
+	26, // 48: flagpb.Flag.GetFlag:output_type -> flagpb.GetFlagResponse
+	28, // 49: flagpb.Flag.ArchiveFlag:output_type -> flagpb.ArchiveFlagResponse
+	30, // 50: flagpb.Flag.RestoreFlag:output_type -> flagpb.RestoreFlagResponse
+	32, // 51: flagpb.Flag.DeleteFlag:output_type -> flagpb.DeleteFlagResponse
+	34, // 52: flagpb.Flag.RenameFlag:output_type -> flagpb.RenameFlagResponse
+	36, // 53: flagpb.Flag.UpdateFlagMetadata:output_type -> flagpb.UpdateFlagMetadataResponse
+	42, // [42:54] is the sub-list for method output_type
+	30, // [30:42] is the sub-list for method input_type
+	30, // [30:30] is the sub-list for extension type_name
+	30, // [30:30] is the sub-list for extension extendee
+	0,  // [0:30] is the sub-list for field type_name
+}
+
+func init() { file_proto_flagpb_flag_proto_init() }
+func file_proto_flagpb_flag_proto_init() {
+	if File_proto_flagpb_flag_proto != nil {
+		return
+	}
+	if !protoimpl.UnsafeEnabled {
+		file_proto_flagpb_flag_proto_msgTypes[0].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*FlagOwner); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_proto_flagpb_flag_proto_msgTypes[1].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Variation); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_proto_flagpb_flag_proto_msgTypes[2].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Condition); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_proto_flagpb_flag_proto_msgTypes[3].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Rule); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_proto_flagpb_flag_proto_msgTypes[4].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*WeightedVariation); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_proto_flagpb_flag_proto_msgTypes[5].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Rollout); i {
+			case 0:
+				return &v.state
 			case 1:
 				return &v.sizeCache
 			case 2:
@@ -345,7 +3128,67 @@ func file_proto_flagpb_flag_proto_init() {
 				return nil
 			}
 		}
-		file_proto_flagpb_flag_proto_msgTypes[3].Exporter = func(v interface{}, i int) interface{} {
+		file_proto_flagpb_flag_proto_msgTypes[6].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*FlagDetails); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_proto_flagpb_flag_proto_msgTypes[7].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*FlagEnvironmentStatus); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_proto_flagpb_flag_proto_msgTypes[8].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*CreateFlagRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_proto_flagpb_flag_proto_msgTypes[9].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*CreateFlagResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_proto_flagpb_flag_proto_msgTypes[10].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*UpdateFlagStatusRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_proto_flagpb_flag_proto_msgTypes[11].Exporter = func(v interface{}, i int) interface{} {
 			switch v := v.(*UpdateFlagStatusResponse); i {
 			case 0:
 				return &v.state
@@ -357,19 +3200,260 @@ func file_proto_flagpb_flag_proto_init() {
 				return nil
 			}
 		}
+		file_proto_flagpb_flag_proto_msgTypes[12].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*UpdateFlagVariationRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_proto_flagpb_flag_proto_msgTypes[13].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*UpdateFlagVariationResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_proto_flagpb_flag_proto_msgTypes[14].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*GetFlagRulesRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_proto_flagpb_flag_proto_msgTypes[15].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*GetFlagRulesResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_proto_flagpb_flag_proto_msgTypes[16].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*UpdateFlagRulesRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_proto_flagpb_flag_proto_msgTypes[17].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*UpdateFlagRulesResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_proto_flagpb_flag_proto_msgTypes[18].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ListFlagsRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_proto_flagpb_flag_proto_msgTypes[19].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ListFlagsResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_proto_flagpb_flag_proto_msgTypes[20].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*GetFlagRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_proto_flagpb_flag_proto_msgTypes[21].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*GetFlagResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_proto_flagpb_flag_proto_msgTypes[22].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ArchiveFlagRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_proto_flagpb_flag_proto_msgTypes[23].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ArchiveFlagResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_proto_flagpb_flag_proto_msgTypes[24].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*RestoreFlagRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_proto_flagpb_flag_proto_msgTypes[25].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*RestoreFlagResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_proto_flagpb_flag_proto_msgTypes[26].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*DeleteFlagRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_proto_flagpb_flag_proto_msgTypes[27].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*DeleteFlagResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_proto_flagpb_flag_proto_msgTypes[28].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*RenameFlagRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_proto_flagpb_flag_proto_msgTypes[29].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*RenameFlagResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_proto_flagpb_flag_proto_msgTypes[30].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*UpdateFlagMetadataRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_proto_flagpb_flag_proto_msgTypes[31].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*UpdateFlagMetadataResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
 	}
 	type x struct{}
 	out := protoimpl.TypeBuilder{
 		File: protoimpl.DescBuilder{
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: file_proto_flagpb_flag_proto_rawDesc,
-			NumEnums:      0,
-			NumMessages:   4,
+			NumEnums:      5,
+			NumMessages:   32,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
 		GoTypes:           file_proto_flagpb_flag_proto_goTypes,
 		DependencyIndexes: file_proto_flagpb_flag_proto_depIdxs,
+		EnumInfos:         file_proto_flagpb_flag_proto_enumTypes,
 		MessageInfos:      file_proto_flagpb_flag_proto_msgTypes,
 	}.Build()
 	File_proto_flagpb_flag_proto = out.File
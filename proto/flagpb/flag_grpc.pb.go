@@ -19,8 +19,18 @@ import (
 const _ = grpc.SupportPackageIsVersion7
 
 const (
-	Flag_CreateFlag_FullMethodName       = "/flagpb.Flag/CreateFlag"
-	Flag_UpdateFlagStatus_FullMethodName = "/flagpb.Flag/UpdateFlagStatus"
+	Flag_CreateFlag_FullMethodName          = "/flagpb.Flag/CreateFlag"
+	Flag_UpdateFlagStatus_FullMethodName    = "/flagpb.Flag/UpdateFlagStatus"
+	Flag_UpdateFlagVariation_FullMethodName = "/flagpb.Flag/UpdateFlagVariation"
+	Flag_GetFlagRules_FullMethodName        = "/flagpb.Flag/GetFlagRules"
+	Flag_UpdateFlagRules_FullMethodName     = "/flagpb.Flag/UpdateFlagRules"
+	Flag_ListFlags_FullMethodName           = "/flagpb.Flag/ListFlags"
+	Flag_GetFlag_FullMethodName             = "/flagpb.Flag/GetFlag"
+	Flag_ArchiveFlag_FullMethodName         = "/flagpb.Flag/ArchiveFlag"
+	Flag_RestoreFlag_FullMethodName         = "/flagpb.Flag/RestoreFlag"
+	Flag_DeleteFlag_FullMethodName          = "/flagpb.Flag/DeleteFlag"
+	Flag_RenameFlag_FullMethodName          = "/flagpb.Flag/RenameFlag"
+	Flag_UpdateFlagMetadata_FullMethodName  = "/flagpb.Flag/UpdateFlagMetadata"
 )
 
 // FlagClient is the client API for Flag service.
@@ -33,6 +43,37 @@ type FlagClient interface {
 	// UpdateFlagStatus updates the status of the flag in the provided
 	// environment.
 	UpdateFlagStatus(ctx context.Context, in *UpdateFlagStatusRequest, opts ...grpc.CallOption) (*UpdateFlagStatusResponse, error)
+	// UpdateFlagVariation updates the variation of the flag that is served in
+	// the provided environment while the flag is active.
+	UpdateFlagVariation(ctx context.Context, in *UpdateFlagVariationRequest, opts ...grpc.CallOption) (*UpdateFlagVariationResponse, error)
+	// GetFlagRules returns the ordered targeting rules of the flag in the
+	// provided environment.
+	GetFlagRules(ctx context.Context, in *GetFlagRulesRequest, opts ...grpc.CallOption) (*GetFlagRulesResponse, error)
+	// UpdateFlagRules replaces the ordered targeting rules of the flag in the
+	// provided environment.
+	UpdateFlagRules(ctx context.Context, in *UpdateFlagRulesRequest, opts ...grpc.CallOption) (*UpdateFlagRulesResponse, error)
+	// ListFlags returns a page of the flags of a project.
+	ListFlags(ctx context.Context, in *ListFlagsRequest, opts ...grpc.CallOption) (*ListFlagsResponse, error)
+	// GetFlag returns the details of a flag along with its settings in every
+	// environment of the project.
+	GetFlag(ctx context.Context, in *GetFlagRequest, opts ...grpc.CallOption) (*GetFlagResponse, error)
+	// ArchiveFlag archives the flag. An archived flag serves its archived
+	// variation in every environment and its settings can't be updated until it
+	// is restored.
+	ArchiveFlag(ctx context.Context, in *ArchiveFlagRequest, opts ...grpc.CallOption) (*ArchiveFlagResponse, error)
+	// RestoreFlag restores an archived flag, so that it is served according to
+	// its settings in every environment again.
+	RestoreFlag(ctx context.Context, in *RestoreFlagRequest, opts ...grpc.CallOption) (*RestoreFlagResponse, error)
+	// DeleteFlag permanently deletes the flag along with its settings in every
+	// environment.
+	DeleteFlag(ctx context.Context, in *DeleteFlagRequest, opts ...grpc.CallOption) (*DeleteFlagResponse, error)
+	// RenameFlag changes the name and the display name of the flag. The
+	// previous name of the flag is still served until the alias period has
+	// passed, so that the clients can move to the new name.
+	RenameFlag(ctx context.Context, in *RenameFlagRequest, opts ...grpc.CallOption) (*RenameFlagResponse, error)
+	// UpdateFlagMetadata replaces the description, the owner, the tags and the
+	// kind of the flag.
+	UpdateFlagMetadata(ctx context.Context, in *UpdateFlagMetadataRequest, opts ...grpc.CallOption) (*UpdateFlagMetadataResponse, error)
 }
 
 type flagClient struct {
@@ -61,6 +102,96 @@ func (c *flagClient) UpdateFlagStatus(ctx context.Context, in *UpdateFlagStatusR
 	return out, nil
 }
 
+func (c *flagClient) UpdateFlagVariation(ctx context.Context, in *UpdateFlagVariationRequest, opts ...grpc.CallOption) (*UpdateFlagVariationResponse, error) {
+	out := new(UpdateFlagVariationResponse)
+	err := c.cc.Invoke(ctx, Flag_UpdateFlagVariation_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *flagClient) GetFlagRules(ctx context.Context, in *GetFlagRulesRequest, opts ...grpc.CallOption) (*GetFlagRulesResponse, error) {
+	out := new(GetFlagRulesResponse)
+	err := c.cc.Invoke(ctx, Flag_GetFlagRules_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *flagClient) UpdateFlagRules(ctx context.Context, in *UpdateFlagRulesRequest, opts ...grpc.CallOption) (*UpdateFlagRulesResponse, error) {
+	out := new(UpdateFlagRulesResponse)
+	err := c.cc.Invoke(ctx, Flag_UpdateFlagRules_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *flagClient) ListFlags(ctx context.Context, in *ListFlagsRequest, opts ...grpc.CallOption) (*ListFlagsResponse, error) {
+	out := new(ListFlagsResponse)
+	err := c.cc.Invoke(ctx, Flag_ListFlags_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *flagClient) GetFlag(ctx context.Context, in *GetFlagRequest, opts ...grpc.CallOption) (*GetFlagResponse, error) {
+	out := new(GetFlagResponse)
+	err := c.cc.Invoke(ctx, Flag_GetFlag_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *flagClient) ArchiveFlag(ctx context.Context, in *ArchiveFlagRequest, opts ...grpc.CallOption) (*ArchiveFlagResponse, error) {
+	out := new(ArchiveFlagResponse)
+	err := c.cc.Invoke(ctx, Flag_ArchiveFlag_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *flagClient) RestoreFlag(ctx context.Context, in *RestoreFlagRequest, opts ...grpc.CallOption) (*RestoreFlagResponse, error) {
+	out := new(RestoreFlagResponse)
+	err := c.cc.Invoke(ctx, Flag_RestoreFlag_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *flagClient) DeleteFlag(ctx context.Context, in *DeleteFlagRequest, opts ...grpc.CallOption) (*DeleteFlagResponse, error) {
+	out := new(DeleteFlagResponse)
+	err := c.cc.Invoke(ctx, Flag_DeleteFlag_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *flagClient) RenameFlag(ctx context.Context, in *RenameFlagRequest, opts ...grpc.CallOption) (*RenameFlagResponse, error) {
+	out := new(RenameFlagResponse)
+	err := c.cc.Invoke(ctx, Flag_RenameFlag_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *flagClient) UpdateFlagMetadata(ctx context.Context, in *UpdateFlagMetadataRequest, opts ...grpc.CallOption) (*UpdateFlagMetadataResponse, error) {
+	out := new(UpdateFlagMetadataResponse)
+	err := c.cc.Invoke(ctx, Flag_UpdateFlagMetadata_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // FlagServer is the server API for Flag service.
 // All implementations must embed UnimplementedFlagServer
 // for forward compatibility
@@ -71,6 +202,37 @@ type FlagServer interface {
 	// UpdateFlagStatus updates the status of the flag in the provided
 	// environment.
 	UpdateFlagStatus(context.Context, *UpdateFlagStatusRequest) (*UpdateFlagStatusResponse, error)
+	// UpdateFlagVariation updates the variation of the flag that is served in
+	// the provided environment while the flag is active.
+	UpdateFlagVariation(context.Context, *UpdateFlagVariationRequest) (*UpdateFlagVariationResponse, error)
+	// GetFlagRules returns the ordered targeting rules of the flag in the
+	// provided environment.
+	GetFlagRules(context.Context, *GetFlagRulesRequest) (*GetFlagRulesResponse, error)
+	// UpdateFlagRules replaces the ordered targeting rules of the flag in the
+	// provided environment.
+	UpdateFlagRules(context.Context, *UpdateFlagRulesRequest) (*UpdateFlagRulesResponse, error)
+	// ListFlags returns a page of the flags of a project.
+	ListFlags(context.Context, *ListFlagsRequest) (*ListFlagsResponse, error)
+	// GetFlag returns the details of a flag along with its settings in every
+	// environment of the project.
+	GetFlag(context.Context, *GetFlagRequest) (*GetFlagResponse, error)
+	// ArchiveFlag archives the flag. An archived flag serves its archived
+	// variation in every environment and its settings can't be updated until it
+	// is restored.
+	ArchiveFlag(context.Context, *ArchiveFlagRequest) (*ArchiveFlagResponse, error)
+	// RestoreFlag restores an archived flag, so that it is served according to
+	// its settings in every environment again.
+	RestoreFlag(context.Context, *RestoreFlagRequest) (*RestoreFlagResponse, error)
+	// DeleteFlag permanently deletes the flag along with its settings in every
+	// environment.
+	DeleteFlag(context.Context, *DeleteFlagRequest) (*DeleteFlagResponse, error)
+	// RenameFlag changes the name and the display name of the flag. The
+	// previous name of the flag is still served until the alias period has
+	// passed, so that the clients can move to the new name.
+	RenameFlag(context.Context, *RenameFlagRequest) (*RenameFlagResponse, error)
+	// UpdateFlagMetadata replaces the description, the owner, the tags and the
+	// kind of the flag.
+	UpdateFlagMetadata(context.Context, *UpdateFlagMetadataRequest) (*UpdateFlagMetadataResponse, error)
 	mustEmbedUnimplementedFlagServer()
 }
 
@@ -84,6 +246,36 @@ func (UnimplementedFlagServer) CreateFlag(context.Context, *CreateFlagRequest) (
 func (UnimplementedFlagServer) UpdateFlagStatus(context.Context, *UpdateFlagStatusRequest) (*UpdateFlagStatusResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method UpdateFlagStatus not implemented")
 }
+func (UnimplementedFlagServer) UpdateFlagVariation(context.Context, *UpdateFlagVariationRequest) (*UpdateFlagVariationResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UpdateFlagVariation not implemented")
+}
+func (UnimplementedFlagServer) GetFlagRules(context.Context, *GetFlagRulesRequest) (*GetFlagRulesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetFlagRules not implemented")
+}
+func (UnimplementedFlagServer) UpdateFlagRules(context.Context, *UpdateFlagRulesRequest) (*UpdateFlagRulesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UpdateFlagRules not implemented")
+}
+func (UnimplementedFlagServer) ListFlags(context.Context, *ListFlagsRequest) (*ListFlagsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListFlags not implemented")
+}
+func (UnimplementedFlagServer) GetFlag(context.Context, *GetFlagRequest) (*GetFlagResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetFlag not implemented")
+}
+func (UnimplementedFlagServer) ArchiveFlag(context.Context, *ArchiveFlagRequest) (*ArchiveFlagResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ArchiveFlag not implemented")
+}
+func (UnimplementedFlagServer) RestoreFlag(context.Context, *RestoreFlagRequest) (*RestoreFlagResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RestoreFlag not implemented")
+}
+func (UnimplementedFlagServer) DeleteFlag(context.Context, *DeleteFlagRequest) (*DeleteFlagResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteFlag not implemented")
+}
+func (UnimplementedFlagServer) RenameFlag(context.Context, *RenameFlagRequest) (*RenameFlagResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RenameFlag not implemented")
+}
+func (UnimplementedFlagServer) UpdateFlagMetadata(context.Context, *UpdateFlagMetadataRequest) (*UpdateFlagMetadataResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UpdateFlagMetadata not implemented")
+}
 func (UnimplementedFlagServer) mustEmbedUnimplementedFlagServer() {}
 
 // UnsafeFlagServer may be embedded to opt out of forward compatibility for this service.
@@ -133,6 +325,186 @@ func _Flag_UpdateFlagStatus_Handler(srv interface{}, ctx context.Context, dec fu
 	return interceptor(ctx, in, info, handler)
 }
 
+func _Flag_UpdateFlagVariation_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UpdateFlagVariationRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(FlagServer).UpdateFlagVariation(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Flag_UpdateFlagVariation_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(FlagServer).UpdateFlagVariation(ctx, req.(*UpdateFlagVariationRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Flag_GetFlagRules_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetFlagRulesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(FlagServer).GetFlagRules(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Flag_GetFlagRules_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(FlagServer).GetFlagRules(ctx, req.(*GetFlagRulesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Flag_UpdateFlagRules_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UpdateFlagRulesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(FlagServer).UpdateFlagRules(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Flag_UpdateFlagRules_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(FlagServer).UpdateFlagRules(ctx, req.(*UpdateFlagRulesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Flag_ListFlags_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListFlagsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(FlagServer).ListFlags(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Flag_ListFlags_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(FlagServer).ListFlags(ctx, req.(*ListFlagsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Flag_GetFlag_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetFlagRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(FlagServer).GetFlag(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Flag_GetFlag_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(FlagServer).GetFlag(ctx, req.(*GetFlagRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Flag_ArchiveFlag_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ArchiveFlagRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(FlagServer).ArchiveFlag(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Flag_ArchiveFlag_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(FlagServer).ArchiveFlag(ctx, req.(*ArchiveFlagRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Flag_RestoreFlag_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RestoreFlagRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(FlagServer).RestoreFlag(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Flag_RestoreFlag_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(FlagServer).RestoreFlag(ctx, req.(*RestoreFlagRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Flag_DeleteFlag_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeleteFlagRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(FlagServer).DeleteFlag(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Flag_DeleteFlag_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(FlagServer).DeleteFlag(ctx, req.(*DeleteFlagRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Flag_RenameFlag_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RenameFlagRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(FlagServer).RenameFlag(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Flag_RenameFlag_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(FlagServer).RenameFlag(ctx, req.(*RenameFlagRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Flag_UpdateFlagMetadata_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UpdateFlagMetadataRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(FlagServer).UpdateFlagMetadata(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Flag_UpdateFlagMetadata_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(FlagServer).UpdateFlagMetadata(ctx, req.(*UpdateFlagMetadataRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // Flag_ServiceDesc is the grpc.ServiceDesc for Flag service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -148,6 +520,46 @@ var Flag_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "UpdateFlagStatus",
 			Handler:    _Flag_UpdateFlagStatus_Handler,
 		},
+		{
+			MethodName: "UpdateFlagVariation",
+			Handler:    _Flag_UpdateFlagVariation_Handler,
+		},
+		{
+			MethodName: "GetFlagRules",
+			Handler:    _Flag_GetFlagRules_Handler,
+		},
+		{
+			MethodName: "UpdateFlagRules",
+			Handler:    _Flag_UpdateFlagRules_Handler,
+		},
+		{
+			MethodName: "ListFlags",
+			Handler:    _Flag_ListFlags_Handler,
+		},
+		{
+			MethodName: "GetFlag",
+			Handler:    _Flag_GetFlag_Handler,
+		},
+		{
+			MethodName: "ArchiveFlag",
+			Handler:    _Flag_ArchiveFlag_Handler,
+		},
+		{
+			MethodName: "RestoreFlag",
+			Handler:    _Flag_RestoreFlag_Handler,
+		},
+		{
+			MethodName: "DeleteFlag",
+			Handler:    _Flag_DeleteFlag_Handler,
+		},
+		{
+			MethodName: "RenameFlag",
+			Handler:    _Flag_RenameFlag_Handler,
+		},
+		{
+			MethodName: "UpdateFlagMetadata",
+			Handler:    _Flag_UpdateFlagMetadata_Handler,
+		},
 	},
 	Streams:  []grpc.StreamDesc{},
 	Metadata: "proto/flagpb/flag.proto",
@@ -21,6 +21,12 @@ const _ = grpc.SupportPackageIsVersion7
 const (
 	Project_CreateNewProject_FullMethodName = "/projectpb.Project/CreateNewProject"
 	Project_GetProjectKey_FullMethodName    = "/projectpb.Project/GetProjectKey"
+	Project_ListProjects_FullMethodName     = "/projectpb.Project/ListProjects"
+	Project_GetProject_FullMethodName       = "/projectpb.Project/GetProject"
+	Project_ArchiveProject_FullMethodName   = "/projectpb.Project/ArchiveProject"
+	Project_RestoreProject_FullMethodName   = "/projectpb.Project/RestoreProject"
+	Project_DeleteProject_FullMethodName    = "/projectpb.Project/DeleteProject"
+	Project_RenameProject_FullMethodName    = "/projectpb.Project/RenameProject"
 )
 
 // ProjectClient is the client API for Project service.
@@ -32,6 +38,27 @@ type ProjectClient interface {
 	CreateNewProject(ctx context.Context, in *CreateNewProjectRequest, opts ...grpc.CallOption) (*CreateNewProjectResponse, error)
 	// GetProjectKey returns the project key of the requested project.
 	GetProjectKey(ctx context.Context, in *GetProjectKeyRequest, opts ...grpc.CallOption) (*GetProjectKeyResponse, error)
+	// ListProjects returns a page of the projects of the currently
+	// authenticated user.
+	ListProjects(ctx context.Context, in *ListProjectsRequest, opts ...grpc.CallOption) (*ListProjectsResponse, error)
+	// GetProject returns the details of the requested project.
+	GetProject(ctx context.Context, in *GetProjectRequest, opts ...grpc.CallOption) (*GetProjectResponse, error)
+	// ArchiveProject archives the project along with its environments, flags
+	// and flag settings. The key of an archived project is refused and its
+	// flags are no longer served until it is restored.
+	ArchiveProject(ctx context.Context, in *ArchiveProjectRequest, opts ...grpc.CallOption) (*ArchiveProjectResponse, error)
+	// RestoreProject restores an archived project along with its environments,
+	// flags and flag settings. A deleted project can only be restored until it
+	// is purged.
+	RestoreProject(ctx context.Context, in *RestoreProjectRequest, opts ...grpc.CallOption) (*RestoreProjectResponse, error)
+	// DeleteProject archives the project and permanently purges it along with
+	// its environments, flags and flag settings once the retention window has
+	// passed.
+	DeleteProject(ctx context.Context, in *DeleteProjectRequest, opts ...grpc.CallOption) (*DeleteProjectResponse, error)
+	// RenameProject changes the name and the display name of the project. The
+	// clients are not affected as they identify the project by its key, which
+	// never changes.
+	RenameProject(ctx context.Context, in *RenameProjectRequest, opts ...grpc.CallOption) (*RenameProjectResponse, error)
 }
 
 type projectClient struct {
@@ -60,6 +87,60 @@ func (c *projectClient) GetProjectKey(ctx context.Context, in *GetProjectKeyRequ
 	return out, nil
 }
 
+func (c *projectClient) ListProjects(ctx context.Context, in *ListProjectsRequest, opts ...grpc.CallOption) (*ListProjectsResponse, error) {
+	out := new(ListProjectsResponse)
+	err := c.cc.Invoke(ctx, Project_ListProjects_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *projectClient) GetProject(ctx context.Context, in *GetProjectRequest, opts ...grpc.CallOption) (*GetProjectResponse, error) {
+	out := new(GetProjectResponse)
+	err := c.cc.Invoke(ctx, Project_GetProject_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *projectClient) ArchiveProject(ctx context.Context, in *ArchiveProjectRequest, opts ...grpc.CallOption) (*ArchiveProjectResponse, error) {
+	out := new(ArchiveProjectResponse)
+	err := c.cc.Invoke(ctx, Project_ArchiveProject_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *projectClient) RestoreProject(ctx context.Context, in *RestoreProjectRequest, opts ...grpc.CallOption) (*RestoreProjectResponse, error) {
+	out := new(RestoreProjectResponse)
+	err := c.cc.Invoke(ctx, Project_RestoreProject_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *projectClient) DeleteProject(ctx context.Context, in *DeleteProjectRequest, opts ...grpc.CallOption) (*DeleteProjectResponse, error) {
+	out := new(DeleteProjectResponse)
+	err := c.cc.Invoke(ctx, Project_DeleteProject_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *projectClient) RenameProject(ctx context.Context, in *RenameProjectRequest, opts ...grpc.CallOption) (*RenameProjectResponse, error) {
+	out := new(RenameProjectResponse)
+	err := c.cc.Invoke(ctx, Project_RenameProject_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // ProjectServer is the server API for Project service.
 // All implementations must embed UnimplementedProjectServer
 // for forward compatibility
@@ -69,6 +150,27 @@ type ProjectServer interface {
 	CreateNewProject(context.Context, *CreateNewProjectRequest) (*CreateNewProjectResponse, error)
 	// GetProjectKey returns the project key of the requested project.
 	GetProjectKey(context.Context, *GetProjectKeyRequest) (*GetProjectKeyResponse, error)
+	// ListProjects returns a page of the projects of the currently
+	// authenticated user.
+	ListProjects(context.Context, *ListProjectsRequest) (*ListProjectsResponse, error)
+	// GetProject returns the details of the requested project.
+	GetProject(context.Context, *GetProjectRequest) (*GetProjectResponse, error)
+	// ArchiveProject archives the project along with its environments, flags
+	// and flag settings. The key of an archived project is refused and its
+	// flags are no longer served until it is restored.
+	ArchiveProject(context.Context, *ArchiveProjectRequest) (*ArchiveProjectResponse, error)
+	// RestoreProject restores an archived project along with its environments,
+	// flags and flag settings. A deleted project can only be restored until it
+	// is purged.
+	RestoreProject(context.Context, *RestoreProjectRequest) (*RestoreProjectResponse, error)
+	// DeleteProject archives the project and permanently purges it along with
+	// its environments, flags and flag settings once the retention window has
+	// passed.
+	DeleteProject(context.Context, *DeleteProjectRequest) (*DeleteProjectResponse, error)
+	// RenameProject changes the name and the display name of the project. The
+	// clients are not affected as they identify the project by its key, which
+	// never changes.
+	RenameProject(context.Context, *RenameProjectRequest) (*RenameProjectResponse, error)
 	mustEmbedUnimplementedProjectServer()
 }
 
@@ -82,6 +184,24 @@ func (UnimplementedProjectServer) CreateNewProject(context.Context, *CreateNewPr
 func (UnimplementedProjectServer) GetProjectKey(context.Context, *GetProjectKeyRequest) (*GetProjectKeyResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method GetProjectKey not implemented")
 }
+func (UnimplementedProjectServer) ListProjects(context.Context, *ListProjectsRequest) (*ListProjectsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListProjects not implemented")
+}
+func (UnimplementedProjectServer) GetProject(context.Context, *GetProjectRequest) (*GetProjectResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetProject not implemented")
+}
+func (UnimplementedProjectServer) ArchiveProject(context.Context, *ArchiveProjectRequest) (*ArchiveProjectResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ArchiveProject not implemented")
+}
+func (UnimplementedProjectServer) RestoreProject(context.Context, *RestoreProjectRequest) (*RestoreProjectResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RestoreProject not implemented")
+}
+func (UnimplementedProjectServer) DeleteProject(context.Context, *DeleteProjectRequest) (*DeleteProjectResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteProject not implemented")
+}
+func (UnimplementedProjectServer) RenameProject(context.Context, *RenameProjectRequest) (*RenameProjectResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RenameProject not implemented")
+}
 func (UnimplementedProjectServer) mustEmbedUnimplementedProjectServer() {}
 
 // UnsafeProjectServer may be embedded to opt out of forward compatibility for this service.
@@ -131,6 +251,114 @@ func _Project_GetProjectKey_Handler(srv interface{}, ctx context.Context, dec fu
 	return interceptor(ctx, in, info, handler)
 }
 
+func _Project_ListProjects_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListProjectsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ProjectServer).ListProjects(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Project_ListProjects_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ProjectServer).ListProjects(ctx, req.(*ListProjectsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Project_GetProject_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetProjectRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ProjectServer).GetProject(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Project_GetProject_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ProjectServer).GetProject(ctx, req.(*GetProjectRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Project_ArchiveProject_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ArchiveProjectRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ProjectServer).ArchiveProject(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Project_ArchiveProject_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ProjectServer).ArchiveProject(ctx, req.(*ArchiveProjectRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Project_RestoreProject_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RestoreProjectRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ProjectServer).RestoreProject(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Project_RestoreProject_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ProjectServer).RestoreProject(ctx, req.(*RestoreProjectRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Project_DeleteProject_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeleteProjectRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ProjectServer).DeleteProject(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Project_DeleteProject_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ProjectServer).DeleteProject(ctx, req.(*DeleteProjectRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Project_RenameProject_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RenameProjectRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ProjectServer).RenameProject(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Project_RenameProject_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ProjectServer).RenameProject(ctx, req.(*RenameProjectRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // Project_ServiceDesc is the grpc.ServiceDesc for Project service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -146,6 +374,30 @@ var Project_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "GetProjectKey",
 			Handler:    _Project_GetProjectKey_Handler,
 		},
+		{
+			MethodName: "ListProjects",
+			Handler:    _Project_ListProjects_Handler,
+		},
+		{
+			MethodName: "GetProject",
+			Handler:    _Project_GetProject_Handler,
+		},
+		{
+			MethodName: "ArchiveProject",
+			Handler:    _Project_ArchiveProject_Handler,
+		},
+		{
+			MethodName: "RestoreProject",
+			Handler:    _Project_RestoreProject_Handler,
+		},
+		{
+			MethodName: "DeleteProject",
+			Handler:    _Project_DeleteProject_Handler,
+		},
+		{
+			MethodName: "RenameProject",
+			Handler:    _Project_RenameProject_Handler,
+		},
 	},
 	Streams:  []grpc.StreamDesc{},
 	Metadata: "proto/projectpb/project.proto",
@@ -19,7 +19,10 @@ import (
 const _ = grpc.SupportPackageIsVersion7
 
 const (
-	FlagProvider_GetFlag_FullMethodName = "/providerpb.FlagProvider/GetFlag"
+	FlagProvider_GetFlag_FullMethodName     = "/providerpb.FlagProvider/GetFlag"
+	FlagProvider_GetFlags_FullMethodName    = "/providerpb.FlagProvider/GetFlags"
+	FlagProvider_GetAllFlags_FullMethodName = "/providerpb.FlagProvider/GetAllFlags"
+	FlagProvider_WatchFlags_FullMethodName  = "/providerpb.FlagProvider/WatchFlags"
 )
 
 // FlagProviderClient is the client API for FlagProvider service.
@@ -28,6 +31,15 @@ const (
 type FlagProviderClient interface {
 	// GetFlag returns the current status of the request flag in the environment.
 	GetFlag(ctx context.Context, in *GetFlagRequest, opts ...grpc.CallOption) (*GetFlagResponse, error)
+	// GetFlags returns the current status of all the requested flags in the
+	// environment.
+	GetFlags(ctx context.Context, in *GetFlagsRequest, opts ...grpc.CallOption) (*GetFlagsResponse, error)
+	// GetAllFlags returns the current status of all the flags of the project in
+	// the environment.
+	GetAllFlags(ctx context.Context, in *GetAllFlagsRequest, opts ...grpc.CallOption) (*GetFlagsResponse, error)
+	// WatchFlags streams the current status of all the flags of the project in
+	// the environment followed by the status of every flag that changes.
+	WatchFlags(ctx context.Context, in *WatchFlagsRequest, opts ...grpc.CallOption) (FlagProvider_WatchFlagsClient, error)
 }
 
 type flagProviderClient struct {
@@ -47,12 +59,71 @@ func (c *flagProviderClient) GetFlag(ctx context.Context, in *GetFlagRequest, op
 	return out, nil
 }
 
+func (c *flagProviderClient) GetFlags(ctx context.Context, in *GetFlagsRequest, opts ...grpc.CallOption) (*GetFlagsResponse, error) {
+	out := new(GetFlagsResponse)
+	err := c.cc.Invoke(ctx, FlagProvider_GetFlags_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *flagProviderClient) GetAllFlags(ctx context.Context, in *GetAllFlagsRequest, opts ...grpc.CallOption) (*GetFlagsResponse, error) {
+	out := new(GetFlagsResponse)
+	err := c.cc.Invoke(ctx, FlagProvider_GetAllFlags_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *flagProviderClient) WatchFlags(ctx context.Context, in *WatchFlagsRequest, opts ...grpc.CallOption) (FlagProvider_WatchFlagsClient, error) {
+	stream, err := c.cc.NewStream(ctx, &FlagProvider_ServiceDesc.Streams[0], FlagProvider_WatchFlags_FullMethodName, opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &flagProviderWatchFlagsClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type FlagProvider_WatchFlagsClient interface {
+	Recv() (*WatchFlagsResponse, error)
+	grpc.ClientStream
+}
+
+type flagProviderWatchFlagsClient struct {
+	grpc.ClientStream
+}
+
+func (x *flagProviderWatchFlagsClient) Recv() (*WatchFlagsResponse, error) {
+	m := new(WatchFlagsResponse)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
 // FlagProviderServer is the server API for FlagProvider service.
 // All implementations must embed UnimplementedFlagProviderServer
 // for forward compatibility
 type FlagProviderServer interface {
 	// GetFlag returns the current status of the request flag in the environment.
 	GetFlag(context.Context, *GetFlagRequest) (*GetFlagResponse, error)
+	// GetFlags returns the current status of all the requested flags in the
+	// environment.
+	GetFlags(context.Context, *GetFlagsRequest) (*GetFlagsResponse, error)
+	// GetAllFlags returns the current status of all the flags of the project in
+	// the environment.
+	GetAllFlags(context.Context, *GetAllFlagsRequest) (*GetFlagsResponse, error)
+	// WatchFlags streams the current status of all the flags of the project in
+	// the environment followed by the status of every flag that changes.
+	WatchFlags(*WatchFlagsRequest, FlagProvider_WatchFlagsServer) error
 	mustEmbedUnimplementedFlagProviderServer()
 }
 
@@ -63,6 +134,15 @@ type UnimplementedFlagProviderServer struct {
 func (UnimplementedFlagProviderServer) GetFlag(context.Context, *GetFlagRequest) (*GetFlagResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method GetFlag not implemented")
 }
+func (UnimplementedFlagProviderServer) GetFlags(context.Context, *GetFlagsRequest) (*GetFlagsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetFlags not implemented")
+}
+func (UnimplementedFlagProviderServer) GetAllFlags(context.Context, *GetAllFlagsRequest) (*GetFlagsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetAllFlags not implemented")
+}
+func (UnimplementedFlagProviderServer) WatchFlags(*WatchFlagsRequest, FlagProvider_WatchFlagsServer) error {
+	return status.Errorf(codes.Unimplemented, "method WatchFlags not implemented")
+}
 func (UnimplementedFlagProviderServer) mustEmbedUnimplementedFlagProviderServer() {}
 
 // UnsafeFlagProviderServer may be embedded to opt out of forward compatibility for this service.
@@ -94,6 +174,63 @@ func _FlagProvider_GetFlag_Handler(srv interface{}, ctx context.Context, dec fun
 	return interceptor(ctx, in, info, handler)
 }
 
+func _FlagProvider_GetFlags_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetFlagsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(FlagProviderServer).GetFlags(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: FlagProvider_GetFlags_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(FlagProviderServer).GetFlags(ctx, req.(*GetFlagsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _FlagProvider_GetAllFlags_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetAllFlagsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(FlagProviderServer).GetAllFlags(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: FlagProvider_GetAllFlags_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(FlagProviderServer).GetAllFlags(ctx, req.(*GetAllFlagsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _FlagProvider_WatchFlags_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(WatchFlagsRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(FlagProviderServer).WatchFlags(m, &flagProviderWatchFlagsServer{stream})
+}
+
+type FlagProvider_WatchFlagsServer interface {
+	Send(*WatchFlagsResponse) error
+	grpc.ServerStream
+}
+
+type flagProviderWatchFlagsServer struct {
+	grpc.ServerStream
+}
+
+func (x *flagProviderWatchFlagsServer) Send(m *WatchFlagsResponse) error {
+	return x.ServerStream.SendMsg(m)
+}
+
 // FlagProvider_ServiceDesc is the grpc.ServiceDesc for FlagProvider service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -105,7 +242,21 @@ var FlagProvider_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "GetFlag",
 			Handler:    _FlagProvider_GetFlag_Handler,
 		},
+		{
+			MethodName: "GetFlags",
+			Handler:    _FlagProvider_GetFlags_Handler,
+		},
+		{
+			MethodName: "GetAllFlags",
+			Handler:    _FlagProvider_GetAllFlags_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "WatchFlags",
+			Handler:       _FlagProvider_WatchFlags_Handler,
+			ServerStreams: true,
+		},
 	},
-	Streams:  []grpc.StreamDesc{},
 	Metadata: "proto/providerpb/flag_provider.proto",
 }